@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/config"
+)
+
+// resolveAccount returns the account an input was paid with: account when
+// given, else the card named by --card. The result must name a configured card
+// or account (see config.ValidateAccount).
+func resolveAccount(appCfg *config.Config, account, card string) (string, error) {
+	if account == "" {
+		account = card
+	}
+	if err := appCfg.ValidateAccount(account); err != nil {
+		return "", err
+	}
+	return account, nil
+}
+
+// entryMeta is what an expense is logged with besides its amount and
+// classification: the run writing it, the account that paid and its tags.
+type entryMeta struct {
+	RunID   string
+	Account string
+	Tags    []string
+}
+
+// options returns the appender options recording m on every entry.
+func (m entryMeta) options() []appender.EntryOption {
+	return []appender.EntryOption{appender.WithAccount(m.Account), appender.WithTags(m.Tags), appender.WithRun(m.RunID)}
+}
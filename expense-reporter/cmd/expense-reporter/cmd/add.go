@@ -7,10 +7,12 @@ import (
 	"expense-reporter/internal/classifier"
 	"expense-reporter/internal/config"
 	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
 	taxonomy "expense-reporter/internal/taxonomy"
 	"expense-reporter/pkg/utils"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"strings"
 	"time"
@@ -21,6 +23,9 @@ import (
 var addDryRun bool
 var addDataDir string
 var addType string
+var addCard string
+var addAccount string
+var addTags []string
 
 var addPredictedSubcategory string
 var addPredictedCategory string
@@ -36,11 +41,27 @@ var addCmd = &cobra.Command{
 The expense format is: <item_description>;<DD/MM or DD/MM/YYYY>;<value>;<sub_category>
 
 Installment notation: append /N to the value to expand into N monthly log entries.
+Uneven plans join terms with +: a down payment plus Nx<value> ("200,00+10x90,00") or
+explicit per-month values ("120,00+90,00+90,00"); @<rate>% after /N applies monthly
+interest (Tabela Price), e.g. "1000,00/10@1,99%".
+Foreign currency: prefix the value with an ISO code (USD, EUR, ...) to convert it to
+BRL with the local rate table; --card applies that card's IOF surcharge.
+Account: --account records the card or account that paid (default: --card); it
+must be listed under "cards" or "accounts" in config.
+Tags: --tag labels the expense (repeat it, or join tags with commas); the tag rules
+in config add theirs. Every installment and split part carries the tags.
+Split notation: give sub=value pairs joined by | instead of a subcategory to log one
+entry per part; the parts must sum to the value and share a parent ID.
 
 Examples:
   expense-reporter add "Uber Centro;15/04/2026;35,50;Uber/Taxi"
   expense-reporter add "Compras Carrefour;03/01/2026;150,00;Supermercado"
   expense-reporter add "Curso online;15/11/2026;90,00/3;Amazon"
+  expense-reporter add "Geladeira;10/05/2026;500,00+10x250,00;Eletrodomésticos"
+  expense-reporter add "Steam;17/04/2026;USD 20,00;Jogos" --card nubank
+  expense-reporter add "Padaria;18/04/2026;12,00;Padaria" --account pix
+  expense-reporter add "Pousada;12/01/2026;800,00;Hospedagem" --tag viagem-floripa
+  expense-reporter add "Carrefour;03/01/2026;150,00;Supermercado=120,00|Limpeza=30,00"
 
 Notes:
   - Date accepts DD/MM (defaults to current year) or DD/MM/YYYY
@@ -55,6 +76,9 @@ func init() {
 	addCmd.Flags().BoolVar(&addDryRun, "dry-run", false, "Validate and parse without inserting into log")
 	addCmd.Flags().StringVar(&addDataDir, "data-dir", "data/classification", "(deprecated, no longer used: add resolves via config/taxonomy.json since T-13)")
 	addCmd.Flags().StringVar(&addType, "type", "", "Expense type (Fixas/Variáveis/Extras/Adicionais) — required only for subcategories that exist under more than one type")
+	addCmd.Flags().StringVar(&addCard, "card", "", "Card the expense was paid with (applies its configured IOF to foreign-currency values)")
+	addCmd.Flags().StringVar(&addAccount, "account", "", "Card or account the expense was paid with (default: --card)")
+	addCmd.Flags().StringSliceVar(&addTags, "tag", nil, "Tag the expense (repeatable)")
 	addCmd.Flags().StringVar(&addPredictedSubcategory, "predicted-subcategory", "", "Model's top prediction for subcategory")
 	addCmd.Flags().StringVar(&addPredictedCategory, "predicted-category", "", "Model's predicted category")
 	addCmd.Flags().StringVar(&addClassificationID, "classification-id", "", "ID from the prior classify call (for cross-reference)")
@@ -66,9 +90,9 @@ func init() {
 func runAdd(cmd *cobra.Command, args []string) error {
 	expenseString := args[0]
 
-	item, dateStr, parsedDate, value, installmentCount, subcategory, ok := parseExpenseForFeedback(expenseString)
+	in, ok := parseExpenseForFeedback(expenseString)
 	if !ok {
-		return fmt.Errorf("invalid expense format: expected \"item;DD/MM[/YYYY];value[/N];subcategory\"")
+		return fmt.Errorf("invalid expense format: expected \"item;DD/MM[/YYYY];[CUR ]value[/N];subcategory\"")
 	}
 
 	appCfg, err := config.Load()
@@ -81,50 +105,188 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if utils.IsSplitSpec(in.Subcategory) {
+		return runAddSplit(cmd, appCfg, sheets, in)
+	}
+
 	// T-13: resolve the full (type, category) path from taxonomy.json — the single
 	// source of truth — instead of deriving category from the feature dictionary and
 	// type from a separate lookup that could disagree.
-	typ, category, err := resolveFullPath(sheets, subcategory, addType, stdinIsInteractive(), os.Stdin)
+	typ, category, err := resolveFullPath(sheets, in.Subcategory, addType, stdinIsInteractive(), os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	conv, err := newForeignConverter(appCfg, addCard)
+	if err != nil {
+		return err
+	}
+	account, err := resolveAccount(appCfg, addAccount, addCard)
 	if err != nil {
 		return err
 	}
+	given, err := resolveTags(appCfg, addTags)
+	if err != nil {
+		return err
+	}
+	meta := entryMeta{Account: account, Tags: expenseTags(appCfg, given, tags.Expense{
+		Item: in.Item, Date: in.Date, Type: typ, Category: category, Subcategory: in.Subcategory, Account: account,
+	})}
+	brlSched, foreign, err := conv.convertSchedule(in.Currency, in.Date, in.Schedule)
+	if err != nil {
+		return err
+	}
+	brlValue := brlSched.Values[0]
 
 	if addDryRun {
-		return runAddDryRun(cmd, item, dateStr, value, typ, subcategory, category)
+		return runAddDryRun(cmd, in.Item, in.DateStr, brlValue, typ, in.Subcategory, category, meta, foreign)
 	}
 
 	if logPath := appCfg.ExpensesLogFilePath(); logPath != "" {
-		if err := appender.ExpandAndAppend(logPath, item, parsedDate, value, installmentCount, typ, category, subcategory); err != nil {
+		opts := recordPlan(appCfg.PlansFilePath(), in.Item, in.DateStr, in.Value, in.Date, brlSched, typ, category, in.Subcategory, meta)
+		opts = append(opts, appender.WithForeign(foreign))
+		if err := appender.AppendSchedule(logPath, in.Item, in.Date, brlSched.Values, typ, category, in.Subcategory,
+			append(opts, meta.options()...)...); err != nil {
 			fmt.Fprintf(os.Stderr, "⚠  expense log: %v\n", err)
 		}
 	}
 
+	// classifications.jsonl records the amount as typed (original currency), so a
+	// later classify/correct of the same input line hashes to the same ID.
 	if addPredictedSubcategory != "" {
-		logPredictedFeedback(appCfg, item, dateStr, value, subcategory, category,
+		logPredictedFeedback(appCfg, in.Item, in.DateStr, in.Value, in.Subcategory, category,
 			addPredictedSubcategory, addPredictedCategory, addClassificationID,
 			addConfidence, addModel)
 	} else {
-		logManualFeedback(appCfg, item, dateStr, value, subcategory, category)
+		logManualFeedback(appCfg, in.Item, in.DateStr, in.Value, in.Subcategory, category)
 	}
 
 	fmt.Println("✓ Expense added successfully!")
 	return nil
 }
 
+// runAddSplit logs a split transaction: one expense-log entry per part, sharing
+// the parent ID of the unsplit line. classifications.jsonl gets a single split
+// entry rather than per-part examples — the item maps to no single subcategory.
+func runAddSplit(cmd *cobra.Command, appCfg *config.Config, sheets []taxonomy.ExpenseType, in addInput) error {
+	parts, spec, err := resolveSplit(sheets, in.Subcategory, in.Value, in.InstallmentCount, addType, stdinIsInteractive(), os.Stdin)
+	if err != nil {
+		return err
+	}
+	conv, err := newForeignConverter(appCfg, addCard)
+	if err != nil {
+		return err
+	}
+	account, err := resolveAccount(appCfg, addAccount, addCard)
+	if err != nil {
+		return err
+	}
+	given, err := resolveTags(appCfg, addTags)
+	if err != nil {
+		return err
+	}
+	if err := convertSplit(conv, in.Currency, in.Date, parts); err != nil {
+		return err
+	}
+	tagSplitParts(appCfg, given, in.Item, in.Date, account, parts)
+
+	if addDryRun {
+		return runAddSplitDryRun(cmd, in, spec, account, parts)
+	}
+
+	parentID := feedback.GenerateID(in.Item, in.DateStr, in.Value)
+	if logPath := appCfg.ExpensesLogFilePath(); logPath != "" {
+		if err := appender.AppendSplit(logPath, in.Item, in.Date, parentID, parts, entryMeta{Account: account}.options()...); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠  expense log: %v\n", err)
+		}
+	}
+
+	predicted := classifier.Result{
+		Subcategory: addPredictedSubcategory,
+		Category:    addPredictedCategory,
+		Confidence:  addConfidence,
+	}
+	logSplitFeedback(appCfg, in.Item, in.DateStr, in.Value, predicted, addModel, spec, "")
+
+	fmt.Printf("✓ Expense added successfully! (split into %d parts)\n", len(parts))
+	return nil
+}
+
 // AddOutput represents the structured output of an add --dry-run command.
 // Type is the expense type resolved from the taxonomy full path (T-13); omitted
 // when empty so the field is additive for callers that don't consume it.
 type AddOutput struct {
-	Item        string  `json:"item"`
-	Value       float64 `json:"value"`
-	Date        string  `json:"date"`
-	Type        string  `json:"type,omitempty"`
-	Subcategory string  `json:"subcategory"`
-	Category    string  `json:"category"`
-	Action      string  `json:"action"`
+	Item        string   `json:"item"`
+	Value       float64  `json:"value"`
+	Date        string   `json:"date"`
+	Type        string   `json:"type,omitempty"`
+	Subcategory string   `json:"subcategory"`
+	Category    string   `json:"category"`
+	Action      string   `json:"action"`
+	Account     string   `json:"account,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	Foreign *feedback.ForeignAmount `json:"foreign,omitempty"`
+	Split   []AddSplitPart          `json:"split,omitempty"`
 }
 
-func runAddDryRun(cmd *cobra.Command, item, date string, value float64, typ, subcategory, category string) error {
+// AddSplitPart is one part of a split transaction in add --dry-run output.
+// Value is in BRL; Foreign is set when the part was converted.
+type AddSplitPart struct {
+	Type        string                  `json:"type,omitempty"`
+	Category    string                  `json:"category"`
+	Subcategory string                  `json:"subcategory"`
+	Value       float64                 `json:"value"`
+	Foreign     *feedback.ForeignAmount `json:"foreign,omitempty"`
+	Tags        []string                `json:"tags,omitempty"`
+}
+
+// runAddSplitDryRun reports the parts a split add would log. Subcategory carries
+// the split notation so JSON callers can tell a split from a plain add.
+func runAddSplitDryRun(cmd *cobra.Command, in addInput, spec, account string, parts []appender.SplitPart) error {
+	out := AddOutput{
+		Item:        in.Item,
+		Date:        in.DateStr,
+		Subcategory: spec,
+		Action:      "would_insert",
+		Account:     account,
+	}
+	for _, p := range parts {
+		out.Value += p.Value
+		out.Split = append(out.Split, AddSplitPart{
+			Type:        p.Type,
+			Category:    p.Category,
+			Subcategory: p.Subcategory,
+			Value:       p.Value,
+			Foreign:     p.Foreign,
+			Tags:        p.Tags,
+		})
+	}
+
+	out.Value = math.Round(out.Value*100) / 100
+
+	if jsonMode, _ := cmd.Flags().GetBool("json"); jsonMode {
+		return printJSON(out)
+	}
+
+	fmt.Printf("Dry run — would insert a split of %d parts:\n", len(out.Split))
+	fmt.Printf("  Item:        %s\n", out.Item)
+	fmt.Printf("  Date:        %s\n", out.Date)
+	fmt.Printf("  Value:       %.2f\n", out.Value)
+	if out.Account != "" {
+		fmt.Printf("  Account:     %s\n", out.Account)
+	}
+	for _, sp := range out.Split {
+		fmt.Printf("  - %-20s %10.2f  %s / %s", sp.Subcategory, sp.Value, sp.Type, sp.Category)
+		if len(sp.Tags) > 0 {
+			fmt.Printf("  [%s]", strings.Join(sp.Tags, ", "))
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+func runAddDryRun(cmd *cobra.Command, item, date string, value float64, typ, subcategory, category string, meta entryMeta, foreign *feedback.ForeignAmount) error {
 	jsonMode, _ := cmd.Flags().GetBool("json")
 
 	if jsonMode {
@@ -136,6 +298,9 @@ func runAddDryRun(cmd *cobra.Command, item, date string, value float64, typ, sub
 			Subcategory: subcategory,
 			Category:    category,
 			Action:      "would_insert",
+			Account:     meta.Account,
+			Tags:        meta.Tags,
+			Foreign:     foreign,
 		})
 	}
 
@@ -143,6 +308,13 @@ func runAddDryRun(cmd *cobra.Command, item, date string, value float64, typ, sub
 	fmt.Printf("  Item:        %s\n", item)
 	fmt.Printf("  Date:        %s\n", date)
 	fmt.Printf("  Value:       %.2f\n", value)
+	if foreign != nil {
+		fmt.Printf("  Original:    %s %.2f @ %.4f (%s)", foreign.Currency, foreign.Amount, foreign.Rate, foreign.RateDate)
+		if foreign.IOFRate > 0 {
+			fmt.Printf(" + IOF %.2f%%", foreign.IOFRate*100)
+		}
+		fmt.Println()
+	}
 	if typ != "" {
 		fmt.Printf("  Type:        %s\n", typ)
 	}
@@ -150,6 +322,12 @@ func runAddDryRun(cmd *cobra.Command, item, date string, value float64, typ, sub
 	if category != "" {
 		fmt.Printf("  Category:    %s\n", category)
 	}
+	if meta.Account != "" {
+		fmt.Printf("  Account:     %s\n", meta.Account)
+	}
+	if len(meta.Tags) > 0 {
+		fmt.Printf("  Tags:        %s\n", strings.Join(meta.Tags, ", "))
+	}
 	return nil
 }
 
@@ -163,34 +341,49 @@ func logParsedManualFeedback(item, date string, value float64, subcategory, cate
 	logManualFeedback(appCfg, item, date, value, subcategory, category)
 }
 
-// parseExpenseForFeedback splits "item;DD/MM[/YYYY];value[/N];subcategory" and parses date + value.
-// Returns the formatted dateStr (DD/MM/YYYY), parsed time.Time, per-installment value, and installment count.
-func parseExpenseForFeedback(expenseString string) (item, dateStr string, parsedDate time.Time, value float64, installmentCount int, subcategory string, ok bool) {
+// addInput is one parsed "item;date;value;subcategory" expense string.
+type addInput struct {
+	Item             string
+	DateStr          string    // formatted DD/MM/YYYY
+	Date             time.Time // parsed date
+	Value            float64   // per-installment (first payment) value, in Currency
+	InstallmentCount int
+	Schedule         utils.InstallmentSchedule // every payment, in Currency
+	Currency         string                    // ISO code of Value; "" = BRL
+	Subcategory      string
+}
+
+// parseExpenseForFeedback splits "item;DD/MM[/YYYY];[CUR ]value[/N];subcategory" and parses
+// date + value. The value keeps its original currency; conversion to BRL is the caller's job.
+func parseExpenseForFeedback(expenseString string) (addInput, bool) {
 	parts := strings.SplitN(expenseString, ";", 4)
 	if len(parts) != 4 {
-		return
+		return addInput{}, false
+	}
+	in := addInput{
+		Item:        strings.TrimSpace(parts[0]),
+		Subcategory: strings.TrimSpace(parts[3]),
 	}
-	item = strings.TrimSpace(parts[0])
 	rawDate := strings.TrimSpace(parts[1])
-	valueStr := strings.TrimSpace(parts[2])
-	subcategory = strings.TrimSpace(parts[3])
-	if item == "" || rawDate == "" || subcategory == "" {
-		return
+	if in.Item == "" || rawDate == "" || in.Subcategory == "" {
+		return addInput{}, false
 	}
 	t, err := utils.ParseDateFlexible(rawDate)
 	if err != nil {
-		return
+		return addInput{}, false
 	}
-	parsedDate = t
-	dateStr = utils.FormatDate(t)
-	v, count, err := utils.ParseCurrencyWithInstallments(valueStr)
+	in.Date = t
+	in.DateStr = utils.FormatDate(t)
+	currency, valueStr := utils.SplitCurrencyCode(parts[2])
+	sched, err := utils.ParseInstallments(valueStr)
 	if err != nil {
-		return
+		return addInput{}, false
 	}
-	value = v
-	installmentCount = count
-	ok = true
-	return
+	in.Schedule = sched
+	in.Value = sched.Values[0]
+	in.InstallmentCount = sched.Count()
+	in.Currency = currency
+	return in, true
 }
 
 // resolveFullPath resolves a bare subcategory to its (type, category) via the
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	addIncomeDeduction bool
+	addIncomeDryRun    bool
+)
+
+var addIncomeCmd = &cobra.Command{
+	Use:   "add-income \"<note>;<DD/MM[/YYYY]>;<##,##>;<block/line>\"",
+	Short: "Add a single income line",
+	Long: `Appends an income line to the income log (income_log_path in config). The last
+field names a revenue line of the taxonomy's incomeCategories: "Block/Line", or
+just the line when only one block has it. --deduction records the value as
+negative (INSS, IRRF and other withholdings).
+
+Examples:
+  expense-reporter add-income "Salário março;05/03/2026;9500,00;Salário/Salário"
+  expense-reporter add-income "INSS março;05/03/2026;908,85;Salário/INSS" --deduction
+  expense-reporter add-income "Dividendos ITSA4;15/03/2026;42,10;Dividendos"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAddIncome,
+}
+
+func init() {
+	addIncomeCmd.Flags().BoolVar(&addIncomeDeduction, "deduction", false, "Record the value as a deduction (negative)")
+	addIncomeCmd.Flags().BoolVar(&addIncomeDryRun, "dry-run", false, "Validate and parse without writing to the income log")
+	rootCmd.AddCommand(addIncomeCmd)
+}
+
+func runAddIncome(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	blocks, err := loadIncomeBlocks(appCfg)
+	if err != nil {
+		return err
+	}
+	entry, err := parseIncome(args[0], addIncomeDeduction, blocks)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  %s  %-30s R$ %s  → %s/%s\n", entry.Date, entry.ItemNote, utils.FormatBRValue(entry.Value), entry.IncomeCategory, entry.IncomeLabel)
+	if addIncomeDryRun {
+		return nil
+	}
+	logPath := appCfg.IncomeLogFilePath()
+	if logPath == "" {
+		return fmt.Errorf("income log path not configured\n  Hint: set income_log_path in config")
+	}
+	if err := feedback.AppendIncome(logPath, entry); err != nil {
+		return err
+	}
+	fmt.Println("✓ Income added successfully!")
+	return nil
+}
+
+// loadIncomeBlocks returns the revenue leaves of the configured taxonomy.
+func loadIncomeBlocks(appCfg *config.Config) ([]taxonomy.RevenueBlock, error) {
+	path := appCfg.TaxonomyFilePath()
+	if path == "" {
+		return nil, fmt.Errorf("taxonomy path not configured")
+	}
+	_, blocks, err := taxonomy.LoadTaxonomy(path, "", "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("loading taxonomy: %w", err)
+	}
+	return blocks, nil
+}
+
+// parseIncome parses "<note>;<date>;<value>;<block/line>" into an income log
+// entry. The date is stored as DD/MM/YYYY (DD/MM takes the current year); a
+// deduction is stored negative.
+func parseIncome(s string, deduction bool, blocks []taxonomy.RevenueBlock) (feedback.IncomeEntry, error) {
+	parts := strings.Split(s, ";")
+	if len(parts) != 4 {
+		return feedback.IncomeEntry{}, fmt.Errorf("invalid income format: expected \"note;DD/MM[/YYYY];value;block/line\"")
+	}
+	note := strings.TrimSpace(parts[0])
+	if note == "" {
+		return feedback.IncomeEntry{}, fmt.Errorf("income note cannot be empty")
+	}
+	date, err := utils.ParseDateFlexible(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return feedback.IncomeEntry{}, err
+	}
+	value, err := utils.ParseCurrency(strings.TrimSpace(parts[2]))
+	if err != nil {
+		return feedback.IncomeEntry{}, err
+	}
+	if deduction {
+		value = -value
+	}
+	leaf, err := taxonomy.ResolveIncomeLeaf(blocks, parts[3])
+	if err != nil {
+		return feedback.IncomeEntry{}, err
+	}
+	return feedback.NewIncomeEntry(note, utils.FormatDate(date), value, leaf.Block, leaf.Label), nil
+}
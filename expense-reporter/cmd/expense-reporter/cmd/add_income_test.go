@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	taxonomy "expense-reporter/internal/taxonomy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIncome(t *testing.T) {
+	blocks := []taxonomy.RevenueBlock{
+		{Block: "Salário", Label: "Salário"},
+		{Block: "Salário", Label: "INSS"},
+	}
+
+	entry, err := parseIncome("Salário março;05/03/2026;9500,00;salário/salário", false, blocks)
+	require.NoError(t, err)
+	assert.Equal(t, "Salário março", entry.ItemNote)
+	assert.Equal(t, "05/03/2026", entry.Date)
+	assert.Equal(t, 9500.0, entry.Value)
+	assert.Equal(t, "Salário", entry.IncomeCategory)
+	assert.Equal(t, "Salário", entry.IncomeLabel)
+	assert.NotEmpty(t, entry.ID)
+
+	entry, err = parseIncome("INSS março;05/03/2026;908,85;INSS", true, blocks)
+	require.NoError(t, err)
+	assert.Equal(t, -908.85, entry.Value, "a deduction is stored negative")
+	assert.Equal(t, "INSS", entry.IncomeLabel)
+
+	_, err = parseIncome("Aluguel;05/03/2026;1500,00;Aluguel", false, blocks)
+	assert.ErrorContains(t, err, "not in the taxonomy's incomeCategories")
+
+	_, err = parseIncome("Salário março;05/03/2026;9500,00", false, blocks)
+	assert.ErrorContains(t, err, "invalid income format")
+
+	_, err = parseIncome(";05/03/2026;9500,00;Salário", false, blocks)
+	assert.ErrorContains(t, err, "cannot be empty")
+}
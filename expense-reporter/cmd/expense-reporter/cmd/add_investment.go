@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	addInvestmentWithdrawal bool
+	addInvestmentDryRun     bool
+)
+
+var addInvestmentCmd = &cobra.Command{
+	Use:   "add-investment \"<item>;<DD/MM[/YYYY]>;<##,##>;<asset class>\"",
+	Short: "Record an investment contribution or withdrawal",
+	Long: `Appends a contribution (or, with --withdrawal, a withdrawal) to the investments
+ledger (investments_log_path in config). The asset class must be one of the
+taxonomy's "investmentClasses".
+
+generate-workbook --investments reads the ledger into the Listas de itens
+Investimentos rows: one row per asset class, contributions negative (money set
+aside from the month's income) and withdrawals positive, so the balance block
+shows what is left after saving.
+
+Examples:
+  expense-reporter add-investment "Tesouro Selic;05/03/2026;1000,00;Renda fixa"
+  expense-reporter add-investment "Resgate CDB;20/03/2026;500,00;Renda fixa" --withdrawal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAddInvestment,
+}
+
+func init() {
+	addInvestmentCmd.Flags().BoolVar(&addInvestmentWithdrawal, "withdrawal", false, "Record a withdrawal instead of a contribution")
+	addInvestmentCmd.Flags().BoolVar(&addInvestmentDryRun, "dry-run", false, "Validate and parse without writing to the ledger")
+	rootCmd.AddCommand(addInvestmentCmd)
+}
+
+func runAddInvestment(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	taxonomyPath := appCfg.TaxonomyFilePath()
+	if taxonomyPath == "" {
+		return fmt.Errorf("taxonomy path not configured")
+	}
+	classes, err := taxonomy.LoadInvestments(taxonomyPath, "", 0)
+	if err != nil {
+		return err
+	}
+
+	kind := feedback.InvestmentContribution
+	if addInvestmentWithdrawal {
+		kind = feedback.InvestmentWithdrawal
+	}
+	entry, err := parseInvestment(args[0], kind, classes)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  %s  %-30s R$ %s  → %s (%s)\n", entry.Date, entry.Item, utils.FormatBRValue(entry.Value), entry.AssetClass, entry.Kind)
+	if addInvestmentDryRun {
+		return nil
+	}
+	logPath := appCfg.InvestmentsLogFilePath()
+	if logPath == "" {
+		return fmt.Errorf("investments log path not configured\n  Hint: set investments_log_path in config")
+	}
+	if err := feedback.AppendInvestment(logPath, entry); err != nil {
+		return err
+	}
+	fmt.Println("✓ Investment recorded")
+	return nil
+}
+
+// parseInvestment parses "<item>;<date>;<value>;<asset class>" into a ledger
+// entry of kind. The date is stored as DD/MM/YYYY (DD/MM takes the current
+// year) and the class with its taxonomy spelling.
+func parseInvestment(s, kind string, classes []taxonomy.AssetClass) (feedback.InvestmentEntry, error) {
+	parts := strings.Split(s, ";")
+	if len(parts) != 4 {
+		return feedback.InvestmentEntry{}, fmt.Errorf("invalid investment format: expected \"item;DD/MM[/YYYY];value;asset class\"")
+	}
+	item := strings.TrimSpace(parts[0])
+	if item == "" {
+		return feedback.InvestmentEntry{}, fmt.Errorf("investment item cannot be empty")
+	}
+	date, err := utils.ParseDateFlexible(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return feedback.InvestmentEntry{}, err
+	}
+	value, err := utils.ParseCurrency(strings.TrimSpace(parts[2]))
+	if err != nil {
+		return feedback.InvestmentEntry{}, err
+	}
+	if value <= 0 {
+		return feedback.InvestmentEntry{}, fmt.Errorf("investment value must be greater than zero")
+	}
+
+	class := strings.TrimSpace(parts[3])
+	known := make([]string, len(classes))
+	for i, c := range classes {
+		known[i] = c.Name
+		if strings.EqualFold(c.Name, class) {
+			return feedback.NewInvestmentEntry(item, utils.FormatDate(date), value, kind, c.Name), nil
+		}
+	}
+	if len(known) == 0 {
+		return feedback.InvestmentEntry{}, fmt.Errorf("the taxonomy declares no investment classes\n  Hint: add \"investmentClasses\" to the taxonomy file")
+	}
+	return feedback.InvestmentEntry{}, fmt.Errorf("asset class %q is not in the taxonomy (known classes: %s)", class, strings.Join(known, ", "))
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"expense-reporter/internal/feedback"
+	taxonomy "expense-reporter/internal/taxonomy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInvestment(t *testing.T) {
+	classes := []taxonomy.AssetClass{{Name: "Renda fixa"}, {Name: "Ações"}}
+
+	entry, err := parseInvestment("Tesouro Selic;05/03/2026;1000,00;renda fixa", feedback.InvestmentContribution, classes)
+	require.NoError(t, err)
+	assert.Equal(t, "Tesouro Selic", entry.Item)
+	assert.Equal(t, "05/03/2026", entry.Date)
+	assert.Equal(t, 1000.0, entry.Value)
+	assert.Equal(t, "Renda fixa", entry.AssetClass, "stored with the taxonomy's spelling")
+	assert.Equal(t, feedback.InvestmentContribution, entry.Kind)
+
+	_, err = parseInvestment("Bitcoin;05/03/2026;200,00;Cripto", feedback.InvestmentContribution, classes)
+	assert.ErrorContains(t, err, "known classes: Renda fixa, Ações")
+
+	_, err = parseInvestment("Resgate;05/03/2026;0,00;Ações", feedback.InvestmentWithdrawal, classes)
+	assert.ErrorContains(t, err, "greater than zero")
+
+	_, err = parseInvestment("Tesouro Selic;05/03/2026;1000,00", feedback.InvestmentContribution, classes)
+	assert.ErrorContains(t, err, "invalid investment format")
+
+	_, err = parseInvestment("Tesouro Selic;05/03/2026;1000,00;Renda fixa", feedback.InvestmentContribution, nil)
+	assert.ErrorContains(t, err, "declares no investment classes")
+}
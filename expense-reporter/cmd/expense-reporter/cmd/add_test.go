@@ -153,7 +153,7 @@ func TestRunAddDryRun_JSON(t *testing.T) {
 	require.NoError(t, err)
 
 	os.Stdout = w
-	runErr := runAddDryRun(cmd, "Uber Centro", "15/04", 35.50, "Variáveis", "Uber/Taxi", "Transporte")
+	runErr := runAddDryRun(cmd, "Uber Centro", "15/04", 35.50, "Variáveis", "Uber/Taxi", "Transporte", entryMeta{}, nil)
 	w.Close()
 	os.Stdout = oldStdout
 
@@ -185,7 +185,7 @@ func TestRunAddDryRun_Text(t *testing.T) {
 	require.NoError(t, err)
 
 	os.Stdout = w
-	runErr := runAddDryRun(cmd, "Uber Centro", "15/04", 35.50, "Variáveis", "Uber/Taxi", "Transporte")
+	runErr := runAddDryRun(cmd, "Uber Centro", "15/04", 35.50, "Variáveis", "Uber/Taxi", "Transporte", entryMeta{Account: "nubank", Tags: []string{"viagem", "reembolsavel"}}, nil)
 	w.Close()
 	os.Stdout = oldStdout
 
@@ -204,6 +204,8 @@ func TestRunAddDryRun_Text(t *testing.T) {
 	assert.Contains(t, output, "Type:        Variáveis")
 	assert.Contains(t, output, "Subcategory: Uber/Taxi")
 	assert.Contains(t, output, "Category:    Transporte")
+	assert.Contains(t, output, "Account:     nubank")
+	assert.Contains(t, output, "Tags:        viagem, reembolsavel")
 }
 
 func TestRunAddDryRun_Text_EmptyCategory(t *testing.T) {
@@ -214,7 +216,7 @@ func TestRunAddDryRun_Text_EmptyCategory(t *testing.T) {
 	require.NoError(t, err)
 
 	os.Stdout = w
-	runErr := runAddDryRun(cmd, "Coffee", "03/01", 12.90, "", "Cafeteria", "")
+	runErr := runAddDryRun(cmd, "Coffee", "03/01", 12.90, "", "Cafeteria", "", entryMeta{}, nil)
 	w.Close()
 	os.Stdout = oldStdout
 
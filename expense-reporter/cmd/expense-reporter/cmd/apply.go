@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,6 +16,9 @@ import (
 	"expense-reporter/internal/excel"
 	"expense-reporter/internal/feedback"
 	"expense-reporter/internal/models"
+	"expense-reporter/internal/runs"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
 	"expense-reporter/pkg/utils"
 )
 
@@ -23,6 +27,8 @@ var (
 	applyYear     int
 	applyDryRun   bool
 	applyBackup   bool
+	applyCard     string
+	applyAccount  string
 )
 
 var applyCmd = &cobra.Command{
@@ -32,7 +38,23 @@ var applyCmd = &cobra.Command{
 and writes feedback entries to classifications.jsonl.
 
 Pending and skipped entries are ignored. Confirmed and corrected entries already
-present in classifications.jsonl receive feedback-only updates (no workbook write).`,
+present in classifications.jsonl receive feedback-only updates (no workbook write).
+
+Split entries insert one row per part; classifications.jsonl records the split
+once, under the original entry's ID.
+
+Foreign-currency entries are converted to BRL for the workbook and expense log
+with the local rate table (plus the IOF of --card); classifications.jsonl keeps
+the original amount.
+
+Each entry's account (carried from batch-auto's account column through the
+review page) is recorded in the expense log; entries without one get --account,
+or --card when --account is not given. So are the tags set on the review page,
+followed by those of the tag_rules in config each row matches.
+
+A DD/MM date (from a review file written before dates were qualified) takes
+--year for the workbook row, the log lines and the entry ID alike. A DD/MM/YYYY
+date keeps its own year whatever --year says.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runApply,
 }
@@ -40,37 +62,61 @@ present in classifications.jsonl receive feedback-only updates (no workbook writ
 func init() {
 	rootCmd.AddCommand(applyCmd)
 	applyCmd.Flags().StringVar(&applyWorkbook, "workbook", "", "Workbook path (overrides config)")
-	applyCmd.Flags().IntVar(&applyYear, "year", time.Now().Year(), "Year for parsing DD/MM dates")
+	applyCmd.Flags().IntVar(&applyYear, "year", time.Now().Year(), "Year for DD/MM dates (DD/MM/YYYY dates keep their own)")
 	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "Print what would be inserted without writing")
 	applyCmd.Flags().BoolVar(&applyBackup, "backup", false, "Create a timestamped backup of the workbook before writing")
+	applyCmd.Flags().StringVar(&applyCard, "card", "", "Card the reviewed expenses were paid with (applies its configured IOF to foreign-currency values)")
+	applyCmd.Flags().StringVar(&applyAccount, "account", "", "Card or account for entries that carry none (default: --card)")
 }
 
-func runApply(cmd *cobra.Command, args []string) error {
+func runApply(cmd *cobra.Command, args []string) (err error) {
 	cfg, err := internalconfig.Load()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	classifPath := cfg.ClassificationsFilePath()
-	if classifPath == "" {
+	if cfg.ClassificationsFilePath() == "" {
 		return fmt.Errorf("classifications log path is not configured")
 	}
-	expensesLogPath := cfg.ExpensesLogFilePath()
+	classif, err := openClassifications(cfg)
+	if err != nil {
+		return err
+	}
+	expenses, err := openExpenses(cfg)
+	if err != nil {
+		return err
+	}
 
 	rf, err := apply.ReadReviewed(args[0])
 	if err != nil {
 		return fmt.Errorf("reading reviewed file: %w", err)
 	}
+	if err := resolveReviewedAccounts(rf.Entries, cfg, applyAccount, applyCard); err != nil {
+		return err
+	}
+	if err := resolveReviewedTags(rf.Entries, cfg); err != nil {
+		return err
+	}
+	qualifyReviewedDates(rf.Entries, applyYear)
 
 	workbookPath := applyWorkbook
 	if workbookPath == "" {
 		workbookPath = cfg.WorkbookFilePath()
 	}
 
-	newRows, corrections, pendingEntries, skippedEntries, err := processEntries(rf.Entries, classifPath)
+	var run runs.Run
+	if !applyDryRun {
+		run = startRun(cfg, "apply", args[0])
+		defer func() { finishRun(cmd.OutOrStdout(), cfg, run, err) }()
+		classif = tagClassifications(classif, run.ID)
+		expenses = tagExpenses(expenses, run.ID)
+	}
+
+	newRows, corrections, pendingEntries, skippedEntries, err := processEntries(rf.Entries, classif)
 	if err != nil {
 		return fmt.Errorf("processing entries: %w", err)
 	}
+	tagReviewedRows(cfg, newRows, applyYear)
 
 	var insertedConfirmed, insertedCorrected int
 	var uninsertable []apply.ReviewedEntry
@@ -78,6 +124,14 @@ func runApply(cmd *cobra.Command, args []string) error {
 		if workbookPath == "" {
 			return fmt.Errorf("workbook path not configured (set EXPENSE_WORKBOOK or use --workbook)")
 		}
+		conv, err := newForeignConverter(cfg, applyCard)
+		if err != nil {
+			return err
+		}
+		values, err := convertNewRows(newRows, conv, applyYear)
+		if err != nil {
+			return err
+		}
 		if err := excel.ValidateWorkbook(workbookPath); err != nil {
 			return fmt.Errorf("validating workbook: %w", err)
 		}
@@ -88,8 +142,10 @@ func runApply(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("backup failed: %w", err)
 			}
 			fmt.Fprintf(cmd.OutOrStdout(), "✓ Backup created: %s\n", filepath.Base(backupPath))
+			run.Backup = backupPath
 		}
-		insertedConfirmed, insertedCorrected, uninsertable, err = insertNewRows(newRows, workbookPath, classifPath, expensesLogPath, applyYear, applyDryRun)
+		run.Workbook = workbookPath
+		insertedConfirmed, insertedCorrected, uninsertable, err = insertNewRows(newRows, values, workbookPath, classif, expenses, applyYear, applyDryRun)
 		if err != nil {
 			return fmt.Errorf("inserting new rows: %w", err)
 		}
@@ -99,7 +155,77 @@ func runApply(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func processEntries(entries []apply.ReviewedEntry, classifPath string) (newRows, corrections, pendingEntries, skippedEntries []apply.ReviewedEntry, err error) {
+// resolveReviewedAccounts gives every entry without an account the default one
+// (account, else card) and checks that each entry's account is configured, so
+// a typo fails the apply before anything is written.
+func resolveReviewedAccounts(entries []apply.ReviewedEntry, cfg *internalconfig.Config, account, card string) error {
+	def, err := resolveAccount(cfg, account, card)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].Account == "" {
+			entries[i].Account = def
+			continue
+		}
+		if err := cfg.ValidateAccount(entries[i].Account); err != nil {
+			return fmt.Errorf("%q: %w", entries[i].Item, err)
+		}
+	}
+	return nil
+}
+
+// resolveReviewedTags checks the tag rules in config and normalizes each
+// entry's tags, so a bad rule or tag fails the apply before anything is written.
+func resolveReviewedTags(entries []apply.ReviewedEntry, cfg *internalconfig.Config) error {
+	for i := range entries {
+		t, err := resolveTags(cfg, entries[i].Tags)
+		if err != nil {
+			return fmt.Errorf("%q: %w", entries[i].Item, err)
+		}
+		entries[i].Tags = t
+	}
+	return nil
+}
+
+// qualifyReviewedDates gives each DD/MM entry date year, the year its workbook
+// row is written under, and re-derives an ID hashed from the DD/MM date, so the
+// workbook, the log lines and their IDs agree on the year. A date that does not
+// parse is left for the insert to report.
+func qualifyReviewedDates(entries []apply.ReviewedEntry, year int) {
+	for i, entry := range entries {
+		if strings.Count(strings.TrimSpace(entry.Date), "/") != 1 {
+			continue
+		}
+		t, err := utils.ParseDateWithYear(strings.TrimSpace(entry.Date), year)
+		if err != nil {
+			continue
+		}
+		date := utils.FormatDate(t)
+		if entry.ID == feedback.GenerateID(entry.Item, entry.Date, entry.Value) {
+			entries[i].ID = feedback.GenerateID(entry.Item, date, entry.Value)
+		}
+		entries[i].Date = date
+	}
+}
+
+// tagReviewedRows adds to each new row the tags of the rules matching it. Split
+// part rows are matched on their own path. A row whose date does not parse is
+// left as is; inserting it fails on the same date.
+func tagReviewedRows(cfg *internalconfig.Config, newRows []apply.ReviewedEntry, year int) {
+	for i, entry := range newRows {
+		date, err := utils.ParseDateWithYear(entry.Date, year)
+		if err != nil {
+			continue
+		}
+		newRows[i].Tags = expenseTags(cfg, entry.Tags, tags.Expense{
+			Item: entry.Item, Date: date, Type: entry.Reviewed.Type, Category: entry.Reviewed.Category,
+			Subcategory: entry.Reviewed.Subcategory, Account: entry.Account,
+		})
+	}
+}
+
+func processEntries(entries []apply.ReviewedEntry, classif store.Log[feedback.Entry]) (newRows, corrections, pendingEntries, skippedEntries []apply.ReviewedEntry, err error) {
 	for _, entry := range entries {
 		switch entry.Action {
 		case apply.ActionPending:
@@ -107,7 +233,11 @@ func processEntries(entries []apply.ReviewedEntry, classifPath string) (newRows,
 		case apply.ActionSkipped:
 			skippedEntries = append(skippedEntries, entry)
 		case apply.ActionConfirmed, apply.ActionCorrected:
-			if hErr := handleActiveEntry(entry, classifPath, &newRows, &corrections); hErr != nil {
+			if hErr := handleActiveEntry(entry, classif, &newRows, &corrections); hErr != nil {
+				return nil, nil, nil, nil, hErr
+			}
+		case apply.ActionSplit:
+			if hErr := handleSplitEntry(entry, classif, &newRows); hErr != nil {
 				return nil, nil, nil, nil, hErr
 			}
 		}
@@ -115,13 +245,35 @@ func processEntries(entries []apply.ReviewedEntry, classifPath string) (newRows,
 	return newRows, corrections, pendingEntries, skippedEntries, nil
 }
 
-func handleActiveEntry(entry apply.ReviewedEntry, classifPath string, newRows, corrections *[]apply.ReviewedEntry) error {
-	prior, found, err := feedback.FindLatestEntry(classifPath, entry.ID)
+// handleSplitEntry expands a split entry into one new row per part. Each part
+// row keeps the entry's ID and Split (the parent record) with Value and Reviewed
+// narrowed to the part. A split already in classifications.jsonl was applied by
+// an earlier run and is left alone, unless that run was reverted.
+func handleSplitEntry(entry apply.ReviewedEntry, classif store.Log[feedback.Entry], newRows *[]apply.ReviewedEntry) error {
+	prior, found, err := latestClassification(classif, entry)
+	if err != nil {
+		return fmt.Errorf("finding prior entry for %q: %w", entry.ID, err)
+	}
+	if found && !prior.Voided() {
+		return nil
+	}
+	for _, p := range entry.Split {
+		part := entry
+		part.Value = p.Value
+		part.Reviewed = &apply.ReviewedLocation{Type: p.Type, Category: p.Category, Subcategory: p.Subcategory}
+		*newRows = append(*newRows, part)
+	}
+	return nil
+}
+
+func handleActiveEntry(entry apply.ReviewedEntry, classif store.Log[feedback.Entry], newRows, corrections *[]apply.ReviewedEntry) error {
+	prior, found, err := latestClassification(classif, entry)
 	if err != nil {
 		return fmt.Errorf("finding prior entry for %q: %w", entry.ID, err)
 	}
 
-	if !found {
+	// A voided prior belongs to a reverted run: the row is no longer in the workbook.
+	if !found || prior.Voided() {
 		*newRows = append(*newRows, entry)
 		return nil
 	}
@@ -139,7 +291,7 @@ func handleActiveEntry(entry apply.ReviewedEntry, classifPath string, newRows, c
 			entry.Reviewed.Subcategory, entry.Reviewed.Category,
 		)
 		corrEntry.Type = entry.Reviewed.Type
-		if err := feedback.Append(classifPath, corrEntry); err != nil {
+		if err := classif.Append(corrEntry); err != nil {
 			return fmt.Errorf("appending corrected entry: %w", err)
 		}
 		*corrections = append(*corrections, entry)
@@ -148,7 +300,56 @@ func handleActiveEntry(entry apply.ReviewedEntry, classifPath string, newRows, c
 	return nil
 }
 
-func insertNewRows(newRows []apply.ReviewedEntry, workbookPath, classifPath, expensesLogPath string, year int, dryRun bool) (insertedConfirmed, insertedCorrected int, uninsertable []apply.ReviewedEntry, err error) {
+// latestClassification returns the entry's latest classification. A log not yet
+// migrated still keys it by the legacy ID, hashed from the date as typed: the
+// entry's date or, once qualifyReviewedDates gave it a year, its DD/MM part.
+func latestClassification(classif store.Log[feedback.Entry], entry apply.ReviewedEntry) (feedback.Entry, bool, error) {
+	prior, found, err := classif.Latest(entry.ID)
+	if err != nil || found {
+		return prior, found, err
+	}
+	dates := []string{entry.Date}
+	if t, err := utils.ParseDateFlexible(entry.Date); err == nil {
+		dates = append(dates, t.Format("02/01"))
+	}
+	for _, date := range dates {
+		legacy := feedback.LegacyID(entry.Item, date, entry.Value)
+		if legacy == entry.ID {
+			continue
+		}
+		if prior, found, err = classif.Latest(legacy); err != nil || found {
+			return prior, found, err
+		}
+	}
+	return prior, false, nil
+}
+
+// brlValue is a new row's amount as written to the workbook and expense log,
+// with the original-currency record when the reviewed entry was foreign.
+type brlValue struct {
+	value   float64
+	foreign *feedback.ForeignAmount
+}
+
+// convertNewRows converts every new row to BRL up front, so a missing rate fails
+// the apply before anything is written. The result is indexed like newRows.
+func convertNewRows(newRows []apply.ReviewedEntry, conv *foreignConverter, year int) ([]brlValue, error) {
+	values := make([]brlValue, len(newRows))
+	for i, entry := range newRows {
+		t, err := utils.ParseDateWithYear(entry.Date, year)
+		if err != nil {
+			return nil, fmt.Errorf("parsing date for %q: %w", entry.Item, err)
+		}
+		v, foreign, err := conv.convert(entry.Currency, t, entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry.Item, err)
+		}
+		values[i] = brlValue{value: v, foreign: foreign}
+	}
+	return values, nil
+}
+
+func insertNewRows(newRows []apply.ReviewedEntry, values []brlValue, workbookPath string, classif store.Log[feedback.Entry], expenses store.Log[feedback.ExpenseEntry], year int, dryRun bool) (insertedConfirmed, insertedCorrected int, uninsertable []apply.ReviewedEntry, err error) {
 	subcatRows, err := excel.FindSubcategoryRowBatch(workbookPath, buildSubcatRequests(newRows))
 	if err != nil {
 		return 0, 0, nil, fmt.Errorf("finding subcategory rows: %w", err)
@@ -164,7 +365,7 @@ func insertNewRows(newRows []apply.ReviewedEntry, workbookPath, classifPath, exp
 		return 0, 0, nil, fmt.Errorf("allocating empty rows: %w", err)
 	}
 
-	batch, writtenIndices, noSlot := buildExpenseBatch(newRows, parsedDates, subcatRows, emptyReqs, targetRows)
+	batch, writtenIndices, noSlot := buildExpenseBatch(newRows, values, parsedDates, subcatRows, emptyReqs, targetRows)
 	uninsertable = append(notFound, noSlot...)
 
 	if dryRun {
@@ -182,7 +383,7 @@ func insertNewRows(newRows []apply.ReviewedEntry, workbookPath, classifPath, exp
 		if err := excel.WriteBatchExpenses(workbookPath, batch); err != nil {
 			return 0, 0, uninsertable, fmt.Errorf("writing batch expenses: %w", err)
 		}
-		confirmed, corrected, err := writeFeedbackForNewRows(newRows, writtenIndices, classifPath, expensesLogPath)
+		confirmed, corrected, err := writeFeedbackForNewRows(newRows, values, writtenIndices, classif, expenses)
 		return confirmed, corrected, uninsertable, err
 	}
 	return 0, 0, uninsertable, nil
@@ -234,7 +435,7 @@ func buildEmptyRowRequests(newRows []apply.ReviewedEntry, subcatRows map[string]
 // and uses req.ExpenseIndex to retrieve the original newRows entry and its parsed date.
 // This is necessary because buildEmptyRowRequests may skip rows (subcategory not in
 // workbook), making emptyReqs shorter than newRows and shifting AllocateEmptyRows keys.
-func buildExpenseBatch(newRows []apply.ReviewedEntry, dates []time.Time, subcatRows map[string]map[string]int, emptyReqs []excel.EmptyRowRequest, targetRows map[int]int) ([]excel.ExpenseWithLocation, []int, []apply.ReviewedEntry) {
+func buildExpenseBatch(newRows []apply.ReviewedEntry, values []brlValue, dates []time.Time, subcatRows map[string]map[string]int, emptyReqs []excel.EmptyRowRequest, targetRows map[int]int) ([]excel.ExpenseWithLocation, []int, []apply.ReviewedEntry) {
 	var batch []excel.ExpenseWithLocation
 	var indices []int
 	var noSlot []apply.ReviewedEntry
@@ -251,8 +452,9 @@ func buildExpenseBatch(newRows []apply.ReviewedEntry, dates []time.Time, subcatR
 		exp := &models.Expense{
 			Item:        entry.Item,
 			Date:        dates[i],
-			Value:       entry.Value,
+			Value:       values[i].value,
 			Subcategory: entry.Reviewed.Subcategory,
+			Account:     entry.Account,
 		}
 		loc := &models.SheetLocation{
 			SheetName:   entry.Reviewed.Type,
@@ -267,22 +469,39 @@ func buildExpenseBatch(newRows []apply.ReviewedEntry, dates []time.Time, subcatR
 	return batch, indices, noSlot
 }
 
-func writeFeedbackForNewRows(newRows []apply.ReviewedEntry, indices []int, classifPath, expensesLogPath string) (insertedConfirmed, insertedCorrected int, err error) {
+// writeFeedbackForNewRows records each written row. A split counts once, as
+// corrected, however many part rows it wrote; it is logged to
+// classifications.jsonl once, by the first part written, and each part's
+// expense entry points at the split's ID.
+func writeFeedbackForNewRows(newRows []apply.ReviewedEntry, values []brlValue, indices []int, classif store.Log[feedback.Entry], expenses store.Log[feedback.ExpenseEntry]) (insertedConfirmed, insertedCorrected int, err error) {
+	splitLogged := map[string]bool{}
 	for _, i := range indices {
 		entry := newRows[i]
+		isSplit := entry.Action == apply.ActionSplit
 		fbEntry, isConfirmed := buildFeedbackEntry(entry)
-		if isConfirmed {
-			insertedConfirmed++
-		} else {
-			insertedCorrected++
-		}
-		if err := feedback.Append(classifPath, fbEntry); err != nil {
-			return insertedConfirmed, insertedCorrected, fmt.Errorf("appending feedback: %w", err)
+		if !splitLogged[entry.ID] {
+			if isConfirmed {
+				insertedConfirmed++
+			} else {
+				insertedCorrected++
+			}
+			if err := classif.Append(fbEntry); err != nil {
+				return insertedConfirmed, insertedCorrected, fmt.Errorf("appending feedback: %w", err)
+			}
+			if isSplit {
+				splitLogged[entry.ID] = true
+			}
 		}
-		if expensesLogPath != "" {
-			expEntry := feedback.NewExpenseEntry(entry.Item, entry.Date, entry.Value, entry.Reviewed.Subcategory, entry.Reviewed.Category)
+		if expenses != nil {
+			expEntry := feedback.NewExpenseEntry(entry.Item, entry.Date, values[i].value, entry.Reviewed.Subcategory, entry.Reviewed.Category)
+			if isSplit {
+				expEntry = feedback.NewSplitExpenseEntry(entry.ID, entry.Item, entry.Date, values[i].value, entry.Reviewed.Subcategory, entry.Reviewed.Category)
+			}
 			expEntry.Type = entry.Reviewed.Type
-			if err := feedback.AppendExpense(expensesLogPath, expEntry); err != nil {
+			expEntry.Foreign = values[i].foreign
+			expEntry.Account = entry.Account
+			expEntry.Tags = entry.Tags
+			if err := expenses.Append(expEntry); err != nil {
 				return insertedConfirmed, insertedCorrected, fmt.Errorf("appending expense log: %w", err)
 			}
 		}
@@ -291,6 +510,19 @@ func writeFeedbackForNewRows(newRows []apply.ReviewedEntry, indices []int, class
 }
 
 func buildFeedbackEntry(entry apply.ReviewedEntry) (feedback.Entry, bool) {
+	if entry.Action == apply.ActionSplit {
+		predicted := classifier.Result{
+			Subcategory: entry.Predicted.Subcategory,
+			Category:    entry.Predicted.Category,
+			Confidence:  entry.Confidence,
+		}
+		spec := entry.SplitSpec()
+		var total float64
+		for _, p := range spec {
+			total += p.Value
+		}
+		return feedback.NewSplitEntry(entry.Item, entry.Date, total, predicted, "review", utils.FormatSplit(spec)), false
+	}
 	if entry.Action == apply.ActionConfirmed {
 		predicted := classifier.Result{
 			Subcategory: entry.Reviewed.Subcategory,
@@ -315,7 +547,7 @@ func buildFeedbackEntry(entry apply.ReviewedEntry) (feedback.Entry, bool) {
 func printSummary(w io.Writer, source string, total int, pendingEntries, skippedEntries []apply.ReviewedEntry, insertedConfirmed, insertedCorrected int, corrections, uninsertable []apply.ReviewedEntry) {
 	inserted := insertedConfirmed + insertedCorrected
 	fmt.Fprintf(w, "Applied %s (%d entries)\n\n", source, total)
-	fmt.Fprintf(w, "Inserted:     %d rows (%d confirmed, %d corrected)\n", inserted, insertedConfirmed, insertedCorrected)
+	fmt.Fprintf(w, "Inserted:     %d entries (%d confirmed, %d corrected)\n", inserted, insertedConfirmed, insertedCorrected)
 	fmt.Fprintf(w, "Uninsertable: %d rows\n", len(uninsertable))
 	fmt.Fprintf(w, "Skipped:      %d rows\n", len(skippedEntries))
 	fmt.Fprintf(w, "Pending:      %d rows\n", len(pendingEntries))
@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+var applyIncomeDryRun bool
+
+var applyIncomeCmd = &cobra.Command{
+	Use:   "apply-income <income-review.csv>",
+	Short: "Append the reviewed credit rows of batch-auto to the income log",
+	Long: `Reads the income-review.csv written by batch-auto once its block and line
+columns are filled in, and appends each row to the income log (income_log_path
+in config). The line column takes "Line" or "Block/Line"; the block column may
+be left empty when only one block has the line. A value with a leading - is
+recorded as a deduction.
+
+Rows with no line, or a line not in the taxonomy's incomeCategories, are listed
+and skipped. So are rows already in the income log, so the same file can be
+applied again after fixing the skipped ones.
+
+The run is recorded in the run journal; runs revert voids the lines it added.
+
+Example:
+  expense-reporter apply-income ./out/income-review.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApplyIncome,
+}
+
+func init() {
+	applyIncomeCmd.Flags().BoolVar(&applyIncomeDryRun, "dry-run", false, "Print what would be appended without writing")
+	rootCmd.AddCommand(applyIncomeCmd)
+}
+
+func runApplyIncome(cmd *cobra.Command, args []string) (err error) {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	blocks, err := loadIncomeBlocks(appCfg)
+	if err != nil {
+		return err
+	}
+	rows, err := readIncomeReviewCSV(args[0])
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+	ready, skipped := resolveIncomeRows(rows, blocks)
+
+	out := cmd.OutOrStdout()
+	for _, s := range skipped {
+		fmt.Fprintf(out, "  SKIP %s\n", s)
+	}
+	if applyIncomeDryRun {
+		for _, r := range ready {
+			entry, err := incomeEntry(r)
+			if err != nil {
+				return fmt.Errorf("%q: %w", r.Item, err)
+			}
+			fmt.Fprintf(out, "  %s  %-30s R$ %s  → %s/%s\n", entry.Date, entry.ItemNote, utils.FormatBRValue(entry.Value), entry.IncomeCategory, entry.IncomeLabel)
+		}
+		fmt.Fprintf(out, "\nDry run: %d to append, %d skipped\n", len(ready), len(skipped))
+		return nil
+	}
+
+	log, err := openIncome(appCfg)
+	if err != nil {
+		return err
+	}
+	if log == nil {
+		return fmt.Errorf("income log path not configured\n  Hint: set income_log_path in config")
+	}
+	logged, err := feedback.LoadIncomeIDs(appCfg.IncomeLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	run := startRun(appCfg, "apply-income", args[0])
+	defer func() { finishRun(out, appCfg, run, err) }()
+	log = tagIncome(log, run.ID)
+
+	var appended, already int
+	for _, r := range ready {
+		ok, err := appendIncomeRow(log, logged, r)
+		if err != nil {
+			return fmt.Errorf("%q: %w", r.Item, err)
+		}
+		if !ok {
+			fmt.Fprintf(out, "  SKIP %q: already in the income log\n", r.Item)
+			already++
+			continue
+		}
+		appended++
+	}
+
+	fmt.Fprintf(out, "\nIncome lines appended: %d\n", appended)
+	if already > 0 {
+		fmt.Fprintf(out, "Already logged:        %d\n", already)
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(out, "Skipped:               %d\n", len(skipped))
+	}
+	return nil
+}
+
+// readIncomeReviewCSV reads the rows of an income-review.csv (see
+// writeIncomeReviewCSV) as credit rows, Category holding the block column and
+// Subcategory the line column.
+func readIncomeReviewCSV(path string) ([]classifiedRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"item", "date", "value", "block", "line"} {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i := col[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []classifiedRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, classifiedRow{
+			Item:        field(record, "item"),
+			Date:        field(record, "date"),
+			RawValue:    field(record, "value"),
+			Category:    field(record, "block"),
+			Subcategory: field(record, "line"),
+			Income:      true,
+		})
+	}
+	return rows, nil
+}
+
+// resolveIncomeRows files each row under the revenue leaf its block and line
+// name. Rows with no line or an unknown leaf are returned in skipped, one
+// reason each.
+func resolveIncomeRows(rows []classifiedRow, blocks []taxonomy.RevenueBlock) (ready []classifiedRow, skipped []string) {
+	for _, r := range rows {
+		if r.Subcategory == "" {
+			skipped = append(skipped, fmt.Sprintf("%q: no income line", r.Item))
+			continue
+		}
+		spec := r.Subcategory
+		if r.Category != "" && !strings.Contains(spec, "/") {
+			spec = r.Category + "/" + spec
+		}
+		leaf, err := taxonomy.ResolveIncomeLeaf(blocks, spec)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%q: %v", r.Item, err))
+			continue
+		}
+		r.Category, r.Subcategory = leaf.Block, leaf.Label
+		ready = append(ready, r)
+	}
+	return ready, skipped
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/store"
+	taxonomy "expense-reporter/internal/taxonomy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyIncome_ReviewQueue applies a reviewed income-review.csv: the rows
+// given a known line reach the income log, the rest are skipped with a reason.
+func TestApplyIncome_ReviewQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "income-review.csv")
+	csv := "item;date;value;block;line;confidence;auto_inserted\n" +
+		"TED recebida;06/03/2026;+200,00;;;0.0000;false\n" +
+		"PIX ACME folha;05/03/2026;+9500,00;;salário;0.0000;false\n" +
+		"INSS março;05/03/2026;-908,85;Salário;INSS;0.0000;false\n" +
+		"Aluguel;10/03/2026;+1500,00;;Aluguel;0.0000;false\n"
+	require.NoError(t, os.WriteFile(path, []byte(csv), 0o644))
+
+	rows, err := readIncomeReviewCSV(path)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	blocks := []taxonomy.RevenueBlock{
+		{Block: "Salário", Label: "Salário"},
+		{Block: "Salário", Label: "INSS"},
+	}
+	ready, skipped := resolveIncomeRows(rows, blocks)
+	require.Len(t, ready, 2)
+	assert.Len(t, skipped, 2, "the blank TED and the unknown Aluguel line")
+	assert.Contains(t, skipped[0], "no income line")
+	assert.Contains(t, skipped[1], "Aluguel")
+
+	log := store.NewJSONL[feedback.IncomeEntry](filepath.Join(t.TempDir(), "income_log.jsonl"))
+	logged := map[string]bool{}
+	for _, r := range ready {
+		appended, err := appendIncomeRow(log, logged, r)
+		require.NoError(t, err)
+		assert.True(t, appended)
+	}
+	entries, err := log.Query(store.Query{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Salário", entries[0].IncomeLabel, "the line resolves to its taxonomy spelling")
+	assert.Equal(t, 9500.0, entries[0].Value)
+	assert.Equal(t, "INSS", entries[1].IncomeLabel)
+	assert.Equal(t, -908.85, entries[1].Value, "a leading - is a deduction")
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/apply"
+	"expense-reporter/internal/classifier"
+	internalconfig "expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func splitReviewedEntry() apply.ReviewedEntry {
+	return apply.ReviewedEntry{
+		ID:     feedback.GenerateID("Carrefour", "03/01", 150.00),
+		Item:   "Carrefour",
+		Date:   "03/01",
+		Value:  150.00,
+		Action: apply.ActionSplit,
+		Split: []apply.SplitPart{
+			{Type: "Variáveis", Category: "Alimentação", Subcategory: "Supermercado", Value: 120.00},
+			{Type: "Variáveis", Category: "Casa", Subcategory: "Limpeza", Value: 30.00},
+		},
+	}
+}
+
+func readJSONLines[T any](t *testing.T, path string) []T {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var out []T
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var v T
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &v))
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestHandleSplitEntry_ExpandsParts(t *testing.T) {
+	classifPath := filepath.Join(t.TempDir(), "classifications.jsonl")
+	entry := splitReviewedEntry()
+
+	var newRows []apply.ReviewedEntry
+	require.NoError(t, handleSplitEntry(entry, store.NewJSONL[feedback.Entry](classifPath), &newRows))
+
+	require.Len(t, newRows, 2)
+	for i, row := range newRows {
+		assert.Equal(t, entry.ID, row.ID)
+		assert.Equal(t, entry.Split[i].Value, row.Value)
+		require.NotNil(t, row.Reviewed)
+		assert.Equal(t, entry.Split[i].Subcategory, row.Reviewed.Subcategory)
+	}
+}
+
+func TestHandleSplitEntry_AlreadyAppliedIsSkipped(t *testing.T) {
+	classifPath := filepath.Join(t.TempDir(), "classifications.jsonl")
+	entry := splitReviewedEntry()
+	require.NoError(t, feedback.Append(classifPath, feedback.Entry{ID: entry.ID, Status: feedback.StatusSplit}))
+
+	var newRows []apply.ReviewedEntry
+	require.NoError(t, handleSplitEntry(entry, store.NewJSONL[feedback.Entry](classifPath), &newRows))
+	assert.Empty(t, newRows)
+}
+
+func TestWriteFeedbackForNewRows_SplitLoggedOnce(t *testing.T) {
+	dir := t.TempDir()
+	classifPath := filepath.Join(dir, "classifications.jsonl")
+	logPath := filepath.Join(dir, "expenses_log.jsonl")
+	entry := splitReviewedEntry()
+
+	var newRows []apply.ReviewedEntry
+	require.NoError(t, handleSplitEntry(entry, store.NewJSONL[feedback.Entry](classifPath), &newRows))
+	values := []brlValue{{value: 120.00}, {value: 30.00}}
+
+	confirmed, corrected, err := writeFeedbackForNewRows(newRows, values, []int{0, 1}, store.NewJSONL[feedback.Entry](classifPath), store.NewJSONL[feedback.ExpenseEntry](logPath))
+	require.NoError(t, err)
+	assert.Equal(t, 0, confirmed)
+	assert.Equal(t, 1, corrected, "one reviewed line, however many parts")
+
+	fb := readJSONLines[feedback.Entry](t, classifPath)
+	require.Len(t, fb, 1, "the split is recorded once, not per part")
+	assert.Equal(t, entry.ID, fb[0].ID)
+	assert.Equal(t, feedback.StatusSplit, fb[0].Status)
+	assert.Equal(t, "Supermercado=120,00|Limpeza=30,00", fb[0].ActualSubcategory)
+
+	logged := readJSONLines[feedback.ExpenseEntry](t, logPath)
+	require.Len(t, logged, 2)
+	for _, e := range logged {
+		assert.Equal(t, entry.ID, e.ParentID)
+		assert.Equal(t, "Variáveis", e.Type)
+	}
+	assert.NotEqual(t, logged[0].ID, logged[1].ID)
+}
+
+func TestHandleActiveEntry_FindsLegacyID(t *testing.T) {
+	log := store.NewJSONL[feedback.Entry](filepath.Join(t.TempDir(), "classifications.jsonl"))
+	prior := feedback.NewConfirmedEntry("Padaria", "06/03", 12, classifier.Result{Subcategory: "Padaria"}, "review")
+	prior.ID = feedback.LegacyID("Padaria", "06/03", 12)
+	require.NoError(t, log.Append(prior))
+
+	entry := apply.ReviewedEntry{ID: feedback.GenerateID("Padaria", "06/03", 12), Item: "Padaria", Date: "06/03", Value: 12, Action: apply.ActionConfirmed}
+	var newRows, corrections []apply.ReviewedEntry
+	require.NoError(t, handleActiveEntry(entry, log, &newRows, &corrections))
+	assert.Empty(t, newRows, "a row applied before the ID change is not inserted again")
+
+	// The same lookup once --year has qualified the DD/MM date.
+	entries := []apply.ReviewedEntry{entry}
+	qualifyReviewedDates(entries, 2024)
+	require.NoError(t, handleActiveEntry(entries[0], log, &newRows, &corrections))
+	assert.Empty(t, newRows)
+}
+
+// TestQualifyReviewedDates checks that a legacy DD/MM review applied with
+// --year 2024 is logged and hashed under 2024, the year of its workbook row.
+func TestQualifyReviewedDates(t *testing.T) {
+	entries := []apply.ReviewedEntry{
+		{ID: feedback.GenerateID("Padaria", "06/03", 12), Item: "Padaria", Date: "06/03", Value: 12},
+		{ID: feedback.GenerateID("Uber", "07/03/2025", 30), Item: "Uber", Date: "07/03/2025", Value: 30},
+		{ID: "custom", Item: "Feira", Date: "08/03", Value: 50},
+	}
+	qualifyReviewedDates(entries, 2024)
+
+	assert.Equal(t, "06/03/2024", entries[0].Date)
+	assert.Equal(t, feedback.GenerateID("Padaria", "06/03/2024", 12), entries[0].ID)
+	assert.Equal(t, "07/03/2025", entries[1].Date, "a dated entry keeps its own year")
+	assert.Equal(t, feedback.GenerateID("Uber", "07/03/2025", 30), entries[1].ID)
+	assert.Equal(t, "08/03/2024", entries[2].Date)
+	assert.Equal(t, "custom", entries[2].ID, "an ID not hashed from the date is kept")
+
+	exp, _ := buildFeedbackEntry(apply.ReviewedEntry{Item: entries[0].Item, Date: entries[0].Date, Value: 12, Action: apply.ActionConfirmed,
+		Reviewed: &apply.ReviewedLocation{Subcategory: "Padaria"}})
+	assert.Equal(t, entries[0].ID, exp.ID)
+	assert.Equal(t, "06/03/2024", exp.Date)
+}
+
+func TestResolveReviewedAccounts(t *testing.T) {
+	cfg := &internalconfig.Config{Cards: map[string]internalconfig.CardConfig{"nubank": {}}, Accounts: []string{"pix"}}
+	entries := []apply.ReviewedEntry{{Item: "Padaria"}, {Item: "Uber", Account: "pix"}}
+
+	require.NoError(t, resolveReviewedAccounts(entries, cfg, "", "nubank"))
+	assert.Equal(t, "nubank", entries[0].Account, "an entry without an account takes --card")
+	assert.Equal(t, "pix", entries[1].Account, "the review's account wins")
+
+	entries = []apply.ReviewedEntry{{Item: "Cinema", Account: "itau"}}
+	assert.ErrorContains(t, resolveReviewedAccounts(entries, cfg, "", ""), `account "itau" is not configured`)
+}
+
+func TestTagReviewedRows(t *testing.T) {
+	cfg := &internalconfig.Config{TagRules: tags.Rules{
+		{Tags: []string{"viagem"}, Category: "Viagens"},
+		{Tags: []string{"carro"}, Subcategory: "Combustível"},
+	}}
+	entries := []apply.ReviewedEntry{{Item: "Pousada", Tags: []string{" férias ", "Férias"}}}
+	require.NoError(t, resolveReviewedTags(entries, cfg))
+	assert.Equal(t, []string{"férias"}, entries[0].Tags)
+
+	entries[0].Date = "12/01"
+	rows := []apply.ReviewedEntry{entries[0], entries[0]}
+	rows[0].Reviewed = &apply.ReviewedLocation{Type: "Extras", Category: "Viagens", Subcategory: "Hospedagem"}
+	rows[1].Reviewed = &apply.ReviewedLocation{Type: "Variáveis", Category: "Transporte", Subcategory: "Combustível"}
+	tagReviewedRows(cfg, rows, 2026)
+	assert.Equal(t, []string{"férias", "viagem"}, rows[0].Tags)
+	assert.Equal(t, []string{"férias", "carro"}, rows[1].Tags, "each split part matches the rules on its own path")
+
+	cfg.TagRules = tags.Rules{{Tags: []string{"x"}}}
+	assert.ErrorContains(t, resolveReviewedTags(entries, cfg), "tag rule 1")
+}
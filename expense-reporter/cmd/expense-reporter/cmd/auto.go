@@ -6,6 +6,7 @@ import (
 	"expense-reporter/internal/classifier"
 	"expense-reporter/internal/config"
 	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
 	"expense-reporter/pkg/utils"
 	"fmt"
 	"io"
@@ -22,6 +23,7 @@ var (
 	autoModel   string
 	autoDataDir string
 	autoConfirm bool
+	autoAccount string
 )
 
 var autoCmd = &cobra.Command{
@@ -32,7 +34,8 @@ If confidence is below the threshold, prints candidates for manual review.
 
 Examples:
   expense-reporter auto "Uber Centro" 35.50 15/04
-  expense-reporter auto "Diarista Letícia" 160,00 05/01 --confirm`,
+  expense-reporter auto "Diarista Letícia" 160,00 05/01 --confirm
+  expense-reporter auto "Padaria" 12,00 18/04 --account pix`,
 	Args: cobra.ExactArgs(3),
 	RunE: runAuto,
 }
@@ -42,15 +45,17 @@ func init() {
 	autoCmd.Flags().StringVar(&autoModel, "model", "my-classifier-q3", "Ollama model to use")
 	autoCmd.Flags().StringVar(&autoDataDir, "data-dir", "data/classification", "Path to classification data directory")
 	autoCmd.Flags().BoolVar(&autoConfirm, "confirm", false, "Always ask for confirmation before inserting")
+	autoCmd.Flags().StringVar(&autoAccount, "account", "", "Card or account the expense was paid with")
 }
 
 func runAuto(cmd *cobra.Command, args []string) error {
 	item := args[0]
 
-	value, installmentCount, err := utils.ParseCurrencyWithInstallments(args[1])
+	sched, err := utils.ParseInstallments(args[1])
 	if err != nil {
 		return fmt.Errorf("invalid value %q: expected a number (e.g. 35.50 or 35,50) or with installments (e.g. 35,50/3)", args[1])
 	}
+	value := sched.Values[0]
 
 	date := args[2]
 
@@ -63,6 +68,13 @@ func runAuto(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
+	account, err := resolveAccount(appCfg, autoAccount, "")
+	if err != nil {
+		return err
+	}
+	if _, err := resolveTags(appCfg, nil); err != nil {
+		return err
+	}
 
 	sheets, err := loadTaxonomyTree(appCfg)
 	if err != nil {
@@ -132,7 +144,7 @@ func runAuto(cmd *cobra.Command, args []string) error {
 				return nil
 			}
 		}
-		return appendExpense(item, date, parsedDate, value, installmentCount, top, appCfg)
+		return appendExpense(item, date, parsedDate, sched, top, account, appCfg)
 	}
 
 	printCandidates(item, value, date, results)
@@ -145,32 +157,40 @@ func runAuto(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func appendExpense(item, date string, parsedDate time.Time, value float64, installmentCount int, result classifier.Result, appCfg *config.Config) error {
+func appendExpense(item, date string, parsedDate time.Time, sched utils.InstallmentSchedule, result classifier.Result, account string, appCfg *config.Config) error {
+	value := sched.Values[0]
 	// T-13: the type comes straight from the predicted full path — no post-hoc
 	// (category, subcategory) lookup that could fail or disagree.
 	logPath := appCfg.ExpensesLogFilePath()
 	if logPath == "" {
 		fmt.Fprintf(os.Stderr, "⚠  expense log: no path configured\n")
 	} else {
-		if err := appender.ExpandAndAppend(logPath, item, parsedDate, value, installmentCount, result.Type, result.Category, result.Subcategory); err != nil {
+		meta := entryMeta{Account: account, Tags: expenseTags(appCfg, nil, tags.Expense{
+			Item: item, Date: parsedDate, Type: result.Type, Category: result.Category, Subcategory: result.Subcategory, Account: account,
+		})}
+		opts := recordPlan(appCfg.PlansFilePath(), item, date, value, parsedDate, sched, result.Type, result.Category, result.Subcategory, meta)
+		opts = append(opts, meta.options()...)
+		if err := appender.AppendSchedule(logPath, item, parsedDate, sched.Values, result.Type, result.Category, result.Subcategory, opts...); err != nil {
 			fmt.Fprintf(os.Stderr, "⚠  expense log append failed: %v\n", err)
 		}
 	}
 
 	fmt.Printf("✓ Appended: %s → %s (%s) — %.0f%% confidence\n",
 		item, result.Subcategory, result.Category, result.Confidence*100)
-	logConfirmedFeedback(appCfg, item, date, value, result, autoModel)
+	logConfirmedFeedback(appCfg, item, date, value, result, autoModel, "")
 	return nil
 }
 
-// logConfirmedFeedback appends a confirmed entry to classifications.jsonl.
+// logConfirmedFeedback appends a confirmed entry to classifications.jsonl,
+// tagged with runID when a journaled run writes it.
 // Non-fatal: logs a warning to stderr if the write fails.
-func logConfirmedFeedback(appCfg *config.Config, item, date string, value float64, result classifier.Result, model string) {
+func logConfirmedFeedback(appCfg *config.Config, item, date string, value float64, result classifier.Result, model, runID string) {
 	path := appCfg.ClassificationsFilePath()
 	if path == "" {
 		return
 	}
 	entry := feedback.NewConfirmedEntry(item, date, value, result, model)
+	entry.RunID = runID
 	if err := feedback.Append(path, entry); err != nil {
 		fmt.Fprintf(os.Stderr, "⚠  feedback log: %v\n", err)
 	}
@@ -11,6 +11,9 @@ import (
 	"expense-reporter/internal/batch"
 	"expense-reporter/internal/classifier"
 	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
 	"expense-reporter/internal/taxonomy"
 	"expense-reporter/pkg/utils"
 
@@ -25,6 +28,8 @@ var (
 	batchAutoTopN      int
 	batchAutoDryRun    bool
 	batchAutoOutputDir string
+	batchAutoCard      string
+	batchAutoAccount   string
 )
 
 var batchAutoCmd = &cobra.Command{
@@ -33,16 +38,36 @@ var batchAutoCmd = &cobra.Command{
 	Long: `Read a 3-field semicolon-delimited CSV (item;DD/MM;value), classify each row,
 and auto-insert rows that exceed the confidence threshold into the workbook.
 
+An optional 4th field holds split notation (sub=value|sub=value): the row skips
+classification and is logged as one entry per part, sharing a parent ID. An
+optional 5th field holds comma-separated tags (item;DD/MM;value;;viagem,floripa);
+the tag rules in config add theirs as rows are logged.
+
+A value prefixed with + is a credit (salary, PIX received, dividends): the row is
+classified against the taxonomy's incomeCategories from past income_log.jsonl
+lines, not by the model, and appended to the income log when confident. A
+credit already in the income log is skipped.
+
 Output files are written to --output-dir (default: same directory as input):
   classified.csv  — all rows with classification results
   review.csv      — rows not auto-inserted (low confidence or excluded)
   rollover.csv    — installment rows whose later months fall into next year (if any)
+  income-review.csv — credit rows not appended to the income log (if any);
+                      fill in their lines and load them with apply-income
 
 Use --dry-run to skip workbook insertion and only produce the CSV outputs.
 
+A value may carry a currency prefix (e.g. "USD 20,00"); it is converted to BRL
+with the local rate table, plus the IOF of the card named by --card.
+
+--account records the card or account that paid for every row of the file
+(default: --card); it is written to the log and to the account column of the
+output CSVs, which review and apply carry through.
+
 Examples:
   expense-reporter batch-auto expenses.csv
-  expense-reporter batch-auto expenses.csv --dry-run --output-dir /tmp/out`,
+  expense-reporter batch-auto expenses.csv --dry-run --output-dir /tmp/out
+  expense-reporter batch-auto fatura-nubank.csv --card nubank`,
 	Args: cobra.ExactArgs(1),
 	RunE: runBatchAuto,
 }
@@ -56,6 +81,8 @@ func init() {
 	batchAutoCmd.Flags().IntVar(&batchAutoTopN, "top", 3, "Number of classification candidates")
 	batchAutoCmd.Flags().BoolVar(&batchAutoDryRun, "dry-run", false, "Classify and write CSVs without inserting into workbook")
 	batchAutoCmd.Flags().StringVar(&batchAutoOutputDir, "output-dir", "", "Directory for output CSV files (default: same as input file)")
+	batchAutoCmd.Flags().StringVar(&batchAutoCard, "card", "", "Card the batch was paid with (applies its configured IOF to foreign-currency values)")
+	batchAutoCmd.Flags().StringVar(&batchAutoAccount, "account", "", "Card or account the batch was paid with (default: --card)")
 }
 
 // classifiedRow holds the result of classifying a single input row.
@@ -67,11 +94,21 @@ type classifiedRow struct {
 	Category     string
 	Confidence   float64
 	AutoInserted bool
-	Type         string // resolved expense type name (empty if not found or ambiguous)
+	Type         string   // resolved expense type name (empty if not found or ambiguous)
+	Account      string   // card or account that paid (--account, or the review's choice)
+	Tags         []string // the input's tags column; the tag rules' are added on append
 	Error        error
+
+	// Split holds the resolved parts of a split row (values in the input currency);
+	// Subcategory then carries the split notation.
+	Split []appender.SplitPart
+
+	// Income marks a credit row classified as revenue: Category holds the
+	// income block and Subcategory its line.
+	Income bool
 }
 
-func runBatchAuto(cmd *cobra.Command, args []string) error {
+func runBatchAuto(cmd *cobra.Command, args []string) (err error) {
 	csvPath := args[0]
 
 	outputDir, err := resolveOutputDir(csvPath, batchAutoOutputDir)
@@ -103,17 +140,46 @@ func runBatchAuto(cmd *cobra.Command, args []string) error {
 		if err := preflightLogPath(appCfg); err != nil {
 			return err
 		}
+		if hasCreditLines(lines) {
+			if err := preflightIncomeLogPath(appCfg); err != nil {
+				return err
+			}
+		}
+	}
+	income, err := loadIncomeDeps(appCfg)
+	if err != nil {
+		return err
+	}
+
+	// Resolved before classification so an unknown --card fails fast too.
+	conv, err := newForeignConverter(appCfg, batchAutoCard)
+	if err != nil {
+		return err
+	}
+	account, err := resolveAccount(appCfg, batchAutoAccount, batchAutoCard)
+	if err != nil {
+		return err
+	}
+	if _, err := resolveTags(appCfg, nil); err != nil {
+		return err
 	}
 
-	results := classifyLines(lines, sheets, appCfg, cfg, batchAutoThreshold)
+	results := classifyLines(lines, sheets, appCfg, cfg, income, batchAutoThreshold)
+	// One input file is one statement: every row was paid from the same account.
+	for i := range results {
+		results[i].Account = account
+	}
 
 	var appendErr error
 	if !batchAutoDryRun {
-		appendErr = appendClassified(results, appCfg, batchAutoModel)
+		run := startRun(appCfg, "batch-auto", csvPath)
+		defer func() { finishRun(os.Stdout, appCfg, run, err) }()
+		appendErr = appendClassified(results, appCfg, conv, batchAutoModel, run.ID)
 	}
 
 	classifiedPath := filepath.Join(outputDir, "classified.csv")
 	reviewPath := filepath.Join(outputDir, "review.csv")
+	incomeReviewPath := filepath.Join(outputDir, "income-review.csv")
 
 	// CSVs are written AFTER appendClassified so they reflect any rows it
 	// downgraded on append failure (a failed row lands in review.csv, not as a
@@ -124,8 +190,13 @@ func runBatchAuto(cmd *cobra.Command, args []string) error {
 	if err := writeReviewCSV(reviewPath, results); err != nil {
 		return fmt.Errorf("writing review.csv: %w", err)
 	}
+	if !hasIncomeRows(results) {
+		incomeReviewPath = ""
+	} else if err := writeIncomeReviewCSV(incomeReviewPath, results); err != nil {
+		return fmt.Errorf("writing income-review.csv: %w", err)
+	}
 
-	printBatchSummary(results, batchAutoDryRun, classifiedPath, reviewPath)
+	printBatchSummary(results, batchAutoDryRun, classifiedPath, reviewPath, incomeReviewPath)
 	if appendErr != nil {
 		return fmt.Errorf("log append failed (classification CSVs preserved at %s): %w", outputDir, appendErr)
 	}
@@ -165,7 +236,7 @@ func loadBatchAutoDeps() ([]taxonomy.ExpenseType, *config.Config, error) {
 	return sheets, appCfg, nil
 }
 
-func classifyLines(lines []string, sheets []taxonomy.ExpenseType, appCfg *config.Config, cfg classifier.Config, threshold float64) []classifiedRow {
+func classifyLines(lines []string, sheets []taxonomy.ExpenseType, appCfg *config.Config, cfg classifier.Config, income incomeDeps, threshold float64) []classifiedRow {
 	total := len(lines)
 	results := make([]classifiedRow, 0, total)
 
@@ -177,10 +248,19 @@ func classifyLines(lines []string, sheets []taxonomy.ExpenseType, appCfg *config
 			continue
 		}
 
+		if row.Split != "" {
+			results = append(results, splitRow(row, sheets, i, total))
+			continue
+		}
+		if row.Credit {
+			results = append(results, incomeRow(row, income, threshold, i, total))
+			continue
+		}
+
 		classResults, err := classifier.Classify(row.Item, row.Value, row.Date, sheets, cfg)
 		if err != nil || len(classResults) == 0 {
 			fmt.Fprintf(os.Stderr, "[%d/%d] REVIEW %q: classifier error: %v\n", i+1, total, row.Item, err)
-			results = append(results, classifiedRow{Item: row.Item, Date: row.Date, RawValue: row.RawValue, Error: err})
+			results = append(results, classifiedRow{Item: row.Item, Date: row.Date, RawValue: row.RawValue, Tags: row.Tags, Error: err})
 			continue
 		}
 
@@ -201,11 +281,34 @@ func classifyLines(lines []string, sheets []taxonomy.ExpenseType, appCfg *config
 			Confidence:   top.Confidence,
 			AutoInserted: autoInsert,
 			Type:         top.Type, // T-13: type comes from the predicted full path
+			Tags:         row.Tags,
 		})
 	}
 	return results
 }
 
+// splitRow resolves a row that came with split notation. No model call is made:
+// the user already named the subcategories, so the row is auto-inserted unless
+// the split is invalid or a part does not resolve unambiguously.
+func splitRow(row inputRow, sheets []taxonomy.ExpenseType, i, total int) classifiedRow {
+	parts, spec, err := resolveSplit(sheets, row.Split, row.Value*float64(row.InstallmentCount), row.InstallmentCount, "", false, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[%d/%d] SKIP  %q: split: %v\n", i+1, total, row.Item, err)
+		return classifiedRow{Item: row.Item, Date: row.Date, RawValue: row.RawValue, Tags: row.Tags, Error: err}
+	}
+	fmt.Printf("[%d/%d] SPLIT  %s → %d parts\n", i+1, total, row.Item, len(parts))
+	return classifiedRow{
+		Item:         row.Item,
+		Date:         row.Date,
+		RawValue:     row.RawValue,
+		Subcategory:  spec,
+		Confidence:   1,
+		AutoInserted: true,
+		Tags:         row.Tags,
+		Split:        parts,
+	}
+}
+
 // preflightLogPath fails fast when the expense log is unwritable, before the
 // batch spends ~12 s/row on the model. Because the log is now the only durable
 // persistence, an unwritable path must abort the run rather than silently lose
@@ -239,23 +342,50 @@ func verifyAppendable(path string) error {
 // the log is the only durable persistence, a per-row failure (value/date parse
 // or append error) downgrades that row in place — AutoInserted=false + Error set —
 // so the summary count stays honest, the row falls into review.csv, and the
-// command exits non-zero (the returned error is wrapped by the caller).
-func appendClassified(results []classifiedRow, appCfg *config.Config, model string) error {
+// command exits non-zero (the returned error is wrapped by the caller). Every
+// entry written is tagged with runID ("" when the run is not journaled).
+func appendClassified(results []classifiedRow, appCfg *config.Config, conv *foreignConverter, model, runID string) error {
 	logPath := appCfg.ExpensesLogFilePath()
+	var income store.Log[feedback.IncomeEntry]
+	var incomeLogged map[string]bool
+	if hasIncomeRows(results) {
+		log, err := openIncome(appCfg)
+		if err != nil {
+			return err
+		}
+		if incomeLogged, err = feedback.LoadIncomeIDs(appCfg.IncomeLogFilePath()); err != nil {
+			return err
+		}
+		income = tagIncome(log, runID)
+	}
 	var failCount int
 	for idx := range results {
 		r := results[idx]
 		if !r.AutoInserted || r.Error != nil {
 			continue
 		}
-		if err := appendOneRow(logPath, r); err != nil {
+		if r.Income {
+			appended, err := appendIncomeRow(income, incomeLogged, r)
+			if err != nil {
+				results[idx].AutoInserted = false
+				results[idx].Error = err
+				fmt.Fprintf(os.Stderr, "  APPEND ERROR %q: %v\n", r.Item, err)
+				failCount++
+			} else if !appended {
+				fmt.Printf("  SKIP %q: already in the income log\n", r.Item)
+			}
+			continue
+		}
+		tagRow(appCfg, &results[idx])
+		r = results[idx]
+		if err := appendOneRow(logPath, appCfg.PlansFilePath(), conv, r, runID); err != nil {
 			results[idx].AutoInserted = false
 			results[idx].Error = err
 			fmt.Fprintf(os.Stderr, "  APPEND ERROR %q: %v\n", r.Item, err)
 			failCount++
 			continue
 		}
-		logConfirmedFeedbackForRow(appCfg, r, model)
+		logConfirmedFeedbackForRow(appCfg, r, model, runID)
 	}
 	if failCount > 0 {
 		return fmt.Errorf("%d row(s) failed to append to the expense log", failCount)
@@ -264,40 +394,87 @@ func appendClassified(results []classifiedRow, appCfg *config.Config, model stri
 }
 
 // appendOneRow expands installments and appends a single classified row to the
-// expense log. Returns an error if the value/date cannot be parsed or the append
-// fails — any of which means the row was not persisted.
-func appendOneRow(logPath string, r classifiedRow) error {
-	perInstallment, installmentCount, err := utils.ParseCurrencyWithInstallments(r.RawValue)
+// expense log, converting a foreign-currency value to BRL first and recording an
+// installment plan in plansPath when the row expands. Returns an error
+// if the value/date cannot be parsed, no rate is available, or the append fails —
+// any of which means the row was not persisted.
+func appendOneRow(logPath, plansPath string, conv *foreignConverter, r classifiedRow, runID string) error {
+	currency, valueStr := utils.SplitCurrencyCode(r.RawValue)
+	sched, err := utils.ParseInstallments(valueStr)
 	if err != nil {
 		return fmt.Errorf("parsing value %q: %w", r.RawValue, err)
 	}
+	perInstallment := sched.Values[0]
 	parsedDate, err := utils.ParseDateFlexible(r.Date)
 	if err != nil {
 		return fmt.Errorf("parsing date %q: %w", r.Date, err)
 	}
-	return appender.ExpandAndAppend(logPath, r.Item, parsedDate, perInstallment, installmentCount, r.Type, r.Category, r.Subcategory)
+	if len(r.Split) > 0 {
+		parts := append([]appender.SplitPart(nil), r.Split...)
+		if err := convertSplit(conv, currency, parsedDate, parts); err != nil {
+			return err
+		}
+		parentID := feedback.GenerateID(r.Item, r.Date, perInstallment)
+		meta := entryMeta{RunID: runID, Account: r.Account} // parts carry their own tags
+		return appender.AppendSplit(logPath, r.Item, parsedDate, parentID, parts, meta.options()...)
+	}
+	brlSched, foreign, err := conv.convertSchedule(currency, parsedDate, sched)
+	if err != nil {
+		return err
+	}
+	meta := entryMeta{RunID: runID, Account: r.Account, Tags: r.Tags}
+	opts := recordPlan(plansPath, r.Item, r.Date, perInstallment, parsedDate, brlSched, r.Type, r.Category, r.Subcategory, meta)
+	opts = append(opts, appender.WithForeign(foreign))
+	return appender.AppendSchedule(logPath, r.Item, parsedDate, brlSched.Values, r.Type, r.Category, r.Subcategory,
+		append(opts, meta.options()...)...)
+}
+
+// tagRow adds the tags of the rules a row matches to those of its tags column,
+// so classified.csv shows what the log records. A split row's parts are
+// tagged one by one, each by its own path. A row whose date does not parse is
+// left alone: appendOneRow reports it.
+func tagRow(appCfg *config.Config, r *classifiedRow) {
+	date, err := utils.ParseDateFlexible(r.Date)
+	if err != nil {
+		return
+	}
+	if len(r.Split) > 0 {
+		tagSplitParts(appCfg, r.Tags, r.Item, date, r.Account, r.Split)
+		return
+	}
+	r.Tags = expenseTags(appCfg, r.Tags, tags.Expense{
+		Item: r.Item, Date: date, Type: r.Type, Category: r.Category, Subcategory: r.Subcategory, Account: r.Account,
+	})
 }
 
 // logConfirmedFeedbackForRow records the confirmed classification to
 // classifications.jsonl for a successfully appended row. Secondary to the expense
 // log: a failure here is non-fatal (logConfirmedFeedback warns internally).
-func logConfirmedFeedbackForRow(appCfg *config.Config, r classifiedRow, model string) {
-	perInstallment, _, err := utils.ParseCurrencyWithInstallments(r.RawValue)
+func logConfirmedFeedbackForRow(appCfg *config.Config, r classifiedRow, model, runID string) {
+	_, valueStr := utils.SplitCurrencyCode(r.RawValue)
+	perInstallment, _, err := utils.ParseCurrencyWithInstallments(valueStr)
 	if err != nil {
 		return
 	}
+	if len(r.Split) > 0 {
+		logSplitFeedback(appCfg, r.Item, r.Date, perInstallment, classifier.Result{}, "", r.Subcategory, runID)
+		return
+	}
 	predicted := classifier.Result{
 		Type:        r.Type,
 		Category:    r.Category,
 		Subcategory: r.Subcategory,
 		Confidence:  r.Confidence,
 	}
-	logConfirmedFeedback(appCfg, r.Item, r.Date, perInstallment, predicted, model)
+	logConfirmedFeedback(appCfg, r.Item, r.Date, perInstallment, predicted, model, runID)
 }
 
-func printBatchSummary(results []classifiedRow, dryRun bool, classifiedPath, reviewPath string) {
-	autoCount, reviewCount, errorCount := 0, 0, 0
+func printBatchSummary(results []classifiedRow, dryRun bool, classifiedPath, reviewPath, incomeReviewPath string) {
+	autoCount, reviewCount, errorCount, incomeCount := 0, 0, 0, 0
 	for _, r := range results {
+		if r.Income {
+			incomeCount++
+		}
 		switch {
 		case r.Error != nil:
 			errorCount++
@@ -316,40 +493,78 @@ func printBatchSummary(results []classifiedRow, dryRun bool, classifiedPath, rev
 	fmt.Printf(appendLine, autoCount)
 	fmt.Printf("  For review    : %d\n", reviewCount)
 	fmt.Printf("  Errors        : %d\n", errorCount)
+	if incomeCount > 0 {
+		fmt.Printf("  Income lines  : %d\n", incomeCount)
+	}
 	fmt.Printf("  classified.csv: %s\n", classifiedPath)
 	fmt.Printf("  review.csv    : %s\n", reviewPath)
+	if incomeReviewPath != "" {
+		fmt.Printf("  income-review.csv: %s\n", incomeReviewPath)
+		fmt.Println("    Hint: fill in its line column, then run apply-income on it")
+	}
 }
 
 // inputRow is a parsed 3-field line.
 type inputRow struct {
-	Item     string
-	Date     string
-	Value    float64 // per-installment value, used for classifier display
-	RawValue string  // original string, preserves installment notation (e.g. "99,90/3")
+	Item             string
+	Date             string
+	Value            float64 // per-installment value in its original currency, used for classifier display
+	InstallmentCount int
+	RawValue         string   // original string, preserves currency and installment notation (e.g. "USD 99,90/3")
+	Split            string   // optional 4th field: split notation (sub=value|sub=value)
+	Tags             []string // optional 5th field: comma-separated tags
+	Credit           bool     // value prefixed with +: an income line
 }
 
-// parse3FieldLine splits "item;DD/MM;value" and parses currency.
-// value may include installment notation (e.g. "99,90/3"); RawValue preserves it.
+// parse3FieldLine splits "item;DD/MM;value[;split[;tags]]" and parses currency.
+// value may include a currency prefix and installment notation (e.g. "USD 99,90/3");
+// RawValue preserves both. The optional 4th field must be split notation (or
+// empty) and the optional 5th a comma-separated tag list. A value prefixed with +
+// is a credit, which takes neither.
 func parse3FieldLine(line string) (inputRow, error) {
-	parts := strings.SplitN(line, ";", 3)
-	if len(parts) != 3 {
+	parts := strings.SplitN(line, ";", 5)
+	if len(parts) < 3 {
 		return inputRow{}, fmt.Errorf("expected 3 fields (item;DD/MM;value), got %d", len(parts))
 	}
+	var split string
+	if len(parts) >= 4 {
+		split = strings.TrimSpace(parts[3])
+		if split != "" && !utils.IsSplitSpec(split) {
+			return inputRow{}, fmt.Errorf("4th field %q is not split notation (sub=value|sub=value)", split)
+		}
+	}
+	var rowTags []string
+	if len(parts) == 5 {
+		var err error
+		if rowTags, err = tags.Parse(parts[4]); err != nil {
+			return inputRow{}, fmt.Errorf("5th field: %w", err)
+		}
+	}
 	item := strings.TrimSpace(parts[0])
-	date := strings.TrimSpace(parts[1])
+	// A DD/MM date gets its year here, so classified.csv, the review queue and
+	// the logs all carry the same one (see feedback.QualifyDate).
+	date := feedback.QualifyDate(parts[1])
 	valueStr := strings.TrimSpace(parts[2])
 	if item == "" {
 		return inputRow{}, fmt.Errorf("empty item field")
 	}
-	perInstallment, _, err := utils.ParseCurrencyWithInstallments(valueStr)
+	if credit, ok := strings.CutPrefix(valueStr, "+"); ok {
+		if len(rowTags) > 0 {
+			return inputRow{}, fmt.Errorf("a credit line cannot be tagged")
+		}
+		return parseCreditLine(item, date, credit, split)
+	}
+	_, amountStr := utils.SplitCurrencyCode(valueStr)
+	perInstallment, count, err := utils.ParseCurrencyWithInstallments(amountStr)
 	if err != nil {
 		return inputRow{}, fmt.Errorf("parsing value %q: %w", valueStr, err)
 	}
-	return inputRow{Item: item, Date: date, Value: perInstallment, RawValue: valueStr}, nil
+	return inputRow{Item: item, Date: date, Value: perInstallment, InstallmentCount: count, RawValue: valueStr, Split: split, Tags: rowTags}, nil
 }
 
-// writeClassifiedCSV writes all classified rows to path.
-// Format: item;date;value;subcategory;category;confidence;auto_inserted;type
+// writeClassifiedCSV writes all classified expense rows to path (income rows go
+// to income-review.csv).
+// Format: item;date;value;subcategory;category;confidence;auto_inserted;type;account;tags
 func writeClassifiedCSV(path string, rows []classifiedRow) error {
 	f, err := os.Create(path)
 	if err != nil {
@@ -359,10 +574,13 @@ func writeClassifiedCSV(path string, rows []classifiedRow) error {
 
 	w := csv.NewWriter(f)
 	w.Comma = ';'
-	if err := w.Write([]string{"item", "date", "value", "subcategory", "category", "confidence", "auto_inserted", "type"}); err != nil {
+	if err := w.Write([]string{"item", "date", "value", "subcategory", "category", "confidence", "auto_inserted", "type", "account", "tags"}); err != nil {
 		return err
 	}
 	for _, r := range rows {
+		if r.Income {
+			continue
+		}
 		w.Write([]string{ //nolint:errcheck
 			r.Item,
 			r.Date,
@@ -372,14 +590,16 @@ func writeClassifiedCSV(path string, rows []classifiedRow) error {
 			fmt.Sprintf("%.4f", r.Confidence),
 			fmt.Sprintf("%v", r.AutoInserted),
 			r.Type,
+			r.Account,
+			tags.Join(r.Tags),
 		})
 	}
 	w.Flush()
 	return w.Error()
 }
 
-// writeReviewCSV writes only rows where auto_inserted == false.
-// Format: item;date;value;subcategory;category;confidence;auto_inserted;type
+// writeReviewCSV writes only expense rows where auto_inserted == false.
+// Format: item;date;value;subcategory;category;confidence;auto_inserted;type;account;tags
 func writeReviewCSV(path string, rows []classifiedRow) error {
 	f, err := os.Create(path)
 	if err != nil {
@@ -389,11 +609,11 @@ func writeReviewCSV(path string, rows []classifiedRow) error {
 
 	w := csv.NewWriter(f)
 	w.Comma = ';'
-	if err := w.Write([]string{"item", "date", "value", "subcategory", "category", "confidence", "auto_inserted", "type"}); err != nil {
+	if err := w.Write([]string{"item", "date", "value", "subcategory", "category", "confidence", "auto_inserted", "type", "account", "tags"}); err != nil {
 		return err
 	}
 	for _, r := range rows {
-		if r.AutoInserted {
+		if r.AutoInserted || r.Income {
 			continue
 		}
 		w.Write([]string{ //nolint:errcheck
@@ -405,9 +625,10 @@ func writeReviewCSV(path string, rows []classifiedRow) error {
 			fmt.Sprintf("%.4f", r.Confidence),
 			"false",
 			r.Type,
+			r.Account,
+			tags.Join(r.Tags),
 		})
 	}
 	w.Flush()
 	return w.Error()
 }
-
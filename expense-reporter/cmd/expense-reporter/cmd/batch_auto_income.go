@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+
+	"expense-reporter/internal/classifier"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/store"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+// incomeDeps holds what credit rows are classified against: the taxonomy's
+// revenue leaves and the past lines of the income log.
+type incomeDeps struct {
+	blocks  []taxonomy.RevenueBlock
+	history []classifier.IncomeExample
+}
+
+// loadIncomeDeps loads the revenue leaves and, when income_log_path is set, the
+// income log history.
+func loadIncomeDeps(appCfg *config.Config) (incomeDeps, error) {
+	blocks, err := loadIncomeBlocks(appCfg)
+	if err != nil {
+		return incomeDeps{}, err
+	}
+	var history []classifier.IncomeExample
+	if path := appCfg.IncomeLogFilePath(); path != "" {
+		if history, err = classifier.LoadIncomeExamples(path); err != nil {
+			return incomeDeps{}, err
+		}
+	}
+	return incomeDeps{blocks: blocks, history: history}, nil
+}
+
+// parseCreditLine builds the inputRow of a credit: a plain BRL amount (the
+// leading + already stripped), no installments, currency or split.
+func parseCreditLine(item, date, amountStr, split string) (inputRow, error) {
+	if split != "" {
+		return inputRow{}, fmt.Errorf("a credit line cannot be split")
+	}
+	code, amountStr := utils.SplitCurrencyCode(amountStr)
+	if code != "" {
+		return inputRow{}, fmt.Errorf("credit value %q: income is recorded in BRL only", code+" "+amountStr)
+	}
+	if strings.Contains(amountStr, "/") {
+		return inputRow{}, fmt.Errorf("credit value %q cannot have installments", amountStr)
+	}
+	value, err := utils.ParseCurrency(amountStr)
+	if err != nil {
+		return inputRow{}, fmt.Errorf("parsing value %q: %w", "+"+amountStr, err)
+	}
+	return inputRow{Item: item, Date: date, Value: value, InstallmentCount: 1, RawValue: "+" + amountStr, Credit: true}, nil
+}
+
+// hasCreditLines reports whether any parseable line is a credit.
+func hasCreditLines(lines []string) bool {
+	for _, line := range lines {
+		if row, err := parse3FieldLine(line); err == nil && row.Credit {
+			return true
+		}
+	}
+	return false
+}
+
+// preflightIncomeLogPath checks the income log can take the credit rows.
+func preflightIncomeLogPath(appCfg *config.Config) error {
+	logPath := appCfg.IncomeLogFilePath()
+	if logPath == "" {
+		return fmt.Errorf("income log path not configured\n  Hint: set income_log_path in config, or use --dry-run")
+	}
+	if err := verifyAppendable(logPath); err != nil {
+		return fmt.Errorf("income log not writable: %w\n  Hint: ensure %s is writable, or use --dry-run", err, logPath)
+	}
+	return nil
+}
+
+// incomeRow classifies a credit row against the revenue leaves. The row is
+// auto-inserted when the best leaf reaches threshold.
+func incomeRow(row inputRow, income incomeDeps, threshold float64, i, total int) classifiedRow {
+	results := classifier.ClassifyIncome(row.Item, income.blocks, income.history)
+	if len(results) == 0 {
+		fmt.Printf("[%d/%d] INCOME REVIEW %s → no matching income line\n", i+1, total, row.Item)
+		return classifiedRow{Item: row.Item, Date: row.Date, RawValue: row.RawValue, Income: true}
+	}
+	top := results[0]
+	autoInsert := top.Confidence >= threshold
+	status := "INCOME REVIEW"
+	if autoInsert {
+		status = "INCOME AUTO  "
+	}
+	fmt.Printf("[%d/%d] %s %s → %s/%s (%.0f%%)\n", i+1, total, status, row.Item, top.Block, top.Label, top.Confidence*100)
+	return classifiedRow{
+		Item:         row.Item,
+		Date:         row.Date,
+		RawValue:     row.RawValue,
+		Subcategory:  top.Label,
+		Category:     top.Block,
+		Confidence:   top.Confidence,
+		AutoInserted: autoInsert,
+		Income:       true,
+	}
+}
+
+// incomeEntry builds the income log entry of a credit row filed under its
+// block (Category) and line (Subcategory). The date is stored as DD/MM/YYYY
+// (see feedback.QualifyDate); a value with a leading - is a deduction.
+func incomeEntry(r classifiedRow) (feedback.IncomeEntry, error) {
+	date, err := utils.ParseDateFlexible(feedback.QualifyDate(r.Date))
+	if err != nil {
+		return feedback.IncomeEntry{}, err
+	}
+	raw := strings.TrimPrefix(strings.TrimSpace(r.RawValue), "+")
+	deduction := strings.HasPrefix(raw, "-")
+	value, err := utils.ParseCurrency(strings.TrimPrefix(raw, "-"))
+	if err != nil {
+		return feedback.IncomeEntry{}, fmt.Errorf("parsing value %q: %w", r.RawValue, err)
+	}
+	if deduction {
+		value = -value
+	}
+	return feedback.NewIncomeEntry(r.Item, utils.FormatDate(date), value, r.Category, r.Subcategory), nil
+}
+
+// appendIncomeRow writes a classified credit row to the income log unless its
+// ID is already in logged (the income log's IDs, see feedback.LoadIncomeIDs),
+// which it then updates. appended is false for a row already logged.
+func appendIncomeRow(log store.Log[feedback.IncomeEntry], logged map[string]bool, r classifiedRow) (appended bool, err error) {
+	entry, err := incomeEntry(r)
+	if err != nil {
+		return false, err
+	}
+	if logged[entry.ID] {
+		return false, nil
+	}
+	if err := log.Append(entry); err != nil {
+		return false, err
+	}
+	logged[entry.ID] = true
+	return true, nil
+}
+
+// hasIncomeRows reports whether any result is a credit row.
+func hasIncomeRows(rows []classifiedRow) bool {
+	for _, r := range rows {
+		if r.Income {
+			return true
+		}
+	}
+	return false
+}
+
+// writeIncomeReviewCSV writes the credit rows that were not appended to the
+// income log, for apply-income once each has a block and line.
+func writeIncomeReviewCSV(path string, rows []classifiedRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Comma = ';'
+	if err := w.Write([]string{"item", "date", "value", "block", "line", "confidence", "auto_inserted"}); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if !r.Income || r.AutoInserted {
+			continue
+		}
+		w.Write([]string{ //nolint:errcheck
+			r.Item,
+			r.Date,
+			r.RawValue,
+			r.Category,
+			r.Subcategory,
+			fmt.Sprintf("%.4f", r.Confidence),
+			"false",
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse3FieldLine_Credit(t *testing.T) {
+	row, err := parse3FieldLine("PIX ACME folha;05/03;+9500,00")
+	require.NoError(t, err)
+	assert.True(t, row.Credit)
+	assert.Equal(t, 9500.0, row.Value)
+	assert.Equal(t, "+9500,00", row.RawValue)
+
+	row, err = parse3FieldLine("Uber;05/03;35,50")
+	require.NoError(t, err)
+	assert.False(t, row.Credit)
+
+	for _, line := range []string{
+		"Reembolso;05/03;+99,90/3",
+		"Refund;05/03;+USD 20,00",
+		"Reembolso;05/03;+150,00;Supermercado=150,00",
+		"Reembolso;05/03;+150,00;;viagem",
+		"Reembolso;05/03;+abc",
+	} {
+		_, err := parse3FieldLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+// TestAppendClassified_IncomeRow verifies an auto-inserted credit row lands in
+// the income log, not the expense log, with its year filled in.
+func TestAppendClassified_IncomeRow(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		ExpensesLogPath: filepath.Join(dir, "expenses_log.jsonl"),
+		IncomeLogPath:   filepath.Join(dir, "income_log.jsonl"),
+	}
+	results := []classifiedRow{{
+		Item:         "PIX ACME folha",
+		Date:         "05/03/2026",
+		RawValue:     "+9500,00",
+		Category:     "Salário",
+		Subcategory:  "Salário",
+		Confidence:   1,
+		AutoInserted: true,
+		Income:       true,
+	}}
+
+	require.NoError(t, appendClassified(results, cfg, &foreignConverter{}, "my-classifier-q3", "run-1"))
+
+	_, err := os.Stat(cfg.ExpensesLogPath)
+	assert.True(t, os.IsNotExist(err), "a credit must not reach the expense log")
+
+	data, err := os.ReadFile(cfg.IncomeLogPath)
+	require.NoError(t, err)
+	var e feedback.IncomeEntry
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(string(data))), &e))
+	assert.Equal(t, "PIX ACME folha", e.ItemNote)
+	assert.Equal(t, "05/03/2026", e.Date)
+	assert.Equal(t, 9500.0, e.Value)
+	assert.Equal(t, "Salário", e.IncomeCategory)
+	assert.Equal(t, "Salário", e.IncomeLabel)
+	assert.Equal(t, "run-1", e.RunID)
+
+	require.NoError(t, appendClassified(results, cfg, &foreignConverter{}, "my-classifier-q3", "run-2"))
+	again, err := os.ReadFile(cfg.IncomeLogFilePath())
+	require.NoError(t, err)
+	assert.Equal(t, data, again, "a credit already in the income log is skipped")
+}
+
+func TestWriteIncomeReviewCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "income-review.csv")
+	rows := []classifiedRow{
+		{Item: "Uber", RawValue: "35,50"},
+		{Item: "PIX ACME folha", RawValue: "+9500,00", Category: "Salário", Subcategory: "Salário", AutoInserted: true, Income: true},
+		{Item: "TED recebida", Date: "06/03", RawValue: "+200,00", Income: true},
+	}
+
+	require.NoError(t, writeIncomeReviewCSV(path, rows))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2, "header + the credit row left for review")
+	assert.Equal(t, "item;date;value;block;line;confidence;auto_inserted", lines[0])
+	assert.Equal(t, "TED recebida;06/03;+200,00;;;0.0000;false", lines[1])
+}
@@ -1,11 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"expense-reporter/internal/appender"
 	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
 
 	"github.com/stretchr/testify/require"
 )
@@ -34,14 +41,91 @@ func TestAppendClassified_DowngradesRowOnAppendFailure(t *testing.T) {
 	// the (secondary) confirmed-feedback write is skipped.
 	cfg := &config.Config{ExpensesLogPath: "/expense-reporter-nonexistent-dir/expenses_log.jsonl"}
 
-	err := appendClassified(results, cfg, "my-classifier-q3")
+	err := appendClassified(results, cfg, &foreignConverter{}, "my-classifier-q3", "")
 
 	require.Error(t, err, "appendClassified should return an error when a row fails to append")
 	require.False(t, results[0].AutoInserted, "the failed row must be downgraded to AutoInserted=false")
 	require.Error(t, results[0].Error, "the failed row must record its append error")
 }
 
+// TestAppendClassified_SplitRow verifies a split row is logged as one entry per part,
+// all sharing the parent ID that its classifications.jsonl split entry carries.
+func TestAppendClassified_SplitRow(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		ExpensesLogPath:     filepath.Join(dir, "expenses_log.jsonl"),
+		ClassificationsPath: filepath.Join(dir, "classifications.jsonl"),
+		TagRules:            tags.Rules{{Tags: []string{"casa"}, Subcategory: "Limpeza"}},
+	}
+	results := []classifiedRow{{
+		Item:         "Carrefour",
+		Date:         "03/01/2026",
+		RawValue:     "150,00",
+		Subcategory:  "Supermercado=120,00|Limpeza=30,00",
+		AutoInserted: true,
+		Account:      "nubank",
+		Tags:         []string{"viagem"},
+		Split: []appender.SplitPart{
+			{Type: "Variáveis", Category: "Alimentação", Subcategory: "Supermercado", Value: 120},
+			{Type: "Variáveis", Category: "Casa", Subcategory: "Limpeza", Value: 30},
+		},
+	}}
+
+	require.NoError(t, appendClassified(results, cfg, &foreignConverter{}, "my-classifier-q3", ""))
+
+	logData, err := os.ReadFile(cfg.ExpensesLogPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(logData)), "\n")
+	require.Len(t, lines, 2)
+
+	fbData, err := os.ReadFile(cfg.ClassificationsPath)
+	require.NoError(t, err)
+	var fb feedback.Entry
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(fbData), &fb))
+	require.Equal(t, feedback.StatusSplit, fb.Status)
+
+	var parts []feedback.ExpenseEntry
+	for _, line := range lines {
+		var e feedback.ExpenseEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		require.Equal(t, fb.ID, e.ParentID, "every part must point at the split's classification entry")
+		require.Equal(t, "nubank", e.Account, "every part must carry the row's account")
+		parts = append(parts, e)
+	}
+	require.Equal(t, []string{"viagem"}, parts[0].Tags)
+	require.Equal(t, []string{"viagem", "casa"}, parts[1].Tags, "a rule matching one part's path tags that part only")
+}
+
+// TestAppendClassified_TagRules verifies a row is logged with its tags column
+// plus the tags of the rules it matches, and classified.csv reports both.
+func TestAppendClassified_TagRules(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		ExpensesLogPath: filepath.Join(dir, "expenses_log.jsonl"),
+		TagRules: tags.Rules{
+			{Tags: []string{"viagem-floripa"}, From: "10/01/2026", To: "20/01/2026"},
+			{Tags: []string{"reembolsavel"}, Item: "uber", Account: "empresa"},
+		},
+	}
+	results := []classifiedRow{{
+		Item: "Uber Aeroporto", Date: "12/01/2026", RawValue: "80,00", Type: "Variáveis", Category: "Transporte",
+		Subcategory: "Uber/Taxi", AutoInserted: true, Account: "nubank", Tags: []string{"trabalho"},
+	}}
+
+	require.NoError(t, appendClassified(results, cfg, &foreignConverter{}, "my-classifier-q3", ""))
+
+	require.Equal(t, []string{"trabalho", "viagem-floripa"}, results[0].Tags, "the account rule does not match nubank")
+	logged := readJSONLines[feedback.ExpenseEntry](t, cfg.ExpensesLogPath)
+	require.Len(t, logged, 1)
+	require.Equal(t, []string{"trabalho", "viagem-floripa"}, logged[0].Tags)
+}
+
 func TestParse3FieldLine(t *testing.T) {
+	// Run on 03/01/2027: a DD/MM date after that day belongs to 2026.
+	now := feedback.Now
+	feedback.Now = func() time.Time { return time.Date(2027, 1, 3, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { feedback.Now = now })
+
 	tests := []struct {
 		name         string
 		line         string
@@ -50,13 +134,21 @@ func TestParse3FieldLine(t *testing.T) {
 		wantRawValue string
 		wantErr      bool
 	}{
-		{"valid line", "Uber Centro;15/04;35,50", "Uber Centro", "15/04", "35,50", false},
-		{"valid with period decimal", "Item;05/01;160.00", "Item", "05/01", "160.00", false},
-		{"installment value", "Uber Centro;15/04;35,50/3", "Uber Centro", "15/04", "35,50/3", false},
+		{"valid line", "Uber Centro;15/04;35,50", "Uber Centro", "15/04/2026", "35,50", false},
+		{"valid with period decimal", "Item;05/01;160.00", "Item", "05/01/2026", "160.00", false},
+		{"installment value", "Uber Centro;15/04;35,50/3", "Uber Centro", "15/04/2026", "35,50/3", false},
+		{"foreign currency value", "Steam;17/04;USD 20,00", "Steam", "17/04/2026", "USD 20,00", false},
+		{"split 4th field", "Carrefour;03/01;150,00;Supermercado=120,00|Limpeza=30,00", "Carrefour", "03/01/2027", "150,00", false},
+		{"empty 4th field", "Carrefour;03/01;150,00;", "Carrefour", "03/01/2027", "150,00", false},
+		{"tags 5th field", "Pousada;12/01;800,00;;viagem, floripa", "Pousada", "12/01/2026", "800,00", false},
+		{"tag with semicolon", "Pousada;12/01;800,00;;viagem;floripa", "", "", "", true},
+		{"4th field not split notation", "Carrefour;03/01;150,00;Supermercado", "", "", "", true},
 		{"too few fields", "Uber;35,50", "", "", "", true},
 		{"empty item", ";15/04;35,50", "", "", "", true},
 		{"invalid value", "Item;15/04;abc", "", "", "", true},
-		{"leading whitespace trimmed", " Uber ;15/04;35,50", "Uber", "15/04", "35,50", false},
+		{"new year crossing", "Ceia;28/12;300,00", "Ceia", "28/12/2026", "300,00", false},
+		{"dated line keeps its year", "Ceia;02/01/2027;300,00", "Ceia", "02/01/2027", "300,00", false},
+		{"leading whitespace trimmed", " Uber ;15/04;35,50", "Uber", "15/04/2026", "35,50", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -81,7 +173,7 @@ func TestParse3FieldLine(t *testing.T) {
 }
 
 func TestBatchAutoCommand_Flags(t *testing.T) {
-	for _, flag := range []string{"model", "data-dir", "ollama-url", "threshold", "top", "dry-run", "output-dir"} {
+	for _, flag := range []string{"model", "data-dir", "ollama-url", "threshold", "top", "dry-run", "output-dir", "card"} {
 		if batchAutoCmd.Flags().Lookup(flag) == nil {
 			t.Errorf("flag %q not registered on batch-auto command", flag)
 		}
@@ -165,7 +257,7 @@ func TestWriteClassifiedCSV_TypeColumn(t *testing.T) {
 	defer os.Remove(f.Name())
 
 	rows := []classifiedRow{
-		{Item: "Aluguel", Date: "05/01", RawValue: "2500,00", Subcategory: "Aluguel", Category: "Moradia", Confidence: 0.95, AutoInserted: true, Type: "Fixas"},
+		{Item: "Aluguel", Date: "05/01", RawValue: "2500,00", Subcategory: "Aluguel", Category: "Moradia", Confidence: 0.95, AutoInserted: true, Type: "Fixas", Account: "nubank", Tags: []string{"casa", "reembolsavel"}},
 		{Item: "Uber Centro", Date: "15/04", RawValue: "35,50", Subcategory: "Uber/Taxi", Category: "Transporte", Confidence: 0.80, AutoInserted: false, Type: ""},
 	}
 
@@ -183,24 +275,30 @@ func TestWriteClassifiedCSV_TypeColumn(t *testing.T) {
 		t.Fatalf("got %d lines, want 3", len(lines))
 	}
 
-	// Header must end with ;type
-	if !strings.HasSuffix(lines[0], ";type") {
+	// Header must end with ;type;account;tags
+	if !strings.HasSuffix(lines[0], ";type;account;tags") {
 		t.Errorf("header missing type column: %q", lines[0])
 	}
 
 	// First data row: type = "Fixas"
 	fields0 := strings.Split(lines[1], ";")
-	if len(fields0) != 8 {
-		t.Fatalf("data row has %d fields, want 8: %q", len(fields0), lines[1])
+	if len(fields0) != 10 {
+		t.Fatalf("data row has %d fields, want 10: %q", len(fields0), lines[1])
 	}
 	if fields0[7] != "Fixas" {
 		t.Errorf("type field: got %q, want %q", fields0[7], "Fixas")
 	}
+	if fields0[8] != "nubank" {
+		t.Errorf("account field: got %q, want %q", fields0[8], "nubank")
+	}
+	if fields0[9] != "casa,reembolsavel" {
+		t.Errorf("tags field: got %q, want %q", fields0[9], "casa,reembolsavel")
+	}
 
 	// Second data row: type = "" (empty)
 	fields1 := strings.Split(lines[2], ";")
-	if len(fields1) != 8 {
-		t.Fatalf("data row has %d fields, want 8: %q", len(fields1), lines[2])
+	if len(fields1) != 10 {
+		t.Fatalf("data row has %d fields, want 10: %q", len(fields1), lines[2])
 	}
 	if fields1[7] != "" {
 		t.Errorf("type field for unresolved row: got %q, want empty", fields1[7])
@@ -236,13 +334,13 @@ func TestWriteReviewCSV_TypeColumn(t *testing.T) {
 	if len(lines) != 3 {
 		t.Fatalf("got %d lines, want 3", len(lines))
 	}
-	if !strings.HasSuffix(lines[0], ";type") {
+	if !strings.HasSuffix(lines[0], ";type;account;tags") {
 		t.Errorf("header missing type column: %q", lines[0])
 	}
 
 	fields := strings.Split(lines[1], ";")
-	if len(fields) != 8 {
-		t.Fatalf("data row has %d fields, want 8: %q", len(fields), lines[1])
+	if len(fields) != 10 {
+		t.Fatalf("data row has %d fields, want 10: %q", len(fields), lines[1])
 	}
 	if fields[7] != "Extras" {
 		t.Errorf("type field: got %q, want %q", fields[7], "Extras")
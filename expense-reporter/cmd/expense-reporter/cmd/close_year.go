@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/store"
+)
+
+var closeYearDryRun bool
+
+var closeYearCmd = &cobra.Command{
+	Use:   "close-year <YYYY>",
+	Short: "Archive a finished year's log entries into per-year files",
+	Long: `Moves the year's lines out of classifications.jsonl, expenses_log.jsonl and
+income_log.jsonl (income_log_path in config, if set) into archives next to them:
+expenses_log.2025.jsonl and so on. Every reader spans the archives, so lookups,
+queries and generate-workbook --year see the same entries as before; a query for
+a date range only opens the archives of the years it covers.
+
+An entry moves with its edits, corrections and voids when its latest line is
+dated in the year. DD/MM dates take the year they were logged in; lines with no
+year to go by stay in the live logs. Lines for a closed year appended later stay
+in the live log too and are still read with the archive.
+
+Installment plans still owing after 31/12 carry into the new year: their later
+installments stay in the live log, and any missing from it are appended.
+
+A timestamped backup of each log is written next to it before it changes.
+
+Examples:
+  expense-reporter close-year 2025 --dry-run
+  expense-reporter close-year 2025`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCloseYear,
+}
+
+func init() {
+	rootCmd.AddCommand(closeYearCmd)
+	closeYearCmd.Flags().BoolVar(&closeYearDryRun, "dry-run", false, "Report what would move without writing anything")
+}
+
+// yearLogs are the logs close-year partitions; income is "" when unconfigured.
+type yearLogs struct {
+	classifications, expenses, income, plans string
+}
+
+func runCloseYear(cmd *cobra.Command, args []string) error {
+	year, err := strconv.Atoi(args[0])
+	if err != nil || len(args[0]) != 4 {
+		return fmt.Errorf("invalid year %q (want YYYY)", args[0])
+	}
+	if year >= today().Year() {
+		return fmt.Errorf("%d is not over yet\n  Hint: close a year once its last entries are logged", year)
+	}
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	logs := yearLogs{
+		classifications: appCfg.ClassificationsFilePath(),
+		expenses:        appCfg.ExpensesLogFilePath(),
+		income:          appCfg.IncomeLogFilePath(),
+		plans:           appCfg.PlansFilePath(),
+	}
+	if logs.expenses == "" {
+		return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	return closeYear(cmd.OutOrStdout(), logs, year, closeYearDryRun)
+}
+
+// closeYear archives year's lines (see feedback.CloseYear) after a dry pass to
+// report them, backing up each log it changes, then carries open installment
+// plans into the new year.
+func closeYear(w io.Writer, logs yearLogs, year int, dryRun bool) error {
+	pending, err := feedback.CloseYear(logs.classifications, logs.expenses, logs.income, year, true)
+	if err != nil {
+		return err
+	}
+	moves := []struct {
+		path  string
+		lines int
+	}{
+		{logs.classifications, pending.Classifications},
+		{logs.expenses, pending.Expenses},
+		{logs.income, pending.Income},
+	}
+
+	verb := "moved"
+	if dryRun {
+		verb = "would move"
+	}
+	fmt.Fprintf(w, "Closing %d:\n", year)
+	backups := map[string]string{}
+	for _, m := range moves {
+		if m.path == "" {
+			continue
+		}
+		if m.lines == 0 {
+			fmt.Fprintf(w, "  %-24s nothing dated %d\n", filepath.Base(m.path)+":", year)
+			continue
+		}
+		fmt.Fprintf(w, "  %-24s %s %d lines to %s\n", filepath.Base(m.path)+":", verb, m.lines, filepath.Base(jsonlog.PartitionPath(m.path, year)))
+		if !dryRun {
+			if _, err := backupOnce(m.path, backups); err != nil {
+				return err
+			}
+		}
+	}
+	if pending.Undated > 0 {
+		fmt.Fprintf(w, "  %d line(s) kept: no year to infer\n", pending.Undated)
+	}
+
+	if !dryRun && pending.Classifications+pending.Expenses+pending.Income > 0 {
+		if _, err := feedback.CloseYear(logs.classifications, logs.expenses, logs.income, year, false); err != nil {
+			return fmt.Errorf("closing %d (backups kept next to each log): %w", year, err)
+		}
+		for _, m := range moves {
+			if m.path == "" || m.lines == 0 {
+				continue
+			}
+			if err := store.DropIndex(m.path); err != nil {
+				return err
+			}
+			if err := store.DropIndex(jsonlog.PartitionPath(m.path, year)); err != nil {
+				return err
+			}
+		}
+	}
+	return carryPlans(w, logs, year, dryRun, backups)
+}
+
+// carryPlans reports the plans still owing after year and appends any of
+// their later installments missing from the expense log.
+func carryPlans(w io.Writer, logs yearLogs, year int, dryRun bool, backups map[string]string) error {
+	if logs.plans == "" {
+		return nil
+	}
+	plans, err := installment.Load(logs.plans)
+	if err != nil {
+		return err
+	}
+	logged, err := feedback.LoadExpenseIDs(logs.expenses)
+	if err != nil {
+		return err
+	}
+
+	var open []installment.Plan
+	var total float64
+	var missing []feedback.ExpenseEntry
+	for _, p := range plans {
+		balance := p.Balance(yearEndOf(year))
+		if balance == 0 {
+			continue
+		}
+		open = append(open, p)
+		total += balance
+		missing = append(missing, p.Carried(year, logged)...)
+	}
+	if len(open) == 0 {
+		fmt.Fprintf(w, "No installment plans owe anything after %d.\n", year)
+		return nil
+	}
+	fmt.Fprintf(w, "Installments carried into %d: %d plan(s), %s outstanding\n", year+1, len(open), brl(total))
+	for _, p := range open {
+		fmt.Fprintf(w, "  %s  %-28s %2d of %-2d left  %s\n",
+			p.ID, p.Item, len(p.Remaining(yearEndOf(year))), p.Count, brl(p.Balance(yearEndOf(year))))
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(w, "  would log %d installment(s) missing from %s\n", len(missing), filepath.Base(logs.expenses))
+		return nil
+	}
+	if _, err := os.Stat(logs.expenses); err == nil {
+		if _, err := backupOnce(logs.expenses, backups); err != nil {
+			return err
+		}
+	}
+	for _, e := range missing {
+		if err := feedback.AppendExpense(logs.expenses, e); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "  ✓ logged %d installment(s) missing from %s\n", len(missing), filepath.Base(logs.expenses))
+	return nil
+}
+
+// yearEndOf returns 31/12 of year, matching parsed entry dates.
+func yearEndOf(year int) time.Time {
+	return time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+}
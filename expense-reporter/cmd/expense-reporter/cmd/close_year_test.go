@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/store"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseYear_ArchivesAndCarriesPlans(t *testing.T) {
+	dir := t.TempDir()
+	logs := yearLogs{
+		classifications: filepath.Join(dir, "classifications.jsonl"),
+		expenses:        filepath.Join(dir, "expenses_log.jsonl"),
+		plans:           filepath.Join(dir, "plans.jsonl"),
+	}
+	require.NoError(t, feedback.AppendExpense(logs.expenses, feedback.NewExpenseEntry("Aluguel", "05/12/2025", 2500, "Aluguel", "Moradia")))
+
+	// A 3× plan from Nov/2025 whose Jan/2026 installment never reached the log.
+	sched, err := utils.ParseInstallments("90,00/3")
+	require.NoError(t, err)
+	plan := installment.NewPlan("p1", "Curso", time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), sched, "Extras", "Educação", "Cursos")
+	require.NoError(t, installment.Append(logs.plans, plan))
+	insts := plan.Installments()
+	for _, inst := range insts[:2] {
+		e := feedback.NewExpenseEntry(inst.Item, utils.FormatDate(inst.Date), inst.Value, "Cursos", "Educação")
+		e.PlanID = plan.ID
+		require.NoError(t, feedback.AppendExpense(logs.expenses, e))
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, closeYear(&out, logs, 2025, true))
+	assert.Contains(t, out.String(), "expenses_log.jsonl:      would move 3 lines to expenses_log.2025.jsonl")
+	assert.Contains(t, out.String(), "Installments carried into 2026: 1 plan(s), R$ 30,00 outstanding")
+	assert.Contains(t, out.String(), "would log 1 installment(s)")
+	_, err = os.Stat(jsonlog.PartitionPath(logs.expenses, 2025))
+	assert.True(t, os.IsNotExist(err), "dry run writes nothing")
+
+	out.Reset()
+	require.NoError(t, closeYear(&out, logs, 2025, false))
+	assert.Contains(t, out.String(), "moved 3 lines")
+	assert.Contains(t, out.String(), "logged 1 installment(s)")
+
+	s, err := store.Open[feedback.ExpenseEntry](logs.expenses, store.BackendJSONL)
+	require.NoError(t, err)
+	live, err := store.NewJSONL[feedback.ExpenseEntry](logs.expenses).Query(store.Query{})
+	require.NoError(t, err)
+	require.Len(t, live, 1)
+	assert.Equal(t, insts[2].ID, live[0].ID, "the carried installment is the only live line")
+	all, err := s.Query(store.Query{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+	backups, err := filepath.Glob(filepath.Join(dir, "expenses_log_backup_*"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+
+	out.Reset()
+	require.NoError(t, closeYear(&out, logs, 2025, false))
+	assert.Contains(t, out.String(), "nothing dated 2025")
+	assert.NotContains(t, out.String(), "logged", "carrying is idempotent")
+}
@@ -36,7 +36,7 @@ func init() {
 }
 
 func runCorrect(cmd *cobra.Command, args []string) error {
-	item, date, _, value, _, actualSubcategory, ok := parseExpenseForFeedback(args[0])
+	in, ok := parseExpenseForFeedback(args[0])
 	if !ok {
 		return fmt.Errorf("invalid expense format: expected \"item;DD/MM;value;subcategory\"")
 	}
@@ -51,6 +51,8 @@ func runCorrect(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("classifications log path is not configured")
 	}
 
+	item, date, value, actualSubcategory := in.Item, in.DateStr, in.Value, in.Subcategory
+
 	id := feedback.GenerateID(item, date, value)
 	prior, found, err := feedback.FindLatestEntry(path, id)
 	if err != nil {
@@ -0,0 +1,544 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
+	"expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	editItem        string
+	editDate        string
+	editValue       string
+	editSubcategory string
+	editType        string
+	editAccount     string
+	editTags        []string
+	editUntags      []string
+	editAll         bool
+	voidAll         bool
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Correct an entry in expenses_log.jsonl",
+	Long: `Appends a record that supersedes the entry with that ID: readers (generate-workbook,
+installments, …) see only the latest version. The entry keeps its ID. IDs may be
+abbreviated to any unique prefix; 'history' shows every version.
+
+A split transaction is edited as one: given a part's ID or the split's parent ID,
+--item, --date, --account, --tag and --untag change every part. --value and
+--subcategory change only the part whose ID is given.
+
+Changing --value drops the foreign-currency record of a converted entry, since it
+no longer describes the amount. --tag adds tags and --untag removes them; the
+tag_rules in config are not re-applied. The Excel workbook is not touched — regenerate it
+with generate-workbook.
+
+A voided entry is restored from its last version before the void, with the
+changes given, if any.
+
+Identical entries (same item, date and value) share an ID. Editing one of them
+would merge them into one entry, so edit refuses unless --all is given.
+
+Examples:
+  expense-reporter edit 3f9a1c --value 2600,00
+  expense-reporter edit 3f9a1c --date 06/03/2026 --subcategory Padaria
+  expense-reporter edit 3f9a1c --subcategory Diarista --type Fixas
+  expense-reporter edit 3f9a1c --account itau
+  expense-reporter edit 3f9a1c --tag viagem --untag casa`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+var voidCmd = &cobra.Command{
+	Use:   "void <id>",
+	Short: "Void an entry in expenses_log.jsonl",
+	Long: `Appends a tombstone for the entry with that ID, so it no longer counts anywhere.
+Nothing is deleted: 'history' still shows the entry, and 'edit <id>' restores it.
+IDs may be abbreviated to any unique prefix. A split transaction is voided as one:
+a part's ID or the split's parent ID voids every part. Identical entries (same
+item, date and value) share an ID and can only be voided together, with --all.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVoid,
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show every version of an entry in expenses_log.jsonl",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd, voidCmd, historyCmd)
+	editCmd.Flags().StringVar(&editItem, "item", "", "New item description")
+	editCmd.Flags().StringVar(&editDate, "date", "", "New date (DD/MM/YYYY)")
+	editCmd.Flags().StringVar(&editValue, "value", "", "New value in BRL (e.g. 35,50)")
+	editCmd.Flags().StringVar(&editSubcategory, "subcategory", "", "New subcategory (category is resolved from the taxonomy)")
+	editCmd.Flags().StringVar(&editType, "type", "", "Expense type, to pick between subcategories of the same name")
+	editCmd.Flags().StringVar(&editAccount, "account", "", "New card or account the expense was paid with")
+	editCmd.Flags().StringSliceVar(&editTags, "tag", nil, "Tag to add (repeatable or comma-separated)")
+	editCmd.Flags().StringSliceVar(&editUntags, "untag", nil, "Tag to remove (repeatable or comma-separated)")
+	editCmd.Flags().BoolVar(&editAll, "all", false, "Edit identical entries sharing the ID, merging them into one")
+	voidCmd.Flags().BoolVar(&voidAll, "all", false, "Void every identical entry sharing the ID")
+}
+
+// errNoExpense reports an ID that names no entry in the expense log.
+var errNoExpense = errors.New("no expense log entry")
+
+// sharedIDError refuses to act on an ID that n identical entries share: an edit
+// or void line supersedes every line of its ID, so it would merge or drop them
+// all (see logschema.Resolve).
+func sharedIDError(verb, effect, id string, n int) error {
+	return fmt.Errorf("%d identical entries share ID %s; a %s would %s them all\n  Hint: pass --all to %s them together", n, id, verb, effect, verb)
+}
+
+// expenseTrail returns, in log order, every line of the entry whose ID is id or
+// starts with it.
+func expenseTrail(log store.Log[feedback.ExpenseEntry], id string) ([]feedback.ExpenseEntry, error) {
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return nil, err
+	}
+	return pickTrail(all, id)
+}
+
+// expenseTrails returns the trails of what id names, for edit, void and
+// history, which treat a split transaction as one: the entry whose ID is id or
+// starts with it, or, when that entry is a split part or id is (a prefix of) a
+// split's parent ID, every part of the split. named is the trail of the entry
+// id names directly; nil when id names the split by its parent ID.
+func expenseTrails(log store.Log[feedback.ExpenseEntry], id string) (trails [][]feedback.ExpenseEntry, named []feedback.ExpenseEntry, err error) {
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var parent string
+	named, err = pickTrail(all, id)
+	switch {
+	case err == nil:
+		parent = named[0].ParentID
+	case errors.Is(err, errNoExpense):
+		if parent = splitParent(all, id); parent == "" {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, err
+	}
+	if parent == "" {
+		return [][]feedback.ExpenseEntry{named}, named, nil
+	}
+	return splitTrails(all, parent), named, nil
+}
+
+// splitParent returns the parent ID that is id or the only one starting with
+// it; "" when there is none or several.
+func splitParent(all []feedback.ExpenseEntry, id string) string {
+	found := ""
+	for _, e := range all {
+		switch {
+		case e.ParentID == id:
+			return id
+		case e.ParentID == "" || e.ParentID == found || !strings.HasPrefix(e.ParentID, id):
+		case found == "":
+			found = e.ParentID
+		default:
+			return ""
+		}
+	}
+	return found
+}
+
+// splitTrails returns the trail of each part of the split with parent ID
+// parent, in the log order of their first lines.
+func splitTrails(all []feedback.ExpenseEntry, parent string) [][]feedback.ExpenseEntry {
+	var order []string
+	byID := map[string][]feedback.ExpenseEntry{}
+	for _, e := range all {
+		if e.ParentID != parent {
+			continue
+		}
+		if _, seen := byID[e.ID]; !seen {
+			order = append(order, e.ID)
+		}
+		byID[e.ID] = append(byID[e.ID], e)
+	}
+	trails := make([][]feedback.ExpenseEntry, len(order))
+	for i, id := range order {
+		trails[i] = byID[id]
+	}
+	return trails
+}
+
+// pickTrail returns, in log order, every line of all whose ID is id or the
+// only one starting with it.
+func pickTrail(all []feedback.ExpenseEntry, id string) ([]feedback.ExpenseEntry, error) {
+	byID := map[string][]feedback.ExpenseEntry{}
+	for _, e := range all {
+		if e.ID != "" && strings.HasPrefix(e.ID, id) {
+			byID[e.ID] = append(byID[e.ID], e)
+		}
+	}
+	if trail, ok := byID[id]; ok {
+		return trail, nil
+	}
+	switch len(byID) {
+	case 0:
+		return nil, fmt.Errorf("%w with ID %q", errNoExpense, id)
+	case 1:
+		for _, trail := range byID {
+			return trail, nil
+		}
+	}
+	ids := make([]string, 0, len(byID))
+	for k := range byID {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("ID prefix %q matches %d entries (%s); use a longer prefix", id, len(ids), strings.Join(ids, ", "))
+}
+
+// openExpenseTrails opens the configured expense log and finds the lines of
+// what id names (see expenseTrails).
+func openExpenseTrails(appCfg *config.Config, id string) (log store.Log[feedback.ExpenseEntry], trails [][]feedback.ExpenseEntry, named []feedback.ExpenseEntry, err error) {
+	if log, err = openExpenses(appCfg); err != nil {
+		return nil, nil, nil, err
+	}
+	if log == nil {
+		return nil, nil, nil, fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	if trails, named, err = expenseTrails(log, id); err != nil {
+		return nil, nil, nil, err
+	}
+	return log, trails, named, nil
+}
+
+// beforeVoid returns what a voided trail resolved to just before its last
+// effective void; nil when the trail holds no void.
+func beforeVoid(trail []feedback.ExpenseEntry) []feedback.ExpenseEntry {
+	for i := len(trail) - 1; i >= 0; i-- {
+		if trail[i].Op != logschema.OpVoid {
+			continue
+		}
+		if prior := feedback.ResolveExpenses(trail[:i]); len(prior) > 0 {
+			return prior
+		}
+	}
+	return nil
+}
+
+// currentExpense resolves an entry's trail to its current state; ok is false
+// when the entry is voided.
+func currentExpense(trail []feedback.ExpenseEntry) (e feedback.ExpenseEntry, ok bool) {
+	resolved := feedback.ResolveExpenses(trail)
+	if len(resolved) == 0 {
+		return feedback.ExpenseEntry{}, false
+	}
+	return resolved[len(resolved)-1], true
+}
+
+// expenseEdit holds the edit flags; empty fields are left unchanged.
+type expenseEdit struct {
+	Item, Date, Value, Subcategory, Type, Account string
+	Tag, Untag                                    []string // tags added, then removed
+	All                                           bool     // merge identical entries sharing the ID
+}
+
+func (c expenseEdit) empty() bool {
+	return c.Item == "" && c.Date == "" && c.Value == "" && c.Subcategory == "" && c.Type == "" && c.Account == "" &&
+		len(c.Tag) == 0 && len(c.Untag) == 0
+}
+
+// applyExpenseEdit returns base with c applied, plus a "field: old → new" line
+// per field that changed. sheets is only consulted when the subcategory changes.
+func applyExpenseEdit(base feedback.ExpenseEntry, c expenseEdit, sheets []taxonomy.ExpenseType) (feedback.ExpenseEntry, []string, error) {
+	next := base
+	var changes []string
+	note := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %s → %s", field, from, to))
+		}
+	}
+
+	if c.Item != "" {
+		next.Item = strings.TrimSpace(c.Item)
+		note("item", base.Item, next.Item)
+	}
+	if c.Date != "" {
+		t, err := utils.ParseDateFlexible(c.Date)
+		if err != nil {
+			return base, nil, fmt.Errorf("invalid --date: %w", err)
+		}
+		next.Date = utils.FormatDate(t)
+		note("date", base.Date, next.Date)
+	}
+	if c.Value != "" {
+		v, err := utils.ParseCurrency(c.Value)
+		if err != nil {
+			return base, nil, fmt.Errorf("invalid --value: %w", err)
+		}
+		if v <= 0 {
+			return base, nil, fmt.Errorf("invalid --value: must be positive")
+		}
+		next.Value = v
+		if v != base.Value {
+			next.Foreign = nil
+		}
+		note("value", brl(base.Value), brl(next.Value))
+	}
+	if c.Type != "" && c.Subcategory == "" {
+		return base, nil, fmt.Errorf("--type only picks between subcategories; pass --subcategory too")
+	}
+	if c.Subcategory != "" {
+		typ, cat, err := taxonomy.ResolveLeaf(sheets, c.Subcategory, c.Type)
+		if err != nil {
+			if errors.Is(err, taxonomy.ErrLeafAmbiguous) {
+				return base, nil, fmt.Errorf("subcategory %q exists under several types; pass --type", c.Subcategory)
+			}
+			return base, nil, fmt.Errorf("subcategory %q: %w", c.Subcategory, err)
+		}
+		next.Type, next.Category, next.Subcategory = typ, cat, c.Subcategory
+		note("path", expensePath(base), expensePath(next))
+	}
+	if c.Account != "" {
+		next.Account = c.Account
+		note("account", orNone(base.Account), next.Account)
+	}
+	if len(c.Tag) > 0 || len(c.Untag) > 0 {
+		next.Tags = tags.Without(tags.Merge(base.Tags, c.Tag), c.Untag)
+		note("tags", orNone(tags.Join(base.Tags)), orNone(tags.Join(next.Tags)))
+	}
+	return next, changes, nil
+}
+
+func expensePath(e feedback.ExpenseEntry) string {
+	if e.Type == "" {
+		return e.Category + "/" + e.Subcategory
+	}
+	return e.Type + "/" + e.Category + "/" + e.Subcategory
+}
+
+// editTrails applies c to the current state of each trail, as runEdit does: a
+// split (several trails) takes the item, date, account and tag changes on
+// every part and the value and subcategory change on the named part only. A
+// voided trail is restored from its version before the void (see beforeVoid);
+// a trail of identical entries sharing an ID is refused unless c.All. It
+// returns the entries that changed, with their "field: old → new" lines.
+func editTrails(trails [][]feedback.ExpenseEntry, named []feedback.ExpenseEntry, c expenseEdit, sheets []taxonomy.ExpenseType) ([]feedback.ExpenseEntry, [][]string, error) {
+	parent := trails[0][0].ParentID
+	partOnly := c.Value != "" || c.Subcategory != "" || c.Type != ""
+	if parent != "" && named == nil && partOnly {
+		return nil, nil, fmt.Errorf("--value and --subcategory change one part of split %s; pass the part's ID\n  Hint: 'history %s' lists the parts", parent, parent)
+	}
+	var edited []feedback.ExpenseEntry
+	var changes [][]string
+	for _, trail := range trails {
+		cur, restored := feedback.ResolveExpenses(trail), false
+		if len(cur) == 0 {
+			if cur, restored = beforeVoid(trail), true; len(cur) == 0 {
+				continue
+			}
+		}
+		if len(cur) > 1 && !c.All {
+			return nil, nil, sharedIDError("edit", "merge", trail[0].ID, len(cur))
+		}
+		base := cur[len(cur)-1]
+		pc := c
+		if parent != "" && (named == nil || trail[0].ID != named[0].ID) {
+			pc.Value, pc.Subcategory, pc.Type = "", "", ""
+		}
+		next, ch, err := applyExpenseEdit(base, pc, sheets)
+		if err != nil {
+			return nil, nil, err
+		}
+		if restored {
+			ch = append([]string{"restored: voided → live"}, ch...)
+		}
+		if len(ch) > 0 {
+			edited = append(edited, next)
+			changes = append(changes, ch)
+		}
+	}
+	return edited, changes, nil
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	c := expenseEdit{Item: editItem, Date: editDate, Value: editValue, Subcategory: editSubcategory, Type: editType, Account: editAccount,
+		Tag: editTags, Untag: editUntags, All: editAll}
+	var err error
+	if c.Tag, err = tags.Normalize(c.Tag); err != nil {
+		return fmt.Errorf("invalid --tag: %w", err)
+	}
+	if c.Untag, err = tags.Normalize(c.Untag); err != nil {
+		return fmt.Errorf("invalid --untag: %w", err)
+	}
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := appCfg.ValidateAccount(c.Account); err != nil {
+		return err
+	}
+	log, trails, named, err := openExpenseTrails(appCfg, args[0])
+	if err != nil {
+		return err
+	}
+
+	var sheets []taxonomy.ExpenseType
+	if c.Subcategory != "" {
+		if sheets, err = loadTaxonomyTree(appCfg); err != nil {
+			return err
+		}
+	}
+	edited, changes, err := editTrails(trails, named, c, sheets)
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if len(edited) == 0 && c.empty() {
+		return fmt.Errorf("nothing to change: pass --item, --date, --value, --subcategory, --account, --tag or --untag")
+	}
+	if len(edited) == 0 {
+		fmt.Fprintf(w, "No change: %s %s already matches\n", args[0], trails[0][0].Item)
+		return nil
+	}
+	for _, next := range edited {
+		if err := log.Append(next.Edit()); err != nil {
+			return fmt.Errorf("appending edit: %w", err)
+		}
+	}
+
+	shared := map[string]int{} // ID → identical entries an edit merges
+	for _, trail := range trails {
+		shared[trail[0].ID] = len(feedback.ResolveExpenses(trail))
+	}
+	for i, next := range edited {
+		fmt.Fprintf(w, "✓ Edited %s %s\n", next.ID, next.Item)
+		for _, ch := range changes[i] {
+			fmt.Fprintf(w, "  %s\n", ch)
+		}
+		if n := shared[next.ID]; n > 1 {
+			fmt.Fprintf(w, "  note: %d identical entries shared this ID; they are now one entry\n", n)
+		}
+	}
+	return nil
+}
+
+func runVoid(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	log, trails, _, err := openExpenseTrails(appCfg, args[0])
+	if err != nil {
+		return err
+	}
+	live, shared, err := voidTrails(trails, voidAll)
+	if err != nil {
+		return err
+	}
+	if len(live) == 0 {
+		return fmt.Errorf("entry %s is already voided", args[0])
+	}
+	for _, e := range live {
+		if err := log.Append(e.Void()); err != nil {
+			return fmt.Errorf("appending void: %w", err)
+		}
+	}
+
+	w := cmd.OutOrStdout()
+	base := live[0]
+	if base.ParentID != "" {
+		fmt.Fprintf(w, "✓ Voided split %s %s (%s): %d part(s)\n", base.ParentID, base.Item, base.Date, len(live))
+		for _, e := range live {
+			fmt.Fprintf(w, "  %s  %-28s %12s\n", e.ID, expensePath(e), brl(e.Value))
+		}
+		return nil
+	}
+	fmt.Fprintf(w, "✓ Voided %s %s (%s, %s)\n", base.ID, base.Item, base.Date, brl(base.Value))
+	if shared > 1 {
+		fmt.Fprintf(w, "  note: %d identical entries shared this ID; all are voided\n", shared)
+	}
+	if base.PlanID != "" {
+		fmt.Fprintf(w, "  note: part of installment plan %s; 'installments cancel' voids its remaining installments\n", base.PlanID)
+	}
+	return nil
+}
+
+// voidTrails returns the current state of each live trail, the entries runVoid
+// tombstones, and the most identical entries any of them shares its ID with. A
+// shared ID is refused unless all.
+func voidTrails(trails [][]feedback.ExpenseEntry, all bool) (live []feedback.ExpenseEntry, shared int, err error) {
+	for _, trail := range trails {
+		cur := feedback.ResolveExpenses(trail)
+		if len(cur) == 0 {
+			continue
+		}
+		if len(cur) > 1 && !all {
+			return nil, 0, sharedIDError("void", "drop", trail[0].ID, len(cur))
+		}
+		shared = max(shared, len(cur))
+		live = append(live, cur[len(cur)-1])
+	}
+	return live, shared, nil
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	_, trails, _, err := openExpenseTrails(appCfg, args[0])
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if parent := trails[0][0].ParentID; parent != "" {
+		fmt.Fprintf(w, "Split %s: %d part(s)\n\n", parent, len(trails))
+	}
+	for i, trail := range trails {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		printHistory(w, trail)
+	}
+	return nil
+}
+
+// printHistory lists every line of an entry's trail, oldest first, then its
+// current state.
+func printHistory(w io.Writer, trail []feedback.ExpenseEntry) {
+	fmt.Fprintf(w, "%s\n", trail[0].ID)
+	for _, e := range trail {
+		action := "added"
+		switch e.Op {
+		case logschema.OpEdit:
+			action = "edited"
+		case logschema.OpVoid:
+			action = "voided"
+		}
+		when := e.Timestamp
+		if when == "" {
+			when = "-"
+		}
+		fmt.Fprintf(w, "  %-20s  %-6s  %-28s  %-10s  %12s  %s\n", when, action, e.Item, e.Date, brl(e.Value), expensePath(e))
+	}
+	if cur, ok := currentExpense(trail); ok {
+		fmt.Fprintf(w, "Current: %s, %s, %s, %s\n", cur.Item, cur.Date, brl(cur.Value), expensePath(cur))
+	} else {
+		fmt.Fprintln(w, "Current: voided")
+	}
+}
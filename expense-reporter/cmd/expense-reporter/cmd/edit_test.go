@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func editTestLog(t *testing.T) (store.Log[feedback.ExpenseEntry], feedback.ExpenseEntry) {
+	t.Helper()
+	log := store.NewJSONL[feedback.ExpenseEntry](filepath.Join(t.TempDir(), "expenses_log.jsonl"))
+	spotify := feedback.NewExpenseEntry("Spotify", "05/03/2026", 21.90, "Spotify", "Assinaturas")
+	spotify.Type = "Fixas"
+	require.NoError(t, log.Append(spotify))
+	require.NoError(t, log.Append(feedback.NewExpenseEntry("Dentista", "10/03/2026", 300, "Dentista", "Saúde")))
+	return log, spotify
+}
+
+func TestExpenseTrail_Prefix(t *testing.T) {
+	log, spotify := editTestLog(t)
+
+	trail, err := expenseTrail(log, spotify.ID[:6])
+	require.NoError(t, err)
+	require.Len(t, trail, 1)
+	assert.Equal(t, spotify.ID, trail[0].ID)
+
+	_, err = expenseTrail(log, "zzzz")
+	assert.ErrorContains(t, err, "no expense log entry")
+
+	_, err = expenseTrail(log, "")
+	assert.ErrorContains(t, err, "matches 2 entries")
+}
+
+func TestApplyExpenseEdit(t *testing.T) {
+	base := feedback.NewExpenseEntry("Consulta", "10/03/2026", 300, "Spotify", "Assinaturas")
+	base.Type = "Fixas"
+	base.Foreign = &feedback.ForeignAmount{Currency: "USD", Amount: 55, Rate: 5.4}
+
+	next, changes, err := applyExpenseEdit(base, expenseEdit{Value: "320,00", Date: "11/3/2026", Subcategory: "Dentista", Type: "Extras"}, addTestSheets())
+	require.NoError(t, err)
+	assert.Equal(t, 320.0, next.Value)
+	assert.Nil(t, next.Foreign, "a new value drops the foreign record")
+	assert.Equal(t, "11/03/2026", next.Date)
+	assert.Equal(t, "Extras/Saúde/Dentista", expensePath(next))
+	assert.Equal(t, base.ID, next.ID, "the ID never changes")
+	assert.Equal(t, []string{
+		"date: 10/03/2026 → 11/03/2026",
+		"value: R$ 300,00 → R$ 320,00",
+		"path: Fixas/Assinaturas/Spotify → Extras/Saúde/Dentista",
+	}, changes)
+
+	_, _, err = applyExpenseEdit(base, expenseEdit{Subcategory: "Dentista"}, addTestSheets())
+	assert.ErrorContains(t, err, "pass --type")
+
+	_, _, err = applyExpenseEdit(base, expenseEdit{Type: "Extras"}, addTestSheets())
+	assert.ErrorContains(t, err, "pass --subcategory")
+
+	_, changes, err = applyExpenseEdit(base, expenseEdit{Item: "Consulta"}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	next, changes, err = applyExpenseEdit(base, expenseEdit{Account: "itau"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "itau", next.Account)
+	assert.Equal(t, []string{"account: (none) → itau"}, changes)
+
+	base.Tags = []string{"casa"}
+	next, changes, err = applyExpenseEdit(base, expenseEdit{Tag: []string{"viagem"}, Untag: []string{"Casa"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viagem"}, next.Tags)
+	assert.Equal(t, []string{"tags: casa → viagem"}, changes)
+}
+
+func TestEditVoidHistory(t *testing.T) {
+	log, spotify := editTestLog(t)
+
+	edited := spotify
+	edited.Value = 23.90
+	require.NoError(t, log.Append(edited.Edit()))
+
+	trail, err := expenseTrail(log, spotify.ID)
+	require.NoError(t, err)
+	cur, ok := currentExpense(trail)
+	require.True(t, ok)
+	assert.Equal(t, 23.90, cur.Value)
+
+	require.NoError(t, log.Append(cur.Void()))
+	trail, err = expenseTrail(log, spotify.ID)
+	require.NoError(t, err)
+	require.Len(t, trail, 3)
+	assert.Equal(t, logschema.OpVoid, trail[2].Op)
+	_, ok = currentExpense(trail)
+	assert.False(t, ok)
+
+	var out bytes.Buffer
+	printHistory(&out, trail)
+	assert.Contains(t, out.String(), "added")
+	assert.Contains(t, out.String(), "edited")
+	assert.Contains(t, out.String(), "R$ 23,90")
+	assert.Contains(t, out.String(), "Current: voided")
+
+	edits, changes, err := editTrails([][]feedback.ExpenseEntry{trail}, trail, expenseEdit{Item: "Spotify Família"}, nil)
+	require.NoError(t, err)
+	require.Len(t, edits, 1)
+	assert.Equal(t, []string{"restored: voided → live", "item: Spotify → Spotify Família"}, changes[0])
+	require.NoError(t, log.Append(edits[0].Edit()))
+	trail, err = expenseTrail(log, spotify.ID)
+	require.NoError(t, err)
+	cur, ok = currentExpense(trail)
+	require.True(t, ok, "an edit after a void brings the entry back")
+	assert.Equal(t, 23.90, cur.Value, "from its last version before the void")
+	assert.Equal(t, "Spotify Família", cur.Item)
+}
+
+func TestExpenseTrails_SplitActsAsOne(t *testing.T) {
+	log, spotify := editTestLog(t)
+	parent := feedback.GenerateID("Mercado", "06/03/2026", 150)
+	feira := feedback.NewSplitExpenseEntry(parent, "Mercado", "06/03/2026", 100, "Feira", "Alimentação")
+	limpeza := feedback.NewSplitExpenseEntry(parent, "Mercado", "06/03/2026", 50, "Limpeza", "Casa")
+	require.NoError(t, log.Append(feira))
+	require.NoError(t, log.Append(limpeza))
+
+	trails, named, err := expenseTrails(log, spotify.ID)
+	require.NoError(t, err)
+	require.Len(t, trails, 1, "a plain entry is its own trail")
+	assert.Equal(t, spotify.ID, named[0].ID)
+
+	trails, named, err = expenseTrails(log, parent[:6])
+	require.NoError(t, err)
+	require.Len(t, trails, 2, "the parent ID names every part")
+	assert.Nil(t, named)
+	assert.Equal(t, feira.ID, trails[0][0].ID)
+	assert.Equal(t, limpeza.ID, trails[1][0].ID)
+
+	_, _, err = editTrails(trails, named, expenseEdit{Value: "90,00"}, nil)
+	assert.ErrorContains(t, err, "pass the part's ID")
+
+	edited, _, err := editTrails(trails, named, expenseEdit{Date: "07/03/2026"}, nil)
+	require.NoError(t, err)
+	require.Len(t, edited, 2, "a date edit moves the whole transaction")
+
+	trails, named, err = expenseTrails(log, limpeza.ID)
+	require.NoError(t, err)
+	require.Len(t, trails, 2, "a part ID names the split too")
+	edited, changes, err := editTrails(trails, named, expenseEdit{Value: "40,00", Item: "Mercado Extra"}, nil)
+	require.NoError(t, err)
+	require.Len(t, edited, 2)
+	assert.Equal(t, []string{"item: Mercado → Mercado Extra"}, changes[0], "the value stays on the named part")
+	assert.Equal(t, 100.0, edited[0].Value)
+	assert.Equal(t, 40.0, edited[1].Value)
+
+	for _, trail := range trails {
+		cur, ok := currentExpense(trail)
+		require.True(t, ok)
+		require.NoError(t, log.Append(cur.Void()))
+	}
+	trails, named, err = expenseTrails(log, parent)
+	require.NoError(t, err)
+	edited, changes, err = editTrails(trails, named, expenseEdit{}, nil)
+	require.NoError(t, err)
+	require.Len(t, edited, 2, "an edit restores every voided part")
+	assert.Equal(t, []string{"restored: voided → live"}, changes[0])
+	assert.Equal(t, 100.0, edited[0].Value)
+	assert.Equal(t, 50.0, edited[1].Value)
+}
+
+// TestEditVoid_SharedIDNeedsAll covers two identical coffees: they share an ID,
+// so a plain edit or void, which would merge or drop both, is refused.
+func TestEditVoid_SharedIDNeedsAll(t *testing.T) {
+	log, _ := editTestLog(t)
+	cafe := feedback.NewExpenseEntry("Café", "07/03/2026", 8.5, "Padaria", "Alimentação")
+	require.NoError(t, log.Append(cafe))
+	require.NoError(t, log.Append(cafe))
+
+	trails, named, err := expenseTrails(log, cafe.ID)
+	require.NoError(t, err)
+	_, _, err = editTrails(trails, named, expenseEdit{Value: "9,00"}, nil)
+	assert.ErrorContains(t, err, "2 identical entries share ID "+cafe.ID)
+	_, _, err = voidTrails(trails, false)
+	assert.ErrorContains(t, err, "pass --all")
+
+	edited, _, err := editTrails(trails, named, expenseEdit{Value: "9,00", All: true}, nil)
+	require.NoError(t, err)
+	require.Len(t, edited, 1)
+	live, shared, err := voidTrails(trails, true)
+	require.NoError(t, err)
+	assert.Len(t, live, 1)
+	assert.Equal(t, 2, shared)
+}
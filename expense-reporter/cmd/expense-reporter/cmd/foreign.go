@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/fx"
+	"expense-reporter/pkg/utils"
+)
+
+// foreignConverter converts foreign-currency input amounts to BRL for the log
+// path: the rate table is loaded once per command and the card's IOF surcharge
+// is resolved up front, so an unknown --card fails before any row is processed.
+type foreignConverter struct {
+	rates   fx.Table
+	card    string
+	iofRate float64
+}
+
+// newForeignConverter loads the rate table from config and resolves card's IOF
+// rate. A missing rates file is not an error here — only converting a foreign
+// amount without a quote is.
+func newForeignConverter(appCfg *config.Config, card string) (*foreignConverter, error) {
+	iofRate, err := appCfg.IOFRateFor(card)
+	if err != nil {
+		return nil, err
+	}
+	var rates fx.Table
+	if path := appCfg.RatesFilePath(); path != "" {
+		if rates, err = fx.Load(path); err != nil {
+			return nil, err
+		}
+	}
+	return &foreignConverter{rates: rates, card: card, iofRate: iofRate}, nil
+}
+
+// convert returns amount in BRL plus the record to preserve on the log entry.
+// A BRL amount (currency "") passes through unchanged with a nil record.
+func (c *foreignConverter) convert(currency string, date time.Time, amount float64) (float64, *feedback.ForeignAmount, error) {
+	if currency == "" {
+		return amount, nil, nil
+	}
+	rate, quotedOn, err := c.rates.Lookup(currency, date)
+	if err != nil {
+		return 0, nil, fmt.Errorf("converting %s %.2f: %w\n  Hint: import PTAX quotes with 'expense-reporter rates import <file.csv>'", currency, amount, err)
+	}
+	return fx.Convert(amount, rate, c.iofRate), &feedback.ForeignAmount{
+		Currency: currency,
+		Amount:   amount,
+		Rate:     rate,
+		RateDate: utils.FormatDate(quotedOn),
+		IOFRate:  c.iofRate,
+		Card:     c.card,
+	}, nil
+}
+
+// convertSchedule converts an installment schedule to BRL, returning the record
+// shared by every expanded entry. Foreign-currency installments must be equal
+// parts without interest, so that one conversion record describes them all.
+// A BRL schedule passes through unchanged with a nil record.
+func (c *foreignConverter) convertSchedule(currency string, date time.Time, sched utils.InstallmentSchedule) (utils.InstallmentSchedule, *feedback.ForeignAmount, error) {
+	if currency == "" {
+		return sched, nil, nil
+	}
+	if !sched.Equal() || sched.InterestRate > 0 {
+		return utils.InstallmentSchedule{}, nil, fmt.Errorf("foreign-currency installments must be equal parts (total/N) without interest")
+	}
+	brl, foreign, err := c.convert(currency, date, sched.Values[0])
+	if err != nil {
+		return utils.InstallmentSchedule{}, nil, err
+	}
+	values := make([]float64, sched.Count())
+	for i := range values {
+		values[i] = brl
+	}
+	principal := math.Round(brl*float64(len(values))*100) / 100
+	return utils.InstallmentSchedule{Values: values, Principal: principal}, foreign, nil
+}
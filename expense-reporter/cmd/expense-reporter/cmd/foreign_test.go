@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"expense-reporter/internal/fx"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignConverter_Convert(t *testing.T) {
+	rates := fx.Table{}
+	rates.Set("USD", time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC), 5.0)
+	conv := &foreignConverter{rates: rates, card: "nubank", iofRate: 0.035}
+
+	t.Run("BRL passes through", func(t *testing.T) {
+		v, foreign, err := conv.convert("", time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC), 35.50)
+		require.NoError(t, err)
+		assert.Equal(t, 35.50, v)
+		assert.Nil(t, foreign)
+	})
+
+	t.Run("weekend purchase converts at Friday quote plus IOF", func(t *testing.T) {
+		v, foreign, err := conv.convert("USD", time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC), 20)
+		require.NoError(t, err)
+		assert.Equal(t, 103.50, v)
+		require.NotNil(t, foreign)
+		assert.Equal(t, "USD", foreign.Currency)
+		assert.Equal(t, 20.0, foreign.Amount)
+		assert.Equal(t, "17/04/2026", foreign.RateDate)
+		assert.Equal(t, "nubank", foreign.Card)
+	})
+
+	t.Run("missing quote errors with import hint", func(t *testing.T) {
+		_, _, err := conv.convert("EUR", time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC), 20)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, fx.ErrNoRate)
+		assert.Contains(t, err.Error(), "rates import")
+	})
+}
+
+func TestForeignConverter_ConvertSchedule(t *testing.T) {
+	rates := fx.Table{}
+	rates.Set("USD", time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC), 5.0)
+	conv := &foreignConverter{rates: rates}
+	date := time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC)
+
+	equal, err := utils.ParseInstallments("30,00/3")
+	require.NoError(t, err)
+	brl, foreign, err := conv.convertSchedule("USD", date, equal)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{50, 50, 50}, brl.Values)
+	require.NotNil(t, foreign)
+	assert.Equal(t, 10.0, foreign.Amount)
+
+	uneven, err := utils.ParseInstallments("20,00+2x5,00")
+	require.NoError(t, err)
+	_, _, err = conv.convertSchedule("USD", date, uneven)
+	assert.ErrorContains(t, err, "equal parts")
+
+	local, _, err := conv.convertSchedule("", date, uneven)
+	require.NoError(t, err)
+	assert.Equal(t, uneven, local)
+}
+
+func TestParseExpenseForFeedback_CurrencyPrefix(t *testing.T) {
+	in, ok := parseExpenseForFeedback("Steam;17/04/2026;USD 30,00/3;Jogos")
+	require.True(t, ok)
+	assert.Equal(t, "Steam", in.Item)
+	assert.Equal(t, "USD", in.Currency)
+	assert.Equal(t, 10.0, in.Value)
+	assert.Equal(t, 3, in.InstallmentCount)
+	assert.Equal(t, "Jogos", in.Subcategory)
+}
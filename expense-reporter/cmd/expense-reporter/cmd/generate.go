@@ -3,11 +3,15 @@ package cmd
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"expense-reporter/internal/batch"
+	"expense-reporter/internal/config"
 	"expense-reporter/internal/generate"
+	"expense-reporter/internal/installment"
 )
 
 var (
@@ -15,8 +19,16 @@ var (
 	generateTaxonomy      string
 	generateEntries       string
 	generateIncomeEntries string
+	generateInvestments   string
+	generatePlans         string
 	generateYear          int
+	generateYears         []int
 	generateHeadroom      int
+	generateUpdate        bool
+	generateDashboard     bool
+	generateAccounts      bool
+	generateTags          bool
+	generateLocale        string
 )
 
 var generateWorkbookCmd = &cobra.Command{
@@ -24,7 +36,52 @@ var generateWorkbookCmd = &cobra.Command{
 	Short: "Generate a complete expense workbook from a taxonomy file",
 	Long: `Builds a workbook (Listas de itens + Receitas + one sheet per expense group)
 from a JSON taxonomy file; optionally fills it with entries from an expenses_log.jsonl file.
-The workbook is regenerated from data, never inserted into.`,
+The workbook is regenerated from data, never inserted into.
+
+When the taxonomy declares "investmentClasses", the Investimentos row of Listas de
+itens becomes one row per asset class, filled from the --investments ledger (see
+add-investment): contributions negative, withdrawals positive.
+
+With --dashboard, a "Painel" sheet follows Listas de itens with four charts read
+live from the Listas totals: revenue vs expenses, expenses stacked by type, the
+year's top categories, and the monthly balance.
+
+With --accounts, a "Contas" sheet follows with each paying card or account's
+monthly expenses (see add --account) and their total. Cards with a closing_day in
+config are totalled by invoice month, so their rows match the card invoices;
+entries without an account share a "(sem conta)" row.
+
+With --tags, a "Tags" sheet follows with each tag's monthly expenses across
+categories (see add --tag and tag_rules in config), by purchase month. An expense
+counts under each of its tags, so the sheet has no total row.
+
+--locale en-US writes the labels, month names, sheet names (Summary, Revenue) and
+number formats in English; amounts stay in reais. Taxonomy names are kept as
+written. Use the same --locale with --update as when the workbook was built.
+
+With --years 2024,2025,2026, a multi-year workbook is built instead: one sheet per
+year (each categoria's monthly totals, type totals, revenue and balance, with the
+year total and the monthly average) and a "Comparativo" sheet setting the years side
+by side with the change and % change between consecutive years. --entries may then
+span several years. An entry dated DD/MM goes in the year it was logged in (the
+year before when that would put it after the day it was logged); one with no
+timestamp to go by is left out with a note.
+
+With --update, the workbook already at --output is rewritten in place: each block's
+data cells are refilled and the block grows or shrinks to fit, while notes, comments,
+extra formulas, conditional formatting and added sheets are kept. Spare rows holding
+anything right of the month columns are kept too. A timestamped backup is written
+first. The taxonomy must still match the workbook's blocks; after adding or renaming
+a subcategory, regenerate without --update.
+
+Examples:
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Planilha_2026.xlsx
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Planilha_2026.xlsx --dashboard
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Planilha_2026.xlsx --accounts
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Planilha_2026.xlsx --tags
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Expenses_2026.xlsx --locale en-US
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Comparativo.xlsx --years 2024,2025,2026
+  expense-reporter generate-workbook --taxonomy taxonomy.json --entries expenses_log.jsonl -o Planilha_2026.xlsx --update`,
 	RunE: runGenerateWorkbook,
 }
 
@@ -35,8 +92,21 @@ func init() {
 	generateWorkbookCmd.Flags().StringVar(&generateTaxonomy, "taxonomy", "", "Taxonomy JSON file path (required)")
 	generateWorkbookCmd.Flags().StringVar(&generateEntries, "entries", "", "Entries JSONL path (optional)")
 	generateWorkbookCmd.Flags().StringVar(&generateIncomeEntries, "income-entries", "", "Income entries JSONL path (income_log.jsonl schema; optional)")
+	generateWorkbookCmd.Flags().StringVar(&generateInvestments, "investments", "", "Investments ledger (investments_log.jsonl) filling the Investimentos rows by asset class (optional)")
+	generateWorkbookCmd.Flags().StringVar(&generatePlans, "plans", "", "Installment plan ledger (plans.jsonl); voided installments are left out (optional)")
 	generateWorkbookCmd.Flags().IntVar(&generateYear, "year", time.Now().Year(), "Year applied to entry dates")
+	generateWorkbookCmd.Flags().IntSliceVar(&generateYears, "years", nil, "Build a multi-year comparison workbook for these years (e.g. 2024,2025)")
 	generateWorkbookCmd.Flags().IntVar(&generateHeadroom, "headroom", 0, "Spare data rows per block beyond busiest month")
+	generateWorkbookCmd.Flags().StringVar(&generateLocale, "locale", generate.Locales[0], "Workbook locale: "+strings.Join(generate.Locales, ", "))
+	generateWorkbookCmd.Flags().BoolVar(&generateDashboard, "dashboard", false, "Add a sheet of charts wired to the Listas totals")
+	generateWorkbookCmd.Flags().BoolVar(&generateAccounts, "accounts", false, "Add a sheet of monthly expenses per card or account, cards by invoice month")
+	generateWorkbookCmd.Flags().BoolVar(&generateTags, "tags", false, "Add a sheet of monthly expenses per tag")
+	generateWorkbookCmd.Flags().BoolVar(&generateUpdate, "update", false, "Rewrite the data cells of the existing workbook at --output, keeping manual edits")
+
+	generateWorkbookCmd.MarkFlagsMutuallyExclusive("years", "update")
+	generateWorkbookCmd.MarkFlagsMutuallyExclusive("years", "dashboard")
+	generateWorkbookCmd.MarkFlagsMutuallyExclusive("years", "accounts")
+	generateWorkbookCmd.MarkFlagsMutuallyExclusive("years", "tags")
 
 	if err := generateWorkbookCmd.MarkFlagRequired("output"); err != nil {
 		panic(err)
@@ -51,9 +121,40 @@ func runGenerateWorkbook(cmd *cobra.Command, args []string) error {
 		TaxonomyPath:      generateTaxonomy,
 		EntriesPath:       generateEntries,
 		IncomeEntriesPath: generateIncomeEntries,
+		InvestmentsPath:   generateInvestments,
 		OutPath:           generateOutput,
 		Year:              generateYear,
+		Years:             generateYears,
 		Headroom:          generateHeadroom,
+		Locale:            generateLocale,
+		Dashboard:         generateDashboard,
+		Accounts:          generateAccounts,
+		Tags:              generateTags,
+		Update:            generateUpdate,
+	}
+	if generateAccounts {
+		appCfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		opts.ClosingDays = appCfg.ClosingDays()
+	}
+	if generatePlans != "" {
+		plans, err := installment.Load(generatePlans)
+		if err != nil {
+			return err
+		}
+		// Only plans closed before cancel voided their installments in the log
+		// still need this; for later ones the log already leaves them out.
+		opts.ExcludeIDs = installment.VoidedIDs(plans)
+	}
+
+	if generateUpdate {
+		backup, err := batch.NewBackupManager().CreateBackup(generateOutput)
+		if err != nil {
+			return fmt.Errorf("backing up workbook: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "backup written: %s\n", backup)
 	}
 
 	if err := generate.Generate(opts); err != nil {
@@ -65,6 +166,10 @@ func runGenerateWorkbook(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting absolute path: %w", err)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "workbook written: %s\n", absPath)
+	verb := "written"
+	if generateUpdate {
+		verb = "updated"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "workbook %s: %s\n", verb, absPath)
 	return nil
 }
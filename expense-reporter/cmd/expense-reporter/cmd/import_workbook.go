@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/importer"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
+)
+
+var (
+	importYear   int
+	importDryRun bool
+)
+
+var importWorkbookCmd = &cobra.Command{
+	Use:   "import-workbook <workbook.xlsx>",
+	Short: "Import a hand-maintained workbook into the expense log",
+	Long: `Reads every type sheet named in the workbook's "Referência de Categorias"
+sheet, finds each subcategory section from the reference rows (or column B when
+the reference sheet records no row), and appends the item/date/value cells of
+every month to expenses_log.jsonl as typed entries, so generate-workbook can
+rebuild the year from the log.
+
+DD/MM dates take --year; an entry without a date is dated the first of its
+month. Items ending in "(n/m)" are installments: entries of the same purchase
+are linked to one plan, recorded in plans_path when configured.
+
+Entries already in the log are skipped, so an import can be re-run after the
+workbook gains rows. Identical rows (same item, date and value) are counted:
+only as many are imported as the log does not hold yet. Entries outside every
+subcategory section are listed and left out. The tag_rules in config tag each
+entry as it is logged. The import is journaled as a run: undo it with
+'runs revert'.
+
+Examples:
+  expense-reporter import-workbook Despesas_2023.xlsx --year 2023 --dry-run
+  expense-reporter import-workbook Despesas_2023.xlsx --year 2023`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportWorkbook,
+}
+
+func init() {
+	rootCmd.AddCommand(importWorkbookCmd)
+	importWorkbookCmd.Flags().IntVar(&importYear, "year", 0, "Year of the workbook, applied to DD/MM dates (required)")
+	importWorkbookCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Report what would be imported without writing anything")
+
+	if err := importWorkbookCmd.MarkFlagRequired("year"); err != nil {
+		panic(err)
+	}
+}
+
+func runImportWorkbook(cmd *cobra.Command, args []string) (err error) {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	expenses, err := openExpenses(appCfg)
+	if err != nil {
+		return err
+	}
+	if expenses == nil {
+		return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	if err := appCfg.TagRules.Validate(); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	result, err := importer.Import(args[0], importYear)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Importing %s (%d)\n", filepath.Base(args[0]), importYear)
+	if importDryRun {
+		_, err := importEntries(w, result, appCfg.ExpensesLogFilePath(), appCfg.PlansFilePath(), nil)
+		return err
+	}
+	if _, err := os.Stat(appCfg.ExpensesLogFilePath()); err == nil {
+		if _, err := backupOnce(appCfg.ExpensesLogFilePath(), map[string]string{}); err != nil {
+			return err
+		}
+	}
+	run := startRun(appCfg, "import-workbook", args[0])
+	defer func() { finishRun(w, appCfg, run, err) }()
+	_, err = importEntries(w, result, appCfg.ExpensesLogFilePath(), appCfg.PlansFilePath(), &importTarget{
+		expenses: tagExpenses(expenses, run.ID),
+		runID:    run.ID,
+		rules:    appCfg.TagRules,
+	})
+	return err
+}
+
+// importTarget is where importEntries writes; nil for a dry run. rules tag
+// each entry as it is appended.
+type importTarget struct {
+	expenses store.Log[feedback.ExpenseEntry]
+	runID    string
+	rules    tags.Rules
+}
+
+// importEntries reports result and, unless to is nil, appends the entries not
+// yet in the log at expensesPath and the plans not yet in the ledger at
+// plansPath ("" when unconfigured). Identical rows share an ID, so an ID's
+// entries are skipped only as many times as the log already holds it. It
+// returns how many entries are (or would be) appended.
+func importEntries(w io.Writer, result importer.Result, expensesPath, plansPath string, to *importTarget) (int, error) {
+	logged, err := feedback.LoadExpenseIDCounts(expensesPath)
+	if err != nil {
+		return 0, err
+	}
+	var fresh []feedback.ExpenseEntry
+	perSheet := map[string]int{}
+	var sheets []string
+	skipped := 0
+	for _, e := range result.Entries {
+		if logged[e.ID] > 0 {
+			logged[e.ID]--
+			skipped++
+			continue
+		}
+		fresh = append(fresh, e)
+		if perSheet[e.Type] == 0 {
+			sheets = append(sheets, e.Type)
+		}
+		perSheet[e.Type]++
+	}
+
+	var plans []installment.Plan
+	if plansPath != "" && len(result.Plans) > 0 {
+		existing, err := installment.Load(plansPath)
+		if err != nil {
+			return 0, err
+		}
+		known := map[string]bool{}
+		for _, p := range existing {
+			known[p.ID] = true
+		}
+		for _, p := range result.Plans {
+			if !known[p.ID] {
+				plans = append(plans, p)
+			}
+		}
+	}
+
+	verb := "imported"
+	if to == nil {
+		verb = "would import"
+	}
+	fmt.Fprintf(w, "  %d entries %s to %s\n", len(fresh), verb, filepath.Base(expensesPath))
+	for _, sheet := range sheets {
+		fmt.Fprintf(w, "    %-22s %d\n", sheet+":", perSheet[sheet])
+	}
+	if skipped > 0 {
+		fmt.Fprintf(w, "  %d already in the log (skipped)\n", skipped)
+	}
+	if plansPath != "" {
+		fmt.Fprintf(w, "  %d installment plan(s) %s to %s\n", len(plans), verb, filepath.Base(plansPath))
+	} else if len(result.Plans) > 0 {
+		fmt.Fprintf(w, "  %d installment plan(s) found; set plans_path in config to record them\n", len(result.Plans))
+	}
+	if len(result.Unplaced) > 0 {
+		fmt.Fprintf(w, "  %d entries outside every subcategory section (left out):\n", len(result.Unplaced))
+		for _, u := range result.Unplaced {
+			fmt.Fprintf(w, "    %s!%s  %s  %s\n", u.Sheet, u.Cell, u.Item, brl(u.Value))
+		}
+	}
+	if to == nil {
+		return len(fresh), nil
+	}
+
+	for _, p := range plans {
+		p.RunID = to.runID
+		if err := installment.Append(plansPath, p); err != nil {
+			return 0, err
+		}
+	}
+	for i, e := range fresh {
+		e.Tags = tags.Merge(e.Tags, to.rules.Match(loggedExpense(e)))
+		if err := to.expenses.Append(e); err != nil {
+			return i, err
+		}
+	}
+	return len(fresh), nil
+}
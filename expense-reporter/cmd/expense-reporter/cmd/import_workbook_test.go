@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/importer"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/store"
+	"expense-reporter/internal/tags"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEntries_SkipsLoggedAndTagsRun(t *testing.T) {
+	dir := t.TempDir()
+	expPath := filepath.Join(dir, "expenses_log.jsonl")
+	plansPath := filepath.Join(dir, "plans.jsonl")
+
+	uber := feedback.NewExpenseEntry("Uber", "15/01/2025", 35.5, "Uber/Taxi", "Transporte")
+	uber.Type = "Variáveis"
+	sofa := feedback.NewExpenseEntry("Sofá (1/2)", "05/03/2025", 400, "Móveis", "Casa")
+	sofa.Type = "Fixas"
+	plan := installment.NewPlan("plan-sofa", "Sofá", time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), utils.InstallmentSchedule{Values: []float64{400, 400}}, "Fixas", "Casa", "Móveis")
+	sofa.PlanID = plan.ID
+	result := importer.Result{
+		Entries:  []feedback.ExpenseEntry{uber, sofa},
+		Plans:    []installment.Plan{plan},
+		Unplaced: []excel.WorkbookEntry{{Sheet: "Variáveis", Cell: "D7", Item: "Avulso", Value: 5}},
+	}
+	require.NoError(t, feedback.AppendExpense(expPath, uber))
+
+	var out bytes.Buffer
+	n, err := importEntries(&out, result, expPath, plansPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Contains(t, out.String(), "1 entries would import")
+	assert.Contains(t, out.String(), "1 already in the log")
+	assert.Contains(t, out.String(), "Variáveis!D7")
+	_, statErr := os.Stat(plansPath)
+	assert.True(t, os.IsNotExist(statErr), "a dry run writes nothing")
+
+	log := store.NewJSONL[feedback.ExpenseEntry](expPath)
+	n, err = importEntries(&out, result, expPath, plansPath, &importTarget{expenses: tagExpenses(log, "run-1"), runID: "run-1",
+		rules: tags.Rules{{Category: "Casa", Tags: []string{"casa"}}, {Item: "uber", Tags: []string{"transporte"}}}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	all, err := log.Query(store.Query{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, sofa.ID, all[1].ID)
+	assert.Equal(t, "run-1", all[1].RunID)
+	assert.Equal(t, []string{"casa"}, all[1].Tags, "the tag rules tag imported entries")
+	plans, err := installment.Load(plansPath)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, "run-1", plans[0].RunID)
+
+	out.Reset()
+	n, err = importEntries(&out, result, expPath, plansPath, &importTarget{expenses: log})
+	require.NoError(t, err)
+	assert.Zero(t, n, "a re-run imports nothing new")
+	assert.Contains(t, out.String(), "0 installment plan(s) imported")
+}
+
+func TestImportEntries_KeepsIdenticalRows(t *testing.T) {
+	expPath := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	cafe := feedback.NewExpenseEntry("Café", "05/03/2025", 8, "Padaria", "Alimentação")
+	cafe.Type = "Variáveis"
+	result := importer.Result{Entries: []feedback.ExpenseEntry{cafe, cafe, cafe}}
+	require.NoError(t, feedback.AppendExpense(expPath, cafe))
+
+	var out bytes.Buffer
+	log := store.NewJSONL[feedback.ExpenseEntry](expPath)
+	n, err := importEntries(&out, result, expPath, "", &importTarget{expenses: log})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "three identical rows, one already logged")
+	assert.Contains(t, out.String(), "1 already in the log")
+
+	out.Reset()
+	n, err = importEntries(&out, result, expPath, "", &importTarget{expenses: log})
+	require.NoError(t, err)
+	assert.Zero(t, n, "a re-run imports nothing new")
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/inspect"
+)
+
+var (
+	inspectDumpOut string
+	inspectDiffOut string
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Dump and compare the structure of workbooks",
+	Long: `Structural view of a workbook: cell values, formulas, styles, merges and the
+row classification (month banner, column labels, data rows, total rows) that
+the generator and the verifiers rely on.`,
+}
+
+var inspectDumpCmd = &cobra.Command{
+	Use:   "dump <workbook.xlsx>",
+	Short: "Dump a workbook's structure as JSON",
+	Long: `Prints the structural dump of every sheet as one JSON document, or with --out
+writes manifest.json plus one <Sheet>.json per sheet into a directory.
+
+Examples:
+  expense-reporter inspect dump budget.xlsx > budget.json
+  expense-reporter inspect dump budget.xlsx --out dump/`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInspectDump,
+}
+
+var inspectDiffCmd = &cobra.Command{
+	Use:   "diff <a.xlsx> <b.xlsx>",
+	Short: "Compare two workbooks sheet by sheet",
+	Long: `Compares two workbooks sheet by sheet and lists the sheets only in one of them,
+changed dimensions, and per cell the changed values, formulas and styles, the
+merges removed and added, and the rows whose classification changed.
+
+Use it to check what a generator change did to its output, or to spot manual
+edits in a workbook before regenerating it. --json prints the diff as JSON;
+--out writes the JSON diff to a file alongside the readable report.
+
+Examples:
+  expense-reporter inspect diff before.xlsx after.xlsx
+  expense-reporter inspect diff generated.xlsx edited.xlsx --out edits.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runInspectDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.AddCommand(inspectDumpCmd, inspectDiffCmd)
+	inspectDumpCmd.Flags().StringVar(&inspectDumpOut, "out", "", "Write manifest.json and per-sheet JSON files into this directory")
+	inspectDiffCmd.Flags().StringVar(&inspectDiffOut, "out", "", "Also write the diff as JSON to this file")
+}
+
+func runInspectDump(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(args[0]); err != nil {
+		return fmt.Errorf("workbook not found: %s", args[0])
+	}
+	if inspectDumpOut != "" {
+		if err := inspect.DumpWorkbook(args[0], inspectDumpOut); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Dump written to %s\n", inspectDumpOut)
+		return nil
+	}
+	book, err := inspect.Load(args[0])
+	if err != nil {
+		return err
+	}
+	return writeIndentedJSON(cmd.OutOrStdout(), book)
+}
+
+func runInspectDiff(cmd *cobra.Command, args []string) error {
+	books := make([]*inspect.Workbook, len(args))
+	for i, path := range args {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("workbook not found: %s", path)
+		}
+		book, err := inspect.Load(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		books[i] = book
+	}
+	diff := inspect.Compare(books[0], books[1])
+
+	if inspectDiffOut != "" {
+		f, err := os.Create(inspectDiffOut)
+		if err != nil {
+			return fmt.Errorf("writing %s: %w", inspectDiffOut, err)
+		}
+		defer f.Close()
+		if err := writeIndentedJSON(f, diff); err != nil {
+			return fmt.Errorf("writing %s: %w", inspectDiffOut, err)
+		}
+	}
+	if outputJSON {
+		return writeIndentedJSON(cmd.OutOrStdout(), diff)
+	}
+	printInspectDiff(cmd.OutOrStdout(), diff)
+	return nil
+}
+
+func writeIndentedJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printInspectDiff(w io.Writer, d inspect.Diff) {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", d.A, d.B)
+	if d.Identical() {
+		fmt.Fprintln(w, "\n✓ The workbooks are structurally identical.")
+		return
+	}
+	for _, s := range d.OnlyInA {
+		fmt.Fprintf(w, "\n- sheet %s (only in %s)\n", s, d.A)
+	}
+	for _, s := range d.OnlyInB {
+		fmt.Fprintf(w, "\n+ sheet %s (only in %s)\n", s, d.B)
+	}
+	for _, s := range d.Sheets {
+		fmt.Fprintf(w, "\n%s:\n", s.Sheet)
+		if s.Dimensions != nil {
+			fmt.Fprintf(w, "  size     %dx%d → %dx%d\n",
+				s.Dimensions.From.Rows, s.Dimensions.From.Cols, s.Dimensions.To.Rows, s.Dimensions.To.Cols)
+		}
+		for _, c := range s.Cells {
+			fmt.Fprintf(w, "  %-8s %-7s %s → %s\n", c.Ref, c.Field, quoteOrEmpty(c.From), quoteOrEmpty(c.To))
+		}
+		for _, m := range s.MergesRemoved {
+			fmt.Fprintf(w, "  - merge  %s %s\n", m.Range, quoteOrEmpty(m.Value))
+		}
+		for _, m := range s.MergesAdded {
+			fmt.Fprintf(w, "  + merge  %s %s\n", m.Range, quoteOrEmpty(m.Value))
+		}
+		for _, r := range s.RowTypes {
+			fmt.Fprintf(w, "  row %-4d %s → %s\n", r.Row, orNone(r.From), orNone(r.To))
+		}
+	}
+	fmt.Fprintf(w, "\n%d sheet(s) changed, %d only in A, %d only in B\n", len(d.Sheets), len(d.OnlyInA), len(d.OnlyInB))
+}
+
+func quoteOrEmpty(s string) string {
+	if s == "" {
+		return "(empty)"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"expense-reporter/internal/inspect"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintInspectDiff(t *testing.T) {
+	d := inspect.Diff{
+		A: "before.xlsx", B: "after.xlsx",
+		OnlyInB: []string{"Dashboard"},
+		Sheets: []inspect.SheetDiff{{
+			Sheet:       "Variáveis",
+			Cells:       []inspect.CellChange{{Ref: "E40", Field: "formula", From: "SUM(E30:E39)", To: "SUM(E30:E38)"}},
+			MergesAdded: []inspect.Merge{{Range: "A1:B1", Value: "Nota"}},
+			RowTypes:    []inspect.RowTypeChange{{Row: 41, From: "total-row", To: ""}},
+		}},
+	}
+	var out bytes.Buffer
+	printInspectDiff(&out, d)
+	assert.Contains(t, out.String(), "+ sheet Dashboard (only in after.xlsx)")
+	assert.Contains(t, out.String(), `E40      formula "SUM(E30:E39)" → "SUM(E30:E38)"`)
+	assert.Contains(t, out.String(), `+ merge  A1:B1 "Nota"`)
+	assert.Contains(t, out.String(), "row 41   total-row → (none)")
+
+	out.Reset()
+	printInspectDiff(&out, inspect.Diff{A: "a.xlsx", B: "a.xlsx"})
+	assert.Contains(t, out.String(), "structurally identical")
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/store"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	installmentsAll    bool
+	installmentsOn     string
+	installmentsPayoff bool
+	installmentsAmount string
+)
+
+var installmentsCmd = &cobra.Command{
+	Use:   "installments",
+	Short: "Track installment plans and the balance still owed on them",
+	Long: `Every expense added with installment notation (value/N) is recorded as a plan in
+the installment ledger (plans_path in config), and its expanded log entries carry
+the plan's ID. Plan IDs may be abbreviated to any unique prefix.`,
+}
+
+var installmentsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plans with remaining balance and future monthly commitments",
+	Args:  cobra.NoArgs,
+	RunE:  runInstallmentsList,
+}
+
+var installmentsShowCmd = &cobra.Command{
+	Use:   "show <plan-id>",
+	Short: "Show a plan's installments and which are paid, due or voided",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runInstallmentsShow,
+}
+
+var installmentsCancelCmd = &cobra.Command{
+	Use:   "cancel <plan-id>",
+	Short: "Cancel a plan, or pay it off early with --payoff",
+	Long: `Closes a plan on --on (default: today). Installments dated after that are voided:
+a void line is appended to expenses_log.jsonl for each one logged, so the
+workbook, report and reconcile no longer count them.
+
+With --payoff the remaining balance is settled in one payment, appended to the
+expense log on the closing date; --amount overrides it (e.g. an early-payment discount).
+
+Examples:
+  expense-reporter installments cancel 3f9a1c --on 10/02/2027
+  expense-reporter installments cancel 3f9a1c --payoff --amount 1900,00`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInstallmentsCancel,
+}
+
+func init() {
+	rootCmd.AddCommand(installmentsCmd)
+	installmentsCmd.AddCommand(installmentsListCmd, installmentsShowCmd, installmentsCancelCmd)
+	installmentsListCmd.Flags().BoolVar(&installmentsAll, "all", false, "Include cancelled and paid-off plans")
+	installmentsCancelCmd.Flags().StringVar(&installmentsOn, "on", "", "Closing date DD/MM/YYYY (default: today)")
+	installmentsCancelCmd.Flags().BoolVar(&installmentsPayoff, "payoff", false, "Settle the remaining balance early instead of cancelling")
+	installmentsCancelCmd.Flags().StringVar(&installmentsAmount, "amount", "", "Payoff amount ##,## (default: remaining balance)")
+}
+
+// recordPlan writes an installment plan to the ledger for a line that expands
+// into more than one log entry, and returns the option linking those entries to
+// it. inputValue is the first payment as typed, so the plan ID matches the
+// line's classifications.jsonl ID; sched is the BRL schedule the log records;
+// meta carries the run recording the plan, the account paying the installments
+// and the tags each of them gets.
+// Single payments and an unconfigured ledger get no plan. Non-fatal: warns on
+// stderr if writing fails.
+func recordPlan(plansPath, item, dateStr string, inputValue float64, date time.Time, sched utils.InstallmentSchedule,
+	typ, category, subcategory string, meta entryMeta) []appender.EntryOption {
+
+	if sched.Count() <= 1 || plansPath == "" {
+		return nil
+	}
+	id := feedback.GenerateID(item, dateStr, inputValue)
+	plan := installment.NewPlan(id, item, date, sched, typ, category, subcategory)
+	plan.RunID = meta.RunID
+	plan.Account = meta.Account
+	plan.Tags = meta.Tags
+	if err := installment.Append(plansPath, plan); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  installment plan: %v\n", err)
+		return nil
+	}
+	return []appender.EntryOption{appender.WithPlan(id)}
+}
+
+// loadPlans returns the configured ledger path and its plans.
+func loadPlans() (*config.Config, string, []installment.Plan, error) {
+	appCfg, err := config.Load()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	path := appCfg.PlansFilePath()
+	if path == "" {
+		return nil, "", nil, fmt.Errorf("plans path not configured\n  Hint: set plans_path in config")
+	}
+	plans, err := installment.Load(path)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return appCfg, path, plans, nil
+}
+
+// today returns the current date at midnight UTC, matching parsed entry dates.
+func today() time.Time {
+	now := feedback.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func brl(v float64) string {
+	return "R$ " + utils.FormatBRValue(v)
+}
+
+func runInstallmentsList(cmd *cobra.Command, args []string) error {
+	_, _, plans, err := loadPlans()
+	if err != nil {
+		return err
+	}
+	asOf := today()
+
+	var total float64
+	shown := 0
+	for _, p := range plans {
+		if p.Status != installment.StatusActive && !installmentsAll {
+			continue
+		}
+		remaining := p.Remaining(asOf)
+		if len(remaining) == 0 && !installmentsAll {
+			continue
+		}
+		balance := p.Balance(asOf)
+		total += balance
+		shown++
+		fmt.Printf("  %s  %-28s %2d/%-2d paid  %s/month  remaining %s  [%s]\n",
+			p.ID, p.Item, p.Count-len(remaining), p.Count, brl(p.PerInstallment), brl(balance), p.Status)
+	}
+	if shown == 0 {
+		fmt.Println("No open installment plans.")
+		return nil
+	}
+	fmt.Printf("\nOutstanding: %s\n", brl(total))
+
+	commitments := installment.Commitments(plans, asOf)
+	months := make([]string, 0, len(commitments))
+	for m := range commitments {
+		months = append(months, m)
+	}
+	sort.Strings(months)
+	if len(months) > 0 {
+		fmt.Println("\nFuture monthly commitments:")
+	}
+	for _, m := range months {
+		t, _ := time.Parse("2006-01", m)
+		fmt.Printf("  %s  %s\n", t.Format("01/2006"), brl(commitments[m]))
+	}
+	return nil
+}
+
+func runInstallmentsShow(cmd *cobra.Command, args []string) error {
+	_, _, plans, err := loadPlans()
+	if err != nil {
+		return err
+	}
+	p, err := installment.Find(plans, args[0])
+	if err != nil {
+		return err
+	}
+	asOf := today()
+
+	fmt.Printf("Plan %s — %s\n", p.ID, p.Item)
+	fmt.Printf("  Path:    %s/%s/%s\n", p.Type, p.Category, p.Subcategory)
+	if len(p.Values) > 0 {
+		fmt.Printf("  Total:   %s in %d uneven payments\n", brl(p.Total), p.Count)
+	} else {
+		fmt.Printf("  Total:   %s in %d × %s\n", brl(p.Total), p.Count, brl(p.PerInstallment))
+	}
+	if p.InterestRate > 0 {
+		fmt.Printf("  Interest: %s%% a.m. on %s (%s in interest)\n",
+			utils.FormatBRValue(p.InterestRate*100), brl(p.Principal), brl(p.Total-p.Principal))
+	}
+	fmt.Printf("  Status:  %s", p.Status)
+	if p.ClosedOn != "" {
+		fmt.Printf(" on %s", p.ClosedOn)
+	}
+	if p.Status == installment.StatusPaidOff {
+		fmt.Printf(" (payoff %s)", brl(p.PayoffAmount))
+	}
+	fmt.Println()
+
+	voided := map[int]bool{}
+	for _, inst := range p.Voided() {
+		voided[inst.Number] = true
+	}
+	for _, inst := range p.Installments() {
+		state := "paid"
+		switch {
+		case p.Status == installment.StatusReverted:
+			state = "reverted"
+		case voided[inst.Number]:
+			state = "voided"
+		case inst.Date.After(asOf):
+			state = "due"
+		}
+		fmt.Printf("  %2d  %s  %s  %s\n", inst.Number, utils.FormatDate(inst.Date), brl(inst.Value), state)
+	}
+	fmt.Printf("  Remaining: %s\n", brl(p.Balance(asOf)))
+	return nil
+}
+
+// voidInstallments appends a void line for each of closed's voided
+// installments still live in the expense log, and returns how many it voided.
+func voidInstallments(log store.Log[feedback.ExpenseEntry], closed installment.Plan) (int, error) {
+	ids := installment.VoidedIDs([]installment.Plan{closed})
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range feedback.ResolveExpenses(all) {
+		if !ids[e.ID] {
+			continue
+		}
+		delete(ids, e.ID) // one void line drops every line sharing the ID
+		if err := log.Append(e.Void()); err != nil {
+			return n, fmt.Errorf("appending void: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func runInstallmentsCancel(cmd *cobra.Command, args []string) error {
+	appCfg, path, plans, err := loadPlans()
+	if err != nil {
+		return err
+	}
+	p, err := installment.Find(plans, args[0])
+	if err != nil {
+		return err
+	}
+	on := today()
+	if installmentsOn != "" {
+		if on, err = utils.ParseDateFlexible(installmentsOn); err != nil {
+			return err
+		}
+	}
+
+	status := installment.StatusCancelled
+	amount := p.Balance(on)
+	if installmentsPayoff {
+		status = installment.StatusPaidOff
+		if installmentsAmount != "" {
+			if amount, err = utils.ParseCurrency(installmentsAmount); err != nil {
+				return err
+			}
+		}
+	} else if installmentsAmount != "" {
+		return fmt.Errorf("--amount requires --payoff")
+	}
+
+	closed, err := p.Close(status, on, amount)
+	if err != nil {
+		return err
+	}
+	voided := len(closed.Voided())
+
+	if installmentsPayoff {
+		logPath := appCfg.ExpensesLogFilePath()
+		if logPath == "" {
+			return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+		}
+		item := fmt.Sprintf("%s (quitação %d/%d)", p.Item, p.Count-voided+1, p.Count)
+		if err := appender.ExpandAndAppend(logPath, item, on, amount, 1, p.Type, p.Category, p.Subcategory,
+			appender.WithPlan(p.ID), appender.WithAccount(p.Account), appender.WithTags(p.Tags)); err != nil {
+			return err
+		}
+	}
+	if err := installment.Append(path, closed); err != nil {
+		return err
+	}
+	expenses, err := openExpenses(appCfg)
+	if err != nil {
+		return err
+	}
+	if expenses != nil {
+		if _, err := voidInstallments(expenses, closed); err != nil {
+			return err
+		}
+	}
+
+	if installmentsPayoff {
+		fmt.Printf("✓ Paid off %s with %s on %s — %d installment(s) voided\n", p.Item, brl(amount), closed.ClosedOn, voided)
+	} else {
+		fmt.Printf("✓ Cancelled %s on %s — %d installment(s) voided (%s no longer owed)\n", p.Item, closed.ClosedOn, voided, brl(amount))
+	}
+	return nil
+}
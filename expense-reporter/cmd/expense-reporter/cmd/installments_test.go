@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/store"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordPlan_LinksExpandedEntries(t *testing.T) {
+	dir := t.TempDir()
+	plansPath := filepath.Join(dir, "plans.jsonl")
+	logPath := filepath.Join(dir, "expenses_log.jsonl")
+	date := time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC)
+
+	sched, err := utils.ParseInstallments("90,00/3")
+	require.NoError(t, err)
+	opts := recordPlan(plansPath, "Curso online", "15/11/2026", 30, date, sched, "Extras", "Educação", "Cursos", entryMeta{Account: "nubank", Tags: []string{"curso"}})
+	require.Len(t, opts, 1)
+	require.NoError(t, appender.ExpandAndAppend(logPath, "Curso online", date, 30, 3, "Extras", "Educação", "Cursos", opts...))
+
+	plans, err := installment.Load(plansPath)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Equal(t, feedback.GenerateID("Curso online", "15/11/2026", 30), plans[0].ID)
+	assert.Equal(t, 90.0, plans[0].Total)
+	assert.Equal(t, "nubank", plans[0].Account)
+	assert.Equal(t, []string{"curso"}, plans[0].Tags)
+
+	logged := readJSONLines[feedback.ExpenseEntry](t, logPath)
+	require.Len(t, logged, 3)
+	for i, inst := range plans[0].Installments() {
+		assert.Equal(t, plans[0].ID, logged[i].PlanID)
+		assert.Equal(t, inst.ID, logged[i].ID)
+	}
+}
+
+func TestVoidInstallments(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	date := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	sched := utils.InstallmentSchedule{Values: []float64{100, 100, 100, 100}, Principal: 400}
+	plan := installment.NewPlan("plan-tv", "TV", date, sched, "Extras", "Casa", "Eletrônicos")
+	require.NoError(t, appender.ExpandAndAppend(logPath, "TV", date, 100, 4, "Extras", "Casa", "Eletrônicos", appender.WithPlan(plan.ID)))
+
+	closed, err := plan.Close(installment.StatusCancelled, time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC), 0)
+	require.NoError(t, err)
+	log := store.NewJSONL[feedback.ExpenseEntry](logPath)
+	n, err := voidInstallments(log, closed)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "March and April are voided")
+
+	all, err := log.Query(store.Query{})
+	require.NoError(t, err)
+	live := feedback.ResolveExpenses(all)
+	require.Len(t, live, 2)
+	assert.Equal(t, "10/01/2026", live[0].Date)
+	assert.Equal(t, "10/02/2026", live[1].Date)
+
+	n, err = voidInstallments(log, closed)
+	require.NoError(t, err)
+	assert.Zero(t, n, "already voided")
+}
+
+func TestRecordPlan_SinglePaymentHasNoPlan(t *testing.T) {
+	plansPath := filepath.Join(t.TempDir(), "plans.jsonl")
+	sched := utils.InstallmentSchedule{Values: []float64{35.5}, Principal: 35.5}
+	opts := recordPlan(plansPath, "Uber", "15/04/2026", 35.5, time.Now(), sched, "Variáveis", "Transporte", "Uber/Taxi", entryMeta{})
+	assert.Empty(t, opts)
+
+	plans, err := installment.Load(plansPath)
+	require.NoError(t, err)
+	assert.Empty(t, plans)
+}
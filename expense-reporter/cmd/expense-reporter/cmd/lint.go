@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/generate"
+	"expense-reporter/internal/inspect"
+	"expense-reporter/internal/lint"
+	"expense-reporter/internal/taxonomy"
+)
+
+var (
+	lintLocale   string
+	lintTaxonomy string
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [workbook.xlsx]",
+	Short: "Check a workbook for broken totals and layout drift",
+	Long: `Checks the workbook (the configured one when no path is given) for the drift
+manual edits leave behind:
+
+  total-range  every SUM on a total row covers exactly the rows of its block,
+               in its own column; totals typed over a formula
+  pull         every summary-sheet pull points at a total row, of the month of
+               its column and of the subcategory its row is labelled with; every
+               block's total is pulled
+  reference    the reference sheet's subcategories exist in their sheets, at
+               the header and total rows it records; sheet subcategories it lacks
+  taxonomy     taxonomy.json's types and subcategories against the sheets
+
+Findings are errors (totals wrong today) or warnings (drift that will bite
+later, e.g. a SUM stopping short of rows that are still empty). The reference
+check needs the "Referência de Categorias" sheet and the taxonomy check a
+taxonomy (--taxonomy or taxonomy_path in config); each is skipped without.
+Exits non-zero when there are errors.
+
+Examples:
+  expense-reporter lint
+  expense-reporter lint Planilha_2026.xlsx --taxonomy taxonomy.json
+  expense-reporter lint Expenses_2026.xlsx --locale en-US --json`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVar(&lintLocale, "locale", generate.Locales[0], "Locale the workbook was built with, naming its summary sheet: "+strings.Join(generate.Locales, ", "))
+	lintCmd.Flags().StringVar(&lintTaxonomy, "taxonomy", "", "Taxonomy JSON file (default: taxonomy_path in config)")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	path, err := lintWorkbookPath(args)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("workbook not found: %s", path)
+	}
+	summary, err := generate.SummarySheet(lintLocale)
+	if err != nil {
+		return err
+	}
+	book, err := inspect.Load(path)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	in := lint.Input{Book: book, Summary: summary}
+	var skipped []string
+	if ref, err := excel.LoadReferenceSheet(path); err == nil {
+		in.Reference = ref
+	} else {
+		skipped = append(skipped, "reference (no "+excel.ReferenceSheet+" sheet)")
+	}
+	taxPath, err := lintTaxonomyPath()
+	if err != nil {
+		return err
+	}
+	if taxPath != "" {
+		types, _, err := taxonomy.LoadTaxonomy(taxPath, "", "", 0)
+		if err != nil {
+			return fmt.Errorf("loading taxonomy: %w", err)
+		}
+		in.Taxonomy = types
+	} else {
+		skipped = append(skipped, "taxonomy (no taxonomy configured)")
+	}
+
+	report := lint.Lint(in)
+	if outputJSON {
+		if err := writeIndentedJSON(w, report); err != nil {
+			return err
+		}
+	} else {
+		printLint(w, path, report, skipped)
+	}
+	if n := report.Count(lint.SeverityError); n > 0 {
+		return fmt.Errorf("lint: %d error(s) in %s", n, path)
+	}
+	return nil
+}
+
+func lintWorkbookPath(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	return GetWorkbookPath()
+}
+
+// lintTaxonomyPath returns --taxonomy, else the configured taxonomy, else "".
+func lintTaxonomyPath() (string, error) {
+	if lintTaxonomy != "" {
+		return lintTaxonomy, nil
+	}
+	appCfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	return appCfg.TaxonomyFilePath(), nil
+}
+
+func printLint(w io.Writer, path string, r lint.Report, skipped []string) {
+	fmt.Fprintf(w, "Linting %s\n", path)
+	for _, s := range skipped {
+		fmt.Fprintf(w, "  skipped: %s\n", s)
+	}
+	if len(r.Findings) == 0 {
+		fmt.Fprintln(w, "\n✓ No problems found.")
+		return
+	}
+	fmt.Fprintln(w)
+	for _, f := range r.Findings {
+		fmt.Fprintf(w, "  %-7s %-11s %-28s %s\n", f.Severity, f.Check, f.Location(), f.Message)
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", r.Count(lint.SeverityError), r.Count(lint.SeverityWarning))
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"expense-reporter/internal/lint"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintLint(t *testing.T) {
+	r := lint.Report{Findings: []lint.Finding{
+		{Severity: lint.SeverityError, Check: lint.CheckTotalRange, Sheet: "Fixas", Cell: "E8", Message: "Aluguel total =SUM(E6:E6) stops short"},
+		{Severity: lint.SeverityWarning, Check: lint.CheckTaxonomy, Sheet: "Fixas", Message: "taxonomy.json subcategory Lazer/Netflix has no block in the sheet"},
+	}}
+	var out bytes.Buffer
+	printLint(&out, "book.xlsx", r, []string{"reference (no Referência de Categorias sheet)"})
+	assert.Contains(t, out.String(), "skipped: reference")
+	assert.Contains(t, out.String(), "error   total-range Fixas!E8")
+	assert.Contains(t, out.String(), "1 error(s), 1 warning(s)")
+
+	out.Reset()
+	printLint(&out, "book.xlsx", lint.Report{}, nil)
+	assert.Contains(t, out.String(), "No problems found")
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/batch"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/internal/store"
+)
+
+var migrateDryRun bool
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade classifications.jsonl and expenses_log.jsonl to the current schema",
+	Long: `Every log line carries a "schema" version. Readers upgrade older lines on the
+fly; migrate rewrites the logs so every line is stored at the current version.
+
+It also replaces legacy entry IDs, hashed from a DD/MM date without a year, with
+year-qualified ones. The year comes from the entry's date or, failing that, from
+when the line was logged; lines with neither keep their ID. Split parts and
+installment plans (plans_path) follow their parent's new ID.
+
+A timestamped backup of each log is written next to it before it is rewritten.
+Logs that are already current are left untouched.
+
+Examples:
+  expense-reporter migrate --dry-run
+  expense-reporter migrate`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Report line versions without rewriting anything")
+}
+
+// migrateTarget is one log migrate handles.
+type migrateTarget struct {
+	path    string
+	schema  *logschema.Schema
+	migrate func(path string) (int, error)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	targets := []migrateTarget{
+		{appCfg.ClassificationsFilePath(), logschema.Classifications, feedback.MigrateClassifications},
+		{appCfg.ExpensesLogFilePath(), logschema.Expenses, feedback.MigrateExpenses},
+	}
+	backups := map[string]string{}
+	for _, t := range targets {
+		if t.path == "" {
+			continue
+		}
+		if err := migrateLog(cmd.OutOrStdout(), t, migrateDryRun, backups); err != nil {
+			return err
+		}
+	}
+	return migrateIDs(cmd.OutOrStdout(), appCfg.ClassificationsFilePath(), appCfg.ExpensesLogFilePath(),
+		appCfg.PlansFilePath(), migrateDryRun, backups)
+}
+
+// migrateLog reports the log's line versions and, unless dryRun, backs it up
+// and rewrites its older lines. The backup is recorded in backups by log path.
+func migrateLog(w io.Writer, t migrateTarget, dryRun bool, backups map[string]string) error {
+	name := filepath.Base(t.path)
+	counts, err := t.schema.Versions(t.path)
+	if err != nil {
+		return err
+	}
+	total, stale := 0, 0
+	for v, n := range counts {
+		total += n
+		if v > t.schema.Current {
+			return fmt.Errorf("%s has %d line(s) at schema %d, newer than this build supports (%d)\n  Hint: upgrade expense-reporter", name, n, v, t.schema.Current)
+		}
+		if v < t.schema.Current {
+			stale += n
+		}
+	}
+	if total == 0 {
+		fmt.Fprintf(w, "%s: empty\n", name)
+		return nil
+	}
+	fmt.Fprintf(w, "%s: %d lines (%s)\n", name, total, formatVersions(counts))
+	if stale == 0 {
+		fmt.Fprintf(w, "  up to date (schema %d)\n", t.schema.Current)
+		return nil
+	}
+	if dryRun {
+		fmt.Fprintf(w, "  would upgrade %d lines to schema %d\n", stale, t.schema.Current)
+		return nil
+	}
+
+	backupPath, err := backupOnce(t.path, backups)
+	if err != nil {
+		return err
+	}
+	upgraded, err := t.migrate(t.path)
+	if err != nil {
+		return fmt.Errorf("migrating %s (backup kept at %s): %w", name, backupPath, err)
+	}
+	if err := store.DropIndex(t.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "  ✓ upgraded %d lines to schema %d (backup: %s)\n", upgraded, t.schema.Current, filepath.Base(backupPath))
+	return nil
+}
+
+// migrateIDs replaces legacy entry IDs (see feedback.MigrateIDs) after a dry
+// pass to report them; each file it rewrites is backed up first unless the
+// schema step already did.
+func migrateIDs(w io.Writer, classificationsPath, expensesPath, plansPath string, dryRun bool, backups map[string]string) error {
+	pending, err := feedback.MigrateIDs(classificationsPath, expensesPath, true)
+	if err != nil {
+		return err
+	}
+	plans := 0
+	if plansPath != "" {
+		all, err := installment.Load(plansPath)
+		if err != nil {
+			return err
+		}
+		for _, p := range all {
+			if _, ok := pending.IDs[p.ID]; ok {
+				plans++
+			}
+		}
+	}
+	if pending.Classifications+pending.Expenses+plans == 0 {
+		fmt.Fprintln(w, "entry IDs: up to date")
+		if pending.Unresolved > 0 {
+			fmt.Fprintf(w, "  %d legacy line(s) kept: no year to infer\n", pending.Unresolved)
+		}
+		return nil
+	}
+	fmt.Fprintf(w, "entry IDs: %d classification lines, %d expense log lines and %d plans keyed by legacy IDs\n",
+		pending.Classifications, pending.Expenses, plans)
+	if pending.Unresolved > 0 {
+		fmt.Fprintf(w, "  %d legacy line(s) kept: no year to infer\n", pending.Unresolved)
+	}
+	if dryRun {
+		fmt.Fprintln(w, "  would replace them with year-qualified IDs")
+		return nil
+	}
+
+	for path, n := range map[string]int{classificationsPath: pending.Classifications, expensesPath: pending.Expenses, plansPath: plans} {
+		if n == 0 {
+			continue
+		}
+		if _, err := backupOnce(path, backups); err != nil {
+			return err
+		}
+	}
+	done, err := feedback.MigrateIDs(classificationsPath, expensesPath, false)
+	if err != nil {
+		return fmt.Errorf("migrating IDs (backups kept next to each log): %w", err)
+	}
+	if plans > 0 {
+		if _, err := installment.RenameIDs(plansPath, done.IDs); err != nil {
+			return fmt.Errorf("migrating plan IDs (backup kept at %s): %w", backups[plansPath], err)
+		}
+	}
+	for _, path := range []string{classificationsPath, expensesPath} {
+		if path == "" {
+			continue
+		}
+		if err := store.DropIndex(path); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "  ✓ replaced IDs on %d classification lines, %d expense log lines and %d plans\n",
+		done.Classifications, done.Expenses, plans)
+	return nil
+}
+
+// backupOnce backs up the file at path unless this migrate run already did, so
+// the backup always holds the content from before the run.
+func backupOnce(path string, backups map[string]string) (string, error) {
+	if b, ok := backups[path]; ok {
+		return b, nil
+	}
+	b, err := batch.NewBackupManager().CreateBackup(path)
+	if err != nil {
+		return "", fmt.Errorf("backing up %s: %w", filepath.Base(path), err)
+	}
+	backups[path] = b
+	return b, nil
+}
+
+// formatVersions renders line counts per version, e.g. "v1: 120, v2: 8".
+func formatVersions(counts map[int]int) string {
+	versions := make([]int, 0, len(counts))
+	for v := range counts {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = fmt.Sprintf("v%d: %d", v, counts[v])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func legacyExpenseLog(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "expenses_log.jsonl")
+	legacy := `{"id":"aaaaaaaaaaaa","item":"Aluguel","date":"05/03/2026","value":2500,"subcategory":"Aluguel","category":"Moradia","sheet":"Fixas","timestamp":""}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0o644))
+	require.NoError(t, feedback.AppendExpense(path, feedback.NewExpenseEntry("Mercado", "03/03/2026", 210, "Supermercado", "Alimentação")))
+	return path
+}
+
+func TestMigrateLog_DryRunChangesNothing(t *testing.T) {
+	path := legacyExpenseLog(t)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	target := migrateTarget{path, logschema.Expenses, feedback.MigrateExpenses}
+	require.NoError(t, migrateLog(&out, target, true, map[string]string{}))
+
+	assert.Contains(t, out.String(), "2 lines (v1: 1, v3: 1)")
+	assert.Contains(t, out.String(), "would upgrade 1 lines")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestMigrateLog_UpgradesWithBackup(t *testing.T) {
+	path := legacyExpenseLog(t)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	target := migrateTarget{path, logschema.Expenses, feedback.MigrateExpenses}
+	require.NoError(t, migrateLog(&out, target, false, map[string]string{}))
+	assert.Contains(t, out.String(), "upgraded 1 lines to schema 3")
+
+	backups, err := filepath.Glob(filepath.Join(filepath.Dir(path), "expenses_log_backup_*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	saved, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, before, saved)
+
+	counts, err := logschema.Expenses.Versions(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{3: 2}, counts)
+
+	out.Reset()
+	require.NoError(t, migrateLog(&out, target, false, map[string]string{}))
+	assert.True(t, strings.Contains(out.String(), "up to date"))
+}
+
+func TestMigrateIDs_RenamesPlansWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	classPath := filepath.Join(dir, "classifications.jsonl")
+	plansPath := filepath.Join(dir, "plans.jsonl")
+	legacyID := feedback.LegacyID("Curso", "01/02", 30)
+	legacy := `{"id":"` + legacyID + `","item":"Curso","date":"01/02","value":30,"status":"manual","timestamp":"2025-02-01T10:00:00Z"}` + "\n"
+	require.NoError(t, os.WriteFile(classPath, []byte(legacy), 0o644))
+	sched, err := utils.ParseInstallments("90,00/3")
+	require.NoError(t, err)
+	require.NoError(t, installment.Append(plansPath, installment.NewPlan(legacyID, "Curso", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), sched, "Extras", "Educação", "Cursos")))
+
+	var out bytes.Buffer
+	require.NoError(t, migrateIDs(&out, classPath, "", plansPath, true, map[string]string{}))
+	assert.Contains(t, out.String(), "1 classification lines, 0 expense log lines and 1 plans")
+	assert.Contains(t, out.String(), "would replace")
+
+	out.Reset()
+	require.NoError(t, migrateIDs(&out, classPath, "", plansPath, false, map[string]string{}))
+	assert.Contains(t, out.String(), "replaced IDs on 1 classification lines")
+	plans, err := installment.Load(plansPath)
+	require.NoError(t, err)
+	assert.Equal(t, feedback.GenerateID("Curso", "01/02/2025", 30), plans[0].ID)
+	backups, err := filepath.Glob(filepath.Join(dir, "*_backup_*"))
+	require.NoError(t, err)
+	assert.Len(t, backups, 2, "classifications and plans backed up")
+
+	out.Reset()
+	require.NoError(t, migrateIDs(&out, classPath, "", plansPath, false, map[string]string{}))
+	assert.Contains(t, out.String(), "entry IDs: up to date")
+}
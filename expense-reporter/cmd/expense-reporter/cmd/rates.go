@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/fx"
+	"expense-reporter/pkg/utils"
+)
+
+var ratesImportCurrencies []string
+
+var ratesCmd = &cobra.Command{
+	Use:   "rates",
+	Short: "Manage the local exchange-rate table used for foreign-currency expenses",
+	Long: `The rate table (rates_path in config) holds BRL quotes per currency and date.
+It is filled from Banco Central PTAX CSV files, so conversion never needs the network.`,
+}
+
+var ratesImportCmd = &cobra.Command{
+	Use:   "import <ptax.csv>",
+	Short: "Import PTAX closing quotes into the rate table",
+	Long: `Reads a Banco Central PTAX CSV (daily bulletin or per-currency history export)
+and records each quote's sell rate. Existing quotes for the same day are replaced.
+
+Examples:
+  expense-reporter rates import 20260417.csv
+  expense-reporter rates import ptax-2026.csv --currency USD,EUR`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRatesImport,
+}
+
+var ratesLookupCmd = &cobra.Command{
+	Use:   "lookup <currency> <DD/MM/YYYY>",
+	Short: "Show the rate a foreign-currency expense on that date would convert at",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRatesLookup,
+}
+
+func init() {
+	rootCmd.AddCommand(ratesCmd)
+	ratesCmd.AddCommand(ratesImportCmd, ratesLookupCmd)
+	ratesImportCmd.Flags().StringSliceVar(&ratesImportCurrencies, "currency", nil, "Only import these currencies (default: all)")
+}
+
+// ratesPath returns the configured rate-table path, or an error with a hint when unset.
+func ratesPath() (string, error) {
+	appCfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	path := appCfg.RatesFilePath()
+	if path == "" {
+		return "", fmt.Errorf("rates path not configured\n  Hint: set rates_path in config")
+	}
+	return path, nil
+}
+
+func runRatesImport(cmd *cobra.Command, args []string) error {
+	path, err := ratesPath()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening PTAX file: %w", err)
+	}
+	defer f.Close()
+	quotes, err := fx.ParsePTAX(f)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", args[0], err)
+	}
+
+	table, err := fx.Load(path)
+	if err != nil {
+		return err
+	}
+	n := table.Import(quotes, ratesImportCurrencies)
+	if err := fx.Save(path, table); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Imported %d quote(s) into %s\n", n, path)
+	return nil
+}
+
+func runRatesLookup(cmd *cobra.Command, args []string) error {
+	path, err := ratesPath()
+	if err != nil {
+		return err
+	}
+	date, err := utils.ParseDateFlexible(args[1])
+	if err != nil {
+		return err
+	}
+	table, err := fx.Load(path)
+	if err != nil {
+		return err
+	}
+	currency := strings.ToUpper(args[0])
+	rate, quotedOn, err := table.Lookup(currency, date)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: %.4f BRL (quoted %s)\n", currency, utils.FormatDate(date), rate, utils.FormatDate(quotedOn))
+	return nil
+}
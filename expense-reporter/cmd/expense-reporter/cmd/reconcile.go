@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/reconcile"
+	"expense-reporter/internal/store"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	reconcileYear    int
+	reconcileEmitLog string
+	reconcileEmitCSV string
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Compare the expense log with the workbook for a year",
+	Long: `Reads every item/date/value triple from the month columns of the workbook's
+expense sheets and compares them with the year's entries in expenses_log.jsonl
+(after edits and voids; installments voided by a cancelled plan are left out).
+
+Entries pair by item name. A pair with the same date and value matches; one
+with only the date or only the value in common is reported as a mismatch.
+Everything else is only in the log or only in the workbook.
+
+The missing side can be written out for import:
+  --emit-log  workbook-only entries as expense log lines (append them to the log)
+  --emit-csv  log-only entries as a batch CSV (insert them with 'batch')
+
+Examples:
+  expense-reporter reconcile --year 2025
+  expense-reporter reconcile --year 2025 --emit-csv missing.csv --emit-log missing.jsonl`,
+	Args: cobra.NoArgs,
+	RunE: runReconcile,
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().IntVar(&reconcileYear, "year", time.Now().Year(), "Year to reconcile")
+	reconcileCmd.Flags().StringVar(&reconcileEmitLog, "emit-log", "", "Write workbook-only entries to this JSONL file")
+	reconcileCmd.Flags().StringVar(&reconcileEmitCSV, "emit-csv", "", "Write log-only entries to this batch CSV file")
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	expenses, err := openExpenses(appCfg)
+	if err != nil {
+		return err
+	}
+	if expenses == nil {
+		return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	workbook, err := GetWorkbookPath()
+	if err != nil {
+		return err
+	}
+	for _, path := range []string{reconcileEmitLog, reconcileEmitCSV} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists\n  Hint: choose a new file to write to", path)
+		}
+	}
+
+	logged, err := yearExpenses(expenses, appCfg.PlansFilePath(), reconcileYear)
+	if err != nil {
+		return err
+	}
+	sheets, err := expenseSheets(appCfg, workbook)
+	if err != nil {
+		return err
+	}
+	book, err := excel.ReadExpenseEntries(workbook, sheets, reconcileYear)
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Reconciling %d: %s (%d entries) vs %s (%d entries)\n",
+		reconcileYear, filepath.Base(appCfg.ExpensesLogFilePath()), len(logged), filepath.Base(workbook), len(book))
+	report := reconcile.Compare(logged, book)
+	printReconcile(w, report)
+
+	if reconcileEmitLog != "" && len(report.OnlyInWorkbook) > 0 {
+		if err := emitReconcileLog(reconcileEmitLog, report.OnlyInWorkbook, reconcileYear); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n→ %d workbook-only entries written to %s\n", len(report.OnlyInWorkbook), reconcileEmitLog)
+	}
+	if reconcileEmitCSV != "" && len(report.OnlyInLog) > 0 {
+		if err := emitReconcileCSV(reconcileEmitCSV, report.OnlyInLog, reconcileYear); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n→ %d log-only entries written to %s\n", len(report.OnlyInLog), reconcileEmitCSV)
+		fmt.Fprintf(w, "  Insert them with: expense-reporter batch %s\n", reconcileEmitCSV)
+	}
+	return nil
+}
+
+// yearExpenses returns the current state of the log's entries dated in year,
+// without the installments voided by a closed plan (as generate-workbook
+// leaves them out). The whole log is resolved before filtering, so an edit
+// that moved an entry across the year boundary counts where it now stands. A
+// DD/MM date takes its year from when the line was logged (see
+// utils.LoggedDate) and is returned qualified.
+func yearExpenses(expenses store.Log[feedback.ExpenseEntry], plansPath string, year int) ([]feedback.ExpenseEntry, error) {
+	current, err := currentExpenses(expenses, plansPath)
+	if err != nil {
+		return nil, err
+	}
+	var out []feedback.ExpenseEntry
+	for _, e := range current {
+		t, err := utils.LoggedDate(e.Date, e.Timestamp, year)
+		if err != nil || t.Year() != year {
+			continue
+		}
+		e.Date = utils.FormatDate(t)
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// currentExpenses returns the current state of every entry in the log, without
+// the installments voided by a closed plan.
+func currentExpenses(expenses store.Log[feedback.ExpenseEntry], plansPath string) ([]feedback.ExpenseEntry, error) {
+	all, err := expenses.Query(store.Query{})
+	if err != nil {
+		return nil, err
+	}
+	voided := map[string]bool{}
+	if plansPath != "" {
+		plans, err := installment.Load(plansPath)
+		if err != nil {
+			return nil, err
+		}
+		// Installments of plans closed before cancel voided them in the log.
+		voided = installment.VoidedIDs(plans)
+	}
+	var out []feedback.ExpenseEntry
+	for _, e := range feedback.ResolveExpenses(all) {
+		if !voided[e.ID] {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// expenseSheets returns the workbook's expense sheets: the sheets named in its
+// reference sheet or, for a workbook without one, the taxonomy's expense types.
+func expenseSheets(appCfg *config.Config, workbook string) ([]string, error) {
+	mappings, err := excel.LoadReferenceSheet(workbook)
+	if err != nil {
+		types, taxErr := loadTaxonomyTree(appCfg)
+		if taxErr != nil {
+			return nil, fmt.Errorf("%w\n  Hint: a workbook without a reference sheet needs taxonomy_path in config", err)
+		}
+		sheets := make([]string, len(types))
+		for i, t := range types {
+			sheets[i] = t.Name
+		}
+		return sheets, nil
+	}
+	seen := map[string]bool{}
+	var sheets []string
+	for _, list := range mappings {
+		for _, m := range list {
+			if !seen[m.SheetName] {
+				seen[m.SheetName] = true
+				sheets = append(sheets, m.SheetName)
+			}
+		}
+	}
+	sort.Strings(sheets)
+	return sheets, nil
+}
+
+func printReconcile(w io.Writer, r reconcile.Report) {
+	fmt.Fprintf(w, "  Matched:              %d\n", r.Matched)
+	fmt.Fprintf(w, "  Mismatched:           %d\n", len(r.Mismatched))
+	fmt.Fprintf(w, "  Only in the log:      %d\n", len(r.OnlyInLog))
+	fmt.Fprintf(w, "  Only in the workbook: %d\n", len(r.OnlyInWorkbook))
+	if r.InSync() {
+		fmt.Fprintln(w, "\n✓ The log and the workbook agree.")
+		return
+	}
+
+	if len(r.Mismatched) > 0 {
+		fmt.Fprintln(w, "\nMismatches:")
+	}
+	for _, m := range r.Mismatched {
+		switch m.Field {
+		case reconcile.FieldValue:
+			fmt.Fprintf(w, "  value  %-28s %s  log %s, workbook %s (%s)\n",
+				m.Log.Item, m.Log.Date, brl(m.Log.Value), brl(m.Workbook.Value), workbookCell(m.Workbook))
+		case reconcile.FieldDate:
+			fmt.Fprintf(w, "  date   %-28s %s  log %s, workbook %s (%s)\n",
+				m.Log.Item, brl(m.Log.Value), m.Log.Date, workbookDate(m.Workbook), workbookCell(m.Workbook))
+		}
+	}
+	if len(r.OnlyInLog) > 0 {
+		fmt.Fprintln(w, "\nOnly in the log:")
+	}
+	for _, e := range r.OnlyInLog {
+		fmt.Fprintf(w, "  %s  %-28s %12s  %s/%s  [%s]\n", e.Date, e.Item, brl(e.Value), e.Category, e.Subcategory, e.ID)
+	}
+	if len(r.OnlyInWorkbook) > 0 {
+		fmt.Fprintln(w, "\nOnly in the workbook:")
+	}
+	for _, b := range r.OnlyInWorkbook {
+		fmt.Fprintf(w, "  %s  %-28s %12s  %s/%s  (%s)\n", workbookDate(b), b.Item, brl(b.Value), b.Category, b.Subcategory, workbookCell(b))
+	}
+}
+
+func workbookDate(b excel.WorkbookEntry) string {
+	if b.Date.IsZero() {
+		return "no date"
+	}
+	return utils.FormatDate(b.Date)
+}
+
+func workbookCell(b excel.WorkbookEntry) string {
+	return b.Sheet + "!" + b.Cell
+}
+
+// emitReconcileLog writes workbook-only entries as expense log lines to path.
+func emitReconcileLog(path string, entries []excel.WorkbookEntry, year int) error {
+	for _, b := range entries {
+		if err := feedback.AppendExpense(path, reconcile.ToLogEntry(b, year)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitReconcileCSV writes log-only entries to path as a batch CSV.
+func emitReconcileCSV(path string, entries []feedback.ExpenseEntry, year int) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Entries in the expense log missing from the %d workbook (expense-reporter reconcile)\n", year)
+	for _, e := range entries {
+		b.WriteString(reconcile.ToInsertLine(e))
+		b.WriteByte('\n')
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/reconcile"
+	"expense-reporter/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYearExpenses_ResolvesBeforeFiltering(t *testing.T) {
+	log := store.NewJSONL[feedback.ExpenseEntry](filepath.Join(t.TempDir(), "expenses_log.jsonl"))
+	mercado := feedback.NewExpenseEntry("Mercado", "30/12/2025", 210, "Supermercado", "Alimentação")
+	moved := mercado.Edit()
+	moved.Date = "02/01/2026"
+	spotify := feedback.NewExpenseEntry("Spotify", "05/03/2025", 21.9, "Spotify", "Assinaturas")
+	for _, e := range []feedback.ExpenseEntry{mercado, spotify, moved, spotify.Void()} {
+		require.NoError(t, log.Append(e))
+	}
+	require.NoError(t, log.Append(feedback.NewExpenseEntry("Padaria", "10/02/2025", 12, "Padaria", "Alimentação")))
+
+	got, err := yearExpenses(log, "", 2025)
+	require.NoError(t, err)
+	require.Len(t, got, 1, "the edit moved Mercado to 2026 and Spotify was voided")
+	assert.Equal(t, "Padaria", got[0].Item)
+}
+
+func TestYearExpenses_YearlessDates(t *testing.T) {
+	log := store.NewJSONL[feedback.ExpenseEntry](filepath.Join(t.TempDir(), "expenses_log.jsonl"))
+	// Lines as apply logged them before dates were qualified.
+	ceia := feedback.NewExpenseEntry("Ceia", "28/12/2025", 300, "Restaurante", "Alimentação")
+	ceia.Date, ceia.Timestamp = "28/12", "2026-01-03T09:00:00Z"
+	uber := feedback.NewExpenseEntry("Uber", "15/04/2025", 35.5, "Uber/Taxi", "Transporte")
+	uber.Date, uber.Timestamp = "15/04", "2025-04-20T10:00:00Z"
+	for _, e := range []feedback.ExpenseEntry{ceia, uber} {
+		require.NoError(t, log.Append(e))
+	}
+
+	got, err := yearExpenses(log, "", 2025)
+	require.NoError(t, err)
+	require.Len(t, got, 2, "a DD/MM date takes the year it was logged in")
+	assert.Equal(t, "28/12/2025", got[0].Date, "logged on 03/01/2026, so last December")
+	assert.Equal(t, "15/04/2025", got[1].Date)
+}
+
+func TestPrintAndEmitReconcile(t *testing.T) {
+	dir := t.TempDir()
+	spotify := feedback.NewExpenseEntry("Spotify", "05/03/2025", 21.9, "Spotify", "Assinaturas")
+	spotify.Type = "Fixas"
+	cinema := excel.WorkbookEntry{Sheet: "Variáveis", Category: "Lazer", Subcategory: "Cinema", Item: "Cinema",
+		Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Month: time.January, Value: 40, Cell: "D40"}
+	r := reconcile.Report{Matched: 3, OnlyInLog: []feedback.ExpenseEntry{spotify}, OnlyInWorkbook: []excel.WorkbookEntry{cinema}}
+
+	var out bytes.Buffer
+	printReconcile(&out, r)
+	assert.Contains(t, out.String(), "Only in the log:      1")
+	assert.Contains(t, out.String(), "(Variáveis!D40)")
+
+	csvPath := filepath.Join(dir, "missing.csv")
+	require.NoError(t, emitReconcileCSV(csvPath, r.OnlyInLog, 2025))
+	data, err := os.ReadFile(csvPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Spotify;05/03;21,90;Fixas,Assinaturas,Spotify\n")
+
+	logPath := filepath.Join(dir, "missing.jsonl")
+	require.NoError(t, emitReconcileLog(logPath, r.OnlyInWorkbook, 2025))
+	ids, err := feedback.LoadExpenseIDs(logPath)
+	require.NoError(t, err)
+	assert.True(t, ids[feedback.GenerateID("Cinema", "20/01/2025", 40)])
+}
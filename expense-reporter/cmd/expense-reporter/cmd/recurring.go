@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/recurring"
+	"expense-reporter/internal/tags"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	recurringThrough string
+	recurringDryRun  bool
+	recurringAhead   int
+)
+
+var recurringCmd = &cobra.Command{
+	Use:   "recurring",
+	Short: "Materialize recurring expenses (rent, fees, subscriptions) into the expense log",
+	Long: `The schedule file (recurring_path in config) is a JSON array of recurring
+expenses, each with item, value, day-of-month, frequency (monthly, quarterly,
+semiannual or yearly), start/end (MM/YYYY) and full taxonomy path.`,
+}
+
+var recurringRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Append due occurrences to expenses_log.jsonl",
+	Long: `Appends every scheduled occurrence up to and including the --through month
+that is not already in the expense log. Occurrences use the same ID as a manual
+"add" of the same item, date and value. An occurrence counts as logged when an
+entry with its item and date is in the log, whatever its value, so running twice
+(or after entering one by hand, or after raising a schedule's value) never
+duplicates an expense. Renaming a schedule's item makes its past months due
+again: set "end" on the old schedule and start a new one instead. The tag_rules
+in config tag each occurrence as it is logged.
+
+Examples:
+  expense-reporter recurring run --through 04/2026
+  expense-reporter recurring run --through 12/2026 --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runRecurringRun,
+}
+
+var recurringListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show missed and upcoming recurring occurrences",
+	Long: `Missed occurrences are due on or before today but not in the expense log;
+upcoming ones fall after today, up to --ahead months from now.`,
+	Args: cobra.NoArgs,
+	RunE: runRecurringList,
+}
+
+func init() {
+	rootCmd.AddCommand(recurringCmd)
+	recurringCmd.AddCommand(recurringRunCmd, recurringListCmd)
+	recurringRunCmd.Flags().StringVar(&recurringThrough, "through", "", "Last month to materialize, MM/YYYY (default: current month)")
+	recurringRunCmd.Flags().BoolVar(&recurringDryRun, "dry-run", false, "Show what would be appended without writing")
+	recurringListCmd.Flags().IntVar(&recurringAhead, "ahead", 1, "Months after the current one to include as upcoming")
+}
+
+// loadRecurring loads the configured schedules and checks that each one's
+// type/category/subcategory is a leaf of the taxonomy.
+func loadRecurring(appCfg *config.Config) ([]recurring.Schedule, error) {
+	path := appCfg.RecurringFilePath()
+	if path == "" {
+		return nil, fmt.Errorf("recurring path not configured\n  Hint: set recurring_path in config")
+	}
+	schedules, err := recurring.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	sheets, err := loadTaxonomyTree(appCfg)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := taxonomy.BuildPathMap(sheets)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schedules {
+		if _, ok := pm.PathFor(s.Type, s.Category, s.Subcategory); !ok {
+			return nil, fmt.Errorf("schedule %q: %s/%s/%s is not in the taxonomy", s.Item, s.Type, s.Category, s.Subcategory)
+		}
+	}
+	return schedules, nil
+}
+
+// pendingOccurrences returns the occurrences through the given month whose
+// item and date (Key) are not yet in the expense log at logPath.
+func pendingOccurrences(schedules []recurring.Schedule, through time.Time, logPath string) ([]recurring.Occurrence, error) {
+	logged, err := feedback.LoadExpenseItemDates(logPath)
+	if err != nil {
+		return nil, err
+	}
+	var pending []recurring.Occurrence
+	for _, occ := range recurring.Due(schedules, through) {
+		if !logged[occ.Key] {
+			pending = append(pending, occ)
+		}
+	}
+	return pending, nil
+}
+
+func runRecurringRun(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	through := feedback.Now()
+	if recurringThrough != "" {
+		if through, err = recurring.ParseMonth(recurringThrough); err != nil {
+			return err
+		}
+	}
+	logPath := appCfg.ExpensesLogFilePath()
+	if logPath == "" {
+		return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	schedules, err := loadRecurring(appCfg)
+	if err != nil {
+		return err
+	}
+	if err := appCfg.TagRules.Validate(); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	pending, err := pendingOccurrences(schedules, through, logPath)
+	if err != nil {
+		return err
+	}
+
+	for _, occ := range pending {
+		s := occ.Schedule
+		fmt.Printf("  %s  %-30s %s  → %s/%s/%s\n", utils.FormatDate(occ.Date), s.Item,
+			"R$ "+utils.FormatBRValue(s.Value), s.Type, s.Category, s.Subcategory)
+		if recurringDryRun {
+			continue
+		}
+		occTags := expenseTags(appCfg, nil, tags.Expense{Item: s.Item, Date: occ.Date, Type: s.Type, Category: s.Category, Subcategory: s.Subcategory})
+		if err := appender.ExpandAndAppend(logPath, s.Item, occ.Date, s.Value, 1, s.Type, s.Category, s.Subcategory, appender.WithTags(occTags)); err != nil {
+			return err
+		}
+	}
+
+	verb := "Appended"
+	if recurringDryRun {
+		verb = "Would append"
+	}
+	fmt.Printf("✓ %s %d occurrence(s) through %s\n", verb, len(pending), through.Format("01/2006"))
+	return nil
+}
+
+func runRecurringList(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	schedules, err := loadRecurring(appCfg)
+	if err != nil {
+		return err
+	}
+	now := feedback.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	pending, err := pendingOccurrences(schedules, today.AddDate(0, recurringAhead, 0), appCfg.ExpensesLogFilePath())
+	if err != nil {
+		return err
+	}
+
+	var missed, upcoming []recurring.Occurrence
+	for _, occ := range pending {
+		if occ.Date.After(today) {
+			upcoming = append(upcoming, occ)
+		} else {
+			missed = append(missed, occ)
+		}
+	}
+	printOccurrences("Missed", missed)
+	printOccurrences("Upcoming", upcoming)
+	if len(missed) > 0 {
+		fmt.Println("\nRun 'expense-reporter recurring run' to append missed occurrences.")
+	}
+	return nil
+}
+
+func printOccurrences(title string, occs []recurring.Occurrence) {
+	fmt.Printf("%s (%d):\n", title, len(occs))
+	for _, occ := range occs {
+		fmt.Printf("  %s  %-30s %s\n", utils.FormatDate(occ.Date), occ.Schedule.Item, "R$ "+utils.FormatBRValue(occ.Schedule.Value))
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/recurring"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingOccurrences_SkipsLogged(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	schedules := []recurring.Schedule{{
+		Item: "Condomínio", Value: 850, Day: 10, Frequency: "monthly", Start: "01/2026",
+		Type: "Fixas", Category: "Moradia", Subcategory: "Condomínio",
+	}}
+	through := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	pending, err := pendingOccurrences(schedules, through, logPath)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+
+	// February entered by hand via "add" — same item, date and value.
+	require.NoError(t, appender.ExpandAndAppend(logPath, "Condomínio", time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		850, 1, "Fixas", "Moradia", "Condomínio"))
+
+	pending, err = pendingOccurrences(schedules, through, logPath)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, time.January, pending[0].Date.Month())
+	assert.Equal(t, time.March, pending[1].Date.Month())
+}
+
+// TestPendingOccurrences_ValueChangeKeepsPastMonths raises the rent after two
+// months were logged: only the month not yet logged is due, at the new value.
+func TestPendingOccurrences_ValueChangeKeepsPastMonths(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	rent := recurring.Schedule{
+		Item: "Aluguel", Value: 2500, Day: 5, Frequency: "monthly", Start: "01/2026",
+		Type: "Fixas", Category: "Moradia", Subcategory: "Aluguel",
+	}
+	for _, occ := range rent.Occurrences(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		require.NoError(t, appender.ExpandAndAppend(logPath, rent.Item, occ.Date, rent.Value, 1, rent.Type, rent.Category, rent.Subcategory))
+	}
+
+	rent.Value = 2650
+	pending, err := pendingOccurrences([]recurring.Schedule{rent}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), logPath)
+	require.NoError(t, err)
+	require.Len(t, pending, 1, "January and February stay logged at the old value")
+	assert.Equal(t, time.March, pending[0].Date.Month())
+	assert.Equal(t, 2650.0, pending[0].Schedule.Value)
+}
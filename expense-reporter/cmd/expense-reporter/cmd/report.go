@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/report"
+	"expense-reporter/pkg/utils"
+)
+
+var (
+	reportYear    int
+	reportBy      string
+	reportAccount string
+	reportTag     string
+	reportInvoice bool
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Total the expense log by month and type, category, subcategory, account or tag",
+	Long: `Totals the year's expenses from expenses_log.jsonl (edits and voids applied,
+installments of closed plans left out) month by month, one row per type,
+category, subcategory, account or tag, largest first. By tag, an expense counts
+in the row of each of its tags, so the rows may add up to more than the total.
+
+--account keeps only the expenses paid with that card or account, and --tag
+only those carrying that tag — "--tag viagem --by category" breaks a trip down
+by category. --invoice
+files each card's expenses under the month of the invoice that bills them,
+using the card's closing_day in config, so "--by account --invoice" matches the
+card invoices; accounts without a closing day keep the purchase month.
+
+Examples:
+  expense-reporter report
+  expense-reporter report --by category --year 2025
+  expense-reporter report --by account --invoice
+  expense-reporter report --by tag
+  expense-reporter report --by category --tag viagem-floripa
+  expense-reporter report --by subcategory --account nubank --invoice --json`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().IntVar(&reportYear, "year", time.Now().Year(), "Year to report")
+	reportCmd.Flags().StringVar(&reportBy, "by", report.Dimensions[0], "Group rows by: "+strings.Join(report.Dimensions, ", "))
+	reportCmd.Flags().StringVar(&reportAccount, "account", "", "Only expenses paid with this card or account")
+	reportCmd.Flags().StringVar(&reportTag, "tag", "", "Only expenses carrying this tag")
+	reportCmd.Flags().BoolVar(&reportInvoice, "invoice", false, "Report card expenses in the month of their invoice (cards' closing_day)")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	appCfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := appCfg.ValidateAccount(reportAccount); err != nil {
+		return err
+	}
+	expenses, err := openExpenses(appCfg)
+	if err != nil {
+		return err
+	}
+	if expenses == nil {
+		return fmt.Errorf("expenses log path not configured\n  Hint: set expenses_log_path in config")
+	}
+	// Every year is read: with --invoice, last December's purchases may be
+	// billed on this January's invoice.
+	entries, err := currentExpenses(expenses, appCfg.PlansFilePath())
+	if err != nil {
+		return err
+	}
+
+	opts := report.Options{Year: reportYear, By: reportBy, Account: reportAccount, Tag: strings.TrimSpace(reportTag)}
+	if reportInvoice {
+		opts.ClosingDays = appCfg.ClosingDays()
+	}
+	table, err := report.Build(entries, opts)
+	if err != nil {
+		return err
+	}
+	if outputJSON {
+		return writeIndentedJSON(cmd.OutOrStdout(), table)
+	}
+	printReport(cmd.OutOrStdout(), table)
+	return nil
+}
+
+func printReport(w io.Writer, t report.Table) {
+	title := fmt.Sprintf("Expenses %d by %s", t.Year, t.By)
+	if t.Account != "" {
+		title += ", paid with " + t.Account
+	}
+	if t.Tag != "" {
+		title += ", tagged " + t.Tag
+	}
+	if t.Invoice {
+		title += " (card expenses by invoice month)"
+	}
+	fmt.Fprintln(w, title)
+	if len(t.Rows) == 0 {
+		fmt.Fprintln(w, "\nNo expenses.")
+		return
+	}
+
+	fmt.Fprintf(w, "\n%-24s", strings.ToUpper(t.By[:1])+t.By[1:])
+	for k := range 12 {
+		fmt.Fprintf(w, " %10s", time.Month(k + 1).String()[:3])
+	}
+	fmt.Fprintf(w, " %12s\n", "Total")
+	for _, r := range t.Rows {
+		printReportRow(w, orNone(r.Key), r)
+	}
+	printReportRow(w, t.Total.Key, t.Total)
+}
+
+func printReportRow(w io.Writer, label string, r report.Row) {
+	fmt.Fprintf(w, "%-24s", label)
+	for _, v := range r.Months {
+		fmt.Fprintf(w, " %10s", utils.FormatBRValue(v))
+	}
+	fmt.Fprintf(w, " %12s\n", utils.FormatBRValue(r.Total))
+}
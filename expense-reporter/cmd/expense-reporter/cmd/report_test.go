@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/report"
+)
+
+func TestPrintReport(t *testing.T) {
+	uber := feedback.NewExpenseEntry("Uber", "04/01/2026", 30, "Uber/Taxi", "Transporte")
+	uber.Account = "nubank"
+	feira := feedback.NewExpenseEntry("Feira", "10/02/2026", 80, "Feira", "Alimentação")
+	table, err := report.Build([]feedback.ExpenseEntry{uber, feira}, report.Options{Year: 2026, By: report.ByAccount})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	printReport(&buf, table)
+	out := buf.String()
+
+	assert.Contains(t, out, "Expenses 2026 by account")
+	assert.Regexp(t, `(?m)^Account\s+Jan\s+Feb`, out)
+	assert.Regexp(t, `(?m)^\(none\)\s+0,00\s+80,00`, out, "entries without an account are listed as (none)")
+	assert.Regexp(t, `(?m)^nubank\s+30,00\s+0,00`, out)
+	assert.Regexp(t, `(?m)^Total\s+30,00\s+80,00\s.*110,00$`, out)
+}
+
+func TestPrintReport_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	printReport(&buf, report.Table{Year: 2026, By: report.ByType, Account: "pix", Invoice: true})
+	assert.Equal(t, "Expenses 2026 by type, paid with pix (card expenses by invoice month)\n\nNo expenses.\n", buf.String())
+
+	buf.Reset()
+	printReport(&buf, report.Table{Year: 2026, By: report.ByCategory, Tag: "viagem"})
+	assert.Equal(t, "Expenses 2026 by category, tagged viagem\n\nNo expenses.\n", buf.String())
+}
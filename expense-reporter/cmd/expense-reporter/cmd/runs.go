@@ -0,0 +1,599 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"expense-reporter/internal/batch"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/runs"
+	"expense-reporter/internal/store"
+)
+
+var runsForce bool
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "List, inspect and revert batch-auto and apply runs",
+	Long: `Every batch-auto, apply, apply-income or import-workbook run that writes to the
+logs is recorded in the run journal (runs_path in config), and each entry it
+appends — expense and income log lines, classifications and installment plans —
+carries the run's ID. Run IDs may be abbreviated to any unique prefix.`,
+}
+
+var runsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded runs, most recent first",
+	Args:  cobra.NoArgs,
+	RunE:  runRunsList,
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:   "show <run-id>",
+	Short: "Show a run and the expense entries it wrote",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRunsShow,
+}
+
+var runsRevertCmd = &cobra.Command{
+	Use:   "revert <run-id>",
+	Short: "Undo everything a run wrote",
+	Long: `Voids every expense and income log entry the run appended, puts each
+classification it wrote back to its previous state, marks its installment plans reverted, and — when
+the run took a workbook backup (apply --backup) — restores the workbook from it.
+Nothing is deleted: the logs keep the run's lines followed by the tombstones.
+
+The workbook is only restored if it is unchanged since the run finished; --force
+restores it anyway. Either way the current workbook is backed up first.
+
+Examples:
+  expense-reporter runs revert 20261018T143005
+  expense-reporter runs revert 20261018T143005-3fa1 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRunsRevert,
+}
+
+func init() {
+	rootCmd.AddCommand(runsCmd)
+	runsCmd.AddCommand(runsListCmd, runsShowCmd, runsRevertCmd)
+	runsRevertCmd.Flags().BoolVar(&runsForce, "force", false, "Revert a run still marked running, and restore a workbook modified since the run")
+}
+
+// startRun records the start of a journaled run and returns it. With no
+// journal configured, or when recording fails (a warning is printed), the run
+// has an empty ID and its entries go untagged.
+func startRun(appCfg *config.Config, command, source string) runs.Run {
+	path := appCfg.RunsFilePath()
+	if path == "" {
+		return runs.Run{}
+	}
+	run := runs.Start(command, source)
+	if err := runs.Append(path, run); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  run journal: %v\n", err)
+		return runs.Run{}
+	}
+	return run
+}
+
+// finishRun records how run ended and prints its ID. Non-fatal: warns on stderr
+// if the journal cannot be written.
+func finishRun(w io.Writer, appCfg *config.Config, run runs.Run, runErr error) {
+	if run.ID == "" {
+		return
+	}
+	if err := runs.Append(appCfg.RunsFilePath(), run.Finish(runErr)); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  run journal: %v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "Run %s (undo with: expense-reporter runs revert %s)\n", run.ID, run.ID)
+}
+
+// runTagged wraps a log so every appended record carries a run ID.
+type runTagged[T store.Record] struct {
+	store.Log[T]
+	tag func(T) T
+}
+
+func (l runTagged[T]) Append(rec T) error {
+	return l.Log.Append(l.tag(rec))
+}
+
+// tagClassifications tags every classification appended to log with runID.
+func tagClassifications(log store.Log[feedback.Entry], runID string) store.Log[feedback.Entry] {
+	if log == nil || runID == "" {
+		return log
+	}
+	return runTagged[feedback.Entry]{Log: log, tag: func(e feedback.Entry) feedback.Entry {
+		e.RunID = runID
+		return e
+	}}
+}
+
+// tagExpenses tags every expense entry appended to log with runID.
+func tagExpenses(log store.Log[feedback.ExpenseEntry], runID string) store.Log[feedback.ExpenseEntry] {
+	if log == nil || runID == "" {
+		return log
+	}
+	return runTagged[feedback.ExpenseEntry]{Log: log, tag: func(e feedback.ExpenseEntry) feedback.ExpenseEntry {
+		e.RunID = runID
+		return e
+	}}
+}
+
+// tagIncome tags every income entry appended to log with runID.
+func tagIncome(log store.Log[feedback.IncomeEntry], runID string) store.Log[feedback.IncomeEntry] {
+	if log == nil || runID == "" {
+		return log
+	}
+	return runTagged[feedback.IncomeEntry]{Log: log, tag: func(e feedback.IncomeEntry) feedback.IncomeEntry {
+		e.RunID = runID
+		return e
+	}}
+}
+
+// loadRuns returns the configured journal path and its runs.
+func loadRuns() (*config.Config, string, []runs.Run, error) {
+	appCfg, err := config.Load()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	path := appCfg.RunsFilePath()
+	if path == "" {
+		return nil, "", nil, fmt.Errorf("runs path not configured\n  Hint: set runs_path in config")
+	}
+	all, err := runs.Load(path)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return appCfg, path, all, nil
+}
+
+// runLogs holds the logs a run writes to; either may be nil when unconfigured.
+type runLogs struct {
+	expenses  store.Log[feedback.ExpenseEntry]
+	classif   store.Log[feedback.Entry]
+	income    store.Log[feedback.IncomeEntry]
+	plansPath string
+}
+
+func openRunLogs(appCfg *config.Config) (runLogs, error) {
+	logs := runLogs{plansPath: appCfg.PlansFilePath()}
+	var err error
+	if logs.expenses, err = openExpenses(appCfg); err != nil {
+		return logs, err
+	}
+	if logs.income, err = openIncome(appCfg); err != nil {
+		return logs, err
+	}
+	if appCfg.ClassificationsFilePath() != "" {
+		if logs.classif, err = openClassifications(appCfg); err != nil {
+			return logs, err
+		}
+	}
+	return logs, nil
+}
+
+// runFootprint counts the lines a run appended to each log.
+type runFootprint struct {
+	expenses, classifications, income, plans int
+}
+
+// footprints scans the logs once and counts lines per run ID.
+func footprints(logs runLogs) (map[string]*runFootprint, error) {
+	out := map[string]*runFootprint{}
+	get := func(id string) *runFootprint {
+		if out[id] == nil {
+			out[id] = &runFootprint{}
+		}
+		return out[id]
+	}
+	if logs.expenses != nil {
+		all, err := logs.expenses.Query(store.Query{})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range all {
+			if e.RunID != "" {
+				get(e.RunID).expenses++
+			}
+		}
+	}
+	if logs.classif != nil {
+		all, err := logs.classif.Query(store.Query{})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range all {
+			if e.RunID != "" {
+				get(e.RunID).classifications++
+			}
+		}
+	}
+	if logs.income != nil {
+		all, err := logs.income.Query(store.Query{})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range all {
+			if e.RunID != "" {
+				get(e.RunID).income++
+			}
+		}
+	}
+	if logs.plansPath != "" {
+		plans, err := installment.Load(logs.plansPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range plans {
+			if p.RunID != "" {
+				get(p.RunID).plans++
+			}
+		}
+	}
+	return out, nil
+}
+
+func runRunsList(cmd *cobra.Command, args []string) error {
+	appCfg, _, all, err := loadRuns()
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if len(all) == 0 {
+		fmt.Fprintln(w, "No runs recorded.")
+		return nil
+	}
+	logs, err := openRunLogs(appCfg)
+	if err != nil {
+		return err
+	}
+	counts, err := footprints(logs)
+	if err != nil {
+		return err
+	}
+	for _, r := range all {
+		fp := counts[r.ID]
+		if fp == nil {
+			fp = &runFootprint{}
+		}
+		fmt.Fprintf(w, "  %s  %-10s  %-9s  %4d expenses  %4d classifications  %s\n",
+			r.ID, r.Command, r.Status, fp.expenses, fp.classifications, filepath.Base(r.Source))
+	}
+	return nil
+}
+
+func runRunsShow(cmd *cobra.Command, args []string) error {
+	appCfg, _, all, err := loadRuns()
+	if err != nil {
+		return err
+	}
+	run, err := runs.Find(all, args[0])
+	if err != nil {
+		return err
+	}
+	logs, err := openRunLogs(appCfg)
+	if err != nil {
+		return err
+	}
+	counts, err := footprints(logs)
+	if err != nil {
+		return err
+	}
+	fp := counts[run.ID]
+	if fp == nil {
+		fp = &runFootprint{}
+	}
+
+	w := cmd.OutOrStdout()
+	fmt.Fprintf(w, "Run %s — %s %s\n", run.ID, run.Command, run.Source)
+	fmt.Fprintf(w, "  Status:   %s\n", run.Status)
+	if run.Error != "" {
+		fmt.Fprintf(w, "  Error:    %s\n", run.Error)
+	}
+	fmt.Fprintf(w, "  Started:  %s\n", run.Started)
+	if run.Finished != "" {
+		fmt.Fprintf(w, "  Finished: %s\n", run.Finished)
+	}
+	if run.RevertedAt != "" {
+		fmt.Fprintf(w, "  Reverted: %s\n", run.RevertedAt)
+	}
+	if run.Workbook != "" {
+		backup := "no backup"
+		if run.Backup != "" {
+			backup = "backup " + run.Backup
+		}
+		fmt.Fprintf(w, "  Workbook: %s (%s)\n", run.Workbook, backup)
+	}
+	fmt.Fprintf(w, "  Wrote:    %d expense entries, %d classifications, %d income entries, %d installment plans\n",
+		fp.expenses, fp.classifications, fp.income, fp.plans)
+
+	if logs.expenses == nil || fp.expenses == 0 {
+		return nil
+	}
+	entries, err := logs.expenses.Query(store.Query{})
+	if err != nil {
+		return err
+	}
+	current := map[string]bool{}
+	for _, e := range feedback.ResolveExpenses(entries) {
+		current[e.ID] = true
+	}
+	for _, e := range entries {
+		if e.RunID != run.ID || e.Op != "" {
+			continue
+		}
+		state := ""
+		if !current[e.ID] {
+			state = "  (voided)"
+		}
+		fmt.Fprintf(w, "  %s  %-28s  %-10s  %12s  %s%s\n", e.ID, e.Item, e.Date, brl(e.Value), expensePath(e), state)
+	}
+	return nil
+}
+
+func runRunsRevert(cmd *cobra.Command, args []string) error {
+	appCfg, path, all, err := loadRuns()
+	if err != nil {
+		return err
+	}
+	run, err := runs.Find(all, args[0])
+	if err != nil {
+		return err
+	}
+	logs, err := openRunLogs(appCfg)
+	if err != nil {
+		return err
+	}
+	return revertRun(cmd.OutOrStdout(), path, logs, run, runsForce)
+}
+
+// revertRun undoes run: the workbook first, since that is the step that can
+// refuse, then the logs, then the journal record.
+func revertRun(w io.Writer, journalPath string, logs runLogs, run runs.Run, force bool) error {
+	switch {
+	case run.Status == runs.StatusReverted:
+		return fmt.Errorf("run %s was already reverted at %s", run.ID, run.RevertedAt)
+	case run.Status == runs.StatusRunning && !force:
+		return fmt.Errorf("run %s is still marked running: it may be in progress, or it crashed\n  Hint: pass --force to revert it anyway", run.ID)
+	}
+
+	restoredFrom, safety, err := restoreRunWorkbook(run, force)
+	if err != nil {
+		return err
+	}
+
+	var voided []feedback.ExpenseEntry
+	var shared []string
+	if logs.expenses != nil {
+		if voided, shared, err = revertExpenses(logs.expenses, run.ID); err != nil {
+			return err
+		}
+	}
+	var income int
+	if logs.income != nil {
+		if income, err = revertIncome(logs.income, run.ID); err != nil {
+			return err
+		}
+	}
+	var restored, dropped int
+	if logs.classif != nil {
+		if restored, dropped, err = revertClassifications(logs.classif, run.ID); err != nil {
+			return err
+		}
+	}
+	var plans int
+	if logs.plansPath != "" {
+		if plans, err = revertPlans(logs.plansPath, run.ID); err != nil {
+			return err
+		}
+	}
+	if err := runs.Append(journalPath, run.Revert()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "✓ Reverted run %s (%s %s)\n", run.ID, run.Command, filepath.Base(run.Source))
+	fmt.Fprintf(w, "  Expense entries voided:    %d\n", len(voided))
+	fmt.Fprintf(w, "  Classifications restored:  %d, voided: %d\n", restored, dropped)
+	if income > 0 {
+		fmt.Fprintf(w, "  Income entries voided:     %d\n", income)
+	}
+	if plans > 0 {
+		fmt.Fprintf(w, "  Installment plans reverted: %d\n", plans)
+	}
+	switch {
+	case restoredFrom != "":
+		fmt.Fprintf(w, "  Workbook restored from %s (previous copy saved as %s)\n", filepath.Base(restoredFrom), filepath.Base(safety))
+	case run.Workbook != "":
+		fmt.Fprintf(w, "  note: no workbook backup was taken; %s still holds the run's rows — regenerate it with generate-workbook\n", run.Workbook)
+	}
+	for _, id := range shared {
+		fmt.Fprintf(w, "  ⚠  %s left in place: entries from outside the run share its ID ('history %s' shows them)\n", id, id)
+	}
+	return nil
+}
+
+// restoreRunWorkbook copies the run's backup over its workbook, after backing
+// up the current workbook. It returns the backup restored from ("" when the run
+// took none) and the safety copy. A workbook modified after the run finished is
+// only restored with force.
+func restoreRunWorkbook(run runs.Run, force bool) (restoredFrom, safety string, err error) {
+	if run.Backup == "" || run.Workbook == "" {
+		return "", "", nil
+	}
+	info, err := os.Stat(run.Workbook)
+	if err != nil {
+		return "", "", fmt.Errorf("workbook: %w", err)
+	}
+	// Journal timestamps have second precision; the run's own write lands
+	// within the second it finished.
+	finished, ok := run.FinishedAt()
+	if !force && ok && info.ModTime().After(finished.Add(time.Second)) {
+		return "", "", fmt.Errorf("workbook %s was modified after the run finished (%s)\n  Hint: pass --force to restore the backup anyway; the current workbook is backed up first",
+			run.Workbook, run.Finished)
+	}
+	mgr := batch.NewBackupManager()
+	if safety, err = mgr.CreateBackup(run.Workbook); err != nil {
+		return "", "", fmt.Errorf("backing up current workbook: %w", err)
+	}
+	if err := mgr.RestoreBackup(run.Backup, run.Workbook); err != nil {
+		return "", "", err
+	}
+	return run.Backup, safety, nil
+}
+
+// revertExpenses voids the current state of every entry the run inserted. An
+// ID also inserted from outside the run (an identical expense logged twice)
+// is left alone and returned in shared: voiding it would drop both. A split
+// transaction goes as one: when any of its parts is shared, every part is left
+// and its parent ID returned in shared.
+func revertExpenses(log store.Log[feedback.ExpenseEntry], runID string) (voided []feedback.ExpenseEntry, shared []string, err error) {
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return nil, nil, err
+	}
+	var order []string
+	trails := map[string][]feedback.ExpenseEntry{}
+	for _, e := range all {
+		if _, seen := trails[e.ID]; !seen {
+			order = append(order, e.ID)
+		}
+		trails[e.ID] = append(trails[e.ID], e)
+	}
+	inRun, outside := map[string]bool{}, map[string]bool{}
+	sharedSplit := map[string]bool{} // parent ID → a part is shared
+	for _, id := range order {
+		for _, e := range trails[id] {
+			if e.Op != "" {
+				continue
+			}
+			if e.RunID == runID {
+				inRun[id] = true
+			} else {
+				outside[id] = true
+			}
+		}
+		if parent := trails[id][0].ParentID; parent != "" && inRun[id] && outside[id] {
+			sharedSplit[parent] = true
+		}
+	}
+	reported := map[string]bool{}
+	for _, id := range order {
+		trail := trails[id]
+		if !inRun[id] {
+			continue
+		}
+		if parent := trail[0].ParentID; parent != "" && sharedSplit[parent] {
+			if !reported[parent] {
+				reported[parent] = true
+				shared = append(shared, parent)
+			}
+			continue
+		}
+		if outside[id] || id == "" {
+			shared = append(shared, id)
+			continue
+		}
+		cur, ok := currentExpense(trail)
+		if !ok {
+			continue
+		}
+		if err := log.Append(cur.Void()); err != nil {
+			return voided, shared, fmt.Errorf("appending void: %w", err)
+		}
+		voided = append(voided, cur)
+	}
+	return voided, shared, nil
+}
+
+// revertClassifications puts each ID whose latest line the run wrote back to
+// its last line from outside the run, or voids it when there is none. IDs
+// classified again after the run are left as they are.
+func revertClassifications(log store.Log[feedback.Entry], runID string) (restored, voided int, err error) {
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return 0, 0, err
+	}
+	var order []string
+	trails := map[string][]feedback.Entry{}
+	for _, e := range all {
+		if _, seen := trails[e.ID]; !seen {
+			order = append(order, e.ID)
+		}
+		trails[e.ID] = append(trails[e.ID], e)
+	}
+	for _, id := range order {
+		trail := trails[id]
+		last := trail[len(trail)-1]
+		if last.RunID != runID {
+			continue
+		}
+		var prior *feedback.Entry
+		for i := len(trail) - 1; i >= 0; i-- {
+			if trail[i].RunID != runID {
+				prior = &trail[i]
+				break
+			}
+		}
+		next := last.Void()
+		if prior != nil && !prior.Voided() {
+			next = prior.Restore()
+		}
+		if err := log.Append(next); err != nil {
+			return restored, voided, fmt.Errorf("appending classification: %w", err)
+		}
+		if next.Voided() {
+			voided++
+		} else {
+			restored++
+		}
+	}
+	return restored, voided, nil
+}
+
+// revertIncome voids every income entry the run appended that still counts.
+// Income lines are only written when their ID is not in the log yet, so a run's
+// ID is its own.
+func revertIncome(log store.Log[feedback.IncomeEntry], runID string) (int, error) {
+	all, err := log.Query(store.Query{})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range feedback.ResolveIncome(all) {
+		if e.RunID != runID {
+			continue
+		}
+		if err := log.Append(e.Void()); err != nil {
+			return n, fmt.Errorf("appending income void: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// revertPlans marks every plan the run recorded as reverted.
+func revertPlans(path, runID string) (int, error) {
+	plans, err := installment.Load(path)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, p := range plans {
+		if p.RunID != runID || p.Status == installment.StatusReverted {
+			continue
+		}
+		if err := installment.Append(path, p.Revert()); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/apply"
+	"expense-reporter/internal/classifier"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/runs"
+	"expense-reporter/internal/store"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type runFixture struct {
+	dir     string
+	journal string
+	logs    runLogs
+	run     runs.Run
+}
+
+// newRunFixture records a finished run that inserted Spotify (plus an entry an
+// earlier import also logged), corrected a prior classification, classified a
+// new item, logged a salary line and recorded an installment plan.
+func newRunFixture(t *testing.T) runFixture {
+	t.Helper()
+	dir := t.TempDir()
+	f := runFixture{
+		dir:     dir,
+		journal: filepath.Join(dir, "runs.jsonl"),
+		logs: runLogs{
+			expenses:  store.NewJSONL[feedback.ExpenseEntry](filepath.Join(dir, "expenses_log.jsonl")),
+			classif:   store.NewJSONL[feedback.Entry](filepath.Join(dir, "classifications.jsonl")),
+			income:    store.NewJSONL[feedback.IncomeEntry](filepath.Join(dir, "income_log.jsonl")),
+			plansPath: filepath.Join(dir, "plans.jsonl"),
+		},
+	}
+
+	uber := classifier.Result{Subcategory: "Uber/Taxi", Category: "Transporte", Confidence: 0.9}
+	prior := feedback.NewConfirmedEntry("Uber", "15/04/2026", 35.5, uber, "m")
+	require.NoError(t, f.logs.classif.Append(prior))
+	dentist := feedback.NewExpenseEntry("Dentista", "10/03/2026", 300, "Dentista", "Saúde")
+	require.NoError(t, f.logs.expenses.Append(dentist))
+
+	f.run = runs.Start("apply", "reviewed.json")
+	require.NoError(t, runs.Append(f.journal, f.run))
+	expenses := tagExpenses(f.logs.expenses, f.run.ID)
+	classif := tagClassifications(f.logs.classif, f.run.ID)
+	require.NoError(t, expenses.Append(feedback.NewExpenseEntry("Spotify", "05/03/2026", 21.9, "Spotify", "Assinaturas")))
+	require.NoError(t, expenses.Append(dentist))
+	require.NoError(t, classif.Append(feedback.NewCorrectedEntry("Uber", "15/04/2026", 35.5, uber, "m", "Uber", "Transporte")))
+	require.NoError(t, classif.Append(feedback.NewConfirmedEntry("Padaria", "06/03/2026", 12, classifier.Result{Subcategory: "Padaria"}, "m")))
+	income := tagIncome(f.logs.income, f.run.ID)
+	require.NoError(t, income.Append(feedback.NewIncomeEntry("PIX ACME folha", "05/03/2026", 9500, "Salário", "Salário")))
+
+	sched, err := utils.ParseInstallments("90,00/3")
+	require.NoError(t, err)
+	plan := installment.NewPlan("p1", "Curso", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), sched, "Extras", "Educação", "Cursos")
+	plan.RunID = f.run.ID
+	require.NoError(t, installment.Append(f.logs.plansPath, plan))
+
+	f.run = f.run.Finish(nil)
+	require.NoError(t, runs.Append(f.journal, f.run))
+	return f
+}
+
+func TestTagLogs_StampRunID(t *testing.T) {
+	f := newRunFixture(t)
+	counts, err := footprints(f.logs)
+	require.NoError(t, err)
+	require.Contains(t, counts, f.run.ID)
+	assert.Equal(t, runFootprint{expenses: 2, classifications: 2, income: 1, plans: 1}, *counts[f.run.ID])
+	assert.Len(t, counts, 1, "entries written outside the run stay untagged")
+}
+
+func TestRevertRun(t *testing.T) {
+	f := newRunFixture(t)
+	var out bytes.Buffer
+	require.NoError(t, revertRun(&out, f.journal, f.logs, f.run, false))
+
+	entries, err := f.logs.expenses.Query(store.Query{})
+	require.NoError(t, err)
+	var items []string
+	for _, e := range feedback.ResolveExpenses(entries) {
+		items = append(items, e.Item)
+	}
+	assert.Equal(t, []string{"Dentista", "Dentista"}, items, "Spotify voided; the shared Dentista ID is left alone")
+	assert.Contains(t, out.String(), "Expense entries voided:    1")
+	assert.Contains(t, out.String(), "left in place")
+
+	uber, found, err := f.logs.classif.Latest(feedback.GenerateID("Uber", "15/04/2026", 35.5))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, feedback.StatusConfirmed, uber.Status, "correction undone")
+	assert.Empty(t, uber.RunID)
+	padaria, _, err := f.logs.classif.Latest(feedback.GenerateID("Padaria", "06/03/2026", 12))
+	require.NoError(t, err)
+	assert.True(t, padaria.Voided(), "classified only by the run")
+
+	income, err := f.logs.income.Query(store.Query{})
+	require.NoError(t, err)
+	assert.Empty(t, feedback.ResolveIncome(income), "the salary line is voided")
+	assert.Contains(t, out.String(), "Income entries voided:     1")
+
+	plans, err := installment.Load(f.logs.plansPath)
+	require.NoError(t, err)
+	assert.Equal(t, installment.StatusReverted, plans[0].Status)
+
+	all, err := runs.Load(f.journal)
+	require.NoError(t, err)
+	assert.Equal(t, runs.StatusReverted, all[0].Status)
+	err = revertRun(&out, f.journal, f.logs, all[0], false)
+	assert.ErrorContains(t, err, "already reverted")
+}
+
+func TestRevertRun_RefusesRunningUnlessForced(t *testing.T) {
+	f := newRunFixture(t)
+	running := f.run
+	running.Status = runs.StatusRunning
+
+	err := revertRun(&bytes.Buffer{}, f.journal, f.logs, running, false)
+	assert.ErrorContains(t, err, "still marked running")
+	require.NoError(t, revertRun(&bytes.Buffer{}, f.journal, f.logs, running, true))
+}
+
+func TestRevertExpenses_SplitGoesAsOne(t *testing.T) {
+	log := store.NewJSONL[feedback.ExpenseEntry](filepath.Join(t.TempDir(), "expenses_log.jsonl"))
+	split := func(item string) (feedback.ExpenseEntry, feedback.ExpenseEntry, string) {
+		parent := feedback.GenerateID(item, "06/03/2026", 150)
+		return feedback.NewSplitExpenseEntry(parent, item, "06/03/2026", 100, "Feira", "Alimentação"),
+			feedback.NewSplitExpenseEntry(parent, item, "06/03/2026", 50, "Limpeza", "Casa"), parent
+	}
+	feira, limpeza, shared := split("Mercado")
+	acougue, padaria, _ := split("Açougue")
+	// The Mercado Feira part was logged before the run; the Açougue split only by it.
+	require.NoError(t, log.Append(feira))
+	run := tagExpenses(log, "run-1")
+	for _, e := range []feedback.ExpenseEntry{feira, limpeza, acougue, padaria} {
+		require.NoError(t, run.Append(e))
+	}
+
+	voided, sharedIDs, err := revertExpenses(log, "run-1")
+	require.NoError(t, err)
+	assert.Len(t, voided, 2, "both Açougue parts")
+	assert.Equal(t, []string{shared}, sharedIDs, "the Mercado split is left whole, reported by its parent ID")
+
+	all, err := log.Query(store.Query{})
+	require.NoError(t, err)
+	var live []string
+	for _, e := range feedback.ResolveExpenses(all) {
+		live = append(live, e.Item+"/"+e.Subcategory)
+	}
+	assert.Equal(t, []string{"Mercado/Feira", "Mercado/Feira", "Mercado/Limpeza"}, live)
+}
+
+func TestRestoreRunWorkbook(t *testing.T) {
+	f := newRunFixture(t)
+	workbook := filepath.Join(f.dir, "Planilha.xlsx")
+	backup := filepath.Join(f.dir, "Planilha_backup.xlsx")
+	require.NoError(t, os.WriteFile(backup, []byte("before"), 0o644))
+	require.NoError(t, os.WriteFile(workbook, []byte("after"), 0o644))
+	finished, ok := f.run.FinishedAt()
+	require.True(t, ok)
+	require.NoError(t, os.Chtimes(workbook, finished, finished))
+
+	run := f.run
+	run.Workbook, run.Backup = workbook, backup
+
+	later := finished.Add(time.Hour)
+	require.NoError(t, os.Chtimes(workbook, later, later))
+	_, _, err := restoreRunWorkbook(run, false)
+	assert.ErrorContains(t, err, "modified after the run")
+
+	from, safety, err := restoreRunWorkbook(run, true)
+	require.NoError(t, err)
+	assert.Equal(t, backup, from)
+	got, err := os.ReadFile(workbook)
+	require.NoError(t, err)
+	assert.Equal(t, "before", string(got))
+	saved, err := os.ReadFile(safety)
+	require.NoError(t, err)
+	assert.Equal(t, "after", string(saved), "the current workbook is backed up first")
+}
+
+func TestHandleActiveEntry_VoidedPriorIsNew(t *testing.T) {
+	log := store.NewJSONL[feedback.Entry](filepath.Join(t.TempDir(), "classifications.jsonl"))
+	prior := feedback.NewConfirmedEntry("Padaria", "06/03", 12, classifier.Result{Subcategory: "Padaria"}, "review")
+	require.NoError(t, log.Append(prior))
+	require.NoError(t, log.Append(prior.Void()))
+
+	entry := apply.ReviewedEntry{ID: prior.ID, Item: "Padaria", Date: "06/03", Value: 12, Action: apply.ActionConfirmed}
+	var newRows, corrections []apply.ReviewedEntry
+	require.NoError(t, handleActiveEntry(entry, log, &newRows, &corrections))
+	assert.Len(t, newRows, 1, "a reverted row is inserted again")
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/classifier"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	taxonomy "expense-reporter/internal/taxonomy"
+	"expense-reporter/pkg/utils"
+)
+
+// resolveSplit parses split notation from a subcategory field, checks the parts
+// sum to total and resolves each part's full taxonomy path (same rules as a
+// single subcategory — see resolveFullPath). Part values stay in the input
+// currency. spec is the canonical notation recorded in classifications.jsonl.
+func resolveSplit(sheets []taxonomy.ExpenseType, field string, total float64, installmentCount int,
+	typeFlag string, interactive bool, in io.Reader) (parts []appender.SplitPart, spec string, err error) {
+
+	if installmentCount > 1 {
+		return nil, "", fmt.Errorf("split transactions cannot use installment notation")
+	}
+	shares, err := utils.ParseSplit(field)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := utils.CheckSplitTotal(shares, total); err != nil {
+		return nil, "", err
+	}
+	parts = make([]appender.SplitPart, len(shares))
+	for i, sh := range shares {
+		typ, category, err := resolveFullPath(sheets, sh.Subcategory, typeFlag, interactive, in)
+		if err != nil {
+			return nil, "", err
+		}
+		parts[i] = appender.SplitPart{Type: typ, Category: category, Subcategory: sh.Subcategory, Value: sh.Value}
+	}
+	return parts, utils.FormatSplit(shares), nil
+}
+
+// convertSplit converts each part of a foreign-currency split to BRL in place,
+// attaching the part's own conversion record. A BRL split is left untouched.
+func convertSplit(conv *foreignConverter, currency string, date time.Time, parts []appender.SplitPart) error {
+	for i := range parts {
+		brl, foreign, err := conv.convert(currency, date, parts[i].Value)
+		if err != nil {
+			return err
+		}
+		parts[i].Value = brl
+		parts[i].Foreign = foreign
+	}
+	return nil
+}
+
+// logSplitFeedback records a split line in classifications.jsonl. value is the
+// line's total in the input currency, so the entry ID matches the parts' parent ID.
+// runID tags the entry when a journaled run writes it. Non-fatal: warns on
+// stderr if writing fails.
+func logSplitFeedback(appCfg *config.Config, item, date string, value float64, predicted classifier.Result, model, spec, runID string) {
+	path := appCfg.ClassificationsFilePath()
+	if path == "" {
+		return
+	}
+	entry := feedback.NewSplitEntry(item, date, value, predicted, model, spec)
+	entry.RunID = runID
+	if err := feedback.Append(path, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠  feedback log: %v\n", err)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSplit(t *testing.T) {
+	sheets := addTestSheets()
+	noInput := strings.NewReader("")
+
+	t.Run("resolves each part's full path", func(t *testing.T) {
+		parts, spec, err := resolveSplit(sheets, "Spotify=20,00|Dentista=80,00", 100, 1, "Extras", false, noInput)
+		require.NoError(t, err)
+		require.Len(t, parts, 2)
+		assert.Equal(t, "Fixas", parts[0].Type)
+		assert.Equal(t, "Assinaturas", parts[0].Category)
+		assert.Equal(t, 20.0, parts[0].Value)
+		assert.Equal(t, "Extras", parts[1].Type)
+		assert.Equal(t, "Saúde", parts[1].Category)
+		assert.Equal(t, "Spotify=20,00|Dentista=80,00", spec)
+	})
+
+	t.Run("parts must sum to the value", func(t *testing.T) {
+		_, _, err := resolveSplit(sheets, "Spotify=20,00|Dentista=70,00", 100, 1, "Extras", false, noInput)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sum to")
+	})
+
+	t.Run("installments rejected", func(t *testing.T) {
+		_, _, err := resolveSplit(sheets, "Spotify=20,00|Dentista=80,00", 100, 3, "Extras", false, noInput)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "installment")
+	})
+
+	t.Run("ambiguous part without --type errors", func(t *testing.T) {
+		_, _, err := resolveSplit(sheets, "Spotify=20,00|Dentista=80,00", 100, 1, "", false, noInput)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--type")
+	})
+}
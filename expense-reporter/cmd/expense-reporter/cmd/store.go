@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/store"
+)
+
+// openClassifications opens classifications.jsonl with the configured store
+// backend. The caller checks that the path is configured.
+func openClassifications(appCfg *config.Config) (store.Log[feedback.Entry], error) {
+	return store.Open[feedback.Entry](appCfg.ClassificationsFilePath(), appCfg.Store)
+}
+
+// openExpenses opens expenses_log.jsonl with the configured store backend.
+// It returns a nil log when no expense log path is configured.
+func openExpenses(appCfg *config.Config) (store.Log[feedback.ExpenseEntry], error) {
+	path := appCfg.ExpensesLogFilePath()
+	if path == "" {
+		return nil, nil
+	}
+	return store.Open[feedback.ExpenseEntry](path, appCfg.Store)
+}
+
+// openIncome opens income_log.jsonl with the configured store backend. It
+// returns a nil log when no income log path is configured.
+func openIncome(appCfg *config.Config) (store.Log[feedback.IncomeEntry], error) {
+	path := appCfg.IncomeLogFilePath()
+	if path == "" {
+		return nil, nil
+	}
+	return store.Open[feedback.IncomeEntry](path, appCfg.Store)
+}
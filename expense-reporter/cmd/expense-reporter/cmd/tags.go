@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/config"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
+	"expense-reporter/pkg/utils"
+)
+
+// resolveTags checks the tag rules in config and normalizes the tags given by
+// hand (--tag, the batch column, the review), so a bad rule or tag fails
+// before anything is logged.
+func resolveTags(appCfg *config.Config, given []string) ([]string, error) {
+	if err := appCfg.TagRules.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return tags.Normalize(given)
+}
+
+// expenseTags returns the tags an expense is logged with: given (already
+// normalized) followed by those of every tag rule it matches.
+func expenseTags(appCfg *config.Config, given []string, e tags.Expense) []string {
+	return tags.Merge(given, appCfg.TagRules.Match(e))
+}
+
+// loggedExpense describes a built log entry to the tag rules. An entry whose
+// date does not parse is matched without one.
+func loggedExpense(e feedback.ExpenseEntry) tags.Expense {
+	date, _ := utils.ParseDateFlexible(e.Date)
+	return tags.Expense{Item: e.Item, Date: date, Type: e.Type, Category: e.Category, Subcategory: e.Subcategory, Account: e.Account}
+}
+
+// tagSplitParts sets the tags of each part of a split: given, which apply to
+// the whole line, then those of the rules matching the part's own path.
+func tagSplitParts(appCfg *config.Config, given []string, item string, date time.Time, account string, parts []appender.SplitPart) {
+	for i, p := range parts {
+		parts[i].Tags = expenseTags(appCfg, given, tags.Expense{
+			Item: item, Date: date, Type: p.Type, Category: p.Category, Subcategory: p.Subcategory, Account: account,
+		})
+	}
+}
@@ -5,19 +5,125 @@ import (
 	"time"
 
 	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
 )
 
+// EntryOption decorates each expense entry before it is appended. Options run in
+// order, after the ID has been computed, so they may add context but never
+// change an entry's identity.
+type EntryOption func(*feedback.ExpenseEntry)
+
+// WithForeign attaches the original foreign-currency amount and conversion to
+// every appended entry (per installment when expanding).
+func WithForeign(foreign *feedback.ForeignAmount) EntryOption {
+	return func(e *feedback.ExpenseEntry) {
+		if foreign != nil {
+			fa := *foreign
+			e.Foreign = &fa
+		}
+	}
+}
+
+// WithPlan links every appended entry to the installment plan it was expanded from.
+func WithPlan(planID string) EntryOption {
+	return func(e *feedback.ExpenseEntry) {
+		e.PlanID = planID
+	}
+}
+
+// WithAccount records the card or account every appended entry was paid with.
+func WithAccount(account string) EntryOption {
+	return func(e *feedback.ExpenseEntry) {
+		e.Account = account
+	}
+}
+
+// WithTags adds tags (normalized, see tags.Normalize) to every appended entry,
+// after any it already carries.
+func WithTags(t []string) EntryOption {
+	return func(e *feedback.ExpenseEntry) {
+		e.Tags = tags.Merge(e.Tags, t)
+	}
+}
+
+// WithRun tags every appended entry with the journal ID of the run writing it.
+func WithRun(runID string) EntryOption {
+	return func(e *feedback.ExpenseEntry) {
+		e.RunID = runID
+	}
+}
+
 // ExpandAndAppend expands installments and appends typed expense entries to expenses_log.jsonl.
-func ExpandAndAppend(logPath, item string, date time.Time, perInstallmentValue float64, installmentCount int, expenseType, category, subcategory string) error {
-	if installmentCount <= 1 {
-		entry := buildEntry(item, formatDate(date), perInstallmentValue, expenseType, category, subcategory)
-		return feedback.AppendExpense(logPath, entry)
+func ExpandAndAppend(logPath, item string, date time.Time, perInstallmentValue float64, installmentCount int, expenseType, category, subcategory string, opts ...EntryOption) error {
+	return AppendSchedule(logPath, item, date, equalValues(perInstallmentValue, installmentCount), expenseType, category, subcategory, opts...)
+}
+
+// AppendSchedule appends one entry per payment in values — the real amount of
+// each month, for uneven or interest-bearing installments (see
+// utils.ParseInstallments). A single value is appended as a regular expense.
+func AppendSchedule(logPath, item string, date time.Time, values []float64, expenseType, category, subcategory string, opts ...EntryOption) error {
+	for _, entry := range ExpandEntries(item, date, values, expenseType, category, subcategory, opts...) {
+		if err := feedback.AppendExpense(logPath, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExpandEntries builds the log entries AppendSchedule would write without
+// writing them: one entry for a single payment, or one "item (i/N)" entry per
+// month for installments, day clamped to the month's length.
+func ExpandEntries(item string, date time.Time, values []float64, expenseType, category, subcategory string, opts ...EntryOption) []feedback.ExpenseEntry {
+	if len(values) == 1 {
+		return []feedback.ExpenseEntry{buildEntry(item, formatDate(date), values[0], expenseType, category, subcategory, opts)}
+	}
+
+	entries := make([]feedback.ExpenseEntry, 0, len(values))
+	for i, value := range values {
+		newItem := formatInstallmentItem(item, i+1, len(values))
+		newDate := addMonths(date, i)
+		entries = append(entries, buildEntry(newItem, formatDate(newDate), value, expenseType, category, subcategory, opts))
 	}
+	return entries
+}
+
+// equalValues returns count copies of value (a single one when count <= 1).
+func equalValues(value float64, count int) []float64 {
+	if count < 1 {
+		count = 1
+	}
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = value
+	}
+	return values
+}
 
-	for i := 1; i <= installmentCount; i++ {
-		newItem := formatInstallmentItem(item, i, installmentCount)
-		newDate := addMonths(date, i-1)
-		entry := buildEntry(newItem, formatDate(newDate), perInstallmentValue, expenseType, category, subcategory)
+// SplitPart is one share of a split transaction, already resolved to its full
+// taxonomy path. Foreign is the part's own conversion record, if any.
+type SplitPart struct {
+	Type        string
+	Category    string
+	Subcategory string
+	Value       float64
+	Foreign     *feedback.ForeignAmount
+	Tags        []string // the part's own tags (from the tag rules), before opts add more
+}
+
+// AppendSplit appends one log entry per part of a split transaction. Every part
+// carries parentID (the classifications.jsonl ID of the original line) and an ID
+// derived from it, so the parts read as one transaction. Split transactions are
+// single-payment: installments are expanded before splitting, never after.
+func AppendSplit(logPath, item string, date time.Time, parentID string, parts []SplitPart, opts ...EntryOption) error {
+	dateStr := formatDate(date)
+	for _, p := range parts {
+		entry := feedback.NewSplitExpenseEntry(parentID, item, dateStr, p.Value, p.Subcategory, p.Category)
+		entry.Type = p.Type
+		WithForeign(p.Foreign)(&entry)
+		WithTags(p.Tags)(&entry)
+		for _, opt := range opts {
+			opt(&entry)
+		}
 		if err := feedback.AppendExpense(logPath, entry); err != nil {
 			return err
 		}
@@ -56,8 +162,11 @@ func formatDate(t time.Time) string {
 	return t.Format("02/01/2006")
 }
 
-func buildEntry(item, dateStr string, value float64, expenseType, category, subcategory string) feedback.ExpenseEntry {
+func buildEntry(item, dateStr string, value float64, expenseType, category, subcategory string, opts []EntryOption) feedback.ExpenseEntry {
 	entry := feedback.NewExpenseEntry(item, dateStr, value, subcategory, category)
 	entry.Type = expenseType
+	for _, opt := range opts {
+		opt(&entry)
+	}
 	return entry
 }
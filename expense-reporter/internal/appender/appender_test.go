@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"expense-reporter/internal/feedback"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -136,3 +139,81 @@ func TestFormatDate(t *testing.T) {
 	result := formatDate(date)
 	assert.Equal(t, "05/03/2026", result)
 }
+
+func TestExpandAndAppend_WithForeignTagsEveryInstallment(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "expenses_log.jsonl")
+
+	foreign := &feedback.ForeignAmount{Currency: "USD", Amount: 10, Rate: 5.10, RateDate: "17/04/2026", IOFRate: 0.035, Card: "nubank"}
+	err := ExpandAndAppend(logPath, "Steam", time.Date(2026, 4, 17, 0, 0, 0, 0, time.UTC), 52.79, 2, "Variáveis", "Lazer", "Jogos", WithForeign(foreign))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	for _, line := range lines {
+		var entry feedback.ExpenseEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, 52.79, entry.Value, "value stays the converted BRL amount")
+		require.NotNil(t, entry.Foreign)
+		assert.Equal(t, *foreign, *entry.Foreign)
+	}
+}
+
+func TestAppendSplit_SharesParentID(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "expenses_log.jsonl")
+
+	parentID := feedback.GenerateID("Carrefour", "03/01/2026", 150.00)
+	parts := []SplitPart{
+		{Type: "Variáveis", Category: "Alimentação", Subcategory: "Supermercado", Value: 120.00},
+		{Type: "Variáveis", Category: "Casa", Subcategory: "Limpeza", Value: 30.00},
+	}
+	err := AppendSplit(logPath, "Carrefour", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC), parentID, parts)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	ids := map[string]bool{}
+	for i, line := range lines {
+		var entry feedback.ExpenseEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, "Carrefour", entry.Item)
+		assert.Equal(t, "03/01/2026", entry.Date)
+		assert.Equal(t, parentID, entry.ParentID)
+		assert.Equal(t, parts[i].Subcategory, entry.Subcategory)
+		assert.Equal(t, parts[i].Category, entry.Category)
+		assert.Equal(t, parts[i].Type, entry.Type)
+		assert.Equal(t, parts[i].Value, entry.Value)
+		ids[entry.ID] = true
+	}
+	assert.Len(t, ids, 2, "each part gets its own ID")
+}
+
+func TestAppendSchedule_UnevenValuesPerMonth(t *testing.T) {
+	tempDir := t.TempDir()
+	logPath := filepath.Join(tempDir, "expenses_log.jsonl")
+
+	values := []float64{500, 250, 250}
+	err := AppendSchedule(logPath, "Sofá", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), values, "Extras", "Casa", "Móveis", WithPlan("plan123"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	wantDates := []string{"31/01/2026", "28/02/2026", "31/03/2026"}
+	for i, line := range lines {
+		var entry feedback.ExpenseEntry
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, values[i], entry.Value)
+		assert.Equal(t, wantDates[i], entry.Date)
+		assert.Equal(t, "plan123", entry.PlanID)
+	}
+}
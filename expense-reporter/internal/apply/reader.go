@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"expense-reporter/pkg/utils"
 )
 
 // ReadReviewed reads and validates the reviewed JSON file at path.
@@ -26,19 +28,30 @@ func ReadReviewed(path string) (ReviewedFile, error) {
 	return reviewed, nil
 }
 
-// validateEntries checks that each entry has a valid Action.
+// validateEntries checks that each entry has a valid Action, and that a split
+// entry has at least two parts summing to its value.
 func validateEntries(entries []ReviewedEntry) error {
 	validActions := map[string]bool{
 		ActionConfirmed: true,
 		ActionCorrected: true,
 		ActionSkipped:   true,
 		ActionPending:   true,
+		ActionSplit:     true,
 	}
 
 	for i, entry := range entries {
 		if !validActions[entry.Action] {
 			return fmt.Errorf("entry %d: unknown action %q", i, entry.Action)
 		}
+		if entry.Action != ActionSplit {
+			continue
+		}
+		if len(entry.Split) < 2 {
+			return fmt.Errorf("entry %d: split needs at least two parts, got %d", i, len(entry.Split))
+		}
+		if err := utils.CheckSplitTotal(entry.SplitSpec(), entry.Value); err != nil {
+			return fmt.Errorf("entry %d: %w", i, err)
+		}
 	}
 
 	return nil
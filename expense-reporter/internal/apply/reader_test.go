@@ -117,6 +117,42 @@ func TestReadReviewed(t *testing.T) {
 	})
 }
 
+func TestReadReviewed_Split(t *testing.T) {
+	write := func(t *testing.T, split string) string {
+		path := filepath.Join(t.TempDir(), "reviewed.json")
+		content := `{"reviewedAt":"2026-05-29T10:00:00Z","source":"classified.csv","entries":[
+  {"id":"a1b2c3d4e5f6","item":"Carrefour","date":"03/01","value":150.00,"confidence":0.7,
+   "predicted":{"category":"Alimentação","subcategory":"Supermercado"},"action":"split","reviewed":null,
+   "split":` + split + `}]}`
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("valid split", func(t *testing.T) {
+		rf, err := ReadReviewed(write(t, `[
+      {"type":"Variáveis","category":"Alimentação","subcategory":"Supermercado","value":120.00},
+      {"type":"Variáveis","category":"Casa","subcategory":"Limpeza","value":30.00}]`))
+		require.NoError(t, err)
+		require.Len(t, rf.Entries[0].Split, 2)
+		assert.Equal(t, "Limpeza", rf.Entries[0].Split[1].Subcategory)
+		assert.Equal(t, 30.0, rf.Entries[0].Split[1].Value)
+	})
+
+	t.Run("parts not summing to value rejected", func(t *testing.T) {
+		_, err := ReadReviewed(write(t, `[
+      {"type":"Variáveis","category":"Alimentação","subcategory":"Supermercado","value":120.00},
+      {"type":"Variáveis","category":"Casa","subcategory":"Limpeza","value":20.00}]`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sum to")
+	})
+
+	t.Run("single part rejected", func(t *testing.T) {
+		_, err := ReadReviewed(write(t, `[{"type":"Variáveis","category":"Alimentação","subcategory":"Supermercado","value":150.00}]`))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at least two")
+	})
+}
+
 func TestReviewedLocation_TypeFieldUnmarshal(t *testing.T) {
 	tests := []struct {
 		name       string
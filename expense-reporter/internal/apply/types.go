@@ -2,6 +2,8 @@ package apply
 
 import (
 	"encoding/json"
+
+	"expense-reporter/pkg/utils"
 )
 
 // Action constants for reviewed entries
@@ -10,6 +12,7 @@ const (
 	ActionCorrected = "corrected"
 	ActionSkipped   = "skipped"
 	ActionPending   = "pending"
+	ActionSplit     = "split"
 )
 
 // ReviewedFile represents the top-level structure of the reviewed JSON file
@@ -25,10 +28,33 @@ type ReviewedEntry struct {
 	Item       string            `json:"item"`
 	Date       string            `json:"date"`
 	Value      float64           `json:"value"`
+	Currency   string            `json:"currency,omitempty"` // ISO code of Value; "" = BRL
+	Account    string            `json:"account,omitempty"`  // card or account that paid; "" = not recorded
+	Tags       []string          `json:"tags,omitempty"`     // free-form tags set on the review page
 	Confidence float64           `json:"confidence"`
 	Predicted  ReviewedLocation  `json:"predicted"`
 	Action     string            `json:"action"`
 	Reviewed   *ReviewedLocation `json:"reviewed"`
+	Split      []SplitPart       `json:"split,omitempty"` // set when Action is split
+}
+
+// SplitPart is one part of an entry the user split across subcategories.
+// Value is in the entry's currency; the parts sum to the entry's Value.
+type SplitPart struct {
+	Type        string  `json:"type"`
+	Category    string  `json:"category"`
+	Subcategory string  `json:"subcategory"`
+	Value       float64 `json:"value"`
+}
+
+// SplitSpec returns the split as utils.SplitPart values, for validation and for
+// rendering the notation recorded in classifications.jsonl.
+func (e *ReviewedEntry) SplitSpec() []utils.SplitPart {
+	parts := make([]utils.SplitPart, len(e.Split))
+	for i, p := range e.Split {
+		parts[i] = utils.SplitPart{Subcategory: p.Subcategory, Value: p.Value}
+	}
+	return parts
 }
 
 // ReviewedLocation represents a type/category/subcategory triple
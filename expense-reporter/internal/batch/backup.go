@@ -82,3 +82,16 @@ func (b *BackupManager) copyFile(src, dst string) error {
 
 	return nil
 }
+
+// RestoreBackup copies a backup created by CreateBackup back over the workbook
+func (b *BackupManager) RestoreBackup(backupPath, workbookPath string) error {
+	if _, err := os.Stat(backupPath); err != nil {
+		return fmt.Errorf("backup file does not exist: %w", err)
+	}
+
+	if err := b.copyFile(backupPath, workbookPath); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	return nil
+}
@@ -303,3 +303,37 @@ func TestBackupManager_MultipleBackups(t *testing.T) {
 		t.Errorf("Second backup content = %q, want %q", string(content2), "modified")
 	}
 }
+
+// Test restoring a backup overwrites the workbook with the backed-up content
+func TestBackupManager_RestoreBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	sourceFile := filepath.Join(tmpDir, "test.xlsx")
+
+	if err := os.WriteFile(sourceFile, []byte("before"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	manager := NewBackupManager()
+	backupPath, err := manager.CreateBackup(sourceFile)
+	if err != nil {
+		t.Fatalf("BackupManager.CreateBackup() error = %v", err)
+	}
+	if err := os.WriteFile(sourceFile, []byte("after"), 0644); err != nil {
+		t.Fatalf("Failed to modify test file: %v", err)
+	}
+
+	if err := manager.RestoreBackup(backupPath, sourceFile); err != nil {
+		t.Fatalf("BackupManager.RestoreBackup() error = %v", err)
+	}
+	got, err := os.ReadFile(sourceFile)
+	if err != nil {
+		t.Fatalf("Failed to read restored file: %v", err)
+	}
+	if string(got) != "before" {
+		t.Errorf("Restored content = %q, want %q", got, "before")
+	}
+
+	if err := manager.RestoreBackup(filepath.Join(tmpDir, "missing.xlsx"), sourceFile); err == nil {
+		t.Error("RestoreBackup() should fail for a missing backup")
+	}
+}
@@ -0,0 +1,136 @@
+package classifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+	taxonomy "expense-reporter/internal/taxonomy"
+)
+
+// leafNameScore is the confidence given to a revenue leaf whose block or label
+// name appears in the item: a hint worth reviewing, below any sane
+// auto-insert threshold.
+const leafNameScore = 0.6
+
+// IncomeResult is a single income classification candidate: a revenue leaf of
+// the taxonomy's incomeCategories.
+type IncomeResult struct {
+	Block      string
+	Label      string
+	Confidence float64
+}
+
+// IncomeExample is a past income line with the leaf it was filed under.
+type IncomeExample struct {
+	Item  string
+	Block string
+	Label string
+}
+
+// ClassifyIncome ranks the revenue leaves for a credit line without a model
+// call: income lines repeat (the same payer every month), so the income log is
+// the best predictor. A leaf scores the best token overlap (Jaccard) between
+// item and its past lines, or leafNameScore when its block or label name
+// appears in item. Leaves scoring zero are dropped; the rest come best first.
+func ClassifyIncome(item string, blocks []taxonomy.RevenueBlock, history []IncomeExample) []IncomeResult {
+	tokens := tokenSet(item)
+	key := func(block, label string) string { return strings.ToLower(block) + "\x00" + strings.ToLower(label) }
+
+	scores := map[string]float64{}
+	for _, ex := range history {
+		k := key(ex.Block, ex.Label)
+		scores[k] = max(scores[k], jaccard(tokens, tokenSet(ex.Item)))
+	}
+
+	var results []IncomeResult
+	for _, b := range blocks {
+		score := scores[key(b.Block, b.Label)]
+		for t := range tokenSet(b.Block + " " + b.Label) {
+			if tokens[t] {
+				score = max(score, leafNameScore)
+			}
+		}
+		if score > 0 {
+			results = append(results, IncomeResult{Block: b.Block, Label: b.Label, Confidence: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Confidence > results[j].Confidence })
+	return results
+}
+
+// LoadIncomeExamples reads the past lines of income_log.jsonl (closed years
+// included), leaving out voided entries. Returns nil, nil if the file does not
+// exist.
+func LoadIncomeExamples(path string) ([]IncomeExample, error) {
+	file, err := jsonlog.OpenYears(path, 0, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening income log: %w", err)
+	}
+	defer file.Close()
+
+	type logRow struct {
+		ID             string `json:"id"`
+		Op             string `json:"op"`
+		IncomeCategory string `json:"income_category"`
+		IncomeLabel    string `json:"income_label"`
+		ItemNote       string `json:"item_note"`
+	}
+	var rows []logRow
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var row logRow
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("parsing income log line: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading income log: %w", err)
+	}
+
+	var examples []IncomeExample
+	for _, row := range logschema.Resolve(rows, func(r logRow) string { return r.ID }, func(r logRow) string { return r.Op }) {
+		if row.ItemNote == "" {
+			continue
+		}
+		examples = append(examples, IncomeExample{Item: row.ItemNote, Block: row.IncomeCategory, Label: row.IncomeLabel})
+	}
+	return examples, nil
+}
+
+// tokenSet returns the distinct tokens of s (see tokenize).
+func tokenSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range tokenize(s) {
+		set[t] = true
+	}
+	return set
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|, 0 when both are empty.
+func jaccard(a, b map[string]bool) float64 {
+	inter := 0
+	for t := range a {
+		if b[t] {
+			inter++
+		}
+	}
+	union := len(a) + len(b) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
@@ -0,0 +1,52 @@
+package classifier
+
+import (
+	"path/filepath"
+	"testing"
+
+	taxonomy "expense-reporter/internal/taxonomy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyIncome(t *testing.T) {
+	blocks := []taxonomy.RevenueBlock{
+		{Block: "Salário", Label: "Salário"},
+		{Block: "Dividendos", Label: "Dividendos"},
+		{Block: "Aluguel", Label: "Aluguel"},
+	}
+	history := []IncomeExample{
+		{Item: "PIX ACME LTDA folha", Block: "Salário", Label: "Salário"},
+		{Item: "Rendimento ITSA4", Block: "Dividendos", Label: "Dividendos"},
+	}
+
+	got := ClassifyIncome("PIX ACME LTDA folha", blocks, history)
+	require.NotEmpty(t, got)
+	assert.Equal(t, "Salário", got[0].Block)
+	assert.Equal(t, 1.0, got[0].Confidence, "a repeated payer matches its past line exactly")
+
+	got = ClassifyIncome("Aluguel apto 42", blocks, history)
+	require.Len(t, got, 1)
+	assert.Equal(t, "Aluguel", got[0].Label)
+	assert.Equal(t, leafNameScore, got[0].Confidence, "a leaf name alone is only a hint")
+
+	assert.Empty(t, ClassifyIncome("TED recebida", blocks, history))
+}
+
+func TestLoadIncomeExamples(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := LoadIncomeExamples(filepath.Join(dir, "missing.jsonl"))
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	path := filepath.Join(dir, "income_log.jsonl")
+	writeFile(t, path, `{"id":"a","item_note":"Salário março","date":"05/03/2026","value":9500,"income_category":"Salário","income_label":"Salário"}
+
+{"id":"b","item_note":"","date":"05/03/2026","value":1,"income_category":"Salário","income_label":"INSS"}
+`)
+	got, err = LoadIncomeExamples(path)
+	require.NoError(t, err)
+	assert.Equal(t, []IncomeExample{{Item: "Salário março", Block: "Salário", Label: "Salário"}}, got)
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
 )
 
 // LoadTrainingExamples reads training examples from <dataDir>/training_data_complete.json.
@@ -67,9 +70,11 @@ func typeHintFromSource(source string) string {
 
 // LoadFeedbackExamples reads confirmed/corrected examples from a classifications.jsonl file.
 // Returns nil, nil if the file does not exist (cold start).
-// Lines with status "manual" are skipped.
+// Lines with status "manual" are skipped, and so are IDs voided or restored by
+// a later line (a reverted run) — only their current line counts. Closed years
+// archived next to the file are read too.
 func LoadFeedbackExamples(path string) ([]Example, error) {
-	file, err := os.Open(path)
+	file, err := jsonlog.OpenYears(path, 0, 0)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -78,7 +83,7 @@ func LoadFeedbackExamples(path string) ([]Example, error) {
 	}
 	defer file.Close()
 
-	var examples []Example
+	var entries []feedbackLine
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -86,21 +91,25 @@ func LoadFeedbackExamples(path string) ([]Example, error) {
 			continue
 		}
 
-		var entry struct {
-			Item                 string  `json:"item"`
-			Date                 string  `json:"date"`
-			Value                float64 `json:"value"`
-			PredictedSubcategory string  `json:"predicted_subcategory"`
-			PredictedCategory    string  `json:"predicted_category"`
-			ActualSubcategory    string  `json:"actual_subcategory"`
-			ActualCategory       string  `json:"actual_category"`
-			Status               string  `json:"status"`
+		upgraded, _, err := logschema.Classifications.Upgrade([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("parsing feedback line: %w", err)
 		}
-
-		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		var entry feedbackLine
+		if err := json.Unmarshal(upgraded, &entry); err != nil {
 			return nil, fmt.Errorf("parsing feedback line: %w", err)
 		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading feedback file: %w", err)
+	}
+	entries = logschema.Resolve(entries,
+		func(e feedbackLine) string { return e.ID },
+		func(e feedbackLine) string { return e.Op })
 
+	var examples []Example
+	for _, entry := range entries {
 		switch entry.Status {
 		case "manual":
 			continue
@@ -124,13 +133,23 @@ func LoadFeedbackExamples(path string) ([]Example, error) {
 			})
 		}
 	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading feedback file: %w", err)
-	}
 	return examples, nil
 }
 
+// feedbackLine is the part of a classifications.jsonl line the loader reads.
+type feedbackLine struct {
+	ID                   string  `json:"id"`
+	Item                 string  `json:"item"`
+	Date                 string  `json:"date"`
+	Value                float64 `json:"value"`
+	PredictedSubcategory string  `json:"predicted_subcategory"`
+	PredictedCategory    string  `json:"predicted_category"`
+	ActualSubcategory    string  `json:"actual_subcategory"`
+	ActualCategory       string  `json:"actual_category"`
+	Status               string  `json:"status"`
+	Op                   string  `json:"op"`
+}
+
 // LoadKeywordIndex reads the keyword index from <dataDir>/feature_dictionary_enhanced.json.
 // Returns an error if the file does not exist (keywords are required).
 func LoadKeywordIndex(dataDir string) (KeywordIndex, error) {
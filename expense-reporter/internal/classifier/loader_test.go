@@ -3,6 +3,7 @@ package classifier
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,6 +91,7 @@ func TestLoadTrainingExamples(t *testing.T) {
 func TestLoadFeedbackExamples(t *testing.T) {
 	const confirmedLine = `{"id":"a1","item":"Uber Centro","date":"15/04","value":35.50,"predicted_subcategory":"Uber","predicted_category":"Transporte","actual_subcategory":"Uber","actual_category":"Transporte","confidence":0.92,"status":"confirmed","model":"m","timestamp":"t"}`
 	const correctedLine = `{"id":"a2","item":"Rappi","date":"10/03","value":25.00,"predicted_subcategory":"Restaurante","predicted_category":"Alimentação","actual_subcategory":"Delivery","actual_category":"Alimentação","confidence":0.70,"status":"corrected","model":"m","timestamp":"t"}`
+	const splitLine = `{"id":"a4","item":"Carrefour","date":"03/01","value":150.00,"predicted_subcategory":"Supermercado","predicted_category":"Alimentação","actual_subcategory":"Supermercado=120,00|Limpeza=30,00","actual_category":"","confidence":0.71,"status":"split","model":"review","timestamp":"t"}`
 	const manualLine = `{"id":"a3","item":"Manual entry","date":"01/01","value":100.00,"predicted_subcategory":"","predicted_category":"","actual_subcategory":"Aluguel","actual_category":"Habitação","confidence":0.0,"status":"manual","model":"","timestamp":"t"}`
 
 	tests := []struct {
@@ -117,11 +119,26 @@ func TestLoadFeedbackExamples(t *testing.T) {
 			name:    "missing file returns nil nil",
 			wantNil: true,
 		},
+		{
+			name:    "split entries skipped — the item maps to no single subcategory",
+			content: confirmedLine + "\n" + splitLine + "\n",
+			wantLen: 1,
+		},
 		{
 			name:    "blank lines skipped",
 			content: confirmedLine + "\n\n" + correctedLine + "\n\n",
 			wantLen: 2,
 		},
+		{
+			name: "voided and restored IDs count only their current line",
+			content: confirmedLine + "\n" + correctedLine + "\n" +
+				strings.Replace(correctedLine, `"status":"corrected"`, `"status":"corrected","op":"void"`, 1) + "\n" +
+				strings.Replace(confirmedLine, `"actual_subcategory":"Uber"`, `"actual_subcategory":"Uber","op":"edit"`, 1) + "\n",
+			wantLen: 1,
+			check: func(t *testing.T, result []Example) {
+				assert.Equal(t, "Uber Centro", result[0].Item)
+			},
+		},
 	}
 
 	for _, tt := range tests {
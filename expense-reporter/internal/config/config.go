@@ -2,85 +2,194 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+
+	"expense-reporter/internal/tags"
 )
 
 // Config holds application-wide settings loaded from config/config.json.
 type Config struct {
-	WorkbookPath        string   `json:"workbook_path"`
-	ReferenceSheet      string   `json:"reference_sheet"`
-	DateYear            int      `json:"date_year"`
-	Verbose             bool     `json:"verbose"`
-	AutoInsertExcluded  []string `json:"auto_insert_excluded"`
-	ClassificationsPath string   `json:"classifications_path"`
-	ExpensesLogPath     string   `json:"expenses_log_path"`
-	TaxonomyPath        string   `json:"taxonomy_path"`
+	WorkbookPath        string                `json:"workbook_path"`
+	ReferenceSheet      string                `json:"reference_sheet"`
+	DateYear            int                   `json:"date_year"`
+	Verbose             bool                  `json:"verbose"`
+	AutoInsertExcluded  []string              `json:"auto_insert_excluded"`
+	ClassificationsPath string                `json:"classifications_path"`
+	ExpensesLogPath     string                `json:"expenses_log_path"`
+	IncomeLogPath       string                `json:"income_log_path"`
+	InvestmentsLogPath  string                `json:"investments_log_path"`
+	TaxonomyPath        string                `json:"taxonomy_path"`
+	RatesPath           string                `json:"rates_path"`
+	RecurringPath       string                `json:"recurring_path"`
+	PlansPath           string                `json:"plans_path"`
+	RunsPath            string                `json:"runs_path"`
+	Store               string                `json:"store"`
+	Cards               map[string]CardConfig `json:"cards"`
+
+	// Accounts names the payment accounts that are not cards (debit, PIX, cash).
+	// Together with the card names they are the accounts an expense may carry.
+	Accounts []string `json:"accounts"`
+
+	// TagRules tag every expense they match as it is logged (see tags.Rule).
+	TagRules tags.Rules `json:"tag_rules"`
+}
+
+// CardConfig holds per-card settings. IOFRate is the IOF surcharge the issuer
+// adds to foreign-currency purchases, as a fraction (0.035 = 3.5%). ClosingDay
+// is the day of the month the invoice closes: purchases after it are billed on
+// the next month's invoice. 0 means the invoice follows the calendar month.
+type CardConfig struct {
+	IOFRate    float64 `json:"iof_rate"`
+	ClosingDay int     `json:"closing_day,omitempty"`
+}
+
+// IOFRateFor returns the IOF surcharge configured for card. An empty card name
+// means "no card" and yields 0; a name missing from the config is an error so a
+// typo never silently drops the surcharge.
+func (c *Config) IOFRateFor(card string) (float64, error) {
+	if card == "" {
+		return 0, nil
+	}
+	cc, ok := c.Cards[card]
+	if !ok {
+		known := make([]string, 0, len(c.Cards))
+		for name := range c.Cards {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		return 0, fmt.Errorf("card %q is not configured (known cards: %s)", card, strings.Join(known, ", "))
+	}
+	return cc.IOFRate, nil
+}
+
+// ValidateAccount checks that account names a configured card or account. An
+// empty name means "no account" and is valid; an unknown one is an error, so a
+// typo never files expenses under an account no invoice will match.
+func (c *Config) ValidateAccount(account string) error {
+	if account == "" {
+		return nil
+	}
+	known := c.AccountNames()
+	for _, name := range known {
+		if name == account {
+			return nil
+		}
+	}
+	if len(known) == 0 {
+		return fmt.Errorf("account %q is not configured\n  Hint: list it under \"accounts\" (or \"cards\") in config", account)
+	}
+	return fmt.Errorf("account %q is not configured (known accounts: %s)", account, strings.Join(known, ", "))
+}
+
+// AccountNames returns the configured cards and accounts, sorted.
+func (c *Config) AccountNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for name := range c.Cards {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for _, name := range c.Accounts {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ClosingDays returns each card's invoice closing day, for the cards that
+// configure one.
+func (c *Config) ClosingDays() map[string]int {
+	days := map[string]int{}
+	for name, cc := range c.Cards {
+		if cc.ClosingDay > 0 {
+			days[name] = cc.ClosingDay
+		}
+	}
+	return days
 }
 
 // TaxonomyFilePath returns the absolute path to the taxonomy JSON file.
 // Same resolution logic as ClassificationsFilePath.
 func (c *Config) TaxonomyFilePath() string {
-	if c.TaxonomyPath == "" {
-		return ""
-	}
-	if filepath.IsAbs(c.TaxonomyPath) {
-		return c.TaxonomyPath
-	}
-	exe, err := os.Executable()
-	if err != nil {
-		return c.TaxonomyPath
-	}
-	return filepath.Join(filepath.Dir(exe), c.TaxonomyPath)
+	return resolvePath(c.TaxonomyPath)
 }
 
 // WorkbookFilePath returns the absolute path to the Excel workbook.
 // Same resolution logic as ClassificationsFilePath.
 func (c *Config) WorkbookFilePath() string {
-	if c.WorkbookPath == "" {
-		return ""
-	}
-	if filepath.IsAbs(c.WorkbookPath) {
-		return c.WorkbookPath
-	}
-	exe, err := os.Executable()
-	if err != nil {
-		return c.WorkbookPath
-	}
-	return filepath.Join(filepath.Dir(exe), c.WorkbookPath)
+	return resolvePath(c.WorkbookPath)
 }
 
 // ExpensesLogFilePath returns the absolute path to expenses_log.jsonl.
 // Same resolution logic as ClassificationsFilePath.
 func (c *Config) ExpensesLogFilePath() string {
-	if c.ExpensesLogPath == "" {
-		return ""
-	}
-	if filepath.IsAbs(c.ExpensesLogPath) {
-		return c.ExpensesLogPath
-	}
-	exe, err := os.Executable()
-	if err != nil {
-		return c.ExpensesLogPath
-	}
-	return filepath.Join(filepath.Dir(exe), c.ExpensesLogPath)
+	return resolvePath(c.ExpensesLogPath)
+}
+
+// IncomeLogFilePath returns the absolute path to income_log.jsonl.
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) IncomeLogFilePath() string {
+	return resolvePath(c.IncomeLogPath)
+}
+
+// InvestmentsLogFilePath returns the absolute path to investments_log.jsonl.
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) InvestmentsLogFilePath() string {
+	return resolvePath(c.InvestmentsLogPath)
+}
+
+// RatesFilePath returns the absolute path to the exchange-rate table (rates.json).
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) RatesFilePath() string {
+	return resolvePath(c.RatesPath)
+}
+
+// RecurringFilePath returns the absolute path to the recurring schedule file.
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) RecurringFilePath() string {
+	return resolvePath(c.RecurringPath)
+}
+
+// PlansFilePath returns the absolute path to the installment plan ledger.
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) PlansFilePath() string {
+	return resolvePath(c.PlansPath)
+}
+
+// RunsFilePath returns the absolute path to the run journal.
+// Same resolution logic as ClassificationsFilePath.
+func (c *Config) RunsFilePath() string {
+	return resolvePath(c.RunsPath)
 }
 
 // ClassificationsFilePath returns the absolute path to classifications.jsonl.
 // If ClassificationsPath is absolute, it is returned as-is.
 // If relative, it is resolved relative to the running binary's directory.
 func (c *Config) ClassificationsFilePath() string {
-	if c.ClassificationsPath == "" {
+	return resolvePath(c.ClassificationsPath)
+}
+
+// resolvePath implements the shared *FilePath resolution: empty stays empty,
+// absolute is returned as-is, relative is joined to the running binary's directory.
+func resolvePath(p string) string {
+	if p == "" {
 		return ""
 	}
-	if filepath.IsAbs(c.ClassificationsPath) {
-		return c.ClassificationsPath
+	if filepath.IsAbs(p) {
+		return p
 	}
 	exe, err := os.Executable()
 	if err != nil {
-		return c.ClassificationsPath
+		return p
 	}
-	return filepath.Join(filepath.Dir(exe), c.ClassificationsPath)
+	return filepath.Join(filepath.Dir(exe), p)
 }
 
 // Load reads config/config.json relative to the source tree root.
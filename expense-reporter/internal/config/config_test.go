@@ -2,6 +2,7 @@ package config
 
 import (
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -84,3 +85,108 @@ func TestClassificationsFilePath_Relative(t *testing.T) {
 		t.Errorf("ClassificationsFilePath() base = %q, want classifications.jsonl", filepath.Base(got))
 	}
 }
+
+func TestRatesFilePath_Relative(t *testing.T) {
+	c := &Config{RatesPath: "rates.json"}
+	got := c.RatesFilePath()
+	if !filepath.IsAbs(got) {
+		t.Errorf("RatesFilePath() = %q, want an absolute path for relative input", got)
+	}
+	if filepath.Base(got) != "rates.json" {
+		t.Errorf("RatesFilePath() base = %q, want rates.json", filepath.Base(got))
+	}
+}
+
+func TestRecurringFilePath_Relative(t *testing.T) {
+	c := &Config{RecurringPath: "recurring.json"}
+	got := c.RecurringFilePath()
+	if !filepath.IsAbs(got) {
+		t.Errorf("RecurringFilePath() = %q, want an absolute path for relative input", got)
+	}
+	if filepath.Base(got) != "recurring.json" {
+		t.Errorf("RecurringFilePath() base = %q, want recurring.json", filepath.Base(got))
+	}
+	if (&Config{}).RecurringFilePath() != "" {
+		t.Error("RecurringFilePath() with empty config should be empty")
+	}
+}
+
+func TestPlansFilePath_Relative(t *testing.T) {
+	c := &Config{PlansPath: "plans.jsonl"}
+	got := c.PlansFilePath()
+	if !filepath.IsAbs(got) {
+		t.Errorf("PlansFilePath() = %q, want an absolute path for relative input", got)
+	}
+	if filepath.Base(got) != "plans.jsonl" {
+		t.Errorf("PlansFilePath() base = %q, want plans.jsonl", filepath.Base(got))
+	}
+}
+
+func TestRunsFilePath_Relative(t *testing.T) {
+	c := &Config{RunsPath: "runs.jsonl"}
+	got := c.RunsFilePath()
+	if !filepath.IsAbs(got) {
+		t.Errorf("RunsFilePath() = %q, want an absolute path for relative input", got)
+	}
+	if filepath.Base(got) != "runs.jsonl" {
+		t.Errorf("RunsFilePath() base = %q, want runs.jsonl", filepath.Base(got))
+	}
+}
+
+func TestIncomeLogFilePath(t *testing.T) {
+	if got := (&Config{}).IncomeLogFilePath(); got != "" {
+		t.Errorf("IncomeLogFilePath() = %q, want empty when unset", got)
+	}
+	c := &Config{IncomeLogPath: "income_log.jsonl"}
+	got := c.IncomeLogFilePath()
+	if !filepath.IsAbs(got) || filepath.Base(got) != "income_log.jsonl" {
+		t.Errorf("IncomeLogFilePath() = %q, want an absolute path ending in income_log.jsonl", got)
+	}
+}
+
+func TestInvestmentsLogFilePath(t *testing.T) {
+	if got := (&Config{}).InvestmentsLogFilePath(); got != "" {
+		t.Errorf("InvestmentsLogFilePath() = %q, want empty when unset", got)
+	}
+	c := &Config{InvestmentsLogPath: "investments_log.jsonl"}
+	got := c.InvestmentsLogFilePath()
+	if !filepath.IsAbs(got) || filepath.Base(got) != "investments_log.jsonl" {
+		t.Errorf("InvestmentsLogFilePath() = %q, want an absolute path ending in investments_log.jsonl", got)
+	}
+}
+
+func TestIOFRateFor(t *testing.T) {
+	c := &Config{Cards: map[string]CardConfig{"nubank": {IOFRate: 0.035}}}
+
+	if got, err := c.IOFRateFor(""); err != nil || got != 0 {
+		t.Errorf("IOFRateFor(\"\") = (%v, %v), want (0, nil)", got, err)
+	}
+	if got, err := c.IOFRateFor("nubank"); err != nil || got != 0.035 {
+		t.Errorf("IOFRateFor(nubank) = (%v, %v), want (0.035, nil)", got, err)
+	}
+	if _, err := c.IOFRateFor("itau"); err == nil {
+		t.Errorf("IOFRateFor(itau) error = nil, want an unknown-card error")
+	}
+}
+
+func TestValidateAccount(t *testing.T) {
+	c := &Config{
+		Cards:    map[string]CardConfig{"nubank": {ClosingDay: 3}, "itau": {}},
+		Accounts: []string{"pix", "debito"},
+	}
+
+	for _, name := range []string{"", "nubank", "pix"} {
+		if err := c.ValidateAccount(name); err != nil {
+			t.Errorf("ValidateAccount(%q) = %v, want nil", name, err)
+		}
+	}
+	if err := c.ValidateAccount("nubnak"); err == nil {
+		t.Errorf("ValidateAccount(nubnak) error = nil, want an unknown-account error")
+	}
+	if got, want := c.AccountNames(), []string{"debito", "itau", "nubank", "pix"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AccountNames() = %v, want %v", got, want)
+	}
+	if got, want := c.ClosingDays(), map[string]int{"nubank": 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ClosingDays() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,122 @@
+package excel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"expense-reporter/pkg/utils"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// WorkbookEntry is one expense found in a month column of an expense sheet.
+type WorkbookEntry struct {
+	Sheet       string
+	Category    string // column A label of the entry's section
+	Subcategory string // column B label of the entry's section
+	Item        string
+	Date        time.Time // zero when the date cell is empty
+	Month       time.Month
+	Value       float64
+	Cell        string // the item cell, e.g. "D12"
+	Row         int    // 1-based row of Cell
+}
+
+// ReadExpenseEntries reads the item/date/value triples from the month columns
+// of each sheet in sheets, in sheet, month and row order. Each entry takes the
+// category and subcategory labels last seen in columns A and B above it (they
+// are merged across a section, so only its first row holds them). Rows without
+// an item or a numeric value, and rows whose date cell is not a date (total
+// rows), are skipped. Dates typed as DD/MM take year.
+func ReadExpenseEntries(workbookPath string, sheets []string, year int) ([]WorkbookEntry, error) {
+	f, err := excelize.OpenFile(workbookPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workbook: %w", err)
+	}
+	defer f.Close()
+
+	var entries []WorkbookEntry
+	for _, sheet := range sheets {
+		rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+		}
+		categories := make([]string, len(rows))
+		subcategories := make([]string, len(rows))
+		var category, subcategory string
+		for i, row := range rows {
+			if a := cellAt(row, 0); a != "" {
+				category = a
+			}
+			if b := cellAt(row, 1); b != "" {
+				subcategory = b
+			}
+			categories[i], subcategories[i] = category, subcategory
+		}
+
+		for month := time.January; month <= time.December; month++ {
+			itemCol, _, _, err := GetMonthColumns(month)
+			if err != nil {
+				return nil, err
+			}
+			col, err := excelize.ColumnNameToNumber(itemCol)
+			if err != nil {
+				return nil, err
+			}
+			for i, row := range rows {
+				item := cellAt(row, col-1)
+				value, err := strconv.ParseFloat(cellAt(row, col+1), 64)
+				if item == "" || err != nil {
+					continue
+				}
+				date, ok := parseCellDate(cellAt(row, col), year)
+				if !ok {
+					continue
+				}
+				entries = append(entries, WorkbookEntry{
+					Sheet:       sheet,
+					Category:    categories[i],
+					Subcategory: subcategories[i],
+					Item:        item,
+					Date:        date,
+					Month:       month,
+					Value:       value,
+					Cell:        fmt.Sprintf("%s%d", itemCol, i+1),
+					Row:         i + 1,
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// parseCellDate reads a raw date cell: an Excel serial number (what the writer
+// stores) or a typed DD/MM[/YYYY] string. An empty cell is a valid, missing
+// date; anything else is not a date.
+func parseCellDate(raw string, year int) (time.Time, bool) {
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if serial, err := strconv.ParseFloat(raw, 64); err == nil {
+		t, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+	if strings.Count(raw, "/") == 1 {
+		t, err := utils.ParseDateWithYear(raw, year)
+		return t, err == nil
+	}
+	t, err := utils.ParseDateFlexible(raw)
+	return t, err == nil
+}
+
+func cellAt(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
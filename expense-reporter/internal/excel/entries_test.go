@@ -0,0 +1,55 @@
+package excel
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/models"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadExpenseEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "Variáveis")
+	f.SetCellValue("Variáveis", "A3", "Transporte")
+	f.SetCellValue("Variáveis", "B3", "Uber/Taxi")
+	f.SetCellValue("Variáveis", "B6", "Ônibus")
+	// Total row of the Uber/Taxi section, as generate-workbook writes it.
+	f.SetCellValue("Variáveis", "M5", "Total")
+	f.SetCellValue("Variáveis", "N5", "-")
+	f.SetCellValue("Variáveis", "O5", 35.5)
+	// A hand-typed entry with a DD/MM text date.
+	f.SetCellValue("Variáveis", "D6", "Passe")
+	f.SetCellValue("Variáveis", "E6", "05/01")
+	f.SetCellValue("Variáveis", "F6", 150)
+	if err := f.SaveAs(path); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	uber := &models.Expense{Item: "Uber", Date: time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC), Value: 35.5, Subcategory: "Uber/Taxi"}
+	if err := WriteExpense(path, uber, &models.SheetLocation{SheetName: "Variáveis", TargetRow: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadExpenseEntries(path, []string{"Variáveis"}, 2025)
+	if err != nil {
+		t.Fatalf("ReadExpenseEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (total row skipped): %+v", len(entries), entries)
+	}
+
+	passe, got := entries[0], entries[1]
+	if passe.Item != "Passe" || passe.Subcategory != "Ônibus" || !passe.Date.Equal(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("January entry = %+v, want Passe on 05/01/2025 under Ônibus", passe)
+	}
+	want := WorkbookEntry{Sheet: "Variáveis", Category: "Transporte", Subcategory: "Uber/Taxi", Item: "Uber",
+		Date: uber.Date, Month: time.April, Value: 35.5, Cell: "M4", Row: 4}
+	if got != want {
+		t.Errorf("April entry = %+v, want %+v", got, want)
+	}
+}
@@ -34,7 +34,11 @@ func ValidateWorkbook(path string) error {
 	return f.Close()
 }
 
-// LoadReferenceSheet loads the "Referência de Categorias" sheet and builds subcategory mappings
+// ReferenceSheet is the sheet mapping each subcategory to its expense sheet and
+// category, read by LoadReferenceSheet.
+const ReferenceSheet = "Referência de Categorias"
+
+// LoadReferenceSheet loads the ReferenceSheet and builds subcategory mappings
 func LoadReferenceSheet(workbookPath string) (map[string][]resolver.SubcategoryMapping, error) {
 	f, err := excelize.OpenFile(workbookPath)
 	if err != nil {
@@ -47,8 +51,7 @@ func LoadReferenceSheet(workbookPath string) (map[string][]resolver.SubcategoryM
 	}()
 
 	// Read the reference sheet
-	sheetName := "Referência de Categorias"
-	rows, err := f.GetRows(sheetName)
+	rows, err := f.GetRows(ReferenceSheet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read reference sheet: %w", err)
 	}
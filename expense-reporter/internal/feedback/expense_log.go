@@ -1,10 +1,16 @@
 package feedback
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/pkg/utils"
 )
 
 // ExpenseEntry is one line in expenses_log.jsonl — a slim record of what was inserted.
@@ -17,6 +23,52 @@ type ExpenseEntry struct {
 	Category    string  `json:"category"`
 	Type        string  `json:"type,omitempty"`
 	Timestamp   string  `json:"timestamp"`
+
+	// ParentID is set on each part of a split transaction: the classifications.jsonl
+	// ID of the original line, shared by every part so they read as one transaction.
+	ParentID string `json:"parent_id,omitempty"`
+
+	// PlanID is set on each expanded installment: the ID of its plan in the
+	// installment ledger (plans.jsonl).
+	PlanID string `json:"plan_id,omitempty"`
+
+	// Foreign is set when the expense was paid in another currency; Value then
+	// holds the converted BRL amount and Foreign preserves how it was derived.
+	Foreign *ForeignAmount `json:"foreign,omitempty"`
+
+	// Account is the card or account the expense was paid with (a name from
+	// config cards/accounts); empty when not recorded.
+	Account string `json:"account,omitempty"`
+
+	// Tags are free-form labels cutting across the taxonomy ("viagem-floripa");
+	// see package tags.
+	Tags []string `json:"tags,omitempty"`
+
+	// RunID is the journal ID of the batch-auto or apply run that wrote the line.
+	RunID string `json:"run_id,omitempty"`
+
+	// Op marks a line that supersedes earlier lines with the same ID:
+	// logschema.OpEdit (this line is the entry's new state) or logschema.OpVoid
+	// (the entry no longer counts). Empty on the original insert.
+	Op string `json:"op,omitempty"`
+}
+
+// ForeignAmount records the original amount of a foreign-currency expense and
+// the conversion applied at insertion: BRL = Amount × Rate × (1 + IOFRate).
+type ForeignAmount struct {
+	Currency string  `json:"currency"`           // ISO 4217 code, e.g. "USD"
+	Amount   float64 `json:"amount"`             // original per-entry amount in Currency
+	Rate     float64 `json:"rate"`               // BRL per unit (PTAX sell rate)
+	RateDate string  `json:"rate_date"`          // DD/MM/YYYY of the quote used
+	IOFRate  float64 `json:"iof_rate,omitempty"` // surcharge fraction applied (0.035 = 3.5%)
+	Card     string  `json:"card,omitempty"`     // card whose IOF rate was applied
+}
+
+// RecordID, RecordDate and RecordPath let the store index expense entries.
+func (e ExpenseEntry) RecordID() string   { return e.ID }
+func (e ExpenseEntry) RecordDate() string { return e.Date }
+func (e ExpenseEntry) RecordPath() (typ, category, subcategory string) {
+	return e.Type, e.Category, e.Subcategory
 }
 
 // NewExpenseEntry builds an ExpenseEntry using the shared GenerateID hash.
@@ -24,7 +76,7 @@ func NewExpenseEntry(item, date string, value float64, subcategory, category str
 	return ExpenseEntry{
 		ID:          GenerateID(item, date, value),
 		Item:        item,
-		Date:        date,
+		Date:        QualifyDate(date),
 		Value:       value,
 		Subcategory: subcategory,
 		Category:    category,
@@ -32,21 +84,171 @@ func NewExpenseEntry(item, date string, value float64, subcategory, category str
 	}
 }
 
+// NewSplitExpenseEntry builds the ExpenseEntry for one part of a split
+// transaction; its ID derives from parentID and the part's subcategory.
+func NewSplitExpenseEntry(parentID, item, date string, value float64, subcategory, category string) ExpenseEntry {
+	entry := NewExpenseEntry(item, date, value, subcategory, category)
+	entry.ID = SplitPartID(parentID, subcategory)
+	entry.ParentID = parentID
+	return entry
+}
+
+// qualifiedDate returns e's date as DD/MM/YYYY, a DD/MM date getting its year
+// from when e was logged (see utils.LoggedDate); it is returned unchanged when
+// e carries no timestamp.
+func (e ExpenseEntry) qualifiedDate() string {
+	if _, err := time.Parse(time.RFC3339, e.Timestamp); err != nil {
+		return e.Date
+	}
+	t, err := utils.LoggedDate(e.Date, e.Timestamp, 0)
+	if err != nil {
+		return e.Date
+	}
+	return utils.FormatDate(t)
+}
+
+// Edit returns a copy of e that, once appended, supersedes every earlier line
+// with e's ID. The caller changes the copy's fields before appending it. The
+// copy is not part of the run that wrote e. A DD/MM date is qualified from e's
+// timestamp first, since the copy's timestamp is the time of the edit.
+func (e ExpenseEntry) Edit() ExpenseEntry {
+	e.Date = e.qualifiedDate()
+	e.Op = logschema.OpEdit
+	e.RunID = ""
+	e.Timestamp = Now().UTC().Format(time.RFC3339)
+	return e
+}
+
+// Void returns the tombstone for e: once appended, the entry with e's ID no
+// longer counts. The tombstone keeps e's fields so history stays readable.
+func (e ExpenseEntry) Void() ExpenseEntry {
+	e.Date = e.qualifiedDate()
+	e.Op = logschema.OpVoid
+	e.RunID = ""
+	e.Timestamp = Now().UTC().Format(time.RFC3339)
+	return e
+}
+
+// ResolveExpenses applies edit and void lines to entries in log order (see
+// logschema.Resolve), leaving one current record per edited ID.
+func ResolveExpenses(entries []ExpenseEntry) []ExpenseEntry {
+	return logschema.Resolve(entries,
+		func(e ExpenseEntry) string { return e.ID },
+		func(e ExpenseEntry) string { return e.Op })
+}
+
 // AppendExpense marshals entry as a single JSON line and appends it to path (creates if absent).
+// The write is locked against concurrent writers and fsynced (see jsonlog.AppendLine).
 func AppendExpense(path string, entry ExpenseEntry) error {
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if _, err := jsonlog.AppendLine(path, entry); err != nil {
+		return fmt.Errorf("appending expense entry: %w", err)
+	}
+	return nil
+}
+
+// LoadExpenseIDs returns the set of entry IDs already in the expense log at path,
+// including the year-qualified ID of each line still keyed by a legacy one and
+// the IDs archived by close-year. A split part also contributes its ParentID,
+// so the transaction it belongs to reads as logged. A missing file yields an
+// empty set.
+func LoadExpenseIDs(path string) (map[string]bool, error) {
+	ids := map[string]bool{}
+	err := scanExpenseLog(path, func(entry ExpenseEntry) {
+		ids[entry.ID] = true
+		if entry.ParentID != "" {
+			ids[entry.ParentID] = true
+		}
+		if upgraded, ok := entry.UpgradeID(); ok {
+			ids[upgraded] = true
+		}
+	})
 	if err != nil {
-		return fmt.Errorf("opening expense log file: %w", err)
+		return nil, err
 	}
-	defer f.Close()
+	return ids, nil
+}
 
-	line, err := json.Marshal(entry)
+// ItemDateKey keys an expense by its normalized item (as GenerateID normalizes
+// it) and its day, leaving the value out.
+func ItemDateKey(item string, date time.Time) string {
+	return strings.ToLower(strings.TrimSpace(item)) + "|" + date.Format(canonicalLayout)
+}
+
+// LoadExpenseItemDates returns the ItemDateKey of every line of the expense log
+// at path and its close-year archives; a DD/MM date takes its year from the
+// line's timestamp (see utils.LoggedDate). Lines whose date does not parse are
+// left out. A missing file yields an empty set.
+func LoadExpenseItemDates(path string) (map[string]bool, error) {
+	keys := map[string]bool{}
+	err := scanExpenseLog(path, func(entry ExpenseEntry) {
+		if date, err := utils.LoggedDate(entry.Date, entry.Timestamp, 0); err == nil {
+			keys[ItemDateKey(entry.Item, date)] = true
+		}
+	})
 	if err != nil {
-		return fmt.Errorf("marshaling expense entry: %w", err)
+		return nil, err
 	}
+	return keys, nil
+}
 
-	if _, err := f.Write(append(line, '\n')); err != nil {
-		return fmt.Errorf("writing expense entry: %w", err)
+// LoadExpenseIDCounts returns how many lines of the expense log at path (and
+// its close-year archives) insert each entry ID, keyed as LoadExpenseIDs is.
+// Identical entries share an ID, so the count tells repeats apart; edit and
+// void lines insert nothing and are not counted. A split transaction counts
+// under its ParentID as many times as its most-inserted part. A missing file
+// yields an empty map.
+func LoadExpenseIDCounts(path string) (map[string]int, error) {
+	counts := map[string]int{}
+	parts := map[string][]string{} // ParentID → part IDs
+	err := scanExpenseLog(path, func(entry ExpenseEntry) {
+		if entry.Op != "" {
+			return
+		}
+		if entry.ParentID != "" && counts[entry.ID] == 0 {
+			parts[entry.ParentID] = append(parts[entry.ParentID], entry.ID)
+		}
+		counts[entry.ID]++
+		if upgraded, ok := entry.UpgradeID(); ok {
+			counts[upgraded]++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for parent, ids := range parts {
+		for _, id := range ids {
+			counts[parent] = max(counts[parent], counts[id])
+		}
+	}
+	return counts, nil
+}
+
+// scanExpenseLog calls fn with each line of the expense log at path and its
+// close-year archives, in file order. A missing file calls fn for nothing.
+func scanExpenseLog(path string, fn func(ExpenseEntry)) error {
+	f, err := jsonlog.OpenYears(path, 0, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening expense log file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry ExpenseEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("parsing expense log line: %w", err)
+		}
+		fn(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading expense log file: %w", err)
 	}
 	return nil
 }
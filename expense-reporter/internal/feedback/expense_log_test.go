@@ -54,6 +54,25 @@ func TestNewExpenseEntry_IDMatchesFeedbackEntry(t *testing.T) {
 	}
 }
 
+func TestNewSplitExpenseEntry(t *testing.T) {
+	parentID := GenerateID("Carrefour", "03/01/2026", 150.00)
+	a := NewSplitExpenseEntry(parentID, "Carrefour", "03/01/2026", 75.00, "Supermercado", "Alimentação")
+	b := NewSplitExpenseEntry(parentID, "Carrefour", "03/01/2026", 75.00, "Limpeza", "Casa")
+
+	if a.ParentID != parentID || b.ParentID != parentID {
+		t.Errorf("ParentID = %q / %q, want %q on both parts", a.ParentID, b.ParentID, parentID)
+	}
+	if a.ID == b.ID {
+		t.Errorf("equal-value parts share ID %q — part IDs must differ by subcategory", a.ID)
+	}
+	if a.ID != SplitPartID(parentID, "supermercado ") {
+		t.Errorf("ID %q does not match SplitPartID (normalized subcategory)", a.ID)
+	}
+	if !hexPattern.MatchString(a.ID) {
+		t.Errorf("ID %q is not 12-char hex", a.ID)
+	}
+}
+
 func TestAppendExpense(t *testing.T) {
 	dir := t.TempDir()
 	path := dir + "/expenses_log.jsonl"
@@ -148,3 +167,59 @@ func TestAppendExpense_CreatesFile(t *testing.T) {
 		t.Errorf("Item = %q, want Supermercado", got.Item)
 	}
 }
+
+func TestLoadExpenseIDs(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/expenses_log.jsonl"
+
+	ids, err := LoadExpenseIDs(path)
+	require.NoError(t, err, "missing file is not an error")
+	assert.Empty(t, ids)
+
+	a := NewExpenseEntry("Aluguel", "05/03/2026", 2500, "Aluguel", "Moradia")
+	b := NewExpenseEntry("Aluguel", "05/04/2026", 2500, "Aluguel", "Moradia")
+	require.NoError(t, AppendExpense(path, a))
+	require.NoError(t, AppendExpense(path, b))
+
+	ids, err = LoadExpenseIDs(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{a.ID: true, b.ID: true}, ids)
+}
+
+func TestLoadExpenseIDCounts(t *testing.T) {
+	path := t.TempDir() + "/expenses_log.jsonl"
+
+	counts, err := LoadExpenseIDCounts(path)
+	require.NoError(t, err, "missing file is not an error")
+	assert.Empty(t, counts)
+
+	cafe := NewExpenseEntry("Café", "05/03/2026", 8, "Padaria", "Alimentação")
+	pao := NewExpenseEntry("Pão", "05/03/2026", 12, "Padaria", "Alimentação")
+	require.NoError(t, AppendExpense(path, cafe))
+	require.NoError(t, AppendExpense(path, cafe))
+	require.NoError(t, AppendExpense(path, pao))
+	require.NoError(t, AppendExpense(path, pao.Edit()))
+	mercado := GenerateID("Mercado", "06/03/2026", 150)
+	feira := NewSplitExpenseEntry(mercado, "Mercado", "06/03/2026", 100, "Feira", "Alimentação")
+	limpeza := NewSplitExpenseEntry(mercado, "Mercado", "06/03/2026", 50, "Limpeza", "Casa")
+	require.NoError(t, AppendExpense(path, feira))
+	require.NoError(t, AppendExpense(path, limpeza))
+
+	counts, err = LoadExpenseIDCounts(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{cafe.ID: 2, pao.ID: 1, feira.ID: 1, limpeza.ID: 1, mercado: 1}, counts,
+		"repeats counted, edit lines not, a split once under its parent")
+
+	ids, err := LoadExpenseIDs(path)
+	require.NoError(t, err)
+	assert.True(t, ids[mercado], "a split's parent ID reads as logged")
+}
+
+func TestExpenseEntry_EditQualifiesYearlessDate(t *testing.T) {
+	e := ExpenseEntry{ID: "x", Item: "Ceia", Date: "28/12", Value: 300, Timestamp: "2027-01-03T09:00:00Z"}
+	assert.Equal(t, "28/12/2026", e.Edit().Date, "the year comes from when the line was logged, not the edit")
+	assert.Equal(t, "28/12/2026", e.Void().Date)
+
+	e.Timestamp = ""
+	assert.Equal(t, "28/12", e.Edit().Date, "without a timestamp the date is left as it is")
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"expense-reporter/internal/classifier"
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
 	"fmt"
 	"os"
 	"strings"
@@ -18,6 +20,10 @@ const (
 	StatusConfirmed Status = "confirmed"
 	StatusCorrected Status = "corrected"
 	StatusManual    Status = "manual"
+	// StatusSplit marks a line the user split across several subcategories.
+	// ActualSubcategory holds the split notation; the classifier loader skips
+	// these, since the item maps to no single subcategory.
+	StatusSplit Status = "split"
 )
 
 // Now is exported so tests can inject a fixed timestamp.
@@ -38,10 +44,31 @@ type Entry struct {
 	Model                string  `json:"model"`
 	Status               Status  `json:"status"`
 	Timestamp            string  `json:"timestamp"`
+
+	// RunID is the journal ID of the batch-auto or apply run that wrote the line.
+	RunID string `json:"run_id,omitempty"`
+	// Op marks a line that supersedes earlier lines with the same ID (see
+	// logschema.Resolve); a void means the ID has no classification.
+	Op string `json:"op,omitempty"`
+}
+
+// RecordID, RecordDate and RecordPath let the store index classification
+// entries; the path is the actual (post-review) classification.
+func (e Entry) RecordID() string   { return e.ID }
+func (e Entry) RecordDate() string { return e.Date }
+func (e Entry) RecordPath() (typ, category, subcategory string) {
+	return e.Type, e.ActualCategory, e.ActualSubcategory
 }
 
-// GenerateID returns the first 12 hex chars of sha256(normalized(item)|date|value).
+// GenerateID returns the first 12 hex chars of sha256(normalized(item)|date|value),
+// where date is the year-qualified canonical date (see CanonicalDate), so the
+// same item and amount on the same day of different years get different IDs.
 func GenerateID(item, date string, value float64) string {
+	return hashID(item, CanonicalDate(date), value)
+}
+
+// hashID hashes the ID input: normalized item, date as given, and value.
+func hashID(item, date string, value float64) string {
 	// Normalize item: lowercase + trim whitespace
 	normalized := strings.ToLower(strings.TrimSpace(item))
 	// Build deterministic input string
@@ -51,34 +78,34 @@ func GenerateID(item, date string, value float64) string {
 	return fmt.Sprintf("%x", hash)[:12]
 }
 
+// SplitPartID returns the ID of one part of a split transaction: the first 12 hex
+// chars of sha256(parentID|normalized(subcategory)). Parts of one split have
+// distinct subcategories, so their IDs never collide even when values are equal.
+func SplitPartID(parentID, subcategory string) string {
+	input := parentID + "|" + strings.ToLower(strings.TrimSpace(subcategory))
+	hash := sha256.Sum256([]byte(input))
+	return fmt.Sprintf("%x", hash)[:12]
+}
+
 // Append marshals entry as a single JSON line and appends it to path (creates if absent).
+// The write is locked against concurrent writers and fsynced (see jsonlog.AppendLine).
 func Append(path string, entry Entry) error {
-	// Open or create the file for appending
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
-	if err != nil {
-		return fmt.Errorf("opening feedback file: %w", err)
-	}
-	defer f.Close()
-
-	// Marshal to a single JSON line
-	line, err := json.Marshal(entry)
-	if err != nil {
-		return fmt.Errorf("marshaling feedback entry: %w", err)
-	}
-
-	// Write line + newline
-	if _, err := f.Write(append(line, '\n')); err != nil {
-		return fmt.Errorf("writing feedback entry: %w", err)
+	if _, err := jsonlog.AppendLine(path, entry); err != nil {
+		return fmt.Errorf("appending feedback entry: %w", err)
 	}
 	return nil
 }
 
 // FindLatestEntry scans the JSONL file at path for the most recent entry whose ID matches.
-// Returns (entry, true, nil) if found, (zero, false, nil) if no match (including missing file),
+// A line still keyed by its legacy ID matches the year-qualified ID it upgrades to (see
+// Entry.UpgradeID), so logs not yet migrated keep resolving.
+// Returns (entry, true, nil) if found, (zero, false, nil) if no match (including missing file
+// and an ID whose latest line is a void),
 // and (zero, false, err) only on read/parse errors. "Most recent" means the last matching line
-// in file order (entries are append-only, so file order is chronological).
+// in file order (entries are append-only, so file order is chronological); the archives of
+// closed years are read first (see jsonlog.OpenYears).
 func FindLatestEntry(path, id string) (Entry, bool, error) {
-	f, err := os.Open(path)
+	f, err := jsonlog.OpenYears(path, 0, 0)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return Entry{}, false, nil
@@ -105,22 +132,49 @@ func FindLatestEntry(path, id string) (Entry, bool, error) {
 		if entry.ID == id {
 			latest = entry
 			found = true
+		} else if upgraded, ok := entry.UpgradeID(); ok && upgraded == id {
+			entry.ID = id
+			latest = entry
+			found = true
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		return Entry{}, false, fmt.Errorf("reading feedback file: %w", err)
 	}
+	if latest.Voided() {
+		return Entry{}, false, nil
+	}
 
 	return latest, found, nil
 }
 
+// Voided reports whether e is a tombstone: the ID no longer has a classification.
+func (e Entry) Voided() bool { return e.Op == logschema.OpVoid }
+
+// Void returns the tombstone for e's ID, keeping e's fields for readability.
+func (e Entry) Void() Entry {
+	e.Op = logschema.OpVoid
+	e.RunID = ""
+	e.Timestamp = Now().UTC().Format(time.RFC3339)
+	return e
+}
+
+// Restore returns e re-recorded as the current classification of its ID,
+// superseding any later line (e.g. after the run that wrote those is reverted).
+func (e Entry) Restore() Entry {
+	e.Op = logschema.OpEdit
+	e.RunID = ""
+	e.Timestamp = Now().UTC().Format(time.RFC3339)
+	return e
+}
+
 // NewConfirmedEntry builds a confirmed Entry where predicted == actual.
 func NewConfirmedEntry(item, date string, value float64, predicted classifier.Result, model string) Entry {
 	return Entry{
 		ID:                   GenerateID(item, date, value),
 		Item:                 item,
-		Date:                 date,
+		Date:                 QualifyDate(date),
 		Value:                value,
 		PredictedSubcategory: predicted.Subcategory,
 		PredictedCategory:    predicted.Category,
@@ -138,7 +192,7 @@ func NewManualEntry(item, date string, value float64, subcategory, category stri
 	return Entry{
 		ID:                   GenerateID(item, date, value),
 		Item:                 item,
-		Date:                 date,
+		Date:                 QualifyDate(date),
 		Value:                value,
 		PredictedSubcategory: "",
 		PredictedCategory:    "",
@@ -158,7 +212,7 @@ func NewCorrectedEntry(item, date string, value float64, predicted classifier.Re
 	return Entry{
 		ID:                   GenerateID(item, date, value),
 		Item:                 item,
-		Date:                 date,
+		Date:                 QualifyDate(date),
 		Value:                value,
 		PredictedSubcategory: predicted.Subcategory,
 		PredictedCategory:    predicted.Category,
@@ -170,3 +224,22 @@ func NewCorrectedEntry(item, date string, value float64, predicted classifier.Re
 		Timestamp:            Now().UTC().Format(time.RFC3339),
 	}
 }
+
+// NewSplitEntry builds a split Entry for a line the user divided across several
+// subcategories. spec is the split notation (see utils.FormatSplit); predicted is
+// the model's prediction for the whole line, zero when the split was manual.
+func NewSplitEntry(item, date string, value float64, predicted classifier.Result, model, spec string) Entry {
+	return Entry{
+		ID:                   GenerateID(item, date, value),
+		Item:                 item,
+		Date:                 QualifyDate(date),
+		Value:                value,
+		PredictedSubcategory: predicted.Subcategory,
+		PredictedCategory:    predicted.Category,
+		Confidence:           predicted.Confidence,
+		ActualSubcategory:    spec,
+		Model:                model,
+		Status:               StatusSplit,
+		Timestamp:            Now().UTC().Format(time.RFC3339),
+	}
+}
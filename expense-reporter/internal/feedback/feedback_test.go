@@ -138,6 +138,27 @@ func TestNewManualEntry(t *testing.T) {
 	}
 }
 
+func TestNewSplitEntry(t *testing.T) {
+	predicted := classifier.Result{Subcategory: "Supermercado", Category: "Alimentação", Confidence: 0.71}
+	e := NewSplitEntry("Carrefour", "03/01", 150.00, predicted, "review", "Supermercado=120,00|Limpeza=30,00")
+
+	if e.Status != StatusSplit {
+		t.Errorf("Status = %q, want %q", e.Status, StatusSplit)
+	}
+	if e.ID != GenerateID("Carrefour", "03/01", 150.00) {
+		t.Errorf("ID %q must be the unsplit line's ID", e.ID)
+	}
+	if e.ActualSubcategory != "Supermercado=120,00|Limpeza=30,00" {
+		t.Errorf("ActualSubcategory = %q, want the split notation", e.ActualSubcategory)
+	}
+	if e.ActualCategory != "" {
+		t.Errorf("ActualCategory = %q, want empty", e.ActualCategory)
+	}
+	if e.PredictedSubcategory != "Supermercado" || e.Confidence != 0.71 {
+		t.Errorf("predicted fields not preserved: %+v", e)
+	}
+}
+
 func TestNewCorrectedEntry(t *testing.T) {
 	fixedTime := time.Date(2026, 3, 13, 14, 30, 0, 0, time.UTC)
 	orig := Now
@@ -290,6 +311,25 @@ func TestFindLatestEntry(t *testing.T) {
 			wantFound: false,
 			wantErr:   false,
 		},
+		{
+			name: "torn trailing line is quarantined, not an error",
+			setup: func(dir string) (string, string) {
+				path := dir + "/feedback.jsonl"
+				entry := Entry{ID: "abc123456789", Item: "Padaria", Date: "01/01/2025", Value: 10.00, Status: StatusConfirmed}
+				if err := Append(path, entry); err != nil {
+					t.Fatalf("Append: %v", err)
+				}
+				f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+				if err != nil {
+					t.Fatalf("OpenFile: %v", err)
+				}
+				f.WriteString(`{"id":"abc123456789","item":"Pada`)
+				f.Close()
+				return path, "abc123456789"
+			},
+			wantFound: true,
+			wantErr:   false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,205 @@
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/pkg/utils"
+)
+
+// canonicalLayout is the date format hashed into entry IDs.
+const canonicalLayout = "2006-01-02"
+
+// CanonicalDate returns date as YYYY-MM-DD, the form GenerateID hashes. A DD/MM
+// date is taken to be recorded now and gets its year as 'migrate' infers it
+// (see utils.InferDate); a date that does not parse is returned trimmed,
+// unchanged.
+func CanonicalDate(date string) string {
+	date = strings.TrimSpace(date)
+	t, err := utils.InferDate(date, Now().UTC())
+	if err != nil {
+		return date
+	}
+	return t.Format(canonicalLayout)
+}
+
+// QualifyDate returns date as DD/MM/YYYY, a DD/MM date getting the year
+// CanonicalDate hashes. Lines are logged with qualified dates so no later
+// reader has to infer the year; a date that does not parse is returned
+// trimmed, unchanged.
+func QualifyDate(date string) string {
+	date = strings.TrimSpace(date)
+	t, err := utils.InferDate(date, Now().UTC())
+	if err != nil {
+		return date
+	}
+	return utils.FormatDate(t)
+}
+
+// LegacyID returns the ID GenerateID produced before IDs were year-qualified:
+// the date was hashed exactly as typed, often DD/MM with no year. Lookups fall
+// back to it for logs that have not been through 'migrate'.
+func LegacyID(item, date string, value float64) string {
+	return hashID(item, strings.TrimSpace(date), value)
+}
+
+// inferDate returns the canonical date of a logged line. A date with a year
+// needs no inference. A DD/MM date takes the year the line was written
+// (timestamp), or the year before when that would put the expense after the
+// day it was logged. ok is false when neither the date nor the timestamp
+// gives a year.
+func inferDate(date, timestamp string) (string, bool) {
+	date = strings.TrimSpace(date)
+	var logged time.Time
+	if strings.Count(date, "/") == 1 {
+		var err error
+		if logged, err = time.Parse(time.RFC3339, timestamp); err != nil {
+			return "", false
+		}
+	}
+	t, err := utils.InferDate(date, logged.UTC())
+	if err != nil {
+		return "", false
+	}
+	return t.Format(canonicalLayout), true
+}
+
+// UpgradeID returns the year-qualified ID of a line still keyed by its legacy
+// ID. ok is false when the line already has a current ID (or one not derived
+// from its fields, e.g. a split part) or its year cannot be inferred.
+func (e Entry) UpgradeID() (id string, ok bool) {
+	return upgradeID(e.ID, e.Item, e.Date, e.Value, e.Timestamp)
+}
+
+// UpgradeID is Entry.UpgradeID for expense log lines. Split parts derive their
+// ID from the parent's, so they are upgraded along with it, not here.
+func (e ExpenseEntry) UpgradeID() (id string, ok bool) {
+	if e.ParentID != "" {
+		return "", false
+	}
+	return upgradeID(e.ID, e.Item, e.Date, e.Value, e.Timestamp)
+}
+
+func upgradeID(id, item, date string, value float64, timestamp string) (string, bool) {
+	if id == "" || id != LegacyID(item, date, value) {
+		return "", false
+	}
+	canonical, ok := inferDate(date, timestamp)
+	if !ok {
+		return "", false
+	}
+	next := hashID(item, canonical, value)
+	return next, next != id
+}
+
+// IDMigration reports what MigrateIDs changed (or would change).
+type IDMigration struct {
+	Classifications int // classification lines given a year-qualified ID
+	Expenses        int // expense log lines with a new ID, parent_id or plan_id
+	Unresolved      int // legacy lines kept as they are: no year to infer
+
+	// IDs maps each replaced classification ID to its successor, for ledgers
+	// that reference classifications by ID (installment plans).
+	IDs map[string]string
+}
+
+// MigrateIDs replaces legacy IDs (see LegacyID) in the classifications and
+// expense logs with year-qualified ones. Later lines of an entry (edits,
+// corrections, voids) follow its first line's new ID; split parts are re-derived
+// from their parent's new ID and plan_id references follow the classification
+// they name. With dryRun nothing is written. Either path may be "" or missing.
+func MigrateIDs(classificationsPath, expensesPath string, dryRun bool) (IDMigration, error) {
+	m := IDMigration{IDs: map[string]string{}}
+	var err error
+	m.Classifications, err = rewriteIDs(classificationsPath, dryRun, func(e *Entry) bool {
+		next, ok := m.IDs[e.ID]
+		if !ok {
+			if next, ok = e.UpgradeID(); !ok {
+				if e.ID != "" && e.ID == LegacyID(e.Item, e.Date, e.Value) {
+					m.Unresolved++
+				}
+				return false
+			}
+			m.IDs[e.ID] = next
+		}
+		e.ID = next
+		return true
+	})
+	if err != nil {
+		return m, err
+	}
+
+	expenseIDs := map[string]string{}
+	m.Expenses, err = rewriteIDs(expensesPath, dryRun, func(e *ExpenseEntry) bool {
+		changed := false
+		if next, ok := expenseIDs[e.ID]; ok {
+			e.ID, changed = next, true
+		} else if parent, ok := m.IDs[e.ParentID]; ok && e.ID == SplitPartID(e.ParentID, e.Subcategory) {
+			next = SplitPartID(parent, e.Subcategory)
+			expenseIDs[e.ID] = next
+			e.ID, changed = next, true
+		} else if next, ok := e.UpgradeID(); ok {
+			expenseIDs[e.ID] = next
+			e.ID, changed = next, true
+		}
+		if parent, ok := m.IDs[e.ParentID]; ok {
+			e.ParentID, changed = parent, true
+		}
+		if plan, ok := m.IDs[e.PlanID]; ok {
+			e.PlanID, changed = plan, true
+		}
+		return changed
+	})
+	return m, err
+}
+
+// rewriteIDs passes every line of the log at path through fix and, unless
+// dryRun, rewrites the lines it changed. It returns how many it changed.
+func rewriteIDs[T any](path string, dryRun bool, fix func(*T) bool) (int, error) {
+	if path == "" {
+		return 0, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	changed := 0
+	edit := func(line []byte) ([]byte, error) {
+		var rec T
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, err
+		}
+		if !fix(&rec) {
+			return line, nil
+		}
+		changed++
+		return json.Marshal(rec)
+	}
+	if !dryRun {
+		_, err := jsonlog.Rewrite(path, edit)
+		return changed, err
+	}
+
+	f, err := jsonlog.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		if _, err := edit(scanner.Bytes()); err != nil {
+			return 0, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,181 @@
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/classifier"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixNow(t *testing.T, at time.Time) {
+	orig := Now
+	Now = func() time.Time { return at }
+	t.Cleanup(func() { Now = orig })
+}
+
+func TestGenerateID_YearQualified(t *testing.T) {
+	fixNow(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "2026-04-15", CanonicalDate("15/04"))
+	assert.Equal(t, "2025-04-05", CanonicalDate("5/4/2025"))
+	assert.Equal(t, GenerateID("Uber", "15/04/2026", 35.5), GenerateID("Uber", "15/04", 35.5), "DD/MM is the current year")
+	assert.NotEqual(t, GenerateID("Uber", "15/04/2025", 35.5), GenerateID("Uber", "15/04/2026", 35.5), "same day, different years")
+	assert.NotEqual(t, LegacyID("Uber", "15/04", 35.5), GenerateID("Uber", "15/04", 35.5))
+}
+
+func TestUpgradeID_InfersYear(t *testing.T) {
+	tests := []struct {
+		name      string
+		date      string
+		timestamp string
+		wantDate  string // "" = not upgradable
+	}{
+		{"full date", "15/04/2025", "", "15/04/2025"},
+		{"DD/MM logged the same year", "15/04", "2025-04-20T10:00:00Z", "15/04/2025"},
+		{"DD/MM logged early the next year", "28/12", "2026-01-03T10:00:00Z", "28/12/2025"},
+		{"DD/MM without timestamp", "15/04", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Entry{ID: LegacyID("Uber", tt.date, 35.5), Item: "Uber", Date: tt.date, Value: 35.5, Timestamp: tt.timestamp}
+			id, ok := e.UpgradeID()
+			if tt.wantDate == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, GenerateID("Uber", tt.wantDate, 35.5), id)
+		})
+	}
+
+	current := Entry{ID: GenerateID("Uber", "15/04/2025", 35.5), Item: "Uber", Date: "15/04/2025", Value: 35.5}
+	_, ok := current.UpgradeID()
+	assert.False(t, ok, "a current ID needs no upgrade")
+}
+
+func TestFindLatestEntry_MatchesLegacyID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "classifications.jsonl")
+	legacy := Entry{ID: LegacyID("Uber", "15/04", 35.5), Item: "Uber", Date: "15/04", Value: 35.5,
+		ActualSubcategory: "Uber/Taxi", Status: StatusConfirmed, Timestamp: "2025-04-20T10:00:00Z"}
+	require.NoError(t, Append(path, legacy))
+
+	e, found, err := FindLatestEntry(path, GenerateID("Uber", "15/04/2025", 35.5))
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "Uber/Taxi", e.ActualSubcategory)
+	assert.Equal(t, GenerateID("Uber", "15/04/2025", 35.5), e.ID)
+
+	_, found, err = FindLatestEntry(path, GenerateID("Uber", "15/04/2026", 35.5))
+	require.NoError(t, err)
+	assert.False(t, found, "the same ride a year later is a different entry")
+}
+
+func TestGenerateID_NewYearCrossing(t *testing.T) {
+	fixNow(t, time.Date(2027, 1, 3, 9, 0, 0, 0, time.UTC))
+
+	// Entered on 03/01/2027, "28/12" is last December: the ID, the logged date
+	// and what migrate infers for a legacy line logged that day all agree.
+	id := GenerateID("Ceia", "28/12", 300)
+	assert.Equal(t, GenerateID("Ceia", "28/12/2026", 300), id)
+	e := NewExpenseEntry("Ceia", "28/12", 300, "Restaurante", "Alimentação")
+	assert.Equal(t, "28/12/2026", e.Date, "the log carries the inferred year")
+	assert.Equal(t, id, e.ID)
+
+	legacy := Entry{ID: LegacyID("Ceia", "28/12", 300), Item: "Ceia", Date: "28/12", Value: 300, Timestamp: "2027-01-03T09:00:00Z"}
+	upgraded, ok := legacy.UpgradeID()
+	require.True(t, ok)
+	assert.Equal(t, id, upgraded)
+
+	// A queue made on 31/12 and applied on 02/01 keeps its IDs: the date was
+	// qualified when the queue was written.
+	fixNow(t, time.Date(2026, 12, 31, 9, 0, 0, 0, time.UTC))
+	queued := QualifyDate("28/12")
+	fixNow(t, time.Date(2027, 1, 2, 9, 0, 0, 0, time.UTC))
+	assert.Equal(t, id, GenerateID("Ceia", queued, 300))
+}
+
+func TestMigrateIDs(t *testing.T) {
+	fixNow(t, time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+	classPath := filepath.Join(dir, "classifications.jsonl")
+	logPath := filepath.Join(dir, "expenses_log.jsonl")
+
+	// Lines as the pre-migration build wrote them: IDs hashed from DD/MM.
+	uberID := LegacyID("Uber", "15/02", 35.5)
+	uber := NewConfirmedEntry("Uber", "15/02", 35.5, classifier.Result{Subcategory: "Uber/Taxi"}, "m")
+	uber.ID, uber.Date = uberID, "15/02"
+	corrected := uber
+	corrected.Status, corrected.ActualSubcategory = StatusCorrected, "99/Taxi"
+	split := NewSplitEntry("Carrefour", "03/01", 150, classifier.Result{}, "", "Supermercado=120,00|Limpeza=30,00")
+	split.ID, split.Date = LegacyID("Carrefour", "03/01", 150), "03/01"
+	orphan := Entry{ID: LegacyID("Padaria", "01/02", 10), Item: "Padaria", Date: "01/02", Value: 10, Status: StatusManual}
+	for _, e := range []Entry{uber, corrected, split, orphan} {
+		require.NoError(t, Append(classPath, e))
+	}
+
+	ride := NewExpenseEntry("Uber", "15/02/2025", 35.5, "Uber/Taxi", "Transporte")
+	ride.ID = LegacyID("Uber", "15/02/2025", 35.5)
+	edited := ride.Edit()
+	edited.Value = 36
+	part := NewSplitExpenseEntry(split.ID, "Carrefour", "03/01/2025", 120, "Supermercado", "Alimentação")
+	course := NewExpenseEntry("Curso (1/3)", "01/02/2025", 30, "Cursos", "Educação")
+	course.ID = LegacyID("Curso (1/3)", "01/02/2025", 30)
+	course.PlanID = uberID // stands in for a plan keyed like its classification
+	for _, e := range []ExpenseEntry{ride, edited, part, course} {
+		require.NoError(t, AppendExpense(logPath, e))
+	}
+	before, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	dry, err := MigrateIDs(classPath, logPath, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, dry.Classifications)
+	assert.Equal(t, 4, dry.Expenses)
+	assert.Equal(t, 1, dry.Unresolved, "a line with no year and no timestamp")
+	after, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "dry run writes nothing")
+
+	done, err := MigrateIDs(classPath, logPath, false)
+	require.NoError(t, err)
+	assert.Equal(t, dry.Classifications, done.Classifications)
+	newUber := GenerateID("Uber", "15/02/2025", 35.5)
+	newSplit := GenerateID("Carrefour", "03/01/2025", 150)
+	assert.Equal(t, map[string]string{uberID: newUber, split.ID: newSplit}, done.IDs)
+
+	entries := readLines[Entry](t, classPath)
+	assert.Equal(t, []string{newUber, newUber, newSplit, orphan.ID}, []string{entries[0].ID, entries[1].ID, entries[2].ID, entries[3].ID})
+
+	logged := readLines[ExpenseEntry](t, logPath)
+	newRide := GenerateID("Uber", "15/02/2025", 35.5)
+	assert.Equal(t, newRide, logged[0].ID)
+	assert.Equal(t, newRide, logged[1].ID, "an edit follows the entry's new ID")
+	assert.Equal(t, newSplit, logged[2].ParentID)
+	assert.Equal(t, SplitPartID(newSplit, "Supermercado"), logged[2].ID)
+	assert.Equal(t, newUber, logged[3].PlanID)
+
+	again, err := MigrateIDs(classPath, logPath, false)
+	require.NoError(t, err)
+	assert.Zero(t, again.Classifications+again.Expenses, "idempotent")
+}
+
+func readLines[T any](t *testing.T, path string) []T {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var out []T
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var v T
+		require.NoError(t, dec.Decode(&v))
+		out = append(out, v)
+	}
+	return out
+}
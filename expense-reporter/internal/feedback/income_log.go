@@ -0,0 +1,127 @@
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+)
+
+// IncomeEntry is one line in income_log.jsonl. Lines written outside this tool
+// carry no ID, so RecordID derives one.
+type IncomeEntry struct {
+	ID             string  `json:"id,omitempty"`
+	Date           string  `json:"date"`
+	Value          float64 `json:"value"`           // signed: deductions are negative
+	IncomeCategory string  `json:"income_category"` // revenue block
+	IncomeLabel    string  `json:"income_label"`    // leaf line within the block
+	ItemNote       string  `json:"item_note"`
+
+	// RunID is the journal ID of the batch-auto or apply-income run that wrote
+	// the line.
+	RunID string `json:"run_id,omitempty"`
+
+	// Op is logschema.OpVoid on a line that drops the entry with the same ID
+	// (a reverted run); empty on the original insert.
+	Op string `json:"op,omitempty"`
+}
+
+// RecordID returns the entry's ID or, for a line written without one, a hash of
+// its note, date and value: GenerateID's when the date has a year, else
+// LegacyID's of the DD/MM date as typed. Neither depends on the clock, so a
+// void or dedupe key derived one year still matches the line the next.
+func (e IncomeEntry) RecordID() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	if strings.Count(strings.TrimSpace(e.Date), "/") == 1 {
+		return LegacyID(e.ItemNote, e.Date, e.Value)
+	}
+	return GenerateID(e.ItemNote, e.Date, e.Value)
+}
+
+// RecordDate returns the entry's date.
+func (e IncomeEntry) RecordDate() string { return e.Date }
+
+// RecordPath files income under block/label; income has no expense type.
+func (e IncomeEntry) RecordPath() (typ, category, subcategory string) {
+	return "", e.IncomeCategory, e.IncomeLabel
+}
+
+// NewIncomeEntry builds an IncomeEntry for the revenue leaf block/label, with
+// the GenerateID hash of its note, date and value.
+func NewIncomeEntry(note, date string, value float64, block, label string) IncomeEntry {
+	return IncomeEntry{
+		ID:             GenerateID(note, date, value),
+		Date:           QualifyDate(date),
+		Value:          value,
+		IncomeCategory: block,
+		IncomeLabel:    label,
+		ItemNote:       note,
+	}
+}
+
+// Void returns the tombstone for e: once appended, the entry with e's ID no
+// longer counts.
+func (e IncomeEntry) Void() IncomeEntry {
+	e.ID = e.RecordID()
+	e.Op = logschema.OpVoid
+	e.RunID = ""
+	return e
+}
+
+// ResolveIncome applies void lines to entries in log order (see
+// logschema.Resolve), keyed by RecordID.
+func ResolveIncome(entries []IncomeEntry) []IncomeEntry {
+	return logschema.Resolve(entries,
+		func(e IncomeEntry) string { return e.RecordID() },
+		func(e IncomeEntry) string { return e.Op })
+}
+
+// LoadIncomeIDs returns the IDs of the entries that count in the income log at
+// path and its close-year archives: voided entries are left out, so a reverted
+// line can be recorded again. A missing file yields an empty set.
+func LoadIncomeIDs(path string) (map[string]bool, error) {
+	ids := map[string]bool{}
+	f, err := jsonlog.OpenYears(path, 0, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ids, nil
+		}
+		return nil, fmt.Errorf("opening income log file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []IncomeEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry IncomeEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("parsing income log line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading income log file: %w", err)
+	}
+	for _, e := range ResolveIncome(entries) {
+		ids[e.RecordID()] = true
+	}
+	return ids, nil
+}
+
+// AppendIncome appends one entry to the income log at path.
+func AppendIncome(path string, entry IncomeEntry) error {
+	if _, err := jsonlog.AppendLine(path, entry); err != nil {
+		return fmt.Errorf("appending income entry: %w", err)
+	}
+	return nil
+}
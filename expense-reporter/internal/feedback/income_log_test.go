@@ -0,0 +1,42 @@
+package feedback
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadIncomeIDs_VoidedLineCanReturn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "income_log.jsonl")
+	ids, err := LoadIncomeIDs(path)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "missing log")
+
+	salary := NewIncomeEntry("PIX ACME folha", "05/03/2026", 9500, "Salário", "Salário")
+	salary.RunID = "run-1"
+	// A line written by hand carries no ID; RecordID derives it.
+	bonus := IncomeEntry{Date: "20/03/2026", Value: 1200, IncomeCategory: "Salário", IncomeLabel: "Bônus", ItemNote: "Bônus"}
+	for _, e := range []IncomeEntry{salary, bonus, salary.Void()} {
+		require.NoError(t, AppendIncome(path, e))
+	}
+
+	ids, err = LoadIncomeIDs(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{bonus.RecordID(): true}, ids)
+	assert.Empty(t, salary.Void().RunID, "a void belongs to no run")
+}
+
+func TestIncomeEntry_RecordIDIgnoresClock(t *testing.T) {
+	undated := IncomeEntry{Date: "05/03", Value: 9500, ItemNote: "Salário"}
+	dated := IncomeEntry{Date: "05/03/2026", Value: 9500, ItemNote: "Salário"}
+
+	fixNow(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC))
+	before, beforeDated := undated.RecordID(), dated.RecordID()
+	fixNow(t, time.Date(2027, 5, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, before, undated.RecordID(), "a DD/MM line keeps its key across years")
+	assert.Equal(t, LegacyID("Salário", "05/03", 9500), before)
+	assert.Equal(t, beforeDated, dated.RecordID())
+}
@@ -0,0 +1,76 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+)
+
+// Investment entry kinds.
+const (
+	InvestmentContribution = "contribution"
+	InvestmentWithdrawal   = "withdrawal"
+)
+
+// InvestmentEntry is one line in investments_log.jsonl: money moved into
+// (contribution) or out of (withdrawal) an asset class. Value is the positive
+// BRL amount; Kind gives the direction.
+type InvestmentEntry struct {
+	ID         string  `json:"id"`
+	Item       string  `json:"item"`
+	Date       string  `json:"date"`
+	Value      float64 `json:"value"`
+	Kind       string  `json:"kind"`
+	AssetClass string  `json:"asset_class"`
+	Timestamp  string  `json:"timestamp"`
+}
+
+// NewInvestmentEntry builds an InvestmentEntry using the shared GenerateID hash.
+// The kind is hashed with the item, so a contribution and a withdrawal of the
+// same amount on the same day get different IDs.
+func NewInvestmentEntry(item, date string, value float64, kind, assetClass string) InvestmentEntry {
+	return InvestmentEntry{
+		ID:         GenerateID(kind+" "+item, date, value),
+		Item:       item,
+		Date:       QualifyDate(date),
+		Value:      value,
+		Kind:       kind,
+		AssetClass: assetClass,
+		Timestamp:  Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// MarshalJSON writes the entry stamped with the current investments_log schema.
+func (e InvestmentEntry) MarshalJSON() ([]byte, error) {
+	type plain InvestmentEntry
+	b, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return logschema.Investments.Stamp(b), nil
+}
+
+// UnmarshalJSON upgrades an older investments_log line to the current schema
+// before decoding it.
+func (e *InvestmentEntry) UnmarshalJSON(b []byte) error {
+	b, _, err := logschema.Investments.Upgrade(b)
+	if err != nil {
+		return err
+	}
+	type plain InvestmentEntry
+	return json.Unmarshal(b, (*plain)(e))
+}
+
+// AppendInvestment appends one entry to the investments log at path.
+func AppendInvestment(path string, entry InvestmentEntry) error {
+	if entry.Kind != InvestmentContribution && entry.Kind != InvestmentWithdrawal {
+		return fmt.Errorf("investment entry %q: unknown kind %q", entry.Item, entry.Kind)
+	}
+	if _, err := jsonlog.AppendLine(path, entry); err != nil {
+		return fmt.Errorf("appending investment entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInvestmentEntry_KindInID(t *testing.T) {
+	in := NewInvestmentEntry("CDB", "05/03/2026", 500, InvestmentContribution, "Renda fixa")
+	out := NewInvestmentEntry("CDB", "05/03/2026", 500, InvestmentWithdrawal, "Renda fixa")
+
+	assert.Regexp(t, hexPattern, in.ID)
+	assert.NotEqual(t, in.ID, out.ID, "same item, date and value in opposite directions")
+	assert.Equal(t, in.ID, NewInvestmentEntry("CDB", "05/03/2026", 500, InvestmentContribution, "Ações").ID,
+		"the asset class does not enter the ID")
+}
@@ -0,0 +1,122 @@
+package feedback
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"expense-reporter/internal/jsonlog"
+)
+
+// YearClose reports what CloseYear moved (or would move) into the year's
+// archives, in lines.
+type YearClose struct {
+	Classifications int
+	Expenses        int
+	Income          int
+	Undated         int // lines kept in the live logs: no year to infer
+}
+
+// CloseYear moves the lines of year from the classifications, expense and
+// income logs into their per-year archives (see jsonlog.PartitionPath), where
+// readers still find them. An entry moves as a whole, with its edits, voids and
+// corrections, when its latest line is dated in year; installments and other
+// entries dated later stay in the live logs. With dryRun nothing is written.
+// Any path may be "" or missing.
+func CloseYear(classificationsPath, expensesPath, incomePath string, year int, dryRun bool) (YearClose, error) {
+	var c YearClose
+	var err error
+	var undated int
+	c.Classifications, undated, err = archiveYear(classificationsPath, year, dryRun, func(e Entry) (string, string, string) {
+		return e.ID, e.Date, e.Timestamp
+	})
+	if err != nil {
+		return c, err
+	}
+	c.Undated += undated
+	c.Expenses, undated, err = archiveYear(expensesPath, year, dryRun, func(e ExpenseEntry) (string, string, string) {
+		return e.ID, e.Date, e.Timestamp
+	})
+	if err != nil {
+		return c, err
+	}
+	c.Undated += undated
+	c.Income, undated, err = archiveYear(incomePath, year, dryRun, func(e IncomeEntry) (string, string, string) {
+		return e.RecordID(), e.Date, ""
+	})
+	c.Undated += undated
+	return c, err
+}
+
+// archiveYear moves the lines of every ID whose latest line falls in year to
+// the log's archive for year. fields returns a line's ID, date and timestamp;
+// the year of a DD/MM date is inferred from the timestamp as migrate does. It
+// returns the lines moved (or, with dryRun, that would move) and the lines
+// whose ID has no year to go by.
+func archiveYear[T any](path string, year int, dryRun bool, fields func(T) (id, date, timestamp string)) (moved, undated int, err error) {
+	if path == "" {
+		return 0, 0, nil
+	}
+	latest, lines, err := latestYears(path, fields)
+	if err != nil {
+		return 0, 0, err
+	}
+	for id, y := range latest {
+		switch y {
+		case year:
+			moved += lines[id]
+		case 0:
+			undated += lines[id]
+		}
+	}
+	if dryRun || moved == 0 {
+		return moved, undated, nil
+	}
+	moved, err = jsonlog.Archive(path, year, func(line []byte) (bool, error) {
+		var rec T
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return false, err
+		}
+		id, _, _ := fields(rec)
+		return latest[id] == year, nil
+	})
+	return moved, undated, err
+}
+
+// latestYears maps each ID in the live log at path to the year of its last
+// line (0 when it has none) and counts its lines.
+func latestYears[T any](path string, fields func(T) (id, date, timestamp string)) (latest, lines map[string]int, err error) {
+	latest, lines = map[string]int{}, map[string]int{}
+	f, err := jsonlog.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return latest, lines, nil
+		}
+		return nil, nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec T
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		id, date, timestamp := fields(rec)
+		latest[id] = 0
+		if canonical, ok := inferDate(date, timestamp); ok {
+			latest[id], _ = strconv.Atoi(canonical[:4])
+		}
+		lines[id]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return latest, lines, nil
+}
@@ -0,0 +1,72 @@
+package feedback
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/jsonlog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseYear(t *testing.T) {
+	fixNow(t, time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+	classPath := filepath.Join(dir, "classifications.jsonl")
+	logPath := filepath.Join(dir, "expenses_log.jsonl")
+	incomePath := filepath.Join(dir, "income_log.jsonl")
+
+	aluguel := NewExpenseEntry("Aluguel", "05/12/2025", 2500, "Aluguel", "Moradia")
+	revised := aluguel.Edit()
+	revised.Value = 2600
+	moved := NewExpenseEntry("Mercado", "30/12/2025", 210, "Supermercado", "Alimentação")
+	movedOn := moved.Edit()
+	movedOn.Date = "02/01/2026"
+	course := NewExpenseEntry("Curso (2/3)", "01/01/2026", 30, "Cursos", "Educação")
+	for _, e := range []ExpenseEntry{aluguel, moved, course, revised, movedOn} {
+		require.NoError(t, AppendExpense(logPath, e))
+	}
+
+	december := NewManualEntry("Padaria", "20/12", 12, "Padaria", "Alimentação")
+	december.Timestamp = "2025-12-20T10:00:00Z"
+	undated := Entry{ID: "aaaaaaaaaaaa", Item: "Feira", Date: "03/11", Value: 40, Status: StatusManual}
+	for _, e := range []Entry{december, undated} {
+		require.NoError(t, Append(classPath, e))
+	}
+	require.NoError(t, os.WriteFile(incomePath, []byte(
+		`{"date":"05/12/2025","value":1000.0,"income_category":"Salário","income_label":"Base","item_note":"Dezembro"}`+"\n"+
+			`{"date":"05/01/2026","value":1000.0,"income_category":"Salário","income_label":"Base","item_note":"Janeiro"}`+"\n"), 0o644))
+
+	dry, err := CloseYear(classPath, logPath, incomePath, 2025, true)
+	require.NoError(t, err)
+	assert.Equal(t, YearClose{Classifications: 1, Expenses: 2, Income: 1, Undated: 1}, dry)
+	_, err = os.Stat(jsonlog.PartitionPath(logPath, 2025))
+	assert.True(t, os.IsNotExist(err), "dry run writes nothing")
+
+	done, err := CloseYear(classPath, logPath, incomePath, 2025, false)
+	require.NoError(t, err)
+	assert.Equal(t, dry, done)
+
+	archived := readLines[ExpenseEntry](t, jsonlog.PartitionPath(logPath, 2025))
+	assert.Equal(t, []string{aluguel.ID, aluguel.ID}, []string{archived[0].ID, archived[1].ID}, "an entry moves with its edits")
+	live := readLines[ExpenseEntry](t, logPath)
+	assert.Equal(t, []string{moved.ID, course.ID, moved.ID}, []string{live[0].ID, live[1].ID, live[2].ID},
+		"an entry edited into the next year stays whole")
+	assert.Len(t, readLines[Entry](t, jsonlog.PartitionPath(classPath, 2025)), 1)
+	assert.Len(t, readLines[IncomeEntry](t, jsonlog.PartitionPath(incomePath, 2025)), 1)
+
+	ids, err := LoadExpenseIDs(logPath)
+	require.NoError(t, err)
+	assert.True(t, ids[aluguel.ID], "archived IDs still count as logged")
+	e, found, err := FindLatestEntry(classPath, december.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "Padaria", e.Item)
+
+	again, err := CloseYear(classPath, logPath, incomePath, 2025, false)
+	require.NoError(t, err)
+	assert.Equal(t, YearClose{Undated: 1}, again, "nothing left to close")
+}
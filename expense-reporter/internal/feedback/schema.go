@@ -0,0 +1,83 @@
+package feedback
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+)
+
+// MarshalJSON writes the entry stamped with the current classifications schema.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	type plain Entry
+	b, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return logschema.Classifications.Stamp(b), nil
+}
+
+// UnmarshalJSON upgrades an older classifications line to the current schema
+// before decoding it.
+func (e *Entry) UnmarshalJSON(b []byte) error {
+	b, _, err := logschema.Classifications.Upgrade(b)
+	if err != nil {
+		return err
+	}
+	type plain Entry
+	return json.Unmarshal(b, (*plain)(e))
+}
+
+// MarshalJSON writes the entry stamped with the current expenses_log schema.
+func (e ExpenseEntry) MarshalJSON() ([]byte, error) {
+	type plain ExpenseEntry
+	b, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return logschema.Expenses.Stamp(b), nil
+}
+
+// UnmarshalJSON upgrades an older expenses_log line to the current schema
+// before decoding it.
+func (e *ExpenseEntry) UnmarshalJSON(b []byte) error {
+	b, _, err := logschema.Expenses.Upgrade(b)
+	if err != nil {
+		return err
+	}
+	type plain ExpenseEntry
+	return json.Unmarshal(b, (*plain)(e))
+}
+
+// MigrateClassifications rewrites the classifications log at path with every
+// line at the current schema. It returns how many lines were upgraded.
+func MigrateClassifications(path string) (int, error) {
+	return migrate[Entry](path, logschema.Classifications)
+}
+
+// MigrateExpenses rewrites the expense log at path with every line at the
+// current schema. It returns how many lines were upgraded.
+func MigrateExpenses(path string) (int, error) {
+	return migrate[ExpenseEntry](path, logschema.Expenses)
+}
+
+// migrate round-trips each older line through T, whose UnmarshalJSON upgrades
+// it and whose MarshalJSON stamps the current version. Current lines are kept
+// byte-for-byte.
+func migrate[T any](path string, schema *logschema.Schema) (int, error) {
+	return jsonlog.Rewrite(path, func(line []byte) ([]byte, error) {
+		v, err := logschema.Version(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing line: %w", err)
+		}
+		if v == schema.Current {
+			return line, nil
+		}
+		var rec T
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, err
+		}
+		return json.Marshal(rec)
+	})
+}
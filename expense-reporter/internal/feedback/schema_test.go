@@ -0,0 +1,72 @@
+package feedback
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpenseEntry_MarshalStampsSchema(t *testing.T) {
+	data, err := json.Marshal(NewExpenseEntry("Aluguel", "05/03/2026", 2500, "Aluguel", "Moradia"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `{"schema":3,"id":`), "got %s", data)
+}
+
+func TestEntry_UnmarshalLegacySheet(t *testing.T) {
+	var e Entry
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"abc","item":"Uber","sheet":"Variáveis","status":"confirmed"}`), &e))
+	assert.Equal(t, "Variáveis", e.Type)
+	assert.Equal(t, StatusConfirmed, e.Status)
+}
+
+func TestExpenseEntry_UnmarshalNewerSchemaFails(t *testing.T) {
+	var e ExpenseEntry
+	err := json.Unmarshal([]byte(`{"schema":9,"id":"abc"}`), &e)
+	assert.ErrorContains(t, err, "newer than this build supports")
+}
+
+func TestMigrateExpenses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	current := NewExpenseEntry("Mercado", "03/03/2026", 210, "Supermercado", "Alimentação")
+	currentLine, err := json.Marshal(current)
+	require.NoError(t, err)
+	legacy := `{"id":"aaaaaaaaaaaa","item":"Aluguel","date":"05/03/2026","value":2500,"subcategory":"Aluguel","category":"Moradia","sheet":"Fixas","timestamp":""}`
+	require.NoError(t, os.WriteFile(path, []byte(legacy+"\n"+string(currentLine)+"\n"), 0o644))
+
+	upgraded, err := MigrateExpenses(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, upgraded)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, string(currentLine), lines[1], "current lines are kept byte-for-byte")
+	assert.True(t, strings.HasPrefix(lines[0], `{"schema":3,`))
+	assert.NotContains(t, lines[0], `"sheet"`)
+
+	var got ExpenseEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, "Fixas", got.Type)
+	assert.Equal(t, "aaaaaaaaaaaa", got.ID)
+
+	upgraded, err = MigrateExpenses(path)
+	require.NoError(t, err)
+	assert.Zero(t, upgraded, "a second run has nothing to do")
+}
+
+func TestInvestmentEntry_MarshalStampsSchema(t *testing.T) {
+	data, err := json.Marshal(NewInvestmentEntry("Tesouro Selic", "05/03/2026", 1000, InvestmentContribution, "Renda fixa"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `{"schema":1,"id":`), "got %s", data)
+
+	var e InvestmentEntry
+	require.NoError(t, json.Unmarshal(data, &e))
+	assert.Equal(t, "Renda fixa", e.AssetClass)
+	assert.Equal(t, InvestmentContribution, e.Kind)
+}
@@ -0,0 +1,92 @@
+// Package fx converts foreign-currency expense amounts to BRL using a local
+// exchange-rate table (rates.json). The table is filled from Banco Central PTAX
+// quote files (see ParsePTAX) so conversion never needs the network.
+package fx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// dateKey is the rates.json date format — ISO so the file sorts chronologically.
+const dateKey = "2006-01-02"
+
+// maxLookbackDays bounds how far back Lookup walks for a quote. PTAX is not
+// published on weekends or bank holidays, so a purchase on a Saturday converts at
+// Friday's rate; a gap longer than a week means the table is stale, not closed.
+const maxLookbackDays = 7
+
+// ErrNoRate is returned by Lookup when no quote exists for the currency within
+// maxLookbackDays of the requested date.
+var ErrNoRate = errors.New("no exchange rate")
+
+// Table maps currency code → ISO date → BRL per one unit of the currency.
+// It is the in-memory form of rates.json.
+type Table map[string]map[string]float64
+
+// Load reads the rate table at path. A missing file yields an empty table, so a
+// fresh install without any imported quotes only fails when a conversion is needed.
+func Load(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Table{}, nil
+		}
+		return nil, fmt.Errorf("reading rates file: %w", err)
+	}
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parsing rates file: %w", err)
+	}
+	if t == nil {
+		t = Table{}
+	}
+	return t, nil
+}
+
+// Save writes the table to path as indented JSON (map keys sort, so the file
+// diffs cleanly between imports).
+func Save(path string, t Table) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling rates: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing rates file: %w", err)
+	}
+	return nil
+}
+
+// Set records rate for currency on date, replacing any existing quote.
+func (t Table) Set(currency string, date time.Time, rate float64) {
+	byDate, ok := t[currency]
+	if !ok {
+		byDate = map[string]float64{}
+		t[currency] = byDate
+	}
+	byDate[date.Format(dateKey)] = rate
+}
+
+// Lookup returns the rate for currency on date, falling back to the most recent
+// earlier quote within maxLookbackDays. quotedOn is the date of the quote used.
+func (t Table) Lookup(currency string, date time.Time) (rate float64, quotedOn time.Time, err error) {
+	byDate := t[currency]
+	for back := 0; back <= maxLookbackDays; back++ {
+		d := date.AddDate(0, 0, -back)
+		if r, ok := byDate[d.Format(dateKey)]; ok {
+			return r, time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return 0, time.Time{}, fmt.Errorf("%w for %s on or up to %d days before %s",
+		ErrNoRate, currency, maxLookbackDays, date.Format("02/01/2006"))
+}
+
+// Convert returns amount in BRL at rate plus the iofRate surcharge (a fraction,
+// 0.035 = 3.5%), rounded to centavos.
+func Convert(amount, rate, iofRate float64) float64 {
+	return math.Round(amount*rate*(1+iofRate)*100) / 100
+}
@@ -0,0 +1,67 @@
+package fx
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestLookup(t *testing.T) {
+	table := Table{}
+	table.Set("USD", day(2026, 4, 17), 5.10) // Friday
+
+	t.Run("exact date", func(t *testing.T) {
+		rate, on, err := table.Lookup("USD", day(2026, 4, 17))
+		require.NoError(t, err)
+		assert.Equal(t, 5.10, rate)
+		assert.Equal(t, day(2026, 4, 17), on)
+	})
+
+	t.Run("weekend falls back to previous quote", func(t *testing.T) {
+		rate, on, err := table.Lookup("USD", day(2026, 4, 19)) // Sunday
+		require.NoError(t, err)
+		assert.Equal(t, 5.10, rate)
+		assert.Equal(t, day(2026, 4, 17), on)
+	})
+
+	t.Run("stale table beyond lookback errors", func(t *testing.T) {
+		_, _, err := table.Lookup("USD", day(2026, 4, 30))
+		assert.True(t, errors.Is(err, ErrNoRate))
+	})
+
+	t.Run("unknown currency errors", func(t *testing.T) {
+		_, _, err := table.Lookup("EUR", day(2026, 4, 17))
+		assert.True(t, errors.Is(err, ErrNoRate))
+	})
+}
+
+func TestConvert(t *testing.T) {
+	assert.Equal(t, 102.0, Convert(20, 5.10, 0))
+	// 20 × 5.10 × 1.035 = 105.57
+	assert.Equal(t, 105.57, Convert(20, 5.10, 0.035))
+}
+
+func TestLoadSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+
+	empty, err := Load(path)
+	require.NoError(t, err, "a missing rates file is an empty table")
+	assert.Empty(t, empty)
+
+	table := Table{}
+	table.Set("USD", day(2026, 4, 17), 5.10)
+	table.Set("EUR", day(2026, 4, 17), 5.80)
+	require.NoError(t, Save(path, table))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, table, loaded)
+}
@@ -0,0 +1,97 @@
+package fx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Quote is one PTAX closing quote: BRL per unit of Currency on Date.
+type Quote struct {
+	Date     time.Time
+	Currency string
+	Buy      float64
+	Sell     float64
+}
+
+// ParsePTAX reads a Banco Central PTAX closing-quote CSV — the format of both the
+// daily bulletin (fechamento/YYYYMMDD.csv) and the per-currency history export:
+//
+//	15042026;220;A;USD;5,1234;5,1240;1,0000;1,0000
+//
+// Fields: date (DDMMYYYY); currency code; type; currency; buy rate; sell rate;
+// buy parity; sell parity. Blank lines are skipped; any other malformed line is
+// an error naming its line number.
+func ParsePTAX(r io.Reader) ([]Quote, error) {
+	var quotes []Quote
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		q, err := parsePTAXLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		quotes = append(quotes, q)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading PTAX file: %w", err)
+	}
+	return quotes, nil
+}
+
+func parsePTAXLine(line string) (Quote, error) {
+	fields := strings.Split(line, ";")
+	if len(fields) < 6 {
+		return Quote{}, fmt.Errorf("expected at least 6 fields, got %d", len(fields))
+	}
+	date, err := time.Parse("02012006", strings.TrimSpace(fields[0]))
+	if err != nil {
+		return Quote{}, fmt.Errorf("invalid date %q", fields[0])
+	}
+	buy, err := parseDecimal(fields[4])
+	if err != nil {
+		return Quote{}, fmt.Errorf("invalid buy rate %q", fields[4])
+	}
+	sell, err := parseDecimal(fields[5])
+	if err != nil {
+		return Quote{}, fmt.Errorf("invalid sell rate %q", fields[5])
+	}
+	return Quote{
+		Date:     date,
+		Currency: strings.TrimSpace(fields[3]),
+		Buy:      buy,
+		Sell:     sell,
+	}, nil
+}
+
+// parseDecimal parses a comma-decimal PTAX number ("5,1234").
+func parseDecimal(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+}
+
+// Import records each quote's sell rate (the rate card issuers convert at) into t.
+// When only is non-empty, quotes for other currencies are ignored. Returns the
+// number of quotes recorded.
+func (t Table) Import(quotes []Quote, only []string) int {
+	keep := map[string]bool{}
+	for _, c := range only {
+		keep[strings.ToUpper(strings.TrimSpace(c))] = true
+	}
+	n := 0
+	for _, q := range quotes {
+		if len(keep) > 0 && !keep[q.Currency] {
+			continue
+		}
+		t.Set(q.Currency, q.Date, q.Sell)
+		n++
+	}
+	return n
+}
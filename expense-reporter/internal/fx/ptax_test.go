@@ -0,0 +1,47 @@
+package fx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ptaxSample = `16042026;220;A;USD;5,1230;5,1236;1,0000;1,0000
+16042026;978;B;EUR;5,8001;5,8030;1,1320;1,1323
+
+17042026;220;A;USD;5,0990;5,1000;1,0000;1,0000
+`
+
+func TestParsePTAX(t *testing.T) {
+	quotes, err := ParsePTAX(strings.NewReader(ptaxSample))
+	require.NoError(t, err)
+	require.Len(t, quotes, 3)
+
+	assert.Equal(t, day(2026, 4, 16), quotes[0].Date)
+	assert.Equal(t, "USD", quotes[0].Currency)
+	assert.Equal(t, 5.1230, quotes[0].Buy)
+	assert.Equal(t, 5.1236, quotes[0].Sell)
+	assert.Equal(t, "EUR", quotes[1].Currency)
+}
+
+func TestParsePTAX_MalformedLineNamesLine(t *testing.T) {
+	_, err := ParsePTAX(strings.NewReader("16042026;220;A;USD;5,1230;5,1236\nnot-a-quote\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestImport_RecordsSellRateFilteredByCurrency(t *testing.T) {
+	quotes, err := ParsePTAX(strings.NewReader(ptaxSample))
+	require.NoError(t, err)
+
+	table := Table{}
+	n := table.Import(quotes, []string{"usd"})
+	assert.Equal(t, 2, n)
+	assert.NotContains(t, table, "EUR")
+
+	rate, _, err := table.Lookup("USD", day(2026, 4, 16))
+	require.NoError(t, err)
+	assert.Equal(t, 5.1236, rate, "card purchases convert at the PTAX sell rate")
+}
@@ -0,0 +1,219 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/xuri/excelize/v2"
+)
+
+// Budget panel columns. On an expense sheet the panel sits right of the month
+// triples (AM left blank) on each budgeted subcategory's total row; on Listas
+// it sits right of the months (P left blank).
+const (
+	budgetPlannedCol  = "AN"
+	budgetActualCol   = "AO"
+	budgetVarianceCol = "AP"
+	budgetConsumedCol = "AQ"
+
+	summaryPlannedCol  = "Q"
+	summaryActualCol   = "R"
+	summaryVarianceCol = "S"
+	summaryConsumedCol = "T"
+)
+
+// sheetHasBudget reports whether any subcategory of sh is budgeted.
+func sheetHasBudget(sh taxonomy.ExpenseType) bool {
+	for _, cat := range sh.Cats {
+		for _, sub := range cat.Subs {
+			if !sub.Budget.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeBudgetHeader writes the expense-sheet panel header: the merged
+// "Orçamento anual" banner on row 1 and Previsto/Realizado/Diferença/%
+// consumido on row 2.
+func writeBudgetHeader(f *excelize.File, st *styleSet, lbl Labels, name string) {
+	f.SetColWidth(name, budgetPlannedCol, budgetConsumedCol, 12.14)
+	f.MergeCell(name, budgetPlannedCol+"1", budgetConsumedCol+"1")
+	f.SetCellValue(name, budgetPlannedCol+"1", lbl.BudgetYear)
+	f.SetCellStyle(name, budgetPlannedCol+"1", budgetPlannedCol+"1", st.MonthBanner)
+	f.SetCellValue(name, budgetPlannedCol+"2", lbl.Planned)
+	f.SetCellValue(name, budgetActualCol+"2", lbl.Actual)
+	f.SetCellValue(name, budgetVarianceCol+"2", lbl.Variance)
+	f.SetCellValue(name, budgetConsumedCol+"2", lbl.Consumed)
+	f.SetCellStyle(name, budgetPlannedCol+"2", budgetConsumedCol+"2", st.HeaderCol)
+}
+
+// writeBudgetPanel writes a budgeted subcategory's panel on its total row: the
+// annual budget, the year's spending (the twelve month totals), their
+// difference, and the share consumed.
+func writeBudgetPanel(f *excelize.File, st *styleSet, name string, budget taxonomy.Budget, totalRow int) {
+	terms := make([]string, 12)
+	for k := range 12 {
+		terms[k] = cell(expenseValorCol(k), totalRow)
+	}
+	planned, actual := cell(budgetPlannedCol, totalRow), cell(budgetActualCol, totalRow)
+	f.SetCellValue(name, planned, budget.Year())
+	f.SetCellFormula(name, actual, sumList(terms))
+	f.SetCellFormula(name, cell(budgetVarianceCol, totalRow), planned+"-"+actual)
+	f.SetCellFormula(name, cell(budgetConsumedCol, totalRow), fmt.Sprintf("IF(%s>0,%s/%s,0)", planned, actual, planned))
+	f.SetCellStyle(name, planned, cell(budgetVarianceCol, totalRow), st.TotalValue)
+	f.SetCellStyle(name, cell(budgetConsumedCol, totalRow), cell(budgetConsumedCol, totalRow), st.TotalPct)
+	flagOverrun(f, st, name, cell(budgetVarianceCol, totalRow), cell(budgetConsumedCol, totalRow))
+}
+
+// flagOverrun adds the overrun highlight to a variance range (negative: spent
+// past the budget) and a consumed range (above 100%).
+func flagOverrun(f *excelize.File, st *styleSet, name, variance, consumed string) {
+	f.SetConditionalFormat(name, variance, []excelize.ConditionalFormatOptions{
+		{Type: "cell", Criteria: "<", Format: &st.Overrun, Value: "0"},
+	})
+	f.SetConditionalFormat(name, consumed, []excelize.ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: &st.Overrun, Value: "1"},
+	})
+}
+
+// hasBudgets reports whether any categoria in the registry is budgeted.
+func (r *layoutRegistry) hasBudgets() bool {
+	for _, name := range r.sheetOrder {
+		for _, ct := range r.expense[name].Cats {
+			if !ct.Budget.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// budgetHeader writes the Listas panel header: the merged "Orçamento anual"
+// banner on row 3 and the column labels on row 5.
+func (b *summaryBuilder) budgetHeader() {
+	f := b.f
+	f.SetColWidth(b.name, summaryPlannedCol, summaryConsumedCol, 16.43)
+	f.MergeCell(b.name, summaryPlannedCol+"3", summaryConsumedCol+"3")
+	f.SetCellValue(b.name, summaryPlannedCol+"3", b.lbl.BudgetYear)
+	f.SetCellStyle(b.name, summaryPlannedCol+"3", summaryConsumedCol+"3", b.st.SummaryMonth)
+	f.SetCellValue(b.name, summaryPlannedCol+"5", b.lbl.Planned)
+	f.SetCellValue(b.name, summaryActualCol+"5", b.lbl.Actual)
+	f.SetCellValue(b.name, summaryVarianceCol+"5", b.lbl.Variance)
+	f.SetCellValue(b.name, summaryConsumedCol+"5", b.lbl.Consumed)
+}
+
+// budgetCells writes a Listas row's panel: Q = planned (a formula), R = the
+// row's year (SUM of D..O), S = their difference, T = the share consumed.
+func (b *summaryBuilder) budgetCells(row int, planned string, curStyle, pctStyle int) {
+	f := b.f
+	q, r := cell(summaryPlannedCol, row), cell(summaryActualCol, row)
+	f.SetCellFormula(b.name, q, planned)
+	f.SetCellFormula(b.name, r, sumRange(cell(summaryMonthCol(0), row), cell(summaryMonthCol(11), row)))
+	f.SetCellFormula(b.name, cell(summaryVarianceCol, row), q+"-"+r)
+	f.SetCellFormula(b.name, cell(summaryConsumedCol, row), fmt.Sprintf("IF(%s>0,%s/%s,0)", q, r, q))
+	f.SetCellStyle(b.name, q, cell(summaryVarianceCol, row), curStyle)
+	f.SetCellStyle(b.name, cell(summaryConsumedCol, row), cell(summaryConsumedCol, row), pctStyle)
+	flagOverrun(f, b.st, b.name, cell(summaryVarianceCol, row), cell(summaryConsumedCol, row))
+}
+
+// writeCategoryBudgetRows emits a budgeted categoria's three rows beneath its
+// group total: "Previsto <cat>" (the monthly budget in D..O), "Diferença
+// <cat>" (planned minus spent) and "% consumido <cat>", then fills the group
+// total's panel from the Previsto row.
+func (b *summaryBuilder) writeCategoryBudgetRows(ct catTotals, groupTotalRow int) {
+	f, st := b.f, b.st
+	plannedRow := b.row
+	f.SetCellStyle(b.name, cell("B", plannedRow), cell("C", plannedRow), st.GroupTotalLbl)
+	f.SetCellValue(b.name, cell("B", plannedRow), fmt.Sprintf(b.lbl.PlannedCategoryFmt, ct.Category))
+	writePlannedMonths(f, b.name, plannedRow, ct.Budget)
+	f.SetCellStyle(b.name, cell(summaryMonthCol(0), plannedRow), cell(summaryMonthCol(11), plannedRow), st.GroupTotalCur)
+	b.row++
+
+	varianceRow := b.row
+	f.SetCellStyle(b.name, cell("B", varianceRow), cell("C", varianceRow), st.GroupTotalLbl)
+	f.SetCellValue(b.name, cell("B", varianceRow), fmt.Sprintf(b.lbl.VarianceCategoryFmt, ct.Category))
+	b.monthFormulas(varianceRow, st.GroupTotalCur, func(k int) string {
+		c := summaryMonthCol(k)
+		return cell(c, plannedRow) + "-" + cell(c, groupTotalRow)
+	})
+	b.row++
+
+	consumedRow := b.row
+	f.SetCellStyle(b.name, cell("B", consumedRow), cell("C", consumedRow), st.GroupTotalLbl)
+	f.SetCellValue(b.name, cell("B", consumedRow), fmt.Sprintf(b.lbl.ConsumedCategoryFmt, ct.Category))
+	b.monthFormulas(consumedRow, st.GroupTotalPct, func(k int) string {
+		return safeRatioFormula(summaryMonthCol(k), plannedRow, groupTotalRow)
+	})
+	b.row++
+
+	flagOverrun(f, st, b.name,
+		cell(summaryMonthCol(0), varianceRow)+":"+cell(summaryMonthCol(11), varianceRow),
+		cell(summaryMonthCol(0), consumedRow)+":"+cell(summaryMonthCol(11), consumedRow))
+	b.budgetCells(groupTotalRow, sumRange(cell(summaryMonthCol(0), plannedRow), cell(summaryMonthCol(11), plannedRow)), st.GroupTotalCur, st.GroupTotalPct)
+}
+
+// writePlannedMonths writes a categoria's monthly budget into D..O of a Listas row.
+func writePlannedMonths(f *excelize.File, name string, row int, budget taxonomy.Budget) {
+	for k := range 12 {
+		f.SetCellValue(name, cell(summaryMonthCol(k), row), budget.Month())
+	}
+}
+
+// checkBudgetPanel checks that a located block carries a budget panel exactly
+// when the taxonomy budgets its subcategory.
+func checkBudgetPanel(f *excelize.File, name string, block locatedBlock, budget taxonomy.Budget) error {
+	formula, err := f.GetCellFormula(name, cell(budgetActualCol, block.TotalRow))
+	if err != nil {
+		return err
+	}
+	if (formula != "") != !budget.IsZero() {
+		return fmt.Errorf("%s: the budget of %s/%s was added or removed in the taxonomy\n  Hint: regenerate it without --update", name, block.Group, block.Label)
+	}
+	return nil
+}
+
+// updatePlannedRows rewrites the monthly budget of every "Previsto <cat>" row
+// on Listas. The workbook must hold one such row per budgeted categoria, in
+// taxonomy order.
+func updatePlannedRows(f *excelize.File, lbl Labels, expenseSheets []taxonomy.ExpenseType) error {
+	type planned struct {
+		label  string
+		budget taxonomy.Budget
+	}
+	var want []planned
+	for _, sh := range expenseSheets {
+		for _, cat := range sh.Cats {
+			if b := cat.Planned(); !b.IsZero() {
+				want = append(want, planned{fmt.Sprintf(lbl.PlannedCategoryFmt, cat.Name), b})
+			}
+		}
+	}
+
+	rows, err := f.GetRows(lbl.SummarySheet)
+	if err != nil {
+		return err
+	}
+	prefix, _, _ := strings.Cut(lbl.PlannedCategoryFmt, "%s")
+	var found []int
+	var labels []string
+	for r, row := range rows {
+		if len(row) > 1 && strings.HasPrefix(row[1], prefix) {
+			found = append(found, r+1)
+			labels = append(labels, row[1])
+		}
+	}
+	hint := "the taxonomy's category budgets no longer match the workbook\n  Hint: regenerate it without --update"
+	if len(found) != len(want) {
+		return fmt.Errorf("%s: %d budgeted categories in the workbook, %d in the taxonomy: %s", lbl.SummarySheet, len(found), len(want), hint)
+	}
+	for i, w := range want {
+		if labels[i] != w.label {
+			return fmt.Errorf("%s: row %d is %q, the taxonomy expects %q: %s", lbl.SummarySheet, found[i], labels[i], w.label, hint)
+		}
+		writePlannedMonths(f, lbl.SummarySheet, found[i], w.budget)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// budgetedFixas is fixasSheet(1, 3) with Aluguel budgeted at 250/month.
+// Fixas: Diarista total row 4, Aluguel total row 8. Listas: the Fixas section
+// starts at row 18 — Diarista 18, Aluguel 19, Total Habitação 20, then the
+// Previsto/Diferença/% consumido rows 21-23.
+func budgetedFixas(monthly float64) []taxonomy.ExpenseType {
+	sheets := fixasSheet(1, 3)
+	sheets[0].Cats[0].Subs[1].Budget = taxonomy.Budget{Monthly: monthly}
+	return sheets
+}
+
+func TestBuildWorkbook_BudgetPanels(t *testing.T) {
+	require.Equal(t, 0, headroomRows)
+	require.True(t, perGroupPctRows)
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(budgetedFixas(250), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	get := func(sheet, ref string) string {
+		v, err := f.GetCellValue(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(sheet, ref string) string {
+		v, err := f.GetCellFormula(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, "Previsto", get("Fixas", "AN2"))
+	assert.Equal(t, "3000", get("Fixas", "AN8"))
+	assert.Equal(t, "SUM(E8,H8,K8,N8,Q8,T8,W8,Z8,AC8,AF8,AI8,AL8)", formula("Fixas", "AO8"))
+	assert.Equal(t, "AN8-AO8", formula("Fixas", "AP8"))
+	assert.Equal(t, "IF(AN8>0,AO8/AN8,0)", formula("Fixas", "AQ8"))
+	assert.Empty(t, formula("Fixas", "AO4"), "unbudgeted subcategories carry no panel")
+
+	assert.Equal(t, "Previsto", get(listas, "Q5"))
+	assert.Equal(t, "Fixas!AN8", formula(listas, "Q19"))
+	assert.Equal(t, "SUM(D19:O19)", formula(listas, "R19"))
+	assert.Empty(t, formula(listas, "Q18"))
+	assert.Equal(t, "Previsto Habitação", get(listas, "B21"))
+	assert.Equal(t, "250", get(listas, "D21"))
+	assert.Equal(t, "D21-D20", formula(listas, "D22"))
+	assert.Equal(t, "IF(D21>0,D20/D21,0)", formula(listas, "D23"))
+	assert.Equal(t, "SUM(D21:O21)", formula(listas, "Q20"))
+	assert.Equal(t, "Total despesas fixas", get(listas, "B26"), "the grand total lands below the budget and percent rows")
+	assert.Equal(t, "SUM(D20)", formula(listas, "D26"))
+	assert.Equal(t, "IF(D26>0,D20/D26,0)", formula(listas, "D24"), "% sobre despesas points at the grand total")
+
+	formats, err := f.GetConditionalFormats("Fixas")
+	require.NoError(t, err)
+	assert.Contains(t, formats, "AP8")
+	assert.Contains(t, formats, "AQ8")
+}
+
+func TestUpdateWorkbook_RefreshesBudgets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(budgetedFixas(250), salario, path))
+
+	// Diarista grows by two rows, moving the Aluguel panel from row 8 to 10.
+	sheets := budgetedFixas(300)
+	sheets[0].Cats[0].Subs[0] = fixasSheet(3, 3)[0].Cats[0].Subs[0]
+	require.NoError(t, updateWorkbook(sheets, salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	v, err := f.GetCellValue("Fixas", "AN10")
+	require.NoError(t, err)
+	assert.Equal(t, "3600", v)
+	formula, err := f.GetCellFormula("Fixas", "AO10")
+	require.NoError(t, err)
+	assert.Equal(t, "SUM(E10,H10,K10,N10,Q10,T10,W10,Z10,AC10,AF10,AI10,AL10)", formula)
+	formula, err = f.GetCellFormula(listas, "Q19")
+	require.NoError(t, err)
+	assert.Equal(t, "Fixas!AN10", formula)
+	v, err = f.GetCellValue(listas, "O21")
+	require.NoError(t, err)
+	assert.Equal(t, "300", v)
+}
+
+func TestUpdateWorkbook_RefusesAddedBudget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 3), salario, path))
+
+	err := updateWorkbook(budgetedFixas(250), salario, path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Habitação/Aluguel")
+	assert.Contains(t, err.Error(), "without --update")
+}
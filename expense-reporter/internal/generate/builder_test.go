@@ -10,6 +10,9 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
+// listas is the summary sheet of the pt-BR workbooks built here.
+const listas = "Listas de itens"
+
 // entriesInOneMonth builds a [12][]taxonomy.Entry with n identical entries placed in January.
 func entriesInOneMonth(n int) [12][]taxonomy.Entry {
 	var months [12][]taxonomy.Entry
@@ -83,12 +86,17 @@ func TestPlannedGrandTotalRow(t *testing.T) {
 		{Category: "B", Subs: make([]subcatTotal, 1)}, // 1+1+2 = 4
 	}
 	assert.Equal(t, 6+5+4, plannedGrandTotalRow(6, cats))
+
+	// A budgeted categoria adds its Previsto/Diferença/% consumido rows.
+	cats[1].Budget = taxonomy.Budget{Monthly: 100}
+	assert.Equal(t, 6+5+4+3, plannedGrandTotalRow(6, cats))
 }
 
 func TestNeedsQuoteAndSheetRef(t *testing.T) {
 	assert.False(t, needsQuote("Fixas"))
 	assert.True(t, needsQuote("Variáveis"))       // accented
 	assert.True(t, needsQuote("Listas de itens")) // spaces
+	assert.True(t, needsQuote("2025"))            // leading digit
 	assert.Equal(t, "Fixas!E5", sheetRef("Fixas", "E", 5))
 	assert.Equal(t, "'Listas de itens'!E5", sheetRef("Listas de itens", "E", 5))
 }
@@ -226,9 +234,9 @@ func TestBuildRevenueMultiBlock(t *testing.T) {
 
 	// Registry: 3 entries, one per leaf subline, in order.
 	require.Len(t, reg.revenue.Blocks, 3)
-	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Salário", Label: "Bruto", TotalRow: 4}, reg.revenue.Blocks[0])
-	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Salário", Label: "INSS", TotalRow: 6}, reg.revenue.Blocks[1])
-	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Variável", Label: "Comissão", TotalRow: 11}, reg.revenue.Blocks[2])
+	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Salário", Label: "Bruto", FirstData: 3, TotalRow: 4}, reg.revenue.Blocks[0])
+	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Salário", Label: "INSS", FirstData: 5, TotalRow: 6}, reg.revenue.Blocks[1])
+	assert.Equal(t, revenueBlockTotal{Category: "Receitas", Block: "Variável", Label: "Comissão", FirstData: 10, TotalRow: 11}, reg.revenue.Blocks[2])
 }
 
 // TestBuildExpenseTypeSizingAndSum checks max-entries sizing + the total SUM spans
@@ -284,50 +292,50 @@ func TestBuildSummaryRevenuePerBlock(t *testing.T) {
 
 	// Revenue section starts at row 6.
 	// Row 6: pull — Bruto (col C)
-	brutoLabel, err := f.GetCellValue(summarySheetName, "C6")
+	brutoLabel, err := f.GetCellValue(listas, "C6")
 	require.NoError(t, err)
 	assert.Equal(t, "Bruto", brutoLabel, "row 6 col C should be first Salário leaf")
 
 	// Row 7: pull — INSS (col C)
-	inssLabel, err := f.GetCellValue(summarySheetName, "C7")
+	inssLabel, err := f.GetCellValue(listas, "C7")
 	require.NoError(t, err)
 	assert.Equal(t, "INSS", inssLabel, "row 7 col C should be second Salário leaf")
 
 	// Row 6 col B should have the Block label "Salário" (merged over rows 6-7).
-	blockLabel, err := f.GetCellValue(summarySheetName, "B6")
+	blockLabel, err := f.GetCellValue(listas, "B6")
 	require.NoError(t, err)
 	assert.Equal(t, "Salário", blockLabel, "col B should carry the Block group label")
 
 	// Row 8: "Total Salário" group total — sums D6:D7 (contiguous pulls).
-	totalSalLabel, err := f.GetCellValue(summarySheetName, "B8")
+	totalSalLabel, err := f.GetCellValue(listas, "B8")
 	require.NoError(t, err)
 	assert.Equal(t, fmt.Sprintf(lbl.TotalCategoryFmt, "Salário"), totalSalLabel)
-	totalSalFormula, err := f.GetCellFormula(summarySheetName, "D8")
+	totalSalFormula, err := f.GetCellFormula(listas, "D8")
 	require.NoError(t, err)
 	assert.Equal(t, "SUM(D6:D7)", totalSalFormula, "group-total should sum its pull range")
 
 	// Row 9: pull — Comissão (Variável, single-leaf block).
-	comissaoLabel, err := f.GetCellValue(summarySheetName, "C9")
+	comissaoLabel, err := f.GetCellValue(listas, "C9")
 	require.NoError(t, err)
 	assert.Equal(t, "Comissão", comissaoLabel)
 
 	// Row 10: "Total Variável" — single-pull group; formula sums D9 (collapsed range).
-	totalVarLabel, err := f.GetCellValue(summarySheetName, "B10")
+	totalVarLabel, err := f.GetCellValue(listas, "B10")
 	require.NoError(t, err)
 	assert.Equal(t, fmt.Sprintf(lbl.TotalCategoryFmt, "Variável"), totalVarLabel)
-	totalVarFormula, err := f.GetCellFormula(summarySheetName, "D10")
+	totalVarFormula, err := f.GetCellFormula(listas, "D10")
 	require.NoError(t, err)
 	assert.Equal(t, "SUM(D9)", totalVarFormula, "single-leaf group-total collapses to SUM(D9)")
 
 	// Row 11: internal bandRow separator.
 	// Row 12: Receitas grand total — sumList of D8 and D10 (block totals, non-contiguous).
-	grandFormula, err := f.GetCellFormula(summarySheetName, "D12")
+	grandFormula, err := f.GetCellFormula(listas, "D12")
 	require.NoError(t, err)
 	assert.Equal(t, "SUM(D8,D10)", grandFormula, "grand total should sum block-total rows only")
 
 	// Confirm the Investimentos % formula references the grand-total row 12 (revenueTotalRow).
 	// Layout: grand total row 12, b.row+=3 → row 15 (Investimentos shell), 16 (total), 17 (pct).
-	pctFormula, err := f.GetCellFormula(summarySheetName, "D17")
+	pctFormula, err := f.GetCellFormula(listas, "D17")
 	require.NoError(t, err)
 	assert.Contains(t, pctFormula, "D12", "Investimentos % should reference the grand-total row 12")
 }
@@ -350,11 +358,11 @@ func TestBuildSummaryPerGroupPctRows(t *testing.T) {
 	require.NoError(t, buildSummarySheet(f, st, lbl, reg))
 
 	// Scan column B of Listas for the per-group percent labels.
-	rows, err := f.GetRows(summarySheetName)
+	rows, err := f.GetRows(listas)
 	require.NoError(t, err)
 	var pctExpRow int
 	for r := 1; r <= len(rows); r++ {
-		v, _ := f.GetCellValue(summarySheetName, cell("B", r))
+		v, _ := f.GetCellValue(listas, cell("B", r))
 		if v == lbl.PctOfExpenses {
 			pctExpRow = r
 			break
@@ -363,7 +371,7 @@ func TestBuildSummaryPerGroupPctRows(t *testing.T) {
 	require.NotZero(t, pctExpRow, "expected a %q row in column B", lbl.PctOfExpenses)
 
 	// The Jan formula on that row (col D) must be an IF percent expression.
-	formula, err := f.GetCellFormula(summarySheetName, cell("D", pctExpRow))
+	formula, err := f.GetCellFormula(listas, cell("D", pctExpRow))
 	require.NoError(t, err)
 	assert.Contains(t, formula, "IF(")
 	assert.Contains(t, formula, "/")
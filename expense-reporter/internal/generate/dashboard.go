@@ -0,0 +1,140 @@
+package generate
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// topCategories is how many categorias the pie shows before folding the rest
+// into "Outras".
+const topCategories = 6
+
+// buildDashboard writes the dashboard sheet: four charts wired to the Listas
+// rows recorded in reg.summary, so they follow every recalculation. The pie
+// reads a small ranking table on the sheet itself (each categoria's year total,
+// ranked by formula), since a chart cannot sort its source.
+func buildDashboard(f *excelize.File, st *styleSet, lbl Labels, reg *layoutRegistry) error {
+	name := lbl.DashboardSheet
+	if _, err := f.NewSheet(name); err != nil {
+		return err
+	}
+	f.SetColWidth(name, "A", "A", 28)
+	f.SetColWidth(name, "B", "B", 16.43)
+	f.SetColWidth(name, "C", "C", 9)
+	f.SetColWidth(name, "D", "D", 28)
+	f.SetColWidth(name, "E", "E", 16.43)
+
+	topFirst, topLast := writeCategoryRanking(f, st, lbl, name, reg.summary.CategoryRows)
+
+	sum := reg.summary
+	months := absRange(lbl.SummarySheet, summaryMonthCol(0), 3, summaryMonthCol(11), 3)
+	monthSeries := func(row int, labelCol string) excelize.ChartSeries {
+		return excelize.ChartSeries{
+			Name:       absRange(lbl.SummarySheet, labelCol, row, labelCol, row),
+			Categories: months,
+			Values:     absRange(lbl.SummarySheet, summaryMonthCol(0), row, summaryMonthCol(11), row),
+		}
+	}
+
+	var byType []excelize.ChartSeries
+	for _, s := range reg.sheetOrder {
+		byType = append(byType, monthSeries(sum.SheetRows[s], "A"))
+	}
+	charts := []struct {
+		at    string
+		chart *excelize.Chart
+	}{
+		{"G2", &excelize.Chart{
+			Type:   excelize.Line,
+			Title:  []excelize.RichTextRun{{Text: lbl.ChartRevenueVsExpenses}},
+			Series: []excelize.ChartSeries{monthSeries(sum.RevenueRow, "A"), monthSeries(sum.ExpensesRow, "A")},
+		}},
+		{"P2", &excelize.Chart{
+			Type:   excelize.ColStacked,
+			Title:  []excelize.RichTextRun{{Text: lbl.ChartExpensesByType}},
+			Series: byType,
+		}},
+		{"G20", &excelize.Chart{
+			Type:  excelize.Pie,
+			Title: []excelize.RichTextRun{{Text: lbl.ChartTopCategories}},
+			Series: []excelize.ChartSeries{{
+				Name:       absRange(name, "D", 2, "D", 2),
+				Categories: absRange(name, "D", topFirst, "D", topLast),
+				Values:     absRange(name, "E", topFirst, "E", topLast),
+			}},
+			PlotArea: excelize.ChartPlotArea{ShowPercent: true},
+		}},
+		{"P20", &excelize.Chart{
+			Type:   excelize.Line,
+			Title:  []excelize.RichTextRun{{Text: lbl.ChartBalance}},
+			Series: []excelize.ChartSeries{monthSeries(sum.BalanceRow, "A")},
+		}},
+	}
+	for _, c := range charts {
+		c.chart.Legend = excelize.ChartLegend{Position: "bottom"}
+		c.chart.Dimension = excelize.ChartDimension{Width: 560, Height: 320}
+		if err := f.AddChart(name, c.at, c.chart); err != nil {
+			return fmt.Errorf("chart %s: %w", c.chart.Title[0].Text, err)
+		}
+	}
+	return nil
+}
+
+// writeCategoryRanking writes the pie's source: A..C list every categoria with
+// its year total (pulled from its Listas group total) and a unique rank (ties
+// broken by order); D..E list the top categorias by rank, then "Outras" for the
+// rest. It returns the first and last rows of D..E.
+func writeCategoryRanking(f *excelize.File, st *styleSet, lbl Labels, name string, cats []categoryRow) (topFirst, topLast int) {
+	const first = 3
+	f.SetCellValue(name, "A2", lbl.Category)
+	f.SetCellValue(name, "B2", lbl.YearTotal)
+	f.SetCellValue(name, "C2", lbl.Rank)
+	f.SetCellValue(name, "D2", lbl.ChartTopCategories)
+	f.SetCellValue(name, "E2", lbl.YearTotal)
+	f.SetCellStyle(name, "A2", "E2", st.SummaryTotalLbl)
+
+	last := first + len(cats) - 1
+	names := absRange("", "A", first, "A", last)
+	totals := absRange("", "B", first, "B", last)
+	ranks := absRange("", "C", first, "C", last)
+	for i, c := range cats {
+		r := first + i
+		f.SetCellValue(name, cell("A", r), fmt.Sprintf(lbl.DashboardCategoryFmt, c.Category, c.Sheet))
+		f.SetCellFormula(name, cell("B", r), sumRange(
+			sheetRef(lbl.SummarySheet, summaryMonthCol(0), c.Row), cell(summaryMonthCol(11), c.Row)))
+		f.SetCellFormula(name, cell("C", r), fmt.Sprintf("RANK(%s,%s)+COUNTIF(%s:%s,%s)-1",
+			cell("B", r), totals, fmt.Sprintf("$B$%d", first), cell("B", r), cell("B", r)))
+		f.SetCellStyle(name, cell("B", r), cell("B", r), st.PullCur)
+	}
+
+	top := min(topCategories, len(cats))
+	for k := 1; k <= top; k++ {
+		r := first + k - 1
+		f.SetCellFormula(name, cell("D", r), fmt.Sprintf("INDEX(%s,MATCH(%d,%s,0))", names, k, ranks))
+		f.SetCellFormula(name, cell("E", r), fmt.Sprintf("INDEX(%s,MATCH(%d,%s,0))", totals, k, ranks))
+		f.SetCellStyle(name, cell("E", r), cell("E", r), st.PullCur)
+	}
+	topLast = first + top - 1
+	if len(cats) > top {
+		topLast++
+		f.SetCellValue(name, cell("D", topLast), lbl.Others)
+		f.SetCellFormula(name, cell("E", topLast), fmt.Sprintf("SUM(%s)-SUM(%s)", totals, absRange("", "E", first, "E", topLast-1)))
+		f.SetCellStyle(name, cell("E", topLast), cell("E", topLast), st.PullCur)
+	}
+	return first, topLast
+}
+
+// absRange formats an absolute range ($A$1:$B$2, or $A$1 for a single cell),
+// prefixed with the quoted sheet name unless sheet is "" — the form chart
+// series require.
+func absRange(sheet, c1 string, r1 int, c2 string, r2 int) string {
+	ref := fmt.Sprintf("$%s$%d", c1, r1)
+	if c1 != c2 || r1 != r2 {
+		ref += fmt.Sprintf(":$%s$%d", c2, r2)
+	}
+	if sheet == "" {
+		return ref
+	}
+	return fmt.Sprintf("'%s'!%s", sheet, ref)
+}
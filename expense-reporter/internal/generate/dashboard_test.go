@@ -0,0 +1,80 @@
+package generate
+
+import (
+	"archive/zip"
+	"html"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuildWorkbook_Dashboard(t *testing.T) {
+	withDashboard = true
+	t.Cleanup(func() { withDashboard = false })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	sheets := fixasSheet(1, 3)
+	sheets[0].Cats = append(sheets[0].Cats, taxonomy.Category{Name: "Lazer", Subs: []taxonomy.Subcat{{Name: "Netflix"}}})
+	require.NoError(t, buildWorkbook(sheets, salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{listas, "Painel", "Receitas", "Fixas"}, f.GetSheetList())
+
+	// Listas group totals: Habitação on row 20, Lazer on row 24.
+	get := func(ref string) string {
+		v, err := f.GetCellValue("Painel", ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(ref string) string {
+		v, err := f.GetCellFormula("Painel", ref)
+		require.NoError(t, err)
+		return v
+	}
+	assert.Equal(t, "Habitação (Fixas)", get("A3"))
+	assert.Equal(t, "Lazer (Fixas)", get("A4"))
+	assert.Equal(t, "SUM('Listas de itens'!D24:O24)", formula("B4"))
+	assert.Equal(t, "RANK(B4,$B$3:$B$4)+COUNTIF($B$3:B4,B4)-1", formula("C4"))
+	assert.Equal(t, "INDEX($B$3:$B$4,MATCH(2,$C$3:$C$4,0))", formula("E4"))
+
+	charts := chartXML(t, path)
+	require.Len(t, charts, 4)
+	all := strings.Join(charts, "\n")
+	assert.Contains(t, all, "<lineChart>")
+	assert.Contains(t, all, "<barChart>")
+	assert.Contains(t, all, "<pieChart>")
+	assert.Contains(t, all, "'Listas de itens'!$D$3:$O$3", "months come from the Listas header")
+	assert.Contains(t, all, "'Painel'!$E$3:$E$4")
+	saldo, err := f.GetCellValue(listas, "A41")
+	require.NoError(t, err)
+	require.Equal(t, "Saldo", saldo)
+	assert.Contains(t, all, "'Listas de itens'!$A$41", "series are named by their Listas labels")
+}
+
+// chartXML returns the chart parts of the xlsx package at path.
+func chartXML(t *testing.T, path string) []string {
+	t.Helper()
+	zr, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer zr.Close()
+	var charts []string
+	for _, zf := range zr.File {
+		if !strings.HasPrefix(zf.Name, "xl/charts/chart") {
+			continue
+		}
+		rc, err := zf.Open()
+		require.NoError(t, err)
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		charts = append(charts, html.UnescapeString(string(b)))
+	}
+	return charts
+}
@@ -45,8 +45,7 @@ func writeTotalRowOpt(f *excelize.File, st *styleSet, lbl Labels, name string, f
 		itemCol, dataCol, valorCol := expenseMonthCols(k)
 		f.SetCellValue(name, cell(itemCol, totalRow), lbl.Total)
 		f.SetCellValue(name, cell(dataCol, totalRow), lbl.TotalDash)
-		formula := fmt.Sprintf("SUM(%s:%s)", cell(valorCol, firstData), cell(valorCol, lastData))
-		f.SetCellFormula(name, cell(valorCol, totalRow), formula)
+		f.SetCellFormula(name, cell(valorCol, totalRow), monthSum(k, firstData, lastData))
 		itemStyle, valueStyle := st.TotalText, st.TotalValue
 		if groupSeps && k%2 == 1 { // vertical separator every 2 month-groups (golden pattern)
 			itemStyle, valueStyle = st.TotalTextLeft, st.TotalValueRight
@@ -57,6 +56,12 @@ func writeTotalRowOpt(f *excelize.File, st *styleSet, lbl Labels, name string, f
 	}
 }
 
+// monthSum is the total-row formula of month k over a block's data rows.
+func monthSum(k, firstData, lastData int) string {
+	valorCol := expenseValorCol(k)
+	return fmt.Sprintf("SUM(%s:%s)", cell(valorCol, firstData), cell(valorCol, lastData))
+}
+
 func writeSeparator(f *excelize.File, st *styleSet, name string, row int, lastCol string) {
 	f.SetCellStyle(name, cell("A", row), cell(lastCol, row), st.Separator)
 }
@@ -84,7 +89,14 @@ func calculateBlockRows(row, maxEntries int) (firstData, lastData, totalRow int)
 // the typed entries. rowHeight is 12.75 for expense sheets and 15 for revenue sheets.
 func writeDataBand(f *excelize.File, st *styleSet, name string, months [12][]taxonomy.Entry,
 	firstData, lastData int, rowHeight float64, lastCol string) {
-	for r := firstData; r <= lastData; r++ {
+	styleDataRows(f, st, name, firstData, firstData, lastData, rowHeight, lastCol)
+	writeEntries(f, name, months, firstData)
+}
+
+// styleDataRows sets row heights and cell styles for rows from..to of a data band
+// starting at firstData (its first row carries the top border).
+func styleDataRows(f *excelize.File, st *styleSet, name string, firstData, from, to int, rowHeight float64, lastCol string) {
+	for r := from; r <= to; r++ {
 		f.SetRowHeight(name, r, rowHeight)
 		style := st.DataCellArial
 		if r == firstData {
@@ -97,7 +109,10 @@ func writeDataBand(f *excelize.File, st *styleSet, name string, months [12][]tax
 			f.SetCellStyle(name, cell(valorCol, r), cell(valorCol, r), st.Currency)
 		}
 	}
+}
 
+// writeEntries fills each month's item/date/value triples from firstData down.
+func writeEntries(f *excelize.File, name string, months [12][]taxonomy.Entry, firstData int) {
 	for k := 0; k < 12; k++ {
 		itemCol, dataCol, valorCol := expenseMonthCols(k)
 		for i, entry := range months[k] {
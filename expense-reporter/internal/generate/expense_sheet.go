@@ -17,17 +17,24 @@ func buildExpenseType(f *excelize.File, st *styleSet, lbl Labels, sh taxonomy.Ex
 	setDataSheetWidths(f, name)
 	writeMonthHeader(f, st, lbl, name)
 	freezeC3(f, name)
+	if sheetHasBudget(sh) {
+		writeBudgetHeader(f, st, lbl, name)
+	}
 
 	layout := &sheetLayout{Sheet: name}
 	row := 3
 	for ci, cat := range sh.Cats {
-		ct := catTotals{Category: cat.Name}
+		ct := catTotals{Category: cat.Name, Budget: cat.Planned()}
 		catFirst := row
 		for _, sub := range cat.Subs {
 			firstData, lastData, totalRow := calculateBlockRows(row, sub.MaxEntries())
 			writeSubcatBlock(f, st, lbl, name, sub, firstData, lastData, totalRow)
+			if !sub.Budget.IsZero() {
+				writeBudgetPanel(f, st, name, sub.Budget, totalRow)
+			}
 			ct.Subs = append(ct.Subs, subcatTotal{
-				Sheet: name, Category: cat.Name, Subcat: sub.Name, TotalRow: totalRow,
+				Sheet: name, Category: cat.Name, Subcat: sub.Name, FirstData: firstData, TotalRow: totalRow,
+				Budget: sub.Budget,
 			})
 			row = totalRow + 1
 		}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"expense-reporter/internal/taxonomy"
 	"github.com/xuri/excelize/v2"
@@ -24,14 +25,66 @@ var perGroupPctRows = true
 // dataYear is the config year applied to entry dates.
 var dataYear = 2026
 
+// workbookLocale selects the label set and number formats (see Locales).
+var workbookLocale = "pt-BR"
+
+// withDashboard adds the chart dashboard sheet after Listas.
+var withDashboard = false
+
+// investmentClasses holds the taxonomy's asset classes with their ledger
+// entries; when set, they replace the manual Investimentos row on Listas.
+var investmentClasses []taxonomy.AssetClass
+
+// accountTotals holds the paying accounts' monthly expenses; when non-nil, the
+// accounts sheet is built after Listas.
+var accountTotals []taxonomy.Group
+
+// tagTotals holds the tags' monthly expenses; when non-nil, the tags sheet is
+// built after the accounts sheet.
+var tagTotals []taxonomy.Group
+
 // Options configures one workbook generation run.
 type Options struct {
 	TaxonomyPath      string // taxonomy JSON file (spec §1.1) — required
 	EntriesPath       string // expenses_log.jsonl-format entries; empty = skeleton only
 	IncomeEntriesPath string // income_log.jsonl-format income entries; empty = none (WS-C)
+	InvestmentsPath   string // investments_log.jsonl ledger; empty = classes left at zero
 	OutPath           string // output .xlsx path — required
 	Year              int    // year applied to entry dates (DD/MM in the log has no year)
 	Headroom          int    // spare data rows per block beyond max-entries (spec §3.2; default 0)
+
+	// ExcludeIDs lists entry IDs to leave out of the workbook: the installments
+	// of plans closed before 'installments cancel' voided them in the log (see
+	// installment.Plan.Voided); nil keeps every entry.
+	ExcludeIDs map[string]bool
+
+	// Locale selects the workbook's labels and number formats (see Locales);
+	// empty is pt-BR. An update must use the locale the workbook was built with.
+	Locale string
+
+	// Dashboard adds a sheet of charts wired to the Listas totals. An update
+	// leaves an existing dashboard as it is: Listas rows do not move.
+	Dashboard bool
+
+	// Accounts adds a sheet totalling each paying card or account per month.
+	// ClosingDays maps cards to their invoice closing day, so a card's months
+	// match its invoices; cards without one keep the purchase month. An update
+	// rebuilds the sheet.
+	Accounts    bool
+	ClosingDays map[string]int
+
+	// Tags adds a sheet totalling each tag per month, by purchase month. An
+	// expense counts under each of its tags. An update rebuilds the sheet.
+	Tags bool
+
+	// Years, when set, builds a multi-year workbook instead: one sheet per
+	// year and a year-over-year comparison, read from a log spanning the years.
+	// Year is then unused.
+	Years []int
+
+	// Update rewrites the data cells of the workbook already generated at
+	// OutPath instead of building a new one, keeping what was added by hand.
+	Update bool
 }
 
 // Generate builds the workbook described by opts and writes it to opts.OutPath.
@@ -42,16 +95,106 @@ func Generate(opts Options) error {
 	if opts.OutPath == "" {
 		return fmt.Errorf("output path is required")
 	}
+	if _, err := newLabels(opts.Locale); err != nil {
+		return err
+	}
+	workbookLocale = opts.Locale
+	if workbookLocale == "" {
+		workbookLocale = Locales[0]
+	}
 	dataYear = opts.Year
 	headroomRows = opts.Headroom
+	withDashboard = opts.Dashboard
+	accountTotals = nil
+	tagTotals = nil
 
-	expenseSheets, revenueBlocks, err := taxonomy.LoadTaxonomy(opts.TaxonomyPath, opts.EntriesPath, opts.IncomeEntriesPath, opts.Year)
+	if len(opts.Years) > 0 {
+		if opts.Accounts {
+			return fmt.Errorf("the accounts sheet is not available in a multi-year workbook")
+		}
+		if opts.Tags {
+			return fmt.Errorf("the tags sheet is not available in a multi-year workbook")
+		}
+		return generateYears(opts)
+	}
+
+	expenseSheets, revenueBlocks, err := taxonomy.LoadTaxonomyExcluding(opts.TaxonomyPath, opts.EntriesPath, opts.IncomeEntriesPath, opts.Year, opts.ExcludeIDs)
+	if err != nil {
+		return err
+	}
+	investmentClasses, err = taxonomy.LoadInvestments(opts.TaxonomyPath, opts.InvestmentsPath, opts.Year)
 	if err != nil {
 		return err
 	}
+	if opts.Accounts {
+		accountTotals = []taxonomy.Group{}
+		if opts.EntriesPath != "" {
+			if accountTotals, err = taxonomy.LoadAccounts(opts.EntriesPath, opts.Year, opts.ClosingDays, opts.ExcludeIDs); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Tags {
+		tagTotals = []taxonomy.Group{}
+		if opts.EntriesPath != "" {
+			if tagTotals, err = taxonomy.LoadTags(opts.EntriesPath, opts.Year, opts.ExcludeIDs); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Update {
+		return updateWorkbook(expenseSheets, revenueBlocks, opts.OutPath)
+	}
 	return buildWorkbook(expenseSheets, revenueBlocks, opts.OutPath)
 }
 
+// generateYears builds the multi-year workbook of opts.Years. The log is loaded
+// across every year (target year 0); a DD/MM entry is placed in the year it
+// was logged in, and only those without a timestamp to go by are left out,
+// with a note.
+func generateYears(opts Options) error {
+	if opts.Update {
+		return fmt.Errorf("a multi-year workbook cannot be updated in place; regenerate it")
+	}
+	years := slices.Clone(opts.Years)
+	slices.Sort(years)
+	years = slices.Compact(years)
+
+	expenseSheets, revenueBlocks, err := taxonomy.LoadTaxonomyExcluding(opts.TaxonomyPath, opts.EntriesPath, opts.IncomeEntriesPath, 0, opts.ExcludeIDs)
+	if err != nil {
+		return err
+	}
+	if n := countUndated(expenseSheets, revenueBlocks); n > 0 {
+		fmt.Fprintf(os.Stderr, "note: entries without a year (DD/MM) or a timestamp left out of the multi-year workbook: %d\n", n)
+	}
+	return buildYearsWorkbook(expenseSheets, revenueBlocks, years, opts.OutPath)
+}
+
+// countUndated counts the loaded entries that could not be given a year.
+func countUndated(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock) int {
+	n := 0
+	count := func(months [12][]taxonomy.Entry) {
+		for _, entries := range months {
+			for _, e := range entries {
+				if e.Year == 0 {
+					n++
+				}
+			}
+		}
+	}
+	for _, sh := range expenseSheets {
+		for _, cat := range sh.Cats {
+			for _, sub := range cat.Subs {
+				count(sub.Months)
+			}
+		}
+	}
+	for _, b := range revenueBlocks {
+		count(b.Months)
+	}
+	return n
+}
+
 // buildWorkbook renders the loaded taxonomy+entries into an xlsx file (port of
 // the scratch builder's run()).
 func buildWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock, outPath string) error {
@@ -62,7 +205,10 @@ func buildWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonom
 	if err != nil {
 		return fmt.Errorf("styles: %w", err)
 	}
-	lbl := newPtBRLabels()
+	lbl, err := newLabels(workbookLocale)
+	if err != nil {
+		return err
+	}
 	reg := newLayoutRegistry()
 
 	// Build source sheets first so Listas can wire to their total rows.
@@ -77,6 +223,21 @@ func buildWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonom
 	if err := buildSummarySheet(f, st, lbl, reg); err != nil {
 		return fmt.Errorf("listas: %w", err)
 	}
+	if withDashboard {
+		if err := buildDashboard(f, st, lbl, reg); err != nil {
+			return fmt.Errorf("dashboard: %w", err)
+		}
+	}
+	if accountTotals != nil {
+		if err := buildGroupSheet(f, st, lbl, accountsSheet(lbl, accountTotals)); err != nil {
+			return fmt.Errorf("accounts: %w", err)
+		}
+	}
+	if tagTotals != nil {
+		if err := buildGroupSheet(f, st, lbl, tagsSheet(lbl, tagTotals)); err != nil {
+			return fmt.Errorf("tags: %w", err)
+		}
+	}
 
 	if err := orderSheets(f, lbl, expenseSheets); err != nil {
 		return err
@@ -94,14 +255,24 @@ func buildWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonom
 	return saveWorkbook(f, outPath)
 }
 
-// orderSheets removes the default sheet and orders: Listas, Receitas, then the
-// expense sheets in taxonomy order. MoveSheet(source, target) moves source
+// orderSheets removes the default sheet and orders: Listas, the dashboard, the
+// accounts and the tags sheets (when built), Receitas, then the expense sheets in taxonomy order. MoveSheet(source, target) moves source
 // before target, so we walk the order backward.
 func orderSheets(f *excelize.File, lbl Labels, expenseSheets []taxonomy.ExpenseType) error {
 	if err := f.DeleteSheet("Sheet1"); err != nil {
 		return err
 	}
-	order := []string{summarySheetName, lbl.RevenueSheet}
+	order := []string{lbl.SummarySheet}
+	if withDashboard {
+		order = append(order, lbl.DashboardSheet)
+	}
+	if accountTotals != nil {
+		order = append(order, lbl.AccountsSheet)
+	}
+	if tagTotals != nil {
+		order = append(order, lbl.TagsSheet)
+	}
+	order = append(order, lbl.RevenueSheet)
 	for _, sh := range expenseSheets {
 		order = append(order, sh.Name)
 	}
@@ -110,7 +281,7 @@ func orderSheets(f *excelize.File, lbl Labels, expenseSheets []taxonomy.ExpenseT
 			return fmt.Errorf("move %s: %w", order[i], err)
 		}
 	}
-	if i, _ := f.GetSheetIndex(summarySheetName); i >= 0 {
+	if i, _ := f.GetSheetIndex(lbl.SummarySheet); i >= 0 {
 		f.SetActiveSheet(i)
 	}
 	return nil
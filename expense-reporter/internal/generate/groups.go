@@ -0,0 +1,121 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/xuri/excelize/v2"
+)
+
+// Group sheet layout (accounts, tags): one row per group under the header, then
+// the total. Months fill B..M.
+const (
+	groupFirstRow = 2
+	groupTotalCol = "N"
+)
+
+// groupMonthCol returns the column of month k on a group sheet (B..M).
+func groupMonthCol(k int) string { return colName(1 + k) }
+
+// groupSheet describes a sheet of monthly totals per group.
+type groupSheet struct {
+	name    string // sheet name
+	header  string // column A header
+	unnamed string // label of the group named ""
+	groups  []taxonomy.Group
+	total   bool // add a total row; false when an entry can count in several groups
+}
+
+// accountsSheet is the accounts sheet: each paying account's monthly expenses,
+// as the card invoices bill them when closing days were given. Entries
+// recording no account share one row.
+func accountsSheet(lbl Labels, accounts []taxonomy.Group) groupSheet {
+	return groupSheet{name: lbl.AccountsSheet, header: lbl.Account, unnamed: lbl.NoAccount, groups: accounts, total: true}
+}
+
+// tagsSheet is the tags sheet: each tag's monthly expenses across categories.
+// An expense counts under each of its tags, so it has no total row.
+func tagsSheet(lbl Labels, tags []taxonomy.Group) groupSheet {
+	return groupSheet{name: lbl.TagsSheet, header: lbl.Tag, groups: tags}
+}
+
+// buildGroupSheet writes g: a row of monthly values and their sum per group,
+// then the month totals when g.total.
+func buildGroupSheet(f *excelize.File, st *styleSet, lbl Labels, g groupSheet) error {
+	name := g.name
+	if _, err := f.NewSheet(name); err != nil {
+		return err
+	}
+	f.SetColWidth(name, "A", "A", 24)
+	f.SetColWidth(name, groupMonthCol(0), groupTotalCol, 14.29)
+
+	f.SetCellValue(name, "A1", g.header)
+	for k := range 12 {
+		f.SetCellValue(name, groupMonthCol(k)+"1", lbl.MonthNames[k])
+	}
+	f.SetCellValue(name, groupTotalCol+"1", lbl.Total)
+	f.SetCellStyle(name, "A1", groupTotalCol+"1", st.SummaryMonth)
+
+	row := groupFirstRow
+	for _, grp := range g.groups {
+		label := grp.Name
+		if label == "" {
+			label = g.unnamed
+		}
+		f.SetCellValue(name, cell("A", row), label)
+		f.SetCellStyle(name, cell("A", row), cell("A", row), st.DataCellArial)
+		for k, v := range grp.Months {
+			f.SetCellValue(name, cell(groupMonthCol(k), row), v)
+		}
+		f.SetCellFormula(name, cell(groupTotalCol, row), sumRange(cell(groupMonthCol(0), row), cell(groupMonthCol(11), row)))
+		f.SetCellStyle(name, cell(groupMonthCol(0), row), cell(groupTotalCol, row), st.PullCur)
+		row++
+	}
+
+	if g.total {
+		f.SetCellValue(name, cell("A", row), lbl.Total)
+		f.SetCellStyle(name, cell("A", row), cell("A", row), st.SummaryTotalLbl)
+		for k := range 13 {
+			col := groupTotalCol
+			if k < 12 {
+				col = groupMonthCol(k)
+			}
+			if row == groupFirstRow {
+				f.SetCellValue(name, cell(col, row), 0)
+			} else {
+				f.SetCellFormula(name, cell(col, row), sumCellRange(col, groupFirstRow, row-1))
+			}
+		}
+		f.SetCellStyle(name, cell(groupMonthCol(0), row), cell(groupTotalCol, row), st.SummaryTotalCur)
+	}
+	f.SetPanes(name, &excelize.Panes{Freeze: true, XSplit: 1, YSplit: 1, TopLeftCell: "B2", ActivePane: "bottomRight"})
+	return nil
+}
+
+// updateGroupSheet rebuilds g in an existing workbook in the position it held;
+// nothing else refers to it. A workbook built without one gets it before the
+// revenue sheet.
+func updateGroupSheet(f *excelize.File, st *styleSet, lbl Labels, g groupSheet) error {
+	sheets := f.GetSheetList()
+	next := lbl.RevenueSheet
+	if i := slices.Index(sheets, g.name); i >= 0 {
+		next = ""
+		if i+1 < len(sheets) {
+			next = sheets[i+1]
+		}
+		if err := f.DeleteSheet(g.name); err != nil {
+			return err
+		}
+	}
+	if err := buildGroupSheet(f, st, lbl, g); err != nil {
+		return err
+	}
+	if next == "" || !slices.Contains(sheets, next) {
+		return nil
+	}
+	if err := f.MoveSheet(g.name, next); err != nil {
+		return fmt.Errorf("move %s: %w", g.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuildWorkbook_AccountsSheet(t *testing.T) {
+	accountTotals = []taxonomy.Group{
+		{Name: "nubank", Months: [12]float64{0: 90, 1: 15}},
+		{Months: [12]float64{1: 80}},
+	}
+	t.Cleanup(func() { accountTotals = nil })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{listas, "Contas", "Receitas", "Fixas"}, f.GetSheetList())
+	get := func(ref string) string {
+		v, err := f.GetCellValue("Contas", ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(ref string) string {
+		v, err := f.GetCellFormula("Contas", ref)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, "Conta", get("A1"))
+	assert.Equal(t, "Janeiro", get("B1"))
+	assert.Equal(t, "nubank", get("A2"))
+	assert.Equal(t, "90", get("B2"))
+	assert.Equal(t, "SUM(B2:M2)", formula("N2"))
+	assert.Equal(t, "(sem conta)", get("A3"))
+	assert.Equal(t, "80", get("C3"))
+	assert.Equal(t, "Total", get("A4"))
+	assert.Equal(t, "SUM(C2:C3)", formula("C4"))
+	assert.Equal(t, "SUM(N2:N3)", formula("N4"))
+}
+
+func TestUpdateWorkbook_RebuildsAccountsSheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	// A workbook built without the sheet gets it before Receitas.
+	accountTotals = []taxonomy.Group{{Name: "nubank", Months: [12]float64{0: 90}}}
+	t.Cleanup(func() { accountTotals = nil })
+	require.NoError(t, updateWorkbook(fixasSheet(1, 1), salario, path))
+
+	accountTotals = []taxonomy.Group{{Name: "itau", Months: [12]float64{0: 40}}, {Name: "nubank", Months: [12]float64{0: 95}}}
+	require.NoError(t, updateWorkbook(fixasSheet(1, 1), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{listas, "Contas", "Receitas", "Fixas"}, f.GetSheetList())
+	rows, err := f.GetRows("Contas")
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"itau", "40"}, rows[1][:2])
+	assert.Equal(t, []string{"nubank", "95"}, rows[2][:2])
+	assert.Equal(t, "Total", rows[3][0])
+}
+
+func TestBuildWorkbook_TagsSheet(t *testing.T) {
+	accountTotals = []taxonomy.Group{{Name: "nubank", Months: [12]float64{0: 840}}}
+	tagTotals = []taxonomy.Group{
+		{Name: "trabalho", Months: [12]float64{0: 40}},
+		{Name: "viagem", Months: [12]float64{0: 840}},
+	}
+	t.Cleanup(func() { accountTotals, tagTotals = nil, nil })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{listas, "Contas", "Tags", "Receitas", "Fixas"}, f.GetSheetList())
+	rows, err := f.GetRows("Tags")
+	require.NoError(t, err)
+	require.Len(t, rows, 3, "no total row: an expense counts under each of its tags")
+	assert.Equal(t, []string{"Tag", "Janeiro"}, rows[0][:2])
+	assert.Equal(t, []string{"trabalho", "40"}, rows[1][:2])
+	assert.Equal(t, []string{"viagem", "840"}, rows[2][:2])
+	formula, err := f.GetCellFormula("Tags", "N3")
+	require.NoError(t, err)
+	assert.Equal(t, "SUM(B3:M3)", formula)
+
+	// An update rebuilds it in place.
+	tagTotals = []taxonomy.Group{{Name: "viagem", Months: [12]float64{0: 900}}}
+	require.NoError(t, updateWorkbook(fixasSheet(1, 1), salario, path))
+	f2, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f2.Close()
+	assert.Equal(t, []string{listas, "Contas", "Tags", "Receitas", "Fixas"}, f2.GetSheetList())
+	rows, err = f2.GetRows("Tags")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"viagem", "900"}, rows[1][:2])
+}
@@ -0,0 +1,75 @@
+package generate
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// writeInvestmentClassRows emits the Investimentos breakdown in place of the
+// manual shell row: one row per asset class (col C = class; D..O = the month's
+// net flow from the ledger, contributions negative) under a merged col-B
+// "Investimentos" label. Returns the first and last class rows.
+func (b *summaryBuilder) writeInvestmentClassRows() (first, last int) {
+	first = b.row
+	for _, ac := range investmentClasses {
+		b.f.SetCellValue(b.name, cell("C", b.row), ac.Name)
+		writeInvestmentMonths(b.f, b.name, b.row, ac.Net)
+		b.f.SetCellStyle(b.name, cell(summaryMonthCol(0), b.row), cell(summaryMonthCol(11), b.row), b.st.PullCur)
+		b.row++
+	}
+	last = b.row - 1
+	b.mergeRevenueBand(b.lbl.Investments, first, last)
+	return first, last
+}
+
+// writeInvestmentMonths writes an asset class's twelve monthly amounts into
+// D..O of a Listas row.
+func writeInvestmentMonths(f *excelize.File, name string, row int, net func(k int) float64) {
+	for k := range 12 {
+		f.SetCellValue(name, cell(summaryMonthCol(k), row), net(k))
+	}
+}
+
+// updateInvestmentRows rewrites the monthly amounts of the Investimentos
+// breakdown on Listas. The workbook must hold one row per asset class, in
+// taxonomy order, starting at the row labelled "Investimentos" in col B and
+// followed by the Investimentos total.
+func updateInvestmentRows(f *excelize.File, lbl Labels) error {
+	if len(investmentClasses) == 0 {
+		return nil
+	}
+	rows, err := f.GetRows(lbl.SummarySheet)
+	if err != nil {
+		return err
+	}
+	hint := "the taxonomy's investment classes no longer match the workbook\n  Hint: regenerate it without --update"
+	first := -1
+	for r, row := range rows {
+		if len(row) > 1 && row[1] == lbl.Investments {
+			first = r
+			break
+		}
+	}
+	if first < 0 {
+		return fmt.Errorf("%s: no investment breakdown found: %s", lbl.SummarySheet, hint)
+	}
+	labelAt := func(r int) string {
+		if r < len(rows) && len(rows[r]) > 2 {
+			return rows[r][2]
+		}
+		return ""
+	}
+	for i, ac := range investmentClasses {
+		if got := labelAt(first + i); got != ac.Name {
+			return fmt.Errorf("%s: row %d is %q, the taxonomy expects investment class %q: %s", lbl.SummarySheet, first+i+1, got, ac.Name, hint)
+		}
+	}
+	if end := first + len(investmentClasses); labelAt(end) != lbl.Total {
+		return fmt.Errorf("%s: row %d holds investment class %q, which the taxonomy no longer declares: %s", lbl.SummarySheet, end+1, labelAt(end), hint)
+	}
+	for i, ac := range investmentClasses {
+		writeInvestmentMonths(f, lbl.SummarySheet, first+i+1, ac.Net)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// investedIn returns the asset classes Renda fixa and Ações; Renda fixa has a
+// 1000 contribution in January (net -1000), Ações a 300 withdrawal in March.
+func investedIn(rendaFixa float64) []taxonomy.AssetClass {
+	var rf, acoes [12][]taxonomy.Entry
+	rf[0] = []taxonomy.Entry{{Item: "Tesouro Selic", Day: 5, Value: -rendaFixa}}
+	acoes[2] = []taxonomy.Entry{{Item: "Venda BOVA11", Day: 20, Value: 300}}
+	return []taxonomy.AssetClass{{Name: "Renda fixa", Months: rf}, {Name: "Ações", Months: acoes}}
+}
+
+func TestBuildWorkbook_InvestmentClasses(t *testing.T) {
+	investmentClasses = investedIn(1000)
+	t.Cleanup(func() { investmentClasses = nil })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	get := func(ref string) string {
+		v, err := f.GetCellValue(listas, ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(ref string) string {
+		v, err := f.GetCellFormula(listas, ref)
+		require.NoError(t, err)
+		return v
+	}
+
+	// Receitas total on row 9; the class rows replace the shell row 12.
+	assert.Equal(t, "Investimentos", get("B12"))
+	assert.Equal(t, "Renda fixa", get("C12"))
+	assert.Equal(t, "Ações", get("C13"))
+	assert.Equal(t, "-1000", get("D12"))
+	assert.Equal(t, "300", get("F13"))
+	assert.Equal(t, "SUM(D12:D13)", formula("D14"))
+	assert.Equal(t, "IF(D9>0,D14/D9,0)", formula("D15"))
+}
+
+func TestUpdateWorkbook_RefreshesInvestments(t *testing.T) {
+	investmentClasses = investedIn(1000)
+	t.Cleanup(func() { investmentClasses = nil })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	investmentClasses = investedIn(1500)
+	require.NoError(t, updateWorkbook(fixasSheet(1, 1), salario, path))
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	v, err := f.GetCellValue(listas, "D12")
+	require.NoError(t, err)
+	f.Close()
+	assert.Equal(t, "-1500", v)
+
+	investmentClasses = investedIn(1500)[:1]
+	err = updateWorkbook(fixasSheet(1, 1), salario, path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"Ações", which the taxonomy no longer declares`)
+
+	investmentClasses = append([]taxonomy.AssetClass{{Name: "Cripto"}}, investedIn(1500)...)
+	err = updateWorkbook(fixasSheet(1, 1), salario, path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `expects investment class "Cripto"`)
+}
@@ -1,6 +1,35 @@
 // Package main provides centralized workbook strings for internationalization.
 package generate
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Locales lists the workbook locales newLabels accepts; the first is the default.
+var Locales = []string{"pt-BR", "en-US"}
+
+// newLabels returns the label set of locale ("" is the default, pt-BR).
+func newLabels(locale string) (Labels, error) {
+	switch locale {
+	case "", "pt-BR":
+		return newPtBRLabels(), nil
+	case "en-US":
+		return newEnUSLabels(), nil
+	}
+	return Labels{}, fmt.Errorf("unknown workbook locale %q (supported: %s)", locale, strings.Join(Locales, ", "))
+}
+
+// SummarySheet returns the name of the summary sheet (Listas de itens) that
+// workbooks of locale are built with.
+func SummarySheet(locale string) (string, error) {
+	lbl, err := newLabels(locale)
+	if err != nil {
+		return "", err
+	}
+	return lbl.SummarySheet, nil
+}
+
 // Labels holds all user-visible strings for the workbook application.
 // The field names are in English to indicate semantic role, while the values
 // contain localized text.
@@ -23,6 +52,7 @@ type Labels struct {
 	SheetExpensesFmt      string
 
 	// sheet / section names
+	SummarySheet string
 	RevenueSheet string
 
 	// saldo block
@@ -35,6 +65,47 @@ type Labels struct {
 	Balance            string
 	Dollar             string
 
+	// budget panel
+	BudgetYear          string
+	Planned             string
+	Actual              string
+	Variance            string
+	Consumed            string
+	PlannedCategoryFmt  string
+	VarianceCategoryFmt string
+	ConsumedCategoryFmt string
+
+	// dashboard
+	DashboardSheet         string
+	ChartRevenueVsExpenses string
+	ChartExpensesByType    string
+	ChartTopCategories     string
+	ChartBalance           string
+	Category               string
+	YearTotal              string
+	Rank                   string
+	Others                 string
+	DashboardCategoryFmt   string
+
+	// multi-year workbook
+	ComparisonSheet string
+	Type            string
+	MonthlyAverage  string
+	MonthsCovered   string
+	YearTotals      string
+	Change          string
+	PctChange       string
+	YearVsFmt       string
+
+	// accounts sheet
+	AccountsSheet string
+	Account       string
+	NoAccount     string
+
+	// tags sheet
+	TagsSheet string
+	Tag       string
+
 	// MonthNames contains the names of months in Portuguese (Brazil).
 	MonthNames [12]string
 }
@@ -42,26 +113,58 @@ type Labels struct {
 // newPtBRLabels returns a Labels struct with all strings localized for Brazilian Portuguese.
 func newPtBRLabels() Labels {
 	return Labels{
-		Month:                 "Mês",
-		Item:                  "Item",
-		Date:                  "Data",
-		Amount:                "Valor",
-		Total:                 "Total",
-		TotalDash:             "–",
-		PctOfExpenses:         "% sobre despesas",
-		PctOfRevenue:          "% sobre receita",
-		TotalCategoryFmt:      "Total %s",
-		TotalSheetExpensesFmt: "Total despesas %s",
-		SheetExpensesFmt:      "Despesas %s",
-		RevenueSheet:          "Receitas",
-		Revenue:               "Receita",
-		Investments:           "Investimentos",
-		TotalIncome:           "Total renda",
-		TotalExpenses:         "Total despesas",
-		ExpenseShareHeader:    "Porcentagem da despesa",
-		IncomeShareHeader:     "Porcentagem da renda",
-		Balance:               "Saldo",
-		Dollar:                "Dólar",
+		Month:                  "Mês",
+		Item:                   "Item",
+		Date:                   "Data",
+		Amount:                 "Valor",
+		Total:                  "Total",
+		TotalDash:              "–",
+		PctOfExpenses:          "% sobre despesas",
+		PctOfRevenue:           "% sobre receita",
+		TotalCategoryFmt:       "Total %s",
+		TotalSheetExpensesFmt:  "Total despesas %s",
+		SheetExpensesFmt:       "Despesas %s",
+		SummarySheet:           "Listas de itens",
+		RevenueSheet:           "Receitas",
+		Revenue:                "Receita",
+		Investments:            "Investimentos",
+		TotalIncome:            "Total renda",
+		TotalExpenses:          "Total despesas",
+		ExpenseShareHeader:     "Porcentagem da despesa",
+		IncomeShareHeader:      "Porcentagem da renda",
+		Balance:                "Saldo",
+		Dollar:                 "Dólar",
+		BudgetYear:             "Orçamento anual",
+		Planned:                "Previsto",
+		Actual:                 "Realizado",
+		Variance:               "Diferença",
+		Consumed:               "% consumido",
+		PlannedCategoryFmt:     "Previsto %s",
+		VarianceCategoryFmt:    "Diferença %s",
+		ConsumedCategoryFmt:    "%% consumido %s",
+		DashboardSheet:         "Painel",
+		ChartRevenueVsExpenses: "Receitas x despesas",
+		ChartExpensesByType:    "Despesas por tipo",
+		ChartTopCategories:     "Maiores categorias",
+		ChartBalance:           "Saldo mensal",
+		Category:               "Categoria",
+		YearTotal:              "Total no ano",
+		Rank:                   "Posição",
+		Others:                 "Outras",
+		DashboardCategoryFmt:   "%s (%s)",
+		ComparisonSheet:        "Comparativo",
+		Type:                   "Tipo",
+		MonthlyAverage:         "Média mensal",
+		MonthsCovered:          "Meses com lançamentos",
+		YearTotals:             "Total anual",
+		Change:                 "Variação",
+		PctChange:              "% variação",
+		YearVsFmt:              "%d x %d",
+		AccountsSheet:          "Contas",
+		Account:                "Conta",
+		NoAccount:              "(sem conta)",
+		TagsSheet:              "Tags",
+		Tag:                    "Tag",
 		MonthNames: [12]string{
 			"Janeiro",
 			"Fevereiro",
@@ -78,3 +181,75 @@ func newPtBRLabels() Labels {
 		},
 	}
 }
+
+// newEnUSLabels returns a Labels struct with all strings in US English.
+func newEnUSLabels() Labels {
+	return Labels{
+		Month:                  "Month",
+		Item:                   "Item",
+		Date:                   "Date",
+		Amount:                 "Amount",
+		Total:                  "Total",
+		TotalDash:              "–",
+		PctOfExpenses:          "% of expenses",
+		PctOfRevenue:           "% of revenue",
+		TotalCategoryFmt:       "Total %s",
+		TotalSheetExpensesFmt:  "Total %s expenses",
+		SheetExpensesFmt:       "%s expenses",
+		SummarySheet:           "Summary",
+		RevenueSheet:           "Revenue",
+		Revenue:                "Revenue",
+		Investments:            "Investments",
+		TotalIncome:            "Total income",
+		TotalExpenses:          "Total expenses",
+		ExpenseShareHeader:     "Share of expenses",
+		IncomeShareHeader:      "Share of income",
+		Balance:                "Balance",
+		Dollar:                 "Dollar",
+		BudgetYear:             "Annual budget",
+		Planned:                "Planned",
+		Actual:                 "Actual",
+		Variance:               "Variance",
+		Consumed:               "% used",
+		PlannedCategoryFmt:     "Planned %s",
+		VarianceCategoryFmt:    "Variance %s",
+		ConsumedCategoryFmt:    "%% used %s",
+		DashboardSheet:         "Dashboard",
+		ChartRevenueVsExpenses: "Revenue vs expenses",
+		ChartExpensesByType:    "Expenses by type",
+		ChartTopCategories:     "Top categories",
+		ChartBalance:           "Monthly balance",
+		Category:               "Category",
+		YearTotal:              "Year total",
+		Rank:                   "Rank",
+		Others:                 "Other",
+		DashboardCategoryFmt:   "%s (%s)",
+		ComparisonSheet:        "Comparison",
+		Type:                   "Type",
+		MonthlyAverage:         "Monthly average",
+		MonthsCovered:          "Months with entries",
+		YearTotals:             "Annual total",
+		Change:                 "Change",
+		PctChange:              "% change",
+		YearVsFmt:              "%d vs %d",
+		AccountsSheet:          "Accounts",
+		Account:                "Account",
+		NoAccount:              "(no account)",
+		TagsSheet:              "Tags",
+		Tag:                    "Tag",
+		MonthNames: [12]string{
+			"January",
+			"February",
+			"March",
+			"April",
+			"May",
+			"June",
+			"July",
+			"August",
+			"September",
+			"October",
+			"November",
+			"December",
+		},
+	}
+}
@@ -1,17 +1,22 @@
 package generate
 
-// subcatTotal records the total-row position of one subcategory block on an expense sheet,
-// so Listas can wire pull formulas to it.
+import "expense-reporter/internal/taxonomy"
+
+// subcatTotal records the rows of one subcategory block on an expense sheet, so Listas
+// can wire pull formulas to its total row and an update can rewrite its data rows.
 type subcatTotal struct {
-	Sheet    string
-	Category string
-	Subcat   string
-	TotalRow int // 1-based row of the total row
+	Sheet     string
+	Category  string
+	Subcat    string
+	FirstData int // 1-based row of the first data row
+	TotalRow  int // 1-based row of the total row
+	Budget    taxonomy.Budget
 }
 
 // catTotals groups the subcatTotal rows of one categoria (preserving order).
 type catTotals struct {
 	Category string
+	Budget   taxonomy.Budget // the categoria's planned spending (taxonomy.Category.Planned)
 	Subs     []subcatTotal
 }
 
@@ -28,19 +33,40 @@ type revenueLayout struct {
 }
 
 type revenueBlockTotal struct {
+	Category  string
+	Block     string
+	Label     string
+	FirstData int
+	TotalRow  int
+}
+
+// summaryLayout records the Listas rows the dashboard charts read. Each row
+// holds its label in col A (balance block) or B (group totals) and its months
+// in D..O.
+type summaryLayout struct {
+	RevenueRow   int            // balance-block Receita row
+	ExpensesRow  int            // balance-block Total despesas row
+	BalanceRow   int            // balance-block Saldo row
+	SheetRows    map[string]int // expense sheet -> balance-block "Despesas <sheet>" row
+	CategoryRows []categoryRow  // every categoria's group-total row, in build order
+}
+
+// categoryRow is one categoria's group-total row on Listas.
+type categoryRow struct {
+	Sheet    string
 	Category string
-	Block    string
-	Label    string
-	TotalRow int
+	Row      int
 }
 
-// layoutRegistry accumulates positions across all source sheets for Listas wiring.
+// layoutRegistry accumulates positions across all source sheets for Listas wiring,
+// then the Listas rows themselves for the dashboard.
 type layoutRegistry struct {
 	expense    map[string]*sheetLayout // keyed by sheet name
 	sheetOrder []string                // expense sheet names in taxonomy (= build) order
 	revenue    revenueLayout
+	summary    summaryLayout
 }
 
 func newLayoutRegistry() *layoutRegistry {
-	return &layoutRegistry{expense: map[string]*sheetLayout{}}
+	return &layoutRegistry{expense: map[string]*sheetLayout{}, summary: summaryLayout{SheetRows: map[string]int{}}}
 }
@@ -0,0 +1,51 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBuildWorkbook_EnUS(t *testing.T) {
+	workbookLocale = "en-US"
+	t.Cleanup(func() { workbookLocale = "pt-BR" })
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Summary", "Revenue", "Fixas"}, f.GetSheetList())
+
+	get := func(sheet, ref string) string {
+		v, err := f.GetCellValue(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+	assert.Equal(t, "January", get("Fixas", "C1"))
+	assert.Equal(t, "Amount", get("Fixas", "E2"))
+	assert.Equal(t, "Total fixas expenses", get("Summary", "B23"))
+	formula, err := f.GetCellFormula("Summary", "D18")
+	require.NoError(t, err)
+	assert.Equal(t, "Fixas!E4", formula)
+
+	numFmtOf := func(ref string) string {
+		id, err := f.GetCellStyle("Fixas", ref)
+		require.NoError(t, err)
+		st, err := f.GetStyle(id)
+		require.NoError(t, err)
+		require.NotNil(t, st.CustomNumFmt)
+		return *st.CustomNumFmt
+	}
+	assert.Equal(t, "[$BRL] #,##0.00", numFmtOf("E3"))
+	assert.Equal(t, "MM/DD", numFmtOf("D3"))
+}
+
+func TestGenerate_UnknownLocale(t *testing.T) {
+	err := Generate(Options{TaxonomyPath: "taxonomy.json", OutPath: "out.xlsx", Locale: "fr-FR"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pt-BR, en-US")
+}
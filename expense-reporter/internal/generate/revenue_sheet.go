@@ -29,10 +29,11 @@ func buildRevenueSheet(f *excelize.File, st *styleSet, lbl Labels, blocks []taxo
 			firstData, lastData, totalRow := calculateBlockRows(row, blocks[i].MaxEntries())
 			writeRevenueBlock(f, st, lbl, name, blocks[i], firstData, lastData, totalRow)
 			reg.revenue.Blocks = append(reg.revenue.Blocks, revenueBlockTotal{
-				Category: blocks[i].Category,
-				Block:    blocks[i].Block,
-				Label:    blocks[i].Label,
-				TotalRow: totalRow,
+				Category:  blocks[i].Category,
+				Block:     blocks[i].Block,
+				Label:     blocks[i].Label,
+				FirstData: firstData,
+				TotalRow:  totalRow,
 			})
 			row = totalRow + 1
 			i++
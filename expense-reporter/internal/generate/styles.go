@@ -43,9 +43,15 @@ type styleSet struct {
 	NearBlack       int // 333333, Arial 10 bold white (label)
 	NearBlackCur    int
 	NearBlackPct    int
+
+	// Budget panel styles (written only when the taxonomy carries budgets).
+	TotalPct int // F2F2F2 total-row percent (expense-sheet % consumido)
+	PullPct  int // plain percent Arial 10 (Listas pull-row % consumido)
+	Overrun  int // conditional (dxf): red fill for spending past its budget
 }
 
-// Number formats. The empty string means General (no CustomNumFmt).
+// Number formats. The empty string means General (no CustomNumFmt). These are
+// the pt-BR formats; localFormats rewrites them for other workbook locales.
 const (
 	fmtGeneral  = ""
 	fmtCurrency = "R$ #,##0.00"
@@ -53,6 +59,15 @@ const (
 	fmtPercent  = "0.00%"
 )
 
+// localFormats maps each pt-BR number format to its form in other locales.
+// Amounts stay in reais, so en-US names the currency rather than using "$".
+var localFormats = map[string]map[string]string{
+	"en-US": {
+		fmtCurrency: `[$BRL] #,##0.00`,
+		fmtDate:     "MM/DD",
+	},
+}
+
 // Palette: the workbook's fill colors, named for their role.
 const (
 	fillHeaderGray   = "C0C0C0" // month banners, summary totals
@@ -62,6 +77,7 @@ const (
 	fillLavender     = "CCCCFF" // summary group totals
 	fillNearBlack    = "333333" // summary balance-block emphasis rows
 	fillBlack        = "000000" // separator rows
+	fillOverrun      = "FFC7CE" // budget overruns (conditional)
 	borderColorBlack = "000000"
 )
 
@@ -104,10 +120,14 @@ func arialWhite(size float64, bold bool) *excelize.Font {
 // Each constructor names WHAT a cell is in the workbook's visual language;
 // the excelize mechanics stay inside.
 
-// numFmt attaches a number format to a style; fmtGeneral leaves it unset.
+// numFmt attaches a number format to a style, localized for workbookLocale;
+// fmtGeneral leaves it unset.
 func numFmt(st *excelize.Style, format string) *excelize.Style {
 	if format != fmtGeneral {
 		f := format
+		if local, ok := localFormats[workbookLocale][format]; ok {
+			f = local
+		}
 		st.CustomNumFmt = &f
 	}
 	return st
@@ -175,6 +195,15 @@ func (r *styleRegistrar) add(st *excelize.Style) int {
 	return id
 }
 
+// addConditional registers a differential style for conditional formats.
+func (r *styleRegistrar) addConditional(st *excelize.Style) int {
+	id, err := r.f.NewConditionalStyle(st)
+	if err != nil && r.firstErr == nil {
+		r.firstErr = err
+	}
+	return id
+}
+
 // family registers one fill+font combination in its three number-format
 // variants: General (labels), currency, and percent.
 func (r *styleRegistrar) family(fillHex string, font *excelize.Font) (lbl, cur, pct int) {
@@ -197,6 +226,8 @@ func newStyles(f *excelize.File) (*styleSet, error) {
 	s.SummaryTotalLbl, s.SummaryTotalCur, s.SummaryTotalPct = r.family(fillHeaderGray, arial(true))
 	s.NearBlack, s.NearBlackCur, s.NearBlackPct = r.family(fillNearBlack, arialWhite(10, true))
 
+	registerBudgetStyles(r, s)
+
 	return s, r.firstErr
 }
 
@@ -230,3 +261,9 @@ func registerSummaryStyles(r *styleRegistrar, s *styleSet) {
 	s.PullCur = r.add(dataCell(fmtCurrency)) // intentionally identical to Currency; kept as its own name for summary-sheet readability
 	s.SummaryMonth = r.add(&excelize.Style{Fill: solidFill(fillHeaderGray), Font: openSans(false)})
 }
+
+func registerBudgetStyles(r *styleRegistrar, s *styleSet) {
+	s.TotalPct = r.add(totalRowCell("", fmtPercent))
+	s.PullPct = r.add(dataCell(fmtPercent))
+	s.Overrun = r.addConditional(&excelize.Style{Fill: solidFill(fillOverrun), Font: &excelize.Font{Color: "9C0006"}})
+}
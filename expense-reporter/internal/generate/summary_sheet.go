@@ -6,17 +6,15 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-const (
-	summarySheetName = "Listas de itens"
-	lastSummaryCol   = "O"
-)
+const lastSummaryCol = "O"
 
 type summaryBuilder struct {
-	f   *excelize.File
-	st  *styleSet
-	lbl Labels
-	reg *layoutRegistry
-	row int
+	name string // the summary sheet (lbl.SummarySheet)
+	f    *excelize.File
+	st   *styleSet
+	lbl  Labels
+	reg  *layoutRegistry
+	row  int
 
 	revenueTotalRow int            // Listas row of the Receitas-section grand total
 	investTotalRow  int            // Listas row of Investimentos total
@@ -25,14 +23,17 @@ type summaryBuilder struct {
 }
 
 func buildSummarySheet(f *excelize.File, st *styleSet, lbl Labels, reg *layoutRegistry) error {
-	if _, err := f.NewSheet(summarySheetName); err != nil {
+	if _, err := f.NewSheet(lbl.SummarySheet); err != nil {
 		return err
 	}
-	b := &summaryBuilder{f: f, st: st, lbl: lbl, reg: reg, sheetGrandRow: map[string]int{}}
+	b := &summaryBuilder{name: lbl.SummarySheet, f: f, st: st, lbl: lbl, reg: reg, sheetGrandRow: map[string]int{}}
 	b.setWidths()
-	f.SetPanes(summarySheetName, &excelize.Panes{Freeze: true, XSplit: 3, YSplit: 3, TopLeftCell: "D4", ActivePane: "bottomRight"})
+	f.SetPanes(b.name, &excelize.Panes{Freeze: true, XSplit: 3, YSplit: 3, TopLeftCell: "D4", ActivePane: "bottomRight"})
 
 	b.header()
+	if reg.hasBudgets() {
+		b.budgetHeader()
+	}
 	b.revenueSection()
 	b.expenseSections()
 	b.balanceBlock()
@@ -41,21 +42,21 @@ func buildSummarySheet(f *excelize.File, st *styleSet, lbl Labels, reg *layoutRe
 }
 
 func (b *summaryBuilder) setWidths() {
-	b.f.SetColWidth(summarySheetName, "A", "A", 12.86)
-	b.f.SetColWidth(summarySheetName, "B", "B", 14)
-	b.f.SetColWidth(summarySheetName, "C", "C", 18.14)
-	b.f.SetColWidth(summarySheetName, "D", lastSummaryCol, 16.43)
+	b.f.SetColWidth(b.name, "A", "A", 12.86)
+	b.f.SetColWidth(b.name, "B", "B", 14)
+	b.f.SetColWidth(b.name, "C", "C", 18.14)
+	b.f.SetColWidth(b.name, "D", lastSummaryCol, 16.43)
 }
 
 func (b *summaryBuilder) setHeights() {
 	for r := 1; r <= b.row; r++ {
 		switch {
 		case r == b.balanceSepRow:
-			b.f.SetRowHeight(summarySheetName, r, 3.75)
+			b.f.SetRowHeight(b.name, r, 3.75)
 		case r <= 20:
-			b.f.SetRowHeight(summarySheetName, r, 15)
+			b.f.SetRowHeight(b.name, r, 15)
 		default:
-			b.f.SetRowHeight(summarySheetName, r, 15.75)
+			b.f.SetRowHeight(b.name, r, 15.75)
 		}
 	}
 }
@@ -63,15 +64,15 @@ func (b *summaryBuilder) setHeights() {
 // header writes rows 3 (month banner) and 5 ("Valor").
 func (b *summaryBuilder) header() {
 	f, st := b.f, b.st
-	f.MergeCell(summarySheetName, "A3", "C3")
-	f.SetCellStyle(summarySheetName, "A3", "C3", st.SummaryMonth)
+	f.MergeCell(b.name, "A3", "C3")
+	f.SetCellStyle(b.name, "A3", "C3", st.SummaryMonth)
 	for k := 0; k < 12; k++ {
 		c := summaryMonthCol(k)
-		f.SetCellValue(summarySheetName, c+"3", b.lbl.MonthNames[k])
-		f.SetCellStyle(summarySheetName, c+"3", c+"3", st.SummaryMonth)
+		f.SetCellValue(b.name, c+"3", b.lbl.MonthNames[k])
+		f.SetCellStyle(b.name, c+"3", c+"3", st.SummaryMonth)
 	}
 	for k := 0; k < 12; k++ {
-		f.SetCellValue(summarySheetName, summaryMonthCol(k)+"5", b.lbl.Amount)
+		f.SetCellValue(b.name, summaryMonthCol(k)+"5", b.lbl.Amount)
 	}
 }
 
@@ -79,19 +80,20 @@ func (b *summaryBuilder) header() {
 func (b *summaryBuilder) monthFormulas(row, valStyle int, fn func(k int) string) {
 	for k := range 12 {
 		c := summaryMonthCol(k)
-		b.f.SetCellFormula(summarySheetName, cell(c, row), fn(k))
-		b.f.SetCellStyle(summarySheetName, cell(c, row), cell(c, row), valStyle)
+		b.f.SetCellFormula(b.name, cell(c, row), fn(k))
+		b.f.SetCellStyle(b.name, cell(c, row), cell(c, row), valStyle)
 	}
 }
 
 // bandRow styles B..O of a row with the 333399 internal-separator band.
 func (b *summaryBuilder) bandRow(row int) {
-	b.f.SetCellStyle(summarySheetName, cell("B", row), cell(lastSummaryCol, row), b.st.IndigoBand)
+	b.f.SetCellStyle(b.name, cell("B", row), cell(lastSummaryCol, row), b.st.IndigoBand)
 }
 
 // revenueSection: Receitas per-Block groups (pull rows + col-B Block label + "Total <Block>"
-// row), then internal bandRow separator, Receitas grand total, then Investimentos shell +
-// total + %. Col A merges across the whole band (incl. Investimentos).
+// row), then internal bandRow separator, Receitas grand total, then Investimentos (the
+// manual shell row, or one row per asset class when the taxonomy declares them) + total
+// + %. Col A merges across the whole band (incl. Investimentos).
 func (b *summaryBuilder) revenueSection() {
 	b.row = 6
 	sectionFirst := b.row
@@ -100,12 +102,16 @@ func (b *summaryBuilder) revenueSection() {
 	b.bandRow(b.row) // internal separator
 	b.row++
 	b.writeRevenueGrandTotalRow(blockTotalRows)
-	investRow := b.writeInvestmentsShellRow()
-	b.writeInvestmentsTotalRow(investRow)
+	if len(investmentClasses) > 0 {
+		b.writeInvestmentsTotalRow(b.writeInvestmentClassRows())
+	} else {
+		investRow := b.writeInvestmentsShellRow()
+		b.writeInvestmentsTotalRow(investRow, investRow)
+	}
 	b.writeInvestmentsPctRow()
 	sectionLast := b.row
 
-	mergeSection(b.f, b.st, b.lbl.RevenueSheet, sectionFirst, sectionLast)
+	mergeSection(b.f, b.st, b.name, b.lbl.RevenueSheet, sectionFirst, sectionLast)
 	b.row += 4 // % row consumed at sectionLast; skip blanks before next section
 }
 
@@ -125,8 +131,8 @@ func (b *summaryBuilder) writeRevenueBlockGroups() []int {
 		// Emit pull rows for every leaf in this Block group.
 		for i < len(blocks) && blocks[i].Block == blockName {
 			leaf := blocks[i]
-			b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
-			b.f.SetCellValue(summarySheetName, cell("C", b.row), leaf.Label)
+			b.f.SetCellStyle(b.name, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
+			b.f.SetCellValue(b.name, cell("C", b.row), leaf.Label)
 			tr := leaf.TotalRow
 			b.monthFormulas(b.row, b.st.PullCur, func(k int) string {
 				return sheetRef(b.lbl.RevenueSheet, expenseValorCol(k), tr)
@@ -140,8 +146,8 @@ func (b *summaryBuilder) writeRevenueBlockGroups() []int {
 		b.mergeRevenueBand(blockName, firstPull, lastPull)
 
 		// "Total <Block>" row: sums this group's pull rows.
-		b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.GroupTotalLbl)
-		b.f.SetCellValue(summarySheetName, cell("B", b.row), fmt.Sprintf(b.lbl.TotalCategoryFmt, blockName))
+		b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.GroupTotalLbl)
+		b.f.SetCellValue(b.name, cell("B", b.row), fmt.Sprintf(b.lbl.TotalCategoryFmt, blockName))
 		b.monthFormulas(b.row, b.st.GroupTotalCur, func(k int) string {
 			c := summaryMonthCol(k)
 			return sumRange(cell(c, firstPull), cell(c, lastPull))
@@ -156,18 +162,18 @@ func (b *summaryBuilder) writeRevenueBlockGroups() []int {
 // degenerate single-row merge) and writes the Block name with IndigoLabel style.
 func (b *summaryBuilder) mergeRevenueBand(blockName string, firstPull, lastPull int) {
 	if lastPull > firstPull {
-		b.f.MergeCell(summarySheetName, cell("B", firstPull), cell("B", lastPull))
+		b.f.MergeCell(b.name, cell("B", firstPull), cell("B", lastPull))
 	}
-	b.f.SetCellValue(summarySheetName, cell("B", firstPull), blockName)
-	b.f.SetCellStyle(summarySheetName, cell("B", firstPull), cell("B", lastPull), b.st.IndigoLabel)
+	b.f.SetCellValue(b.name, cell("B", firstPull), blockName)
+	b.f.SetCellStyle(b.name, cell("B", firstPull), cell("B", lastPull), b.st.IndigoLabel)
 }
 
 // writeRevenueGrandTotalRow emits the C0C0C0 Receitas grand total (C=lbl.Total; D..O =
 // sumList of per-Block group-total rows — non-contiguous, so NOT sumCellRange) and
 // records revenueTotalRow for use by balanceBlock and Investimentos %.
 func (b *summaryBuilder) writeRevenueGrandTotalRow(blockTotalRows []int) {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("C", b.row), b.lbl.Total)
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("C", b.row), b.lbl.Total)
 	b.monthFormulas(b.row, b.st.SummaryTotalCur, func(k int) string {
 		c := summaryMonthCol(k)
 		terms := make([]string, len(blockTotalRows))
@@ -183,24 +189,28 @@ func (b *summaryBuilder) writeRevenueGrandTotalRow(blockTotalRows []int) {
 // writeInvestmentsShellRow emits the manual-entry Investimentos row (no formulas)
 // and returns its row.
 func (b *summaryBuilder) writeInvestmentsShellRow() int {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
-	b.f.SetCellValue(summarySheetName, cell("C", b.row), b.lbl.Investments)
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
+	b.f.SetCellValue(b.name, cell("C", b.row), b.lbl.Investments)
 	for k := 0; k < 12; k++ {
 		c := summaryMonthCol(k)
-		b.f.SetCellStyle(summarySheetName, cell(c, b.row), cell(c, b.row), b.st.PullCur)
+		b.f.SetCellStyle(b.name, cell(c, b.row), cell(c, b.row), b.st.PullCur)
 	}
 	investRow := b.row
 	b.row++
 	return investRow
 }
 
-// writeInvestmentsTotalRow emits the Investimentos total (a direct pull of the
-// shell row) and records investTotalRow.
-func (b *summaryBuilder) writeInvestmentsTotalRow(investRow int) {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("C", b.row), b.lbl.Total)
+// writeInvestmentsTotalRow emits the Investimentos total (a direct pull of a
+// single row, else the SUM of the class rows) and records investTotalRow.
+func (b *summaryBuilder) writeInvestmentsTotalRow(first, last int) {
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("C", b.row), b.lbl.Total)
 	b.monthFormulas(b.row, b.st.SummaryTotalCur, func(k int) string {
-		return cell(summaryMonthCol(k), investRow)
+		c := summaryMonthCol(k)
+		if first == last {
+			return cell(c, first)
+		}
+		return sumRange(cell(c, first), cell(c, last))
 	})
 	b.investTotalRow = b.row
 	b.row++
@@ -209,18 +219,18 @@ func (b *summaryBuilder) writeInvestmentsTotalRow(investRow int) {
 // writeInvestmentsPctRow emits the "% sobre receita" row for Investimentos
 // (guarded against a zero revenue denominator).
 func (b *summaryBuilder) writeInvestmentsPctRow() {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("C", b.row), b.lbl.PctOfRevenue)
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("C", b.row), b.lbl.PctOfRevenue)
 	invTot, recTot := b.investTotalRow, b.revenueTotalRow
 	b.monthFormulas(b.row, b.st.GroupTotalPct, func(k int) string {
 		return safeRatioFormula(summaryMonthCol(k), recTot, invTot)
 	})
 }
 
-func mergeSection(f *excelize.File, st *styleSet, label string, first, last int) {
-	f.MergeCell(summarySheetName, cell("A", first), cell("A", last))
-	f.SetCellValue(summarySheetName, cell("A", first), label)
-	f.SetCellStyle(summarySheetName, cell("A", first), cell("A", last), st.SectionLabel)
+func mergeSection(f *excelize.File, st *styleSet, name, label string, first, last int) {
+	f.MergeCell(name, cell("A", first), cell("A", last))
+	f.SetCellValue(name, cell("A", first), label)
+	f.SetCellStyle(name, cell("A", first), cell("A", last), st.SectionLabel)
 }
 
 // expenseSections: one band per source sheet (Fixas, Variáveis, Extras, Adicionais).
@@ -240,7 +250,9 @@ func (b *summaryBuilder) expenseSection(sName string) {
 
 	var groupTotalRows []int
 	for _, ct := range layout.Cats {
-		groupTotalRows = append(groupTotalRows, b.writeCategoryGroup(sName, ct, plannedGrand))
+		row := b.writeCategoryGroup(sName, ct, plannedGrand)
+		groupTotalRows = append(groupTotalRows, row)
+		b.reg.summary.CategoryRows = append(b.reg.summary.CategoryRows, categoryRow{Sheet: sName, Category: ct.Category, Row: row})
 	}
 	grandRow := b.writeSheetGrandTotalRow(sName, groupTotalRows)
 	b.bandRow(b.row) // internal separator
@@ -248,36 +260,43 @@ func (b *summaryBuilder) expenseSection(sName string) {
 	b.writeSectionPctOfRevenueRow(grandRow)
 	sectionLast := b.row
 
-	mergeSection(b.f, b.st, sName, sectionFirst, sectionLast)
+	mergeSection(b.f, b.st, b.name, sName, sectionFirst, sectionLast)
 	b.row += 2 // % row consumed; skip blank to next section
 }
 
 // writeCategoryGroup emits one categoria band: its pull rows, the merged col-B
-// label, the group total, and (when enabled) the per-group percent rows.
-// Returns the group-total row.
+// label, the group total, its budget rows when budgeted, and (when enabled) the
+// per-group percent rows. Returns the group-total row.
 func (b *summaryBuilder) writeCategoryGroup(sName string, ct catTotals, plannedGrand int) int {
 	firstPull, lastPull := b.writeCategoryPullRows(sName, ct)
 	b.mergeCategoryBand(ct, firstPull, lastPull)
 	groupTotalRow := b.writeGroupTotalRow(ct, firstPull, lastPull)
+	if !ct.Budget.IsZero() {
+		b.writeCategoryBudgetRows(ct, groupTotalRow)
+	}
 	if perGroupPctRows {
 		b.emitGroupPctRows(groupTotalRow, plannedGrand)
 	}
 	return groupTotalRow
 }
 
-// writeCategoryPullRows emits one pull row per subcategory of the categoria and
-// returns the pull band's first and last rows.
+// writeCategoryPullRows emits one pull row per subcategory of the categoria (a
+// budgeted one also pulls its annual budget into the panel) and returns the
+// pull band's first and last rows.
 func (b *summaryBuilder) writeCategoryPullRows(sName string, ct catTotals) (firstPull, lastPull int) {
 	firstPull = b.row
 	for i, sub := range ct.Subs {
 		if i == 0 {
-			b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
+			b.f.SetCellStyle(b.name, cell("B", b.row), cell("B", b.row), b.st.IndigoBand)
 		}
-		b.f.SetCellValue(summarySheetName, cell("C", b.row), sub.Subcat)
+		b.f.SetCellValue(b.name, cell("C", b.row), sub.Subcat)
 		tr := sub.TotalRow
 		b.monthFormulas(b.row, b.st.PullCur, func(k int) string {
 			return sheetRef(sName, expenseValorCol(k), tr)
 		})
+		if !sub.Budget.IsZero() {
+			b.budgetCells(b.row, sheetRef(sName, budgetPlannedCol, tr), b.st.PullCur, b.st.PullPct)
+		}
 		b.row++
 	}
 	return firstPull, b.row - 1
@@ -287,17 +306,17 @@ func (b *summaryBuilder) writeCategoryPullRows(sName string, ct catTotals) (firs
 // degenerate single-row merge) and writes the categoria label.
 func (b *summaryBuilder) mergeCategoryBand(ct catTotals, firstPull, lastPull int) {
 	if lastPull > firstPull {
-		b.f.MergeCell(summarySheetName, cell("B", firstPull), cell("B", lastPull))
+		b.f.MergeCell(b.name, cell("B", firstPull), cell("B", lastPull))
 	}
-	b.f.SetCellValue(summarySheetName, cell("B", firstPull), ct.Category)
-	b.f.SetCellStyle(summarySheetName, cell("B", firstPull), cell("B", lastPull), b.st.IndigoLabel)
+	b.f.SetCellValue(b.name, cell("B", firstPull), ct.Category)
+	b.f.SetCellStyle(b.name, cell("B", firstPull), cell("B", lastPull), b.st.IndigoLabel)
 }
 
 // writeGroupTotalRow emits the CCCCFF group total (B = "Total <cat>"; D..O = SUM
 // of pulls; B/C General, D..O currency) and returns its row.
 func (b *summaryBuilder) writeGroupTotalRow(ct catTotals, firstPull, lastPull int) int {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.GroupTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("B", b.row), fmt.Sprintf(b.lbl.TotalCategoryFmt, ct.Category))
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.GroupTotalLbl)
+	b.f.SetCellValue(b.name, cell("B", b.row), fmt.Sprintf(b.lbl.TotalCategoryFmt, ct.Category))
 	b.monthFormulas(b.row, b.st.GroupTotalCur, func(k int) string {
 		c := summaryMonthCol(k)
 		return sumRange(cell(c, firstPull), cell(c, lastPull))
@@ -311,8 +330,8 @@ func (b *summaryBuilder) writeGroupTotalRow(ct catTotals, firstPull, lastPull in
 // despesas <sheet-lower>"; D..O = sum of the group totals), records it in
 // sheetGrandRow, and returns its row.
 func (b *summaryBuilder) writeSheetGrandTotalRow(sName string, groupTotalRows []int) int {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("B", b.row), fmt.Sprintf(b.lbl.TotalSheetExpensesFmt, lower(sName)))
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("B", b.row), fmt.Sprintf(b.lbl.TotalSheetExpensesFmt, lower(sName)))
 	grandRow := b.row
 	b.monthFormulas(b.row, b.st.SummaryTotalCur, func(k int) string {
 		c := summaryMonthCol(k)
@@ -330,8 +349,8 @@ func (b *summaryBuilder) writeSheetGrandTotalRow(sName string, groupTotalRows []
 // writeSectionPctOfRevenueRow emits the section-level "% sobre receita" row
 // (CCCCFF pct on D..O; B..C C0C0C0 label; guarded on the revenue denominator).
 func (b *summaryBuilder) writeSectionPctOfRevenueRow(grandRow int) {
-	b.f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
-	b.f.SetCellValue(summarySheetName, cell("B", b.row), b.lbl.PctOfRevenue)
+	b.f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("B", b.row), b.lbl.PctOfRevenue)
 	recTot := b.revenueTotalRow
 	b.monthFormulas(b.row, b.st.GroupTotalPct, func(k int) string {
 		return safeRatioFormula(summaryMonthCol(k), recTot, grandRow)
@@ -340,13 +359,17 @@ func (b *summaryBuilder) writeSectionPctOfRevenueRow(grandRow int) {
 
 // plannedGrandTotalRow returns the row where a despesa section's grand total will
 // land, given the section's first row. Each categoria consumes its pull rows + 1
-// group-total row + (when enabled) 2 per-group percent rows. The per-group
+// group-total row + (when budgeted) 3 budget rows + (when enabled) 2 per-group
+// percent rows. The per-group
 // "% sobre despesas" formula references the grand total, which is written after the
 // loop — so its row must be known in advance.
 func plannedGrandTotalRow(sectionFirst int, cats []catTotals) int {
 	row := sectionFirst
 	for _, ct := range cats {
 		row += len(ct.Subs) + 1
+		if !ct.Budget.IsZero() {
+			row += 3
+		}
 		if perGroupPctRows {
 			row += 2
 		}
@@ -366,8 +389,8 @@ func (b *summaryBuilder) emitGroupPctRows(grpRow, grandRow int) {
 // D..O = IF(denom>0, group/denom, 0) per month.
 func (b *summaryBuilder) groupPctRow(label string, grpRow, denomRow int) {
 	f, st := b.f, b.st
-	f.SetCellStyle(summarySheetName, cell("B", b.row), cell("C", b.row), st.GroupTotalLbl)
-	f.SetCellValue(summarySheetName, cell("B", b.row), label)
+	f.SetCellStyle(b.name, cell("B", b.row), cell("C", b.row), st.GroupTotalLbl)
+	f.SetCellValue(b.name, cell("B", b.row), label)
 	b.monthFormulas(b.row, st.GroupTotalPct, func(k int) string {
 		return safeRatioFormula(summaryMonthCol(k), denomRow, grpRow)
 	})
@@ -378,15 +401,21 @@ func (b *summaryBuilder) groupPctRow(label string, grpRow, denomRow int) {
 // (dynamic — per-group percent rows push it down) behind a thin separator.
 func (b *summaryBuilder) balanceBlock() {
 	b.balanceSepRow = b.row
-	b.f.SetRowHeight(summarySheetName, b.balanceSepRow, 3.75)
+	b.f.SetRowHeight(b.name, b.balanceSepRow, 3.75)
 	b.row++
 	order := b.reg.sheetOrder
 
-	_, _, totalRendaRow := b.writeIncomeRows()
+	receitaRow, _, totalRendaRow := b.writeIncomeRows()
 	despRows, totalDespRow := b.writeExpenseRows(order)
 	b.writeExpenseShareBlock(order, despRows, totalDespRow)
 	b.writeIncomeShareBlock(order, despRows, totalRendaRow)
-	b.writeFinalBalanceRow(totalRendaRow, totalDespRow)
+	balanceRow := b.writeFinalBalanceRow(totalRendaRow, totalDespRow)
+
+	sum := &b.reg.summary
+	sum.RevenueRow, sum.ExpensesRow, sum.BalanceRow = receitaRow, totalDespRow, balanceRow
+	for s, r := range despRows {
+		sum.SheetRows[s] = r
+	}
 }
 
 // writeIncomeRows writes the Revenue pull, Investments pull, and TotalIncome sum rows.
@@ -449,9 +478,10 @@ func (b *summaryBuilder) writeIncomeShareBlock(order []string, despRows map[stri
 	}
 }
 
-// writeFinalBalanceRow writes the Balance row (total income minus total expenses).
-func (b *summaryBuilder) writeFinalBalanceRow(totalRendaRow, totalDespRow int) {
-	b.balanceRow(b.lbl.Balance, b.st.NearBlack, b.st.NearBlackCur, func(k int) string {
+// writeFinalBalanceRow writes the Balance row (total income minus total expenses)
+// and returns its row.
+func (b *summaryBuilder) writeFinalBalanceRow(totalRendaRow, totalDespRow int) int {
+	return b.balanceRow(b.lbl.Balance, b.st.NearBlack, b.st.NearBlackCur, func(k int) string {
 		c := summaryMonthCol(k)
 		return fmt.Sprintf("%s-%s", cell(c, totalRendaRow), cell(c, totalDespRow))
 	})
@@ -461,8 +491,8 @@ func (b *summaryBuilder) writeFinalBalanceRow(totalRendaRow, totalDespRow int) {
 // (General numFmt); D..O carry valStyle. Returns the row number.
 func (b *summaryBuilder) balanceRow(label string, lblStyle, valStyle int, fn func(k int) string) int {
 	r := b.row
-	b.f.SetCellValue(summarySheetName, cell("A", r), label)
-	b.f.SetCellStyle(summarySheetName, cell("A", r), cell("C", r), lblStyle)
+	b.f.SetCellValue(b.name, cell("A", r), label)
+	b.f.SetCellStyle(b.name, cell("A", r), cell("C", r), lblStyle)
 	b.monthFormulas(r, valStyle, fn)
 	b.row++
 	return r
@@ -471,8 +501,8 @@ func (b *summaryBuilder) balanceRow(label string, lblStyle, valStyle int, fn fun
 // balanceRowPct: label A (C0C0C0), D..O percent (CCCCFF).
 func (b *summaryBuilder) balanceRowPct(label string, fn func(k int) string) int {
 	r := b.row
-	b.f.SetCellValue(summarySheetName, cell("A", r), label)
-	b.f.SetCellStyle(summarySheetName, cell("A", r), cell("C", r), b.st.SummaryTotalLbl)
+	b.f.SetCellValue(b.name, cell("A", r), label)
+	b.f.SetCellStyle(b.name, cell("A", r), cell("C", r), b.st.SummaryTotalLbl)
 	b.monthFormulas(r, b.st.GroupTotalPct, fn)
 	b.row++
 	return r
@@ -481,7 +511,7 @@ func (b *summaryBuilder) balanceRowPct(label string, fn func(k int) string) int
 // balanceLabelRow: a full-width 333333 label row (col A label, rest near-black).
 func (b *summaryBuilder) balanceLabelRow(label string) {
 	r := b.row
-	b.f.SetCellValue(summarySheetName, cell("A", r), label)
-	b.f.SetCellStyle(summarySheetName, cell("A", r), cell(lastSummaryCol, r), b.st.NearBlack)
+	b.f.SetCellValue(b.name, cell("A", r), label)
+	b.f.SetCellStyle(b.name, cell("A", r), cell(lastSummaryCol, r), b.st.NearBlack)
 	b.row++
 }
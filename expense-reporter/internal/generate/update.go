@@ -0,0 +1,339 @@
+package generate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/xuri/excelize/v2"
+)
+
+// updateWorkbook rewrites the data cells of the generated workbook at path in
+// place: every block is located from its merged labels (see locateLayout),
+// grown or shrunk to the rows its entries need, refilled, and its total-row
+// formulas rewritten. Rows are inserted and removed through excelize, which
+// moves merges, conditional formats and formulas on every sheet (Listas
+// included) along with them; comments are moved here. Everything else — cells
+// right of the data columns, added sheets, formatting — is left as it was. The
+// taxonomy must still describe the workbook's blocks, in order. The accounts
+// sheet, when requested, is rebuilt whole.
+func updateWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock, path string) error {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	st, err := newStyles(f)
+	if err != nil {
+		return fmt.Errorf("styles: %w", err)
+	}
+	lbl, err := newLabels(workbookLocale)
+	if err != nil {
+		return err
+	}
+	reg, err := locateLayout(f, lbl, expenseSheets, revenueBlocks)
+	if err != nil {
+		return err
+	}
+	if err := updatePlannedRows(f, lbl, expenseSheets); err != nil {
+		return err
+	}
+	if err := updateInvestmentRows(f, lbl); err != nil {
+		return err
+	}
+
+	revenue := make([]blockUpdate, len(revenueBlocks))
+	for i, b := range revenueBlocks {
+		total := reg.revenue.Blocks[i]
+		revenue[i] = blockUpdate{months: b.Months, maxEntries: b.MaxEntries(), firstData: total.FirstData, totalRow: total.TotalRow}
+	}
+	if err := updateSheet(f, st, lbl.RevenueSheet, revenue, 15, lastRevenueCol); err != nil {
+		return fmt.Errorf("revenue: %w", err)
+	}
+	for _, sh := range expenseSheets {
+		var blocks []blockUpdate
+		for ci, cat := range sh.Cats {
+			for si, sub := range cat.Subs {
+				total := reg.expense[sh.Name].Cats[ci].Subs[si]
+				blocks = append(blocks, blockUpdate{months: sub.Months, maxEntries: sub.MaxEntries(), firstData: total.FirstData, totalRow: total.TotalRow, budget: sub.Budget})
+			}
+		}
+		if err := updateSheet(f, st, sh.Name, blocks, 12.75, lastExpenseCol); err != nil {
+			return fmt.Errorf("expense %s: %w", sh.Name, err)
+		}
+	}
+	if accountTotals != nil {
+		if err := updateGroupSheet(f, st, lbl, accountsSheet(lbl, accountTotals)); err != nil {
+			return fmt.Errorf("accounts: %w", err)
+		}
+	}
+	if tagTotals != nil {
+		if err := updateGroupSheet(f, st, lbl, tagsSheet(lbl, tagTotals)); err != nil {
+			return fmt.Errorf("tags: %w", err)
+		}
+	}
+
+	if err := f.UpdateLinkedValue(); err != nil {
+		return fmt.Errorf("update linked: %w", err)
+	}
+	yes := true
+	if err := f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &yes}); err != nil {
+		return fmt.Errorf("calc props: %w", err)
+	}
+	return saveWorkbook(f, path)
+}
+
+// labelSpan is a label merged down one column: a block's col-B label or a
+// category's col-A label.
+type labelSpan struct {
+	Label       string
+	First, Last int
+}
+
+// labelSpans returns the single-column merges of col below the header rows of
+// sheet name, in row order.
+func labelSpans(f *excelize.File, name, col string) ([]labelSpan, error) {
+	merges, err := f.GetMergeCells(name)
+	if err != nil {
+		return nil, err
+	}
+	colNum := colIndex(col) + 1
+	var spans []labelSpan
+	for _, m := range merges {
+		c1, r1, err := excelize.CellNameToCoordinates(m.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+		c2, r2, err := excelize.CellNameToCoordinates(m.GetEndAxis())
+		if err != nil {
+			return nil, err
+		}
+		if c1 != colNum || c2 != colNum || r1 < 3 {
+			continue
+		}
+		spans = append(spans, labelSpan{Label: strings.TrimSpace(m.GetCellValue()), First: r1, Last: r2})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].First < spans[j].First })
+	return spans, nil
+}
+
+// locatedBlock is a block found on a sheet: its col-B label inside the col-A
+// label of its group.
+type locatedBlock struct {
+	Group, Label        string
+	FirstData, TotalRow int
+}
+
+// locateBlocks finds the blocks of sheet name: each col-B merge spans a
+// block's data rows and its total row.
+func locateBlocks(f *excelize.File, name string) ([]locatedBlock, error) {
+	groups, err := labelSpans(f, name, "A")
+	if err != nil {
+		return nil, err
+	}
+	labels, err := labelSpans(f, name, "B")
+	if err != nil {
+		return nil, err
+	}
+	blocks := make([]locatedBlock, len(labels))
+	for i, l := range labels {
+		blocks[i] = locatedBlock{Label: l.Label, FirstData: l.First, TotalRow: l.Last}
+		for _, g := range groups {
+			if l.First >= g.First && l.First <= g.Last {
+				blocks[i].Group = g.Label
+			}
+		}
+	}
+	return blocks, nil
+}
+
+// locateLayout records the block positions of an existing generated workbook
+// into a layout registry, checking them against the taxonomy: each sheet must
+// hold the taxonomy's blocks, under the same groups, in the same order, with a
+// budget panel on exactly the budgeted ones.
+func locateLayout(f *excelize.File, lbl Labels, expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock) (*layoutRegistry, error) {
+	reg := newLayoutRegistry()
+
+	found, err := locateBlocks(f, lbl.RevenueSheet)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", lbl.RevenueSheet, err)
+	}
+	want := make([]locatedBlock, len(revenueBlocks))
+	for i, b := range revenueBlocks {
+		want[i] = locatedBlock{Group: b.Block, Label: b.Label}
+	}
+	if err := matchBlocks(lbl.RevenueSheet, found, want); err != nil {
+		return nil, err
+	}
+	for i, b := range revenueBlocks {
+		reg.revenue.Blocks = append(reg.revenue.Blocks, revenueBlockTotal{
+			Category: b.Category, Block: b.Block, Label: b.Label,
+			FirstData: found[i].FirstData, TotalRow: found[i].TotalRow,
+		})
+	}
+
+	for _, sh := range expenseSheets {
+		found, err := locateBlocks(f, sh.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", sh.Name, err)
+		}
+		want = want[:0]
+		for _, cat := range sh.Cats {
+			for _, sub := range cat.Subs {
+				want = append(want, locatedBlock{Group: cat.Name, Label: sub.Name})
+			}
+		}
+		if err := matchBlocks(sh.Name, found, want); err != nil {
+			return nil, err
+		}
+		layout := &sheetLayout{Sheet: sh.Name}
+		i := 0
+		for _, cat := range sh.Cats {
+			ct := catTotals{Category: cat.Name, Budget: cat.Planned()}
+			for _, sub := range cat.Subs {
+				if err := checkBudgetPanel(f, sh.Name, found[i], sub.Budget); err != nil {
+					return nil, err
+				}
+				ct.Subs = append(ct.Subs, subcatTotal{
+					Sheet: sh.Name, Category: cat.Name, Subcat: sub.Name,
+					FirstData: found[i].FirstData, TotalRow: found[i].TotalRow, Budget: sub.Budget,
+				})
+				i++
+			}
+			layout.Cats = append(layout.Cats, ct)
+		}
+		reg.expense[sh.Name] = layout
+		reg.sheetOrder = append(reg.sheetOrder, sh.Name)
+	}
+	return reg, nil
+}
+
+// matchBlocks checks the blocks found on a sheet against those the taxonomy
+// describes for it.
+func matchBlocks(name string, found, want []locatedBlock) error {
+	hint := "the taxonomy no longer matches the workbook's blocks\n  Hint: regenerate it without --update"
+	if len(found) != len(want) {
+		return fmt.Errorf("%s: %d blocks in the workbook, %d in the taxonomy: %s", name, len(found), len(want), hint)
+	}
+	for i := range want {
+		if found[i].Group != want[i].Group || found[i].Label != want[i].Label {
+			return fmt.Errorf("%s: block %d is %s/%s in the workbook, %s/%s in the taxonomy: %s",
+				name, i+1, found[i].Group, found[i].Label, want[i].Group, want[i].Label, hint)
+		}
+	}
+	return nil
+}
+
+// blockUpdate is one block to rewrite: its new entries, current rows and
+// budget (zero for revenue and unbudgeted blocks).
+type blockUpdate struct {
+	months              [12][]taxonomy.Entry
+	maxEntries          int
+	firstData, totalRow int
+	budget              taxonomy.Budget
+}
+
+// updateSheet resizes and refills the blocks of sheet name, bottom-up so each
+// resize leaves the rows of the blocks still to do where they were found.
+// Spare rows holding anything right of lastCol, or a comment, are kept rather
+// than removed. Comments are lifted off the sheet first and put back at their
+// moved rows.
+func updateSheet(f *excelize.File, st *styleSet, name string, blocks []blockUpdate, rowHeight float64, lastCol string) error {
+	rows, err := f.GetRows(name)
+	if err != nil {
+		return err
+	}
+	comments, err := f.GetComments(name)
+	if err != nil {
+		return err
+	}
+	commentRows := make([]int, len(comments))
+	kept := map[int]bool{}
+	for i, c := range comments {
+		_, commentRows[i], err = excelize.CellNameToCoordinates(c.Cell)
+		if err != nil {
+			return err
+		}
+		kept[commentRows[i]] = true
+		if err := f.DeleteComment(name, c.Cell); err != nil {
+			return err
+		}
+	}
+	last := colIndex(lastCol)
+	for r, row := range rows {
+		for c := last + 1; c < len(row); c++ {
+			if strings.TrimSpace(row[c]) != "" {
+				kept[r+1] = true
+			}
+		}
+	}
+	shift := func(at, n int) {
+		for i, r := range commentRows {
+			if r >= at {
+				commentRows[i] += n
+			}
+		}
+	}
+
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		lastData := b.totalRow - 1
+		_, wantLast, _ := calculateBlockRows(b.firstData, b.maxEntries)
+		if wantLast > lastData {
+			n := wantLast - lastData
+			if err := f.InsertRows(name, b.totalRow, n); err != nil {
+				return err
+			}
+			shift(b.totalRow, n)
+			styleDataRows(f, st, name, b.firstData, lastData+1, wantLast, rowHeight, lastCol)
+			lastData = wantLast
+		}
+		for r := lastData; r > wantLast; r-- {
+			if kept[r] {
+				continue
+			}
+			if err := f.RemoveRow(name, r); err != nil {
+				return err
+			}
+			shift(r+1, -1)
+			lastData--
+		}
+		totalRow := lastData + 1
+
+		for k := 0; k < 12; k++ {
+			itemCol, dataCol, valorCol := expenseMonthCols(k)
+			for r := b.firstData; r <= lastData; r++ {
+				for _, col := range []string{itemCol, dataCol, valorCol} {
+					if err := f.SetCellValue(name, cell(col, r), nil); err != nil {
+						return err
+					}
+				}
+			}
+			if err := f.SetCellFormula(name, cell(valorCol, totalRow), monthSum(k, b.firstData, lastData)); err != nil {
+				return err
+			}
+		}
+		writeEntries(f, name, b.months, b.firstData)
+		if !b.budget.IsZero() {
+			if err := f.SetCellValue(name, cell(budgetPlannedCol, totalRow), b.budget.Year()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, c := range comments {
+		col, _, err := excelize.CellNameToCoordinates(c.Cell)
+		if err != nil {
+			return err
+		}
+		if c.Cell, err = excelize.CoordinatesToCellName(col, commentRows[i]); err != nil {
+			return err
+		}
+		if err := f.AddComment(name, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func fixasSheet(diarista, aluguel int) []taxonomy.ExpenseType {
+	months := func(item string, n int) [12][]taxonomy.Entry {
+		var m [12][]taxonomy.Entry
+		for i := 0; i < n; i++ {
+			m[0] = append(m[0], taxonomy.Entry{Item: item, Day: i + 1, Value: 100})
+		}
+		return m
+	}
+	return []taxonomy.ExpenseType{{Name: "Fixas", Cats: []taxonomy.Category{
+		{Name: "Habitação", Subs: []taxonomy.Subcat{
+			{Name: "Diarista", Months: months("Diarista", diarista)},
+			{Name: "Aluguel", Months: months("Aluguel", aluguel)},
+		}},
+	}}}
+}
+
+var salario = []taxonomy.RevenueBlock{
+	{Category: "Receitas", Block: "Salário", Label: "Bruto", Months: [12][]taxonomy.Entry{0: {{Item: "Salário", Day: 5, Value: 9000}}}},
+}
+
+func TestUpdateWorkbook_KeepsManualEdits(t *testing.T) {
+	require.Equal(t, 0, headroomRows)
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	// Diarista: data row 3, total 4. Aluguel: data rows 5..7, total 8.
+	require.NoError(t, buildWorkbook(fixasSheet(1, 3), salario, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	require.NoError(t, f.SetCellValue("Fixas", "AN5", "conferir contrato"))
+	require.NoError(t, f.SetCellValue("Fixas", "AN6", "reajuste"))
+	require.NoError(t, f.AddComment("Fixas", excelize.Comment{Cell: "AN8", Author: "eu", Text: "revisar"}))
+	_, err = f.NewSheet("Notas")
+	require.NoError(t, err)
+	require.NoError(t, f.SetCellFormula("Notas", "A1", "Fixas!E8"))
+	require.NoError(t, f.Save())
+	require.NoError(t, f.Close())
+
+	// Diarista grows to 3 rows (Aluguel moves down 2); Aluguel shrinks to one
+	// entry but keeps its row holding "reajuste".
+	require.NoError(t, updateWorkbook(fixasSheet(3, 1), salario, path))
+
+	f, err = excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	get := func(sheet, ref string) string {
+		v, err := f.GetCellValue(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(sheet, ref string) string {
+		v, err := f.GetCellFormula(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, "Diarista", get("Fixas", "C5"))
+	assert.Equal(t, "SUM(E3:E5)", formula("Fixas", "E6"))
+	assert.Equal(t, "Aluguel", get("Fixas", "C7"))
+	assert.Empty(t, get("Fixas", "C8"), "the kept spare row is cleared")
+	assert.Equal(t, "SUM(E7:E8)", formula("Fixas", "E9"))
+	assert.Equal(t, "conferir contrato", get("Fixas", "AN7"))
+	assert.Equal(t, "reajuste", get("Fixas", "AN8"))
+	assert.Equal(t, "Fixas!E9", formula("Notas", "A1"), "references on other sheets follow the total row")
+
+	comments, err := f.GetComments("Fixas")
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "AN9", comments[0].Cell)
+	assert.Equal(t, "revisar", comments[0].Text)
+}
+
+func TestUpdateWorkbook_RefusesChangedTaxonomy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, buildWorkbook(fixasSheet(1, 1), salario, path))
+
+	changed := fixasSheet(1, 1)
+	changed[0].Cats[0].Subs[1].Name = "Condomínio"
+	err := updateWorkbook(changed, salario, path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Aluguel")
+	assert.Contains(t, err.Error(), "without --update")
+}
@@ -41,8 +41,12 @@ func sheetRef(name, col string, row int) string {
 }
 
 // needsQuote returns true when an Excel sheet name must be quoted in a formula reference.
-// Names are unquoted only when every character is ASCII alphanumeric.
+// Names are unquoted only when every character is ASCII alphanumeric and the
+// first is not a digit (a year sheet such as "2025" must be quoted).
 func needsQuote(s string) bool {
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		return true
+	}
 	for _, r := range s {
 		isASCIIAlnum := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
 		if !isASCIIAlnum {
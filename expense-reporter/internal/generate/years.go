@@ -0,0 +1,295 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/xuri/excelize/v2"
+)
+
+// Multi-year workbook layout. Every year sheet and the comparison sheet share
+// one row plan, so the comparison reads each year's cell on its own row.
+const (
+	yearFirstRow   = 4   // first data row; C2 holds the months covered
+	yearTotalCol   = "O" // after the months in C..N
+	yearAverageCol = "P"
+)
+
+// yearMonthCol returns the column of month k on a year sheet (C..N).
+func yearMonthCol(k int) string { return colName(2 + k) }
+
+// yearRowKind is what a row of the multi-year plan holds.
+type yearRowKind int
+
+const (
+	yearCategoryRow  yearRowKind = iota // one categoria of one expense type
+	yearTypeTotalRow                    // an expense type's total
+	yearExpensesRow                     // all expenses
+	yearRevenueRow                      // all revenue
+	yearBalanceRow                      // revenue minus expenses
+)
+
+// yearRow is one row of the multi-year plan.
+type yearRow struct {
+	Kind     yearRowKind
+	Type     string
+	Category string
+	Row      int
+	Terms    []int // rows summed by a total row
+}
+
+// planYearRows lays out the rows shared by the year sheets and the comparison:
+// each type's categorias then its total, the expenses total, revenue and balance.
+func planYearRows(expenseSheets []taxonomy.ExpenseType) []yearRow {
+	var rows []yearRow
+	row := yearFirstRow
+	var typeTotals []int
+	for _, sh := range expenseSheets {
+		var cats []int
+		for _, cat := range sh.Cats {
+			rows = append(rows, yearRow{Kind: yearCategoryRow, Type: sh.Name, Category: cat.Name, Row: row})
+			cats = append(cats, row)
+			row++
+		}
+		rows = append(rows, yearRow{Kind: yearTypeTotalRow, Type: sh.Name, Row: row, Terms: cats})
+		typeTotals = append(typeTotals, row)
+		row += 2 // total, blank
+	}
+	rows = append(rows,
+		yearRow{Kind: yearExpensesRow, Row: row, Terms: typeTotals},
+		yearRow{Kind: yearRevenueRow, Row: row + 1},
+		yearRow{Kind: yearBalanceRow, Row: row + 2, Terms: []int{row + 1, row}},
+	)
+	return rows
+}
+
+// label returns the row's col-A and col-B labels.
+func (r yearRow) label(lbl Labels) (a, b string) {
+	switch r.Kind {
+	case yearCategoryRow:
+		return r.Type, r.Category
+	case yearTypeTotalRow:
+		return r.Type, fmt.Sprintf(lbl.TotalCategoryFmt, lower(r.Type))
+	case yearExpensesRow:
+		return "", lbl.TotalExpenses
+	case yearRevenueRow:
+		return "", lbl.Revenue
+	}
+	return "", lbl.Balance
+}
+
+// styles returns the row's label, currency and percent styles.
+func (r yearRow) styles(st *styleSet) (lblStyle, curStyle, pctStyle int) {
+	switch r.Kind {
+	case yearCategoryRow, yearRevenueRow:
+		return st.DataCellArial, st.PullCur, st.PullPct
+	case yearTypeTotalRow:
+		return st.GroupTotalLbl, st.GroupTotalCur, st.GroupTotalPct
+	}
+	return st.SummaryTotalLbl, st.SummaryTotalCur, st.SummaryTotalPct
+}
+
+// buildYearsWorkbook renders a multi-year workbook: the comparison sheet, then
+// one sheet per year. expenseSheets and revenueBlocks are loaded across years
+// (target year 0); each year sheet keeps the entries dated in its year.
+func buildYearsWorkbook(expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock, years []int, outPath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	st, err := newStyles(f)
+	if err != nil {
+		return fmt.Errorf("styles: %w", err)
+	}
+	lbl, err := newLabels(workbookLocale)
+	if err != nil {
+		return err
+	}
+	rows := planYearRows(expenseSheets)
+
+	for _, year := range years {
+		sheets := make([]taxonomy.ExpenseType, len(expenseSheets))
+		for i, sh := range expenseSheets {
+			sheets[i] = sh.ForYear(year)
+		}
+		blocks := make([]taxonomy.RevenueBlock, len(revenueBlocks))
+		for i, b := range revenueBlocks {
+			blocks[i] = b.ForYear(year)
+		}
+		if err := buildYearSheet(f, st, lbl, strconv.Itoa(year), sheets, blocks, rows); err != nil {
+			return fmt.Errorf("year %d: %w", year, err)
+		}
+	}
+	if err := buildComparisonSheet(f, st, lbl, years, rows); err != nil {
+		return fmt.Errorf("comparison: %w", err)
+	}
+
+	if err := f.DeleteSheet("Sheet1"); err != nil {
+		return err
+	}
+	if i, _ := f.GetSheetIndex(lbl.ComparisonSheet); i >= 0 {
+		f.SetActiveSheet(i)
+	}
+	if err := f.UpdateLinkedValue(); err != nil {
+		return fmt.Errorf("update linked: %w", err)
+	}
+	yes := true
+	if err := f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &yes}); err != nil {
+		return fmt.Errorf("calc props: %w", err)
+	}
+	return saveWorkbook(f, outPath)
+}
+
+// buildYearSheet writes one year: month sums per categoria, type totals,
+// expenses, revenue and balance, each with its year total and its average over
+// the months covered (up to the last month holding any entry).
+func buildYearSheet(f *excelize.File, st *styleSet, lbl Labels, name string, expenseSheets []taxonomy.ExpenseType, revenueBlocks []taxonomy.RevenueBlock, rows []yearRow) error {
+	if _, err := f.NewSheet(name); err != nil {
+		return err
+	}
+	f.SetColWidth(name, "A", "A", 20.57)
+	f.SetColWidth(name, "B", "B", 24)
+	f.SetColWidth(name, yearMonthCol(0), yearAverageCol, 14.29)
+	writeYearHeader(f, st, lbl, name)
+
+	catSums := map[string][12]float64{}
+	covered := 0
+	add := func(sums *[12]float64, months [12][]taxonomy.Entry) {
+		for k, entries := range months {
+			for _, e := range entries {
+				sums[k] += e.Value
+				covered = max(covered, k+1)
+			}
+		}
+	}
+	for _, sh := range expenseSheets {
+		for _, cat := range sh.Cats {
+			var sums [12]float64
+			for _, sub := range cat.Subs {
+				add(&sums, sub.Months)
+			}
+			catSums[sh.Name+"\x00"+cat.Name] = sums
+		}
+	}
+	var revenue [12]float64
+	for _, b := range revenueBlocks {
+		add(&revenue, b.Months)
+	}
+	f.SetCellValue(name, "A2", lbl.MonthsCovered)
+	f.SetCellValue(name, "C2", covered)
+
+	for _, r := range rows {
+		a, b := r.label(lbl)
+		lblStyle, curStyle, _ := r.styles(st)
+		f.SetCellValue(name, cell("A", r.Row), a)
+		f.SetCellValue(name, cell("B", r.Row), b)
+		f.SetCellStyle(name, cell("A", r.Row), cell("B", r.Row), lblStyle)
+		for k := range 12 {
+			c := cell(yearMonthCol(k), r.Row)
+			switch r.Kind {
+			case yearCategoryRow:
+				f.SetCellValue(name, c, catSums[r.Type+"\x00"+r.Category][k])
+			case yearRevenueRow:
+				f.SetCellValue(name, c, revenue[k])
+			case yearBalanceRow:
+				f.SetCellFormula(name, c, cell(yearMonthCol(k), r.Terms[0])+"-"+cell(yearMonthCol(k), r.Terms[1]))
+			default:
+				terms := make([]string, len(r.Terms))
+				for i, t := range r.Terms {
+					terms[i] = cell(yearMonthCol(k), t)
+				}
+				f.SetCellFormula(name, c, sumList(terms))
+			}
+		}
+		total := cell(yearTotalCol, r.Row)
+		f.SetCellFormula(name, total, sumRange(cell(yearMonthCol(0), r.Row), cell(yearMonthCol(11), r.Row)))
+		f.SetCellFormula(name, cell(yearAverageCol, r.Row), fmt.Sprintf("IF($C$2>0,%[1]s/$C$2,0)", total))
+		f.SetCellStyle(name, cell(yearMonthCol(0), r.Row), cell(yearAverageCol, r.Row), curStyle)
+	}
+	f.SetPanes(name, &excelize.Panes{Freeze: true, XSplit: 2, YSplit: 3, TopLeftCell: "C4", ActivePane: "bottomRight"})
+	return nil
+}
+
+// writeYearHeader writes a year sheet's header row: Tipo, Categoria, the
+// months, Total and Média mensal.
+func writeYearHeader(f *excelize.File, st *styleSet, lbl Labels, name string) {
+	f.SetCellValue(name, "A1", lbl.Type)
+	f.SetCellValue(name, "B1", lbl.Category)
+	for k := range 12 {
+		f.SetCellValue(name, yearMonthCol(k)+"1", lbl.MonthNames[k])
+	}
+	f.SetCellValue(name, yearTotalCol+"1", lbl.Total)
+	f.SetCellValue(name, yearAverageCol+"1", lbl.MonthlyAverage)
+	f.SetCellStyle(name, "A1", yearAverageCol+"1", st.SummaryMonth)
+}
+
+// buildComparisonSheet writes the year-over-year comparison: for every row of
+// the plan, each year's total and monthly average (pulled from the year
+// sheets), then the change and % change between consecutive years.
+func buildComparisonSheet(f *excelize.File, st *styleSet, lbl Labels, years []int, rows []yearRow) error {
+	name := lbl.ComparisonSheet
+	if _, err := f.NewSheet(name); err != nil {
+		return err
+	}
+	n := len(years)
+	totalCol := func(i int) string { return colName(2 + i) }
+	avgCol := func(i int) string { return colName(2 + n + i) }
+	changeCol := func(i int) string { return colName(2 + 2*n + i - 1) } // i >= 1
+	pctCol := func(i int) string { return colName(2 + 3*n + i - 2) }    // i >= 1
+	lastCol := colName(2 + 4*n - 3)
+
+	f.SetColWidth(name, "A", "A", 20.57)
+	f.SetColWidth(name, "B", "B", 24)
+	f.SetColWidth(name, "C", lastCol, 14.29)
+
+	group := func(first, last, label string) {
+		if first != last {
+			f.MergeCell(name, first+"1", last+"1")
+		}
+		f.SetCellValue(name, first+"1", label)
+	}
+	f.SetCellValue(name, "A1", lbl.Type)
+	f.SetCellValue(name, "B1", lbl.Category)
+	group(totalCol(0), totalCol(n-1), lbl.YearTotals)
+	group(avgCol(0), avgCol(n-1), lbl.MonthlyAverage)
+	if n > 1 {
+		group(changeCol(1), changeCol(n-1), lbl.Change)
+		group(pctCol(1), pctCol(n-1), lbl.PctChange)
+	}
+	for i, y := range years {
+		f.SetCellValue(name, totalCol(i)+"2", strconv.Itoa(y))
+		f.SetCellValue(name, avgCol(i)+"2", strconv.Itoa(y))
+		if i > 0 {
+			vs := fmt.Sprintf(lbl.YearVsFmt, y, years[i-1])
+			f.SetCellValue(name, changeCol(i)+"2", vs)
+			f.SetCellValue(name, pctCol(i)+"2", vs)
+		}
+	}
+	f.SetCellStyle(name, "A1", lastCol+"2", st.SummaryMonth)
+
+	for _, r := range rows {
+		a, b := r.label(lbl)
+		lblStyle, curStyle, pctStyle := r.styles(st)
+		f.SetCellValue(name, cell("A", r.Row), a)
+		f.SetCellValue(name, cell("B", r.Row), b)
+		f.SetCellStyle(name, cell("A", r.Row), cell("B", r.Row), lblStyle)
+		for i, y := range years {
+			sheet := strconv.Itoa(y)
+			f.SetCellFormula(name, cell(totalCol(i), r.Row), sheetRef(sheet, yearTotalCol, r.Row))
+			f.SetCellFormula(name, cell(avgCol(i), r.Row), sheetRef(sheet, yearAverageCol, r.Row))
+			if i == 0 {
+				continue
+			}
+			cur, prev := cell(totalCol(i), r.Row), cell(totalCol(i-1), r.Row)
+			f.SetCellFormula(name, cell(changeCol(i), r.Row), cur+"-"+prev)
+			f.SetCellFormula(name, cell(pctCol(i), r.Row), fmt.Sprintf(`IF(%[2]s=0,"",(%[1]s-%[2]s)/%[2]s)`, cur, prev))
+		}
+		f.SetCellStyle(name, cell(totalCol(0), r.Row), cell(avgCol(n-1), r.Row), curStyle)
+		if n > 1 {
+			f.SetCellStyle(name, cell(changeCol(1), r.Row), cell(changeCol(n-1), r.Row), curStyle)
+			f.SetCellStyle(name, cell(pctCol(1), r.Row), cell(pctCol(n-1), r.Row), pctStyle)
+		}
+	}
+	f.SetPanes(name, &excelize.Panes{Freeze: true, XSplit: 2, YSplit: 3, TopLeftCell: "C4", ActivePane: "bottomRight"})
+	return f.MoveSheet(name, strconv.Itoa(years[0]))
+}
@@ -0,0 +1,83 @@
+package generate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/taxonomy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestPlanYearRows(t *testing.T) {
+	rows := planYearRows(fixasSheet(1, 1))
+	require.Len(t, rows, 5)
+	assert.Equal(t, yearRow{Kind: yearCategoryRow, Type: "Fixas", Category: "Habitação", Row: 4}, rows[0])
+	assert.Equal(t, yearRow{Kind: yearTypeTotalRow, Type: "Fixas", Row: 5, Terms: []int{4}}, rows[1])
+	assert.Equal(t, yearRow{Kind: yearExpensesRow, Row: 7, Terms: []int{5}}, rows[2])
+	assert.Equal(t, yearRow{Kind: yearRevenueRow, Row: 8}, rows[3])
+	assert.Equal(t, yearRow{Kind: yearBalanceRow, Row: 9, Terms: []int{8, 7}}, rows[4])
+}
+
+func TestBuildYearsWorkbook(t *testing.T) {
+	var diarista, aluguel, revenue [12][]taxonomy.Entry
+	diarista[0] = []taxonomy.Entry{{Item: "Diarista", Day: 3, Value: 100, Year: 2024}}
+	diarista[2] = []taxonomy.Entry{{Item: "Diarista", Day: 3, Value: 150, Year: 2025}}
+	aluguel[1] = []taxonomy.Entry{{Item: "Aluguel", Day: 10, Value: 200, Year: 2025}, {Item: "Aluguel", Day: 10, Value: 999}}
+	revenue[0] = []taxonomy.Entry{{Item: "Salário", Day: 5, Value: 9000, Year: 2024}, {Item: "Salário", Day: 5, Value: 9500, Year: 2025}}
+	sheets := []taxonomy.ExpenseType{{Name: "Fixas", Cats: []taxonomy.Category{{Name: "Habitação", Subs: []taxonomy.Subcat{
+		{Name: "Diarista", Months: diarista},
+		{Name: "Aluguel", Months: aluguel},
+	}}}}}
+	blocks := []taxonomy.RevenueBlock{{Category: "Receitas", Block: "Salário", Label: "Bruto", Months: revenue}}
+
+	path := filepath.Join(t.TempDir(), "years.xlsx")
+	require.NoError(t, buildYearsWorkbook(sheets, blocks, []int{2024, 2025}, path))
+
+	f, err := excelize.OpenFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Comparativo", "2024", "2025"}, f.GetSheetList())
+
+	get := func(sheet, ref string) string {
+		v, err := f.GetCellValue(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+	formula := func(sheet, ref string) string {
+		v, err := f.GetCellFormula(sheet, ref)
+		require.NoError(t, err)
+		return v
+	}
+
+	// 2024: Diarista in January only. 2025: Aluguel in February, Diarista in
+	// March; the year-less entry is left out.
+	assert.Equal(t, "1", get("2024", "C2"))
+	assert.Equal(t, "100", get("2024", "C4"))
+	assert.Equal(t, "3", get("2025", "C2"))
+	assert.Equal(t, "0", get("2025", "C4"))
+	assert.Equal(t, "200", get("2025", "D4"))
+	assert.Equal(t, "150", get("2025", "E4"))
+	assert.Equal(t, "9500", get("2025", "C8"))
+	assert.Equal(t, "Total fixas", get("2025", "B5"))
+	assert.Equal(t, "SUM(C4)", formula("2025", "C5"))
+	assert.Equal(t, "C8-C7", formula("2025", "C9"))
+	assert.Equal(t, "SUM(C4:N4)", formula("2025", "O4"))
+	assert.Equal(t, "IF($C$2>0,O4/$C$2,0)", formula("2025", "P4"))
+
+	// Comparativo: totals C..D, averages E..F, change G, % change H.
+	assert.Equal(t, "Habitação", get("Comparativo", "B4"))
+	assert.Equal(t, "2024", get("Comparativo", "C2"))
+	assert.Equal(t, "2025 x 2024", get("Comparativo", "G2"))
+	assert.Equal(t, "'2024'!O4", formula("Comparativo", "C4"))
+	assert.Equal(t, "'2025'!P4", formula("Comparativo", "F4"))
+	assert.Equal(t, "D4-C4", formula("Comparativo", "G4"))
+	assert.Equal(t, `IF(C4=0,"",(D4-C4)/C4)`, formula("Comparativo", "H4"))
+}
+
+func TestGenerate_YearsRefusesUpdate(t *testing.T) {
+	err := Generate(Options{TaxonomyPath: "taxonomy.json", OutPath: "out.xlsx", Years: []int{2024, 2025}, Update: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot be updated")
+}
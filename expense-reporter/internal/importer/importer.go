@@ -0,0 +1,233 @@
+// Package importer rebuilds expense log entries from a hand-maintained workbook
+// (one with the "Referência de Categorias" sheet), so years that were only ever
+// typed into a spreadsheet can be regenerated from expenses_log.jsonl.
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/installment"
+	"expense-reporter/internal/resolver"
+	"expense-reporter/pkg/utils"
+)
+
+// Block is the rows of one subcategory section of a type sheet: from the
+// subcategory's header row to the row before its total (or, without a total
+// row in the reference sheet, before the next section). End is 0 for a
+// section that runs to the end of the sheet.
+type Block struct {
+	resolver.SubcategoryMapping
+	Start, End int
+}
+
+func (b Block) contains(row int) bool {
+	return row >= b.Start && (b.End == 0 || row <= b.End)
+}
+
+// Result is what Import read from a workbook.
+type Result struct {
+	Entries  []feedback.ExpenseEntry // typed log entries, in sheet, month and row order
+	Plans    []installment.Plan      // one per installment purchase found
+	Unplaced []excel.WorkbookEntry   // entries outside every subcategory section
+}
+
+// Import reads every month's item/date/value cells of each type sheet named in
+// the workbook's reference sheet and converts them to expense log entries filed
+// under the section's type, category and subcategory. DD/MM dates and empty
+// date cells take year (an empty date is the first of its month).
+func Import(workbookPath string, year int) (Result, error) {
+	blocks, err := Blocks(workbookPath)
+	if err != nil {
+		return Result{}, err
+	}
+	sheets := make([]string, 0, len(blocks))
+	for sheet := range blocks {
+		sheets = append(sheets, sheet)
+	}
+	sort.Strings(sheets)
+	book, err := excel.ReadExpenseEntries(workbookPath, sheets, year)
+	if err != nil {
+		return Result{}, err
+	}
+	return Convert(blocks, book, year), nil
+}
+
+// Blocks maps each type sheet in the workbook's reference sheet to its
+// subcategory sections, in row order. A section starts at the header row the
+// reference sheet records or, when it records none, at the first row whose
+// column B holds the subcategory name (see excel.FindSubcategoryRowBatch).
+func Blocks(workbookPath string) (map[string][]Block, error) {
+	mappings, err := excel.LoadReferenceSheet(workbookPath)
+	if err != nil {
+		return nil, err
+	}
+	var all []resolver.SubcategoryMapping
+	var lookups []excel.SubcategoryLookupRequest
+	for _, list := range mappings {
+		for _, m := range list {
+			all = append(all, m)
+			if m.RowNumber == 0 {
+				lookups = append(lookups, excel.SubcategoryLookupRequest{SheetName: m.SheetName, Subcategory: m.Subcategory})
+			}
+		}
+	}
+	found, err := excel.FindSubcategoryRowBatch(workbookPath, lookups)
+	if err != nil {
+		return nil, fmt.Errorf("locating subcategory sections: %w", err)
+	}
+
+	blocks := map[string][]Block{}
+	for _, m := range all {
+		start := m.RowNumber
+		if start == 0 {
+			start = found[m.SheetName][m.Subcategory]
+		}
+		blocks[m.SheetName] = append(blocks[m.SheetName], Block{SubcategoryMapping: m, Start: start})
+	}
+	for sheet, list := range blocks {
+		sort.Slice(list, func(i, j int) bool { return list[i].Start < list[j].Start })
+		for i := range list {
+			switch {
+			case list[i].TotalRow > list[i].Start:
+				list[i].End = list[i].TotalRow - 1
+			case i+1 < len(list):
+				list[i].End = list[i+1].Start - 1
+			}
+		}
+		blocks[sheet] = list
+	}
+	return blocks, nil
+}
+
+// Convert files each workbook entry under the block of its sheet containing its
+// row and builds its log entry. Items ending in an installment suffix "(n/m)"
+// become installments of a plan; see plans.
+func Convert(blocks map[string][]Block, book []excel.WorkbookEntry, year int) Result {
+	var r Result
+	var parts []part
+	for _, w := range book {
+		b, ok := blockOf(blocks[w.Sheet], w.Row)
+		if !ok {
+			r.Unplaced = append(r.Unplaced, w)
+			continue
+		}
+		date := w.Date
+		if date.IsZero() {
+			date = time.Date(year, w.Month, 1, 0, 0, 0, 0, time.UTC)
+		}
+		item := w.Item
+		base, n, m, isInstallment := parseInstallment(w.Item)
+		if isInstallment {
+			item = fmt.Sprintf("%s (%d/%d)", base, n, m)
+		}
+		e := feedback.NewExpenseEntry(item, utils.FormatDate(date), w.Value, b.Subcategory, b.Category)
+		e.Type = b.SheetName
+		r.Entries = append(r.Entries, e)
+		if isInstallment {
+			parts = append(parts, part{entry: len(r.Entries) - 1, base: base, n: n, m: m, date: date})
+		}
+	}
+	r.Plans = plans(r.Entries, parts)
+	return r
+}
+
+// part is an installment found in the workbook: entry indexes Result.Entries.
+type part struct {
+	entry int
+	base  string
+	n, m  int
+	date  time.Time
+}
+
+// plans groups installments by purchase (the same item, count, section and
+// first installment date, counted back n-1 months from each), links their
+// entries to a plan and returns the plans. Payments the workbook does not show
+// (earlier or later years) are assumed equal to the last one it does.
+func plans(entries []feedback.ExpenseEntry, parts []part) []installment.Plan {
+	type purchase struct {
+		first  time.Time
+		values map[int]float64
+		last   int
+		parts  []part
+	}
+	var order []string
+	groups := map[string]*purchase{}
+	for _, p := range parts {
+		e := entries[p.entry]
+		first := addMonths(p.date, -(p.n - 1))
+		key := strings.Join([]string{e.Type, e.Category, e.Subcategory, strings.ToLower(p.base), strconv.Itoa(p.m), utils.FormatDate(first)}, "\x00")
+		g, ok := groups[key]
+		if !ok {
+			g = &purchase{first: first, values: map[int]float64{}}
+			groups[key] = g
+			order = append(order, key)
+		}
+		g.values[p.n] = e.Value
+		if p.n > g.last {
+			g.last = p.n
+		}
+		g.parts = append(g.parts, p)
+	}
+
+	out := make([]installment.Plan, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		head := g.parts[0]
+		e := entries[head.entry]
+		values := make([]float64, head.m)
+		for i := range values {
+			v, ok := g.values[i+1]
+			if !ok {
+				v = g.values[g.last]
+			}
+			values[i] = v
+		}
+		id := feedback.GenerateID(head.base, utils.FormatDate(g.first), values[0])
+		out = append(out, installment.NewPlan(id, head.base, g.first, utils.InstallmentSchedule{Values: values},
+			e.Type, e.Category, e.Subcategory))
+		for _, p := range g.parts {
+			entries[p.entry].PlanID = id
+		}
+	}
+	return out
+}
+
+func blockOf(blocks []Block, row int) (Block, bool) {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].contains(row) {
+			return blocks[i], true
+		}
+	}
+	return Block{}, false
+}
+
+var installmentSuffix = regexp.MustCompile(`^(.*\S)\s*\((\d+)/(\d+)\)$`)
+
+// parseInstallment splits "Geladeira (3/10)" into its item, number and count.
+func parseInstallment(item string) (base string, n, m int, ok bool) {
+	match := installmentSuffix.FindStringSubmatch(item)
+	if match == nil {
+		return "", 0, 0, false
+	}
+	n, _ = strconv.Atoi(match[2])
+	m, _ = strconv.Atoi(match[3])
+	if n < 1 || m < 2 || n > m {
+		return "", 0, 0, false
+	}
+	return match[1], n, m, true
+}
+
+// addMonths shifts t by n months, clamping the day to the target month's
+// length as the installment expansion does.
+func addMonths(t time.Time, n int) time.Time {
+	target := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(target.Year(), target.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return time.Date(target.Year(), target.Month(), min(t.Day(), last), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"expense-reporter/internal/feedback"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// handWorkbook writes a workbook typed by hand: a reference sheet (rows 1-4
+// are headers) and one type sheet with two subcategory sections.
+func handWorkbook(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Despesas_2025.xlsx")
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const ref = "Referência de Categorias"
+	require.NoError(t, f.SetSheetName("Sheet1", ref))
+	for cell, v := range map[string]any{
+		"A5": "Variáveis", "B5": "Transporte", "C5": "Uber/Taxi", "D5": 3, "F5": 6,
+		"A6": "Variáveis", "B6": "Transporte", "C6": "Ônibus", // no row: found in column B
+	} {
+		require.NoError(t, f.SetCellValue(ref, cell, v))
+	}
+
+	const sheet = "Variáveis"
+	_, err := f.NewSheet(sheet)
+	require.NoError(t, err)
+	for cell, v := range map[string]any{
+		"A3": "Transporte", "B3": "Uber/Taxi",
+		"D3": "Uber", "E3": "15/01", "F3": 35.5,
+		"D4": "Geladeira (2/3)", "E4": "10/01", "F4": 300,
+		"G3": "Geladeira (3/3)", "H3": "10/02", "I3": 300,
+		"D6": "Total", "E6": "-",
+		"D7": "Avulso", "E7": "20/01", "F7": 5, // between the Uber/Taxi total and Ônibus
+		"B8": "Ônibus",
+		"D8": "Passe", "F8": 150,
+	} {
+		require.NoError(t, f.SetCellValue(sheet, cell, v))
+	}
+	require.NoError(t, f.SetCellFormula(sheet, "F6", "SUM(F3:F5)"))
+	require.NoError(t, f.SaveAs(path))
+	return path
+}
+
+func TestBlocks(t *testing.T) {
+	blocks, err := Blocks(handWorkbook(t))
+	require.NoError(t, err)
+
+	list := blocks["Variáveis"]
+	require.Len(t, list, 2)
+	assert.Equal(t, "Uber/Taxi", list[0].Subcategory)
+	assert.Equal(t, [2]int{3, 5}, [2]int{list[0].Start, list[0].End}, "ends before the total row")
+	assert.Equal(t, "Ônibus", list[1].Subcategory)
+	assert.Equal(t, [2]int{8, 0}, [2]int{list[1].Start, list[1].End}, "last section runs to the end of the sheet")
+}
+
+func TestImport(t *testing.T) {
+	result, err := Import(handWorkbook(t), 2025)
+	require.NoError(t, err)
+
+	require.Len(t, result.Unplaced, 1)
+	assert.Equal(t, "D7", result.Unplaced[0].Cell)
+
+	byItem := map[string]feedback.ExpenseEntry{}
+	for _, e := range result.Entries {
+		byItem[e.Item] = e
+	}
+	require.Len(t, byItem, 4)
+	uber := byItem["Uber"]
+	assert.Equal(t, feedback.GenerateID("Uber", "15/01/2025", 35.5), uber.ID)
+	assert.Equal(t, []string{"Variáveis", "Transporte", "Uber/Taxi"}, []string{uber.Type, uber.Category, uber.Subcategory})
+	assert.Empty(t, uber.PlanID)
+	assert.Equal(t, "01/01/2025", byItem["Passe"].Date, "an empty date is the first of its month")
+	assert.Equal(t, "Ônibus", byItem["Passe"].Subcategory)
+
+	require.Len(t, result.Plans, 1)
+	plan := result.Plans[0]
+	assert.Equal(t, feedback.GenerateID("Geladeira", "10/12/2024", 300), plan.ID)
+	assert.Equal(t, "Geladeira", plan.Item)
+	assert.Equal(t, "10/12/2024", plan.FirstDate, "counted back from installment 2 in January")
+	assert.Equal(t, 3, plan.Count)
+	assert.Equal(t, 900.0, plan.Total)
+
+	installments := plan.Installments()
+	for _, n := range []int{2, 3} {
+		e := byItem[installments[n-1].Item]
+		assert.Equal(t, plan.ID, e.PlanID)
+		assert.Equal(t, installments[n-1].ID, e.ID, "installment %d matches the plan's expansion", n)
+	}
+}
+
+func TestParseInstallment(t *testing.T) {
+	tests := []struct {
+		item string
+		base string
+		n, m int
+		ok   bool
+	}{
+		{"Geladeira (3/10)", "Geladeira", 3, 10, true},
+		{"Notebook  (1/2)", "Notebook", 1, 2, true},
+		{"Mercado", "", 0, 0, false},
+		{"Ingresso (1/1)", "", 0, 0, false},
+		{"Curso (4/3)", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		base, n, m, ok := parseInstallment(tt.item)
+		assert.Equal(t, tt.ok, ok, tt.item)
+		assert.Equal(t, tt.base, base, tt.item)
+		assert.Equal(t, [2]int{tt.n, tt.m}, [2]int{n, m}, tt.item)
+	}
+}
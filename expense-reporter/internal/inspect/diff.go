@@ -0,0 +1,233 @@
+package inspect
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Diff is the structural difference between two workbook dumps, A (before)
+// and B (after). Sheets are matched by name; only sheets that differ are kept.
+type Diff struct {
+	A       string      `json:"a"`
+	B       string      `json:"b"`
+	OnlyInA []string    `json:"onlyInA,omitempty"`
+	OnlyInB []string    `json:"onlyInB,omitempty"`
+	Sheets  []SheetDiff `json:"sheets,omitempty"`
+}
+
+// Identical reports whether the two workbooks have no structural difference.
+func (d Diff) Identical() bool {
+	return len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0 && len(d.Sheets) == 0
+}
+
+// SheetDiff lists what changed in one sheet present in both workbooks.
+type SheetDiff struct {
+	Sheet         string          `json:"sheet"`
+	Dimensions    *DimChange      `json:"dimensions,omitempty"`
+	Cells         []CellChange    `json:"cells,omitempty"`
+	MergesRemoved []Merge         `json:"mergesRemoved,omitempty"`
+	MergesAdded   []Merge         `json:"mergesAdded,omitempty"`
+	RowTypes      []RowTypeChange `json:"rowTypes,omitempty"`
+}
+
+func (s SheetDiff) empty() bool {
+	return s.Dimensions == nil && len(s.Cells) == 0 && len(s.MergesRemoved) == 0 &&
+		len(s.MergesAdded) == 0 && len(s.RowTypes) == 0
+}
+
+type DimChange struct {
+	From Dim `json:"from"`
+	To   Dim `json:"to"`
+}
+
+// CellChange is one changed attribute of a cell. A cell present on one side
+// only compares against an empty, unstyled cell.
+type CellChange struct {
+	Ref   string `json:"ref"`
+	Field string `json:"field"` // "value", "formula" or "style"
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type RowTypeChange struct {
+	Row  int    `json:"row"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Compare diffs two workbook dumps sheet by sheet: values, formulas, styles,
+// merges and row classification. Sheets only in A are reported in A's order,
+// sheets only in B (and the changed sheets) in B's order.
+func Compare(a, b *Workbook) Diff {
+	d := Diff{A: a.Source, B: b.Source}
+	before := make(map[string]*SheetDump, len(a.Sheets))
+	for _, s := range a.Sheets {
+		before[s.Sheet] = s
+	}
+	after := make(map[string]bool, len(b.Sheets))
+	for _, s := range b.Sheets {
+		after[s.Sheet] = true
+	}
+	for _, s := range a.Sheets {
+		if !after[s.Sheet] {
+			d.OnlyInA = append(d.OnlyInA, s.Sheet)
+		}
+	}
+	for _, s := range b.Sheets {
+		old, ok := before[s.Sheet]
+		if !ok {
+			d.OnlyInB = append(d.OnlyInB, s.Sheet)
+			continue
+		}
+		if sd := compareSheet(old, s); !sd.empty() {
+			d.Sheets = append(d.Sheets, sd)
+		}
+	}
+	return d
+}
+
+func compareSheet(a, b *SheetDump) SheetDiff {
+	sd := SheetDiff{Sheet: b.Sheet}
+	if a.Dimensions != b.Dimensions {
+		sd.Dimensions = &DimChange{From: a.Dimensions, To: b.Dimensions}
+	}
+	sd.Cells = compareCells(cellsByRef(a.Rows), cellsByRef(b.Rows))
+	sd.MergesRemoved, sd.MergesAdded = compareMerges(a.MergedCells, b.MergedCells)
+	sd.RowTypes = compareRowTypes(a.Rows, b.Rows)
+	return sd
+}
+
+func cellsByRef(rows []RowDump) map[string]Cell {
+	cells := map[string]Cell{}
+	for _, rd := range rows {
+		for _, c := range rd.Cells {
+			cells[c.Col+strconv.Itoa(rd.Row)] = c
+		}
+	}
+	return cells
+}
+
+func compareCells(a, b map[string]Cell) []CellChange {
+	refs := make([]string, 0, len(a)+len(b))
+	for ref := range a {
+		refs = append(refs, ref)
+	}
+	for ref := range b {
+		if _, ok := a[ref]; !ok {
+			refs = append(refs, ref)
+		}
+	}
+	sortRefs(refs)
+
+	var changes []CellChange
+	for _, ref := range refs {
+		from, to := a[ref], b[ref]
+		if from.Value != to.Value {
+			changes = append(changes, CellChange{Ref: ref, Field: "value", From: from.Value, To: to.Value})
+		}
+		if from.Formula != to.Formula {
+			changes = append(changes, CellChange{Ref: ref, Field: "formula", From: from.Formula, To: to.Formula})
+		}
+		if from.Style != to.Style {
+			changes = append(changes, CellChange{Ref: ref, Field: "style", From: from.Style.String(), To: to.Style.String()})
+		}
+	}
+	return changes
+}
+
+// sortRefs orders cell references by row, then column.
+func sortRefs(refs []string) {
+	sort.Slice(refs, func(i, j int) bool {
+		ci, ri, _ := excelize.CellNameToCoordinates(refs[i])
+		cj, rj, _ := excelize.CellNameToCoordinates(refs[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ci < cj
+	})
+}
+
+// compareMerges returns the merges only in a and only in b. A merge whose
+// range is unchanged but whose value changed appears in both.
+func compareMerges(a, b []Merge) (removed, added []Merge) {
+	inA, inB := map[Merge]bool{}, map[Merge]bool{}
+	for _, m := range a {
+		inA[m] = true
+	}
+	for _, m := range b {
+		inB[m] = true
+	}
+	for _, m := range a {
+		if !inB[m] {
+			removed = append(removed, m)
+		}
+	}
+	for _, m := range b {
+		if !inA[m] {
+			added = append(added, m)
+		}
+	}
+	return removed, added
+}
+
+// compareRowTypes reports rows whose classification changed; a row missing
+// from one side has the empty type there.
+func compareRowTypes(a, b []RowDump) []RowTypeChange {
+	types := func(rows []RowDump) map[int]string {
+		m := make(map[int]string, len(rows))
+		for _, rd := range rows {
+			m[rd.Row] = rd.RowType
+		}
+		return m
+	}
+	from, to := types(a), types(b)
+	rows := make([]int, 0, len(from)+len(to))
+	for r := range from {
+		rows = append(rows, r)
+	}
+	for r := range to {
+		if _, ok := from[r]; !ok {
+			rows = append(rows, r)
+		}
+	}
+	sort.Ints(rows)
+
+	var changes []RowTypeChange
+	for _, r := range rows {
+		if from[r] != to[r] {
+			changes = append(changes, RowTypeChange{Row: r, From: from[r], To: to[r]})
+		}
+	}
+	return changes
+}
+
+// String renders a style compactly, e.g. "bg=C0C0C0 bold border=TB"; the
+// default style is "default".
+func (s Style) String() string {
+	var parts []string
+	if s.BgColor != "" {
+		parts = append(parts, "bg="+s.BgColor)
+	}
+	if s.Bold {
+		parts = append(parts, "bold")
+	}
+	border := ""
+	for _, side := range []struct {
+		on     bool
+		letter string
+	}{{s.BorderTop, "T"}, {s.BorderBottom, "B"}, {s.BorderLeft, "L"}, {s.BorderRight, "R"}} {
+		if side.on {
+			border += side.letter
+		}
+	}
+	if border != "" {
+		parts = append(parts, "border="+border)
+	}
+	if len(parts) == 0 {
+		return "default"
+	}
+	return strings.Join(parts, " ")
+}
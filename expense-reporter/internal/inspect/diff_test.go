@@ -0,0 +1,87 @@
+package inspect
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// saveMini writes a one-block sheet (column labels, a data row, a total row)
+// plus any extra sheets, letting edit change it before saving.
+func saveMini(t *testing.T, path string, edit func(f *excelize.File)) {
+	t.Helper()
+	f := excelize.NewFile()
+	_, err := f.NewSheet("Mini")
+	require.NoError(t, err)
+	require.NoError(t, f.DeleteSheet("Sheet1"))
+	f.SetCellValue("Mini", "C1", "Item")
+	f.SetCellValue("Mini", "E1", "Valor")
+	f.SetCellValue("Mini", "B2", "Padaria")
+	f.SetCellValue("Mini", "E2", 12)
+	f.SetCellValue("Mini", "A3", "Total")
+	f.SetCellFormula("Mini", "E3", "SUM(E2:E2)")
+	if edit != nil {
+		edit(f)
+	}
+	require.NoError(t, f.SaveAs(path))
+}
+
+func TestCompare_Identical(t *testing.T) {
+	dir := t.TempDir()
+	saveMini(t, filepath.Join(dir, "a.xlsx"), nil)
+	saveMini(t, filepath.Join(dir, "b.xlsx"), nil)
+
+	a, err := Load(filepath.Join(dir, "a.xlsx"))
+	require.NoError(t, err)
+	b, err := Load(filepath.Join(dir, "b.xlsx"))
+	require.NoError(t, err)
+	assert.True(t, Compare(a, b).Identical())
+}
+
+func TestCompare_Changes(t *testing.T) {
+	dir := t.TempDir()
+	saveMini(t, filepath.Join(dir, "a.xlsx"), func(f *excelize.File) {
+		_, err := f.NewSheet("Velha")
+		require.NoError(t, err)
+	})
+	saveMini(t, filepath.Join(dir, "b.xlsx"), func(f *excelize.File) {
+		f.SetCellValue("Mini", "E2", 15)
+		f.SetCellFormula("Mini", "E3", "SUM(E2:E4)")
+		bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		require.NoError(t, err)
+		require.NoError(t, f.SetCellStyle("Mini", "B2", "B2", bold))
+		f.MergeCell("Mini", "A5", "B5")
+		f.SetCellValue("Mini", "A5", "Nota")
+		_, err = f.NewSheet("Nova")
+		require.NoError(t, err)
+	})
+
+	a, err := Load(filepath.Join(dir, "a.xlsx"))
+	require.NoError(t, err)
+	b, err := Load(filepath.Join(dir, "b.xlsx"))
+	require.NoError(t, err)
+	d := Compare(a, b)
+
+	assert.Equal(t, []string{"Velha"}, d.OnlyInA)
+	assert.Equal(t, []string{"Nova"}, d.OnlyInB)
+	require.Len(t, d.Sheets, 1)
+	s := d.Sheets[0]
+	assert.Equal(t, "Mini", s.Sheet)
+	require.NotNil(t, s.Dimensions)
+	assert.Equal(t, 5, s.Dimensions.To.Rows)
+	assert.Contains(t, s.Cells, CellChange{Ref: "B2", Field: "style", From: "default", To: "bold"})
+	assert.Contains(t, s.Cells, CellChange{Ref: "E2", Field: "value", From: "12", To: "15"})
+	assert.Contains(t, s.Cells, CellChange{Ref: "E3", Field: "formula", From: "SUM(E2:E2)", To: "SUM(E2:E4)"})
+	assert.Contains(t, s.Cells, CellChange{Ref: "A5", Field: "value", From: "", To: "Nota"})
+	assert.Empty(t, s.MergesRemoved)
+	assert.Equal(t, []Merge{{Range: "A5:B5", Value: "Nota"}}, s.MergesAdded)
+	assert.Contains(t, s.RowTypes, RowTypeChange{Row: 5, From: "", To: "category-label"})
+}
+
+func TestStyleString(t *testing.T) {
+	assert.Equal(t, "default", Style{}.String())
+	assert.Equal(t, "bg=F2F2F2 bold border=TB", Style{BgColor: "F2F2F2", Bold: true, BorderTop: true, BorderBottom: true}.String())
+}
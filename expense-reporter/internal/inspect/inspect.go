@@ -30,6 +30,27 @@ func DumpWorkbook(workbookPath, outputDir string) error {
 	return dumpWorkbook(wb, workbookPath, outputDir)
 }
 
+// Load opens the workbook and dumps every sheet in memory, in workbook order.
+// Unlike DumpWorkbook, a sheet that fails to dump is an error: a partial dump
+// would show up as spurious differences in Compare.
+func Load(workbookPath string) (*Workbook, error) {
+	wb, err := excelize.OpenFile(workbookPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open workbook: %w", err)
+	}
+	defer wb.Close()
+
+	book := &Workbook{Source: workbookPath}
+	for _, sheetName := range wb.GetSheetList() {
+		dump, err := buildSheetDump(wb, sheetName)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", sheetName, err)
+		}
+		book.Sheets = append(book.Sheets, dump)
+	}
+	return book, nil
+}
+
 func dumpWorkbook(wb *excelize.File, workbookPath, outputDir string) error {
 	sheets := wb.GetSheetList()
 	manifest := Manifest{Source: workbookPath, Sheets: make([]SheetInfo, 0, len(sheets))}
@@ -463,6 +484,11 @@ func hasDataValues(rd RowDump) bool {
 
 // ── JSON schema types ────────────────────────────────────────────────────────
 
+// Workbook is the in-memory counterpart of a DumpWorkbook output directory.
+type Workbook struct {
+	Source string       `json:"source"`
+	Sheets []*SheetDump `json:"sheets"`
+}
 type Manifest struct {
 	Source string      `json:"source"`
 	Sheets []SheetInfo `json:"sheets"`
@@ -0,0 +1,349 @@
+// Package installment keeps a ledger of installment plans (plans.jsonl) so the
+// expanded "item (i/N)" log entries can be traced back to one purchase and the
+// outstanding balance answered without re-deriving it from item names.
+package installment
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/pkg/utils"
+)
+
+// Plan statuses. A cancelled plan stops owing its remaining installments; a
+// paid-off plan settled them early with a single payment on ClosedOn. A
+// reverted plan was written by a run that was undone (runs revert): it never
+// existed, and its log entries are voided.
+const (
+	StatusActive    = "active"
+	StatusCancelled = "cancelled"
+	StatusPaidOff   = "paid_off"
+	StatusReverted  = "reverted"
+)
+
+// Plan is one line in plans.jsonl. The ledger is append-only: a status change
+// appends a new record with the same ID, and the last record for an ID wins.
+// Values are in BRL (converted at insertion for foreign purchases).
+type Plan struct {
+	ID             string   `json:"id"`
+	Item           string   `json:"item"`
+	FirstDate      string   `json:"first_date"` // DD/MM/YYYY of installment 1
+	Count          int      `json:"count"`
+	PerInstallment float64  `json:"per_installment"` // the regular (last) payment
+	Total          float64  `json:"total"`           // sum of all payments, interest included
+	Type           string   `json:"type,omitempty"`
+	Category       string   `json:"category"`
+	Subcategory    string   `json:"subcategory"`
+	Account        string   `json:"account,omitempty"` // card or account paying the installments
+	Tags           []string `json:"tags,omitempty"`    // tags every installment carries
+
+	// Values lists every payment when they differ (down payment, explicit
+	// values); omitted when all Count payments equal PerInstallment.
+	Values []float64 `json:"values,omitempty"`
+	// InterestRate is the monthly rate of an interest-bearing plan; Principal is
+	// then the cash price the interest was charged on.
+	InterestRate float64 `json:"interest_rate,omitempty"`
+	Principal    float64 `json:"principal,omitempty"`
+
+	Status       string  `json:"status"`
+	ClosedOn     string  `json:"closed_on,omitempty"`     // DD/MM/YYYY of cancellation or payoff
+	PayoffAmount float64 `json:"payoff_amount,omitempty"` // amount paid to settle early
+	RunID        string  `json:"run_id,omitempty"`        // journal ID of the run that recorded the plan
+	Timestamp    string  `json:"timestamp"`
+}
+
+// Installment is one scheduled payment of a plan. ID is the ID of its entry in
+// expenses_log.jsonl.
+type Installment struct {
+	Number int
+	Item   string
+	Date   time.Time
+	Value  float64
+	ID     string
+}
+
+// NewPlan builds an active plan from its payment schedule (in BRL). id is the
+// classifications.jsonl ID of the original input line, shared by its expanded
+// entries as plan_id.
+func NewPlan(id, item string, firstDate time.Time, sched utils.InstallmentSchedule, typ, category, subcategory string) Plan {
+	p := Plan{
+		ID:             id,
+		Item:           item,
+		FirstDate:      utils.FormatDate(firstDate),
+		Count:          sched.Count(),
+		PerInstallment: sched.Values[sched.Count()-1],
+		Total:          sched.Total(),
+		Type:           typ,
+		Category:       category,
+		Subcategory:    subcategory,
+		Status:         StatusActive,
+		Timestamp:      feedback.Now().UTC().Format(time.RFC3339),
+	}
+	if !sched.Equal() {
+		p.Values = sched.Values
+	}
+	if sched.InterestRate > 0 {
+		p.InterestRate = sched.InterestRate
+		p.Principal = sched.Principal
+	}
+	return p
+}
+
+// Schedule returns the amount of each payment, in order.
+func (p Plan) Schedule() []float64 {
+	if len(p.Values) > 0 {
+		return p.Values
+	}
+	values := make([]float64, p.Count)
+	for i := range values {
+		values[i] = p.PerInstallment
+	}
+	return values
+}
+
+// Installments returns the plan's full schedule, expanded exactly as
+// appender.AppendSchedule expanded it into the log.
+func (p Plan) Installments() []Installment {
+	first, err := utils.ParseDateFlexible(p.FirstDate)
+	if err != nil {
+		return nil
+	}
+	entries := appender.ExpandEntries(p.Item, first, p.Schedule(), p.Type, p.Category, p.Subcategory)
+	out := make([]Installment, len(entries))
+	for i, e := range entries {
+		date, _ := utils.ParseDateFlexible(e.Date)
+		out[i] = Installment{Number: i + 1, Item: e.Item, Date: date, Value: e.Value, ID: e.ID}
+	}
+	return out
+}
+
+// closedOn returns the plan's cancellation/payoff date; ok is false while active.
+func (p Plan) closedOn() (time.Time, bool) {
+	if p.Status == StatusActive || p.ClosedOn == "" {
+		return time.Time{}, false
+	}
+	t, err := utils.ParseDateFlexible(p.ClosedOn)
+	return t, err == nil
+}
+
+// Remaining returns the installments still owed after asOf: those dated after
+// it, or none once the plan is cancelled or paid off.
+func (p Plan) Remaining(asOf time.Time) []Installment {
+	if p.Status != StatusActive {
+		return nil
+	}
+	var out []Installment
+	for _, inst := range p.Installments() {
+		if inst.Date.After(asOf) {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// Balance sums the value of Remaining(asOf).
+func (p Plan) Balance(asOf time.Time) float64 {
+	var sum float64
+	for _, inst := range p.Remaining(asOf) {
+		sum += inst.Value
+	}
+	return roundCents(sum)
+}
+
+// Voided returns the installments that will never be paid because the plan was
+// closed: those dated after ClosedOn. 'installments cancel' appends a void line
+// for each one's log entry; a plan closed before it did so left its entries
+// live, and only VoidedIDs keeps them out of the workbook.
+func (p Plan) Voided() []Installment {
+	closed, ok := p.closedOn()
+	if !ok {
+		return nil
+	}
+	var out []Installment
+	for _, inst := range p.Installments() {
+		if inst.Date.After(closed) {
+			out = append(out, inst)
+		}
+	}
+	return out
+}
+
+// Carried returns the log entries of p's installments still owed after the
+// end of year whose IDs are missing from logged (the expense log's IDs), linked
+// to the plan, so close-year can carry them into the new year's log. Closed
+// plans carry nothing.
+func (p Plan) Carried(year int, logged map[string]bool) []feedback.ExpenseEntry {
+	yearEnd := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	if len(p.Remaining(yearEnd)) == 0 {
+		return nil
+	}
+	first, err := utils.ParseDateFlexible(p.FirstDate)
+	if err != nil {
+		return nil
+	}
+	var out []feedback.ExpenseEntry
+	for _, e := range appender.ExpandEntries(p.Item, first, p.Schedule(), p.Type, p.Category, p.Subcategory, appender.WithPlan(p.ID), appender.WithAccount(p.Account), appender.WithTags(p.Tags)) {
+		date, err := utils.ParseDateFlexible(e.Date)
+		if err != nil || !date.After(yearEnd) || logged[e.ID] {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Close returns a copy of p closed on date with status StatusCancelled or
+// StatusPaidOff; payoff is the settlement amount and only recorded for a payoff.
+// It errors if the plan is already closed or has nothing left after date.
+func (p Plan) Close(status string, date time.Time, payoff float64) (Plan, error) {
+	if p.Status != StatusActive {
+		return Plan{}, fmt.Errorf("plan %s is already %s", p.ID, p.Status)
+	}
+	if len(p.Remaining(date)) == 0 {
+		return Plan{}, fmt.Errorf("plan %s has no installments after %s", p.ID, utils.FormatDate(date))
+	}
+	p.Status = status
+	p.ClosedOn = utils.FormatDate(date)
+	p.PayoffAmount = 0
+	if status == StatusPaidOff {
+		p.PayoffAmount = roundCents(payoff)
+	}
+	p.Timestamp = feedback.Now().UTC().Format(time.RFC3339)
+	return p, nil
+}
+
+// Revert returns a copy of p marked StatusReverted, for undoing the run that
+// recorded it.
+func (p Plan) Revert() Plan {
+	p.Status = StatusReverted
+	p.Timestamp = feedback.Now().UTC().Format(time.RFC3339)
+	return p
+}
+
+// Commitments sums the remaining installments of plans by month ("YYYY-MM"),
+// answering "how much is already committed for each coming month".
+func Commitments(plans []Plan, asOf time.Time) map[string]float64 {
+	out := map[string]float64{}
+	for _, p := range plans {
+		for _, inst := range p.Remaining(asOf) {
+			key := inst.Date.Format("2006-01")
+			out[key] = roundCents(out[key] + inst.Value)
+		}
+	}
+	return out
+}
+
+// VoidedIDs returns the log entry IDs of every voided installment across plans.
+func VoidedIDs(plans []Plan) map[string]bool {
+	ids := map[string]bool{}
+	for _, p := range plans {
+		for _, inst := range p.Voided() {
+			ids[inst.ID] = true
+		}
+	}
+	return ids
+}
+
+// Append writes plan as a single JSON line to path (creates if absent).
+func Append(path string, plan Plan) error {
+	if _, err := jsonlog.AppendLine(path, plan); err != nil {
+		return fmt.Errorf("appending plan: %w", err)
+	}
+	return nil
+}
+
+// Load reads the ledger at path and returns the latest record of each plan,
+// ordered by first installment date. A missing file yields no plans.
+func Load(path string) ([]Plan, error) {
+	f, err := jsonlog.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening plans file: %w", err)
+	}
+	defer f.Close()
+
+	latest := map[string]Plan{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var p Plan
+		if err := json.Unmarshal(line, &p); err != nil {
+			return nil, fmt.Errorf("parsing plan line: %w", err)
+		}
+		latest[p.ID] = p
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading plans file: %w", err)
+	}
+
+	plans := make([]Plan, 0, len(latest))
+	for _, p := range latest {
+		plans = append(plans, p)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		di, _ := utils.ParseDateFlexible(plans[i].FirstDate)
+		dj, _ := utils.ParseDateFlexible(plans[j].FirstDate)
+		if !di.Equal(dj) {
+			return di.Before(dj)
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	return plans, nil
+}
+
+// RenameIDs rewrites the ledger at path, giving each plan whose ID is a key of
+// ids the mapped ID (see feedback.MigrateIDs). It returns how many lines changed.
+func RenameIDs(path string, ids map[string]string) (int, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	return jsonlog.Rewrite(path, func(line []byte) ([]byte, error) {
+		var p Plan
+		if err := json.Unmarshal(line, &p); err != nil {
+			return nil, fmt.Errorf("parsing plan line: %w", err)
+		}
+		next, ok := ids[p.ID]
+		if !ok {
+			return line, nil
+		}
+		p.ID = next
+		return json.Marshal(p)
+	})
+}
+
+// Find returns the plan whose ID starts with prefix. It errors when no plan or
+// more than one plan matches.
+func Find(plans []Plan, prefix string) (Plan, error) {
+	var matches []Plan
+	for _, p := range plans {
+		if strings.HasPrefix(p.ID, prefix) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Plan{}, fmt.Errorf("no installment plan with ID %q", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return Plan{}, fmt.Errorf("ID prefix %q matches %d plans — use more characters", prefix, len(matches))
+	}
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
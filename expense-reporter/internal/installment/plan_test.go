@@ -0,0 +1,151 @@
+package installment
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/appender"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func schedule(value string) utils.InstallmentSchedule {
+	sched, err := utils.ParseInstallments(value)
+	if err != nil {
+		panic(err)
+	}
+	return sched
+}
+
+// notebook is a 10× R$ 300,00 purchase whose installments run Nov/2026–Aug/2027.
+func notebook() Plan {
+	return NewPlan("abc123def456", "Notebook", day(2026, 11, 30), schedule("3000,00/10"), "Extras", "Eletrônicos", "Computador")
+}
+
+func TestInstallments_MatchLogExpansion(t *testing.T) {
+	p := notebook()
+	entries := appender.ExpandEntries(p.Item, day(2026, 11, 30), p.Schedule(), p.Type, p.Category, p.Subcategory)
+	insts := p.Installments()
+
+	require.Len(t, insts, 10)
+	for i, inst := range insts {
+		assert.Equal(t, entries[i].ID, inst.ID)
+		assert.Equal(t, i+1, inst.Number)
+	}
+	assert.Equal(t, day(2027, 2, 28), insts[3].Date, "day clamped like the log expansion")
+	assert.Equal(t, 3000.0, p.Total)
+}
+
+func TestBalanceAndCommitments(t *testing.T) {
+	p := notebook()
+	asOf := day(2027, 1, 15) // installments 1–2 paid
+
+	assert.Len(t, p.Remaining(asOf), 8)
+	assert.Equal(t, 2400.0, p.Balance(asOf))
+
+	c := Commitments([]Plan{p, p}, asOf)
+	assert.Equal(t, 600.0, c["2027-02"])
+	assert.Len(t, c, 8)
+}
+
+func TestClose(t *testing.T) {
+	p := notebook()
+
+	t.Run("payoff voids later installments", func(t *testing.T) {
+		closed, err := p.Close(StatusPaidOff, day(2027, 1, 15), 2000)
+		require.NoError(t, err)
+		assert.Equal(t, StatusPaidOff, closed.Status)
+		assert.Equal(t, "15/01/2027", closed.ClosedOn)
+		assert.Equal(t, 2000.0, closed.PayoffAmount)
+		assert.Zero(t, closed.Balance(day(2027, 1, 15)))
+		assert.Len(t, closed.Voided(), 8)
+		assert.Len(t, VoidedIDs([]Plan{closed, p}), 8, "active plans void nothing")
+	})
+
+	t.Run("cancel records no payoff", func(t *testing.T) {
+		closed, err := p.Close(StatusCancelled, day(2027, 1, 15), 2000)
+		require.NoError(t, err)
+		assert.Zero(t, closed.PayoffAmount)
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		closed, err := p.Close(StatusCancelled, day(2027, 1, 15), 0)
+		require.NoError(t, err)
+		_, err = closed.Close(StatusPaidOff, day(2027, 2, 15), 0)
+		assert.ErrorContains(t, err, "already cancelled")
+	})
+
+	t.Run("nothing left", func(t *testing.T) {
+		_, err := p.Close(StatusCancelled, day(2027, 9, 1), 0)
+		assert.ErrorContains(t, err, "no installments after")
+	})
+}
+
+func TestCarried(t *testing.T) {
+	p := notebook()
+	insts := p.Installments()
+	logged := map[string]bool{insts[2].ID: true} // Jan/2027 already logged
+
+	carried := p.Carried(2026, logged)
+	require.Len(t, carried, 7, "Feb–Aug/2027; 2026 months and logged ones are not carried")
+	assert.Equal(t, "Notebook (4/10)", carried[0].Item)
+	assert.Equal(t, insts[3].ID, carried[0].ID)
+	assert.Equal(t, p.ID, carried[0].PlanID)
+
+	closed, err := p.Close(StatusCancelled, day(2026, 12, 15), 0)
+	require.NoError(t, err)
+	assert.Empty(t, closed.Carried(2026, nil))
+	assert.Empty(t, p.Carried(2027, nil), "nothing owed after 2027")
+}
+
+func TestLoad_LatestRecordWins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plans.jsonl")
+
+	plans, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, plans)
+
+	p := notebook()
+	other := NewPlan(feedback.GenerateID("Geladeira", "05/03/2026", 250), "Geladeira", day(2026, 3, 5), schedule("3000,00/12"), "Extras", "Casa", "Eletrodomésticos")
+	closed, err := p.Close(StatusCancelled, day(2027, 1, 15), 0)
+	require.NoError(t, err)
+	require.NoError(t, Append(path, p))
+	require.NoError(t, Append(path, other))
+	require.NoError(t, Append(path, closed))
+
+	plans, err = Load(path)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	assert.Equal(t, "Geladeira", plans[0].Item, "ordered by first installment date")
+	assert.Equal(t, StatusCancelled, plans[1].Status)
+
+	found, err := Find(plans, "abc1")
+	require.NoError(t, err)
+	assert.Equal(t, "Notebook", found.Item)
+	_, err = Find(plans, "zzz")
+	assert.Error(t, err)
+}
+
+func TestNewPlan_UnevenAndInterest(t *testing.T) {
+	down := NewPlan("d0", "Sofá", day(2026, 5, 10), schedule("500,00+4x250,00"), "Extras", "Casa", "Móveis")
+	assert.Equal(t, []float64{500, 250, 250, 250, 250}, down.Values)
+	assert.Equal(t, 1500.0, down.Total)
+	assert.Equal(t, 250.0, down.PerInstallment)
+	assert.Equal(t, 500.0, down.Balance(day(2026, 7, 20)))
+	assert.Equal(t, 500.0, down.Installments()[0].Value)
+
+	financed := NewPlan("f0", "TV", day(2026, 5, 10), schedule("1000,00/10@1,99%"), "Extras", "Casa", "Eletrônicos")
+	assert.Nil(t, financed.Values, "equal payments are not listed")
+	assert.Equal(t, 111.27, financed.PerInstallment)
+	assert.Equal(t, 1112.7, financed.Total)
+	assert.Equal(t, 0.0199, financed.InterestRate)
+	assert.Equal(t, 1000.0, financed.Principal)
+}
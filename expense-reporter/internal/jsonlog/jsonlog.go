@@ -0,0 +1,306 @@
+// Package jsonlog is the file layer under every append-only JSONL log
+// (classifications.jsonl, expenses_log.jsonl, plans.jsonl, …). It serializes
+// writers across processes with an advisory lock, fsyncs each appended line,
+// and repairs a torn final line left by a crash so readers never fail on it.
+package jsonlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"expense-reporter/internal/logger"
+)
+
+// LockSuffix names the lock file next to a log: expenses_log.jsonl.lock.
+// The log itself is never locked, so a log rewritten by rename stays protected.
+const LockSuffix = ".lock"
+
+// QuarantineSuffix names the file torn lines are moved to: expenses_log.jsonl.torn.
+const QuarantineSuffix = ".torn"
+
+// Lock takes the exclusive advisory lock for the log at path, waiting for any
+// other holder, and returns the function that releases it.
+func Lock(path string) (unlock func(), err error) {
+	f, err := os.OpenFile(path+LockSuffix, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock for %s: %w", path, err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// AppendLine marshals v and appends it to path as one newline-terminated line
+// (creating the file if absent), under the log's lock and fsynced before
+// returning. It returns the offset the line starts at. A torn final line left
+// by an earlier crash is repaired first, so the new line never fuses with it.
+func AppendLine(path string, v any) (int64, error) {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling record: %w", err)
+	}
+	unlock, err := Lock(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if _, err := repair(path); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("opening %s: %w", path, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return 0, fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Sync(); err != nil {
+		return 0, fmt.Errorf("syncing %s: %w", path, err)
+	}
+	return info.Size(), nil
+}
+
+// Open repairs the log at path (see Repair) and opens it for reading. Like
+// os.Open, a missing file returns an error satisfying os.IsNotExist.
+func Open(path string) (*os.File, error) {
+	if _, err := Repair(path); err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Repair checks the end of the log at path under its lock. A final line
+// without a newline is either completed (it is valid JSON, e.g. a hand edit)
+// or, when it is a torn write, moved to path + QuarantineSuffix and cut from
+// the log. It reports whether a line was quarantined; a missing log is fine.
+func Repair(path string) (quarantined bool, err error) {
+	if !needsRepair(path) {
+		return false, nil
+	}
+	unlock, err := Lock(path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return repair(path)
+}
+
+// needsRepair is the lock-free fast path: true when the log exists, is not
+// empty and does not end in a newline.
+func needsRepair(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false
+	}
+	return last[0] != '\n'
+}
+
+// repair does the work of Repair; the caller holds the lock.
+func repair(path string) (bool, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	tail, start, err := trailingLine(f)
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if tail == nil {
+		return false, nil
+	}
+
+	trimmed := bytes.TrimSpace(tail)
+	if len(trimmed) == 0 || json.Valid(trimmed) {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return false, fmt.Errorf("repairing %s: %w", path, err)
+		}
+		if _, err := f.Write([]byte{'\n'}); err != nil {
+			return false, fmt.Errorf("repairing %s: %w", path, err)
+		}
+		return false, f.Sync()
+	}
+
+	if err := quarantine(path, tail); err != nil {
+		return false, err
+	}
+	if err := f.Truncate(start); err != nil {
+		return false, fmt.Errorf("truncating %s: %w", path, err)
+	}
+	if err := f.Sync(); err != nil {
+		return false, fmt.Errorf("syncing %s: %w", path, err)
+	}
+	logger.Warn("jsonlog: quarantined torn final line", "log", path, "moved_to", path+QuarantineSuffix)
+	return true, nil
+}
+
+// trailingLine returns the bytes after the log's last newline and where they
+// start, or nil when the log ends in a newline (or is empty).
+func trailingLine(f *os.File) ([]byte, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return nil, 0, nil
+	}
+	const chunk = 64 * 1024
+	end := size
+	for end > 0 {
+		start := max(end-chunk, 0)
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil {
+			return nil, 0, err
+		}
+		if end == size && buf[len(buf)-1] == '\n' {
+			return nil, 0, nil
+		}
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			lineStart := start + int64(i) + 1
+			tail := make([]byte, size-lineStart)
+			_, err := f.ReadAt(tail, lineStart)
+			return tail, lineStart, err
+		}
+		end = start
+	}
+	tail := make([]byte, size)
+	_, err = f.ReadAt(tail, 0)
+	return tail, 0, err
+}
+
+// quarantine appends a torn line, stamped with when it was found, to the
+// log's quarantine file.
+func quarantine(path string, tail []byte) error {
+	q, err := os.OpenFile(path+QuarantineSuffix, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening quarantine for %s: %w", path, err)
+	}
+	defer q.Close()
+	stamp := fmt.Sprintf("# %s\n", time.Now().UTC().Format(time.RFC3339))
+	if _, err := q.Write(append([]byte(stamp), append(tail, '\n')...)); err != nil {
+		return fmt.Errorf("writing quarantine for %s: %w", path, err)
+	}
+	return q.Sync()
+}
+
+// Rewrite replaces every non-blank line of the log at path with fn(line),
+// under the log's lock so no append is lost in between. The new content is
+// written to a temporary file, fsynced and renamed over the log. It returns
+// how many lines fn changed; when none did, the log is left untouched.
+func Rewrite(path string, fn func(line []byte) ([]byte, error)) (int, error) {
+	unlock, err := Lock(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if _, err := repair(path); err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var out bytes.Buffer
+	changed := 0
+	for n, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		next, err := fn(line)
+		if err != nil {
+			return 0, fmt.Errorf("%s line %d: %w", path, n+1, err)
+		}
+		if !bytes.Equal(next, line) {
+			changed++
+		}
+		out.Write(next)
+		out.WriteByte('\n')
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	if err := replaceFile(path, out.Bytes()); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// replaceFile atomically replaces path with data: written to a temporary file
+// in the same directory, fsynced and renamed over it. An existing file keeps
+// its permissions. The caller holds the log's lock.
+func replaceFile(path string, data []byte) error {
+	perm := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("rewriting %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("rewriting %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("rewriting %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("rewriting %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename in it survives a crash. Best effort:
+// not every platform can open a directory for syncing.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
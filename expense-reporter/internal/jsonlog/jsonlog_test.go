@@ -0,0 +1,202 @@
+package jsonlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rec struct {
+	ID string `json:"id"`
+	N  int    `json:"n"`
+}
+
+func readLines(t *testing.T, path string) []rec {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var out []rec
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r rec
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r), "line %q", scanner.Text())
+		out = append(out, r)
+	}
+	return out
+}
+
+func TestAppendLine_ReturnsOffsets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+
+	off, err := AppendLine(path, rec{ID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), off)
+
+	off, err = AppendLine(path, rec{ID: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(`{"id":"a","n":0}`)+1), off)
+
+	assert.Equal(t, []rec{{ID: "a"}, {ID: "b"}}, readLines(t, path))
+}
+
+func TestAppendLine_ConcurrentWritersDoNotInterleave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	const writers, each = 8, 25
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range each {
+				_, err := AppendLine(path, rec{ID: strings.Repeat("x", 200), N: w*each + i})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := readLines(t, path)
+	require.Len(t, lines, writers*each)
+	seen := map[int]bool{}
+	for _, r := range lines {
+		seen[r.N] = true
+	}
+	assert.Len(t, seen, writers*each)
+}
+
+func TestRepair_QuarantinesTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"a","n":1}`+"\n"+`{"id":"b","n`), 0o644))
+
+	quarantined, err := Repair(path)
+	require.NoError(t, err)
+	assert.True(t, quarantined)
+	assert.Equal(t, []rec{{ID: "a", N: 1}}, readLines(t, path))
+
+	torn, err := os.ReadFile(path + QuarantineSuffix)
+	require.NoError(t, err)
+	assert.Contains(t, string(torn), `{"id":"b","n`)
+
+	// The next append starts on a clean line.
+	_, err = AppendLine(path, rec{ID: "c"})
+	require.NoError(t, err)
+	assert.Equal(t, []rec{{ID: "a", N: 1}, {ID: "c"}}, readLines(t, path))
+}
+
+func TestRepair_CompletesValidFinalLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"a","n":1}`), 0o644))
+
+	quarantined, err := Repair(path)
+	require.NoError(t, err)
+	assert.False(t, quarantined)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"a","n":1}`+"\n", string(data))
+	assert.NoFileExists(t, path+QuarantineSuffix)
+}
+
+func TestRepair_TornOnlyLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":`), 0o644))
+
+	quarantined, err := Repair(path)
+	require.NoError(t, err)
+	assert.True(t, quarantined)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRewrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":"a","n":1}`+"\n\n"+`{"id":"b","n":2}`+"\n"), 0o644))
+
+	changed, err := Rewrite(path, func(line []byte) ([]byte, error) { return line, nil })
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\n\n", "an unchanged log is not rewritten")
+
+	changed, err = Rewrite(path, func(line []byte) ([]byte, error) {
+		var r rec
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, err
+		}
+		if r.ID == "b" {
+			r.N = 20
+		}
+		return json.Marshal(r)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []rec{{ID: "a", N: 1}, {ID: "b", N: 20}}, readLines(t, path))
+}
+
+func TestArchive_MovesLinesToPartition(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.jsonl")
+	for _, r := range []rec{{ID: "a", N: 2025}, {ID: "b", N: 2026}, {ID: "c", N: 2025}} {
+		_, err := AppendLine(path, r)
+		require.NoError(t, err)
+	}
+	in2025 := func(line []byte) (bool, error) {
+		var r rec
+		err := json.Unmarshal(line, &r)
+		return r.N == 2025, err
+	}
+
+	n, err := Archive(path, 2025, in2025)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, filepath.Join(dir, "log.2025.jsonl"), PartitionPath(path, 2025))
+	assert.Equal(t, []rec{{ID: "a", N: 2025}, {ID: "c", N: 2025}}, readLines(t, PartitionPath(path, 2025)))
+	assert.Equal(t, []rec{{ID: "b", N: 2026}}, readLines(t, path))
+
+	n, err = Archive(path, 2025, in2025)
+	require.NoError(t, err)
+	assert.Zero(t, n, "nothing left to move")
+
+	years, err := Partitions(path)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2025}, years)
+
+	for _, tt := range []struct {
+		from, to int
+		want     []string
+	}{
+		{0, 0, []string{"a", "c", "b"}},
+		{2026, 0, []string{"b"}},
+		{2024, 2025, []string{"a", "c", "b"}},
+	} {
+		f, err := OpenYears(path, tt.from, tt.to)
+		require.NoError(t, err)
+		var ids []string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var r rec
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+			ids = append(ids, r.ID)
+		}
+		f.Close()
+		assert.Equal(t, tt.want, ids, "years %d..%d", tt.from, tt.to)
+	}
+
+	_, err = OpenYears(filepath.Join(dir, "missing.jsonl"), 0, 0)
+	assert.True(t, os.IsNotExist(err))
+}
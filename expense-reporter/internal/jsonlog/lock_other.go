@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package jsonlog
+
+import "os"
+
+// Platforms without file locking rely on a single writer.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) {}
@@ -0,0 +1,176 @@
+package jsonlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PartitionPath names the archive holding year's lines of the log at path,
+// written by close-year: expenses_log.jsonl → expenses_log.2025.jsonl.
+func PartitionPath(path string, year int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s.%04d%s", strings.TrimSuffix(path, ext), year, ext)
+}
+
+// Partitions returns the years archived next to the log at path, ascending.
+func Partitions(path string) ([]int, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	matches, err := filepath.Glob(escapeGlob(base) + ".[0-9][0-9][0-9][0-9]" + escapeGlob(ext))
+	if err != nil {
+		return nil, fmt.Errorf("listing partitions of %s: %w", path, err)
+	}
+	years := make([]int, 0, len(matches))
+	for _, m := range matches {
+		year, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(m, base+"."), ext))
+		if err != nil {
+			continue
+		}
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years, nil
+}
+
+// Files returns the files holding the lines of the log at path dated from..to
+// (inclusive years; 0 leaves that end unbounded): the matching archives in year
+// order, then the live log, which always takes part since lines for a closed
+// year may still be appended to it. The live log is listed even when missing.
+func Files(path string, from, to int) ([]string, error) {
+	years, err := Partitions(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, year := range years {
+		if (from == 0 || year >= from) && (to == 0 || year <= to) {
+			files = append(files, PartitionPath(path, year))
+		}
+	}
+	return append(files, path), nil
+}
+
+// OpenYears is Open across partitions: it repairs and opens every file Files
+// lists and reads them as one log, oldest first. Like Open, it returns an error
+// satisfying os.IsNotExist only when none of the files exists.
+func OpenYears(path string, from, to int) (io.ReadCloser, error) {
+	paths, err := Files(path, from, to)
+	if err != nil {
+		return nil, err
+	}
+	m := &multiFile{}
+	for _, p := range paths {
+		f, err := Open(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			m.Close()
+			return nil, err
+		}
+		m.files = append(m.files, f)
+	}
+	if len(m.files) == 0 {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	readers := make([]io.Reader, len(m.files))
+	for i, f := range m.files {
+		readers[i] = f
+	}
+	m.Reader = io.MultiReader(readers...)
+	return m, nil
+}
+
+// multiFile reads its files back to back. Each ends in a newline (Open
+// repairs it), so lines never fuse across files.
+type multiFile struct {
+	io.Reader
+	files []*os.File
+}
+
+func (m *multiFile) Close() error {
+	var first error
+	for _, f := range m.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Archive moves the lines of the log at path for which move returns true to
+// the end of its partition for year (see PartitionPath), under both logs'
+// locks. The partition is written first, so a crash in between leaves the
+// lines in both files rather than in neither. It returns how many lines moved;
+// with none, neither file is touched. A missing log moves nothing.
+func Archive(path string, year int, move func(line []byte) (bool, error)) (int, error) {
+	unlock, err := Lock(path)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if _, err := repair(path); err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var kept, moved bytes.Buffer
+	n := 0
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		ok, err := move(line)
+		if err != nil {
+			return 0, fmt.Errorf("%s line %d: %w", path, i+1, err)
+		}
+		dst := &kept
+		if ok {
+			dst, n = &moved, n+1
+		}
+		dst.Write(line)
+		dst.WriteByte('\n')
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	archive := PartitionPath(path, year)
+	unlockArchive, err := Lock(archive)
+	if err != nil {
+		return 0, err
+	}
+	defer unlockArchive()
+	if _, err := repair(archive); err != nil {
+		return 0, err
+	}
+	existing, err := os.ReadFile(archive)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("reading %s: %w", archive, err)
+	}
+	if err := replaceFile(archive, append(existing, moved.Bytes()...)); err != nil {
+		return 0, err
+	}
+	if err := replaceFile(path, kept.Bytes()); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// escapeGlob quotes the glob metacharacters in a literal path.
+func escapeGlob(s string) string {
+	r := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`)
+	return r.Replace(s)
+}
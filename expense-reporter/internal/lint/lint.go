@@ -0,0 +1,657 @@
+// Package lint checks a workbook for the drift manual edits leave behind: total
+// formulas whose SUM range no longer covers their block, summary pulls aimed
+// at the wrong row, and subcategories that the sheets, the reference sheet and
+// taxonomy.json disagree about. It reads the workbook through inspect's
+// structural dump and row classification.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/inspect"
+	"expense-reporter/internal/resolver"
+	"expense-reporter/internal/taxonomy"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Finding severities. An error is a formula or layout that gives wrong
+// totals now; a warning is drift that will once data reaches it, or that
+// another command will trip over.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Checks, one per kind of finding.
+const (
+	CheckTotalRange = "total-range" // a total row's SUM over its block
+	CheckPull       = "pull"        // a summary-sheet pull of a total row
+	CheckReference  = "reference"   // the reference sheet against the sheets
+	CheckTaxonomy   = "taxonomy"    // taxonomy.json against the sheets
+)
+
+// Finding is one problem, located at a cell (or at a sheet when Cell is empty).
+type Finding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Sheet    string `json:"sheet"`
+	Cell     string `json:"cell,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Location is Sheet!Cell, or the sheet alone.
+func (f Finding) Location() string {
+	if f.Cell == "" {
+		return f.Sheet
+	}
+	return f.Sheet + "!" + f.Cell
+}
+
+// Input is what Lint checks. Reference and Taxonomy are optional: the checks
+// against them are skipped when nil.
+type Input struct {
+	Book      *inspect.Workbook
+	Summary   string // the summary sheet holding the pulls (Listas de itens)
+	Reference map[string][]resolver.SubcategoryMapping
+	Taxonomy  []taxonomy.ExpenseType
+}
+
+// Report is the outcome of Lint.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings of severity.
+func (r Report) Count(severity string) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Lint runs every check. The block sheets — those whose total rows are
+// checked — are the sheets the summary pulls from, the reference sheet names
+// and the taxonomy declares, whichever the workbook has.
+func Lint(in Input) Report {
+	l := &linter{in: in, dumps: in.Book.Sheets, sheets: map[string]*sheetIndex{}}
+	summary := l.dump(in.Summary)
+	var pulls []pull
+	if summary != nil {
+		pulls = collectPulls(summary)
+	}
+	l.indexBlockSheets(pulls)
+
+	for _, name := range l.order {
+		l.checkTotals(l.sheets[name])
+	}
+	if summary != nil {
+		l.checkPulls(summary, pulls)
+	}
+	if in.Reference != nil {
+		l.checkReference()
+	}
+	if in.Taxonomy != nil {
+		l.checkTaxonomy()
+	}
+	return Report{Findings: l.findings}
+}
+
+type linter struct {
+	in       Input
+	dumps    []*inspect.SheetDump
+	sheets   map[string]*sheetIndex // block sheets by name
+	order    []string               // block sheets in workbook order
+	findings []Finding
+}
+
+func (l *linter) add(severity, check, sheet, cell, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		Severity: severity, Check: check, Sheet: sheet, Cell: cell, Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) dump(name string) *inspect.SheetDump {
+	for _, s := range l.dumps {
+		if s.Sheet == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (l *linter) indexBlockSheets(pulls []pull) {
+	wanted := map[string]bool{}
+	for _, p := range pulls {
+		wanted[p.Sheet] = true
+	}
+	for _, list := range l.in.Reference {
+		for _, m := range list {
+			wanted[m.SheetName] = true
+		}
+	}
+	for _, t := range l.in.Taxonomy {
+		wanted[t.Name] = true
+	}
+	for _, s := range l.dumps {
+		if wanted[s.Sheet] && s.Sheet != l.in.Summary && s.Sheet != excel.ReferenceSheet {
+			l.sheets[s.Sheet] = newSheetIndex(s)
+			l.order = append(l.order, s.Sheet)
+		}
+	}
+}
+
+// ── Sheet index ──────────────────────────────────────────────────────────────
+
+// block is one subcategory section of a block sheet: its data rows First..
+// Total-1 and its total row. Subcat is the column-B label over the section.
+type block struct {
+	Subcat string
+	First  int
+	Total  int
+}
+
+type sheetIndex struct {
+	name    string
+	cells   map[string]inspect.Cell
+	rows    map[int][]inspect.Cell // cells per row, in column order
+	types   map[int]string
+	colB    map[int]string // column-B text per row, merged labels spread over their range
+	blocks  []block
+	byTotal map[int]block
+}
+
+func newSheetIndex(s *inspect.SheetDump) *sheetIndex {
+	ix := &sheetIndex{
+		name:    s.Sheet,
+		cells:   map[string]inspect.Cell{},
+		rows:    map[int][]inspect.Cell{},
+		types:   map[int]string{},
+		colB:    map[int]string{},
+		byTotal: map[int]block{},
+	}
+	for _, rd := range s.Rows {
+		ix.types[rd.Row] = rd.RowType
+		ix.rows[rd.Row] = rd.Cells
+		for _, c := range rd.Cells {
+			ix.cells[c.Col+strconv.Itoa(rd.Row)] = c
+			if c.Col == "B" && c.Value != "" {
+				ix.colB[rd.Row] = c.Value
+			}
+		}
+	}
+	mergeStart := map[int]int{} // row -> first row of the column-B merge covering it
+	for _, m := range s.MergedCells {
+		c1, r1, c2, r2, ok := parseRange(m.Range)
+		if !ok || c1 != "B" || c2 != "B" {
+			continue
+		}
+		for r := r1; r <= r2; r++ {
+			mergeStart[r] = r1
+			if m.Value != "" {
+				ix.colB[r] = m.Value
+			}
+		}
+	}
+
+	// A section runs from the first row after the previous boundary (header,
+	// separator or total row) that is not a category label, down to its total
+	// row; a column-B merge reaching the total row marks its start outright.
+	boundary, first, label := 0, 0, ""
+	for _, rd := range s.Rows {
+		switch rd.RowType {
+		case "total-row":
+			b := block{Subcat: label, First: first, Total: rd.Row}
+			if start, ok := mergeStart[rd.Row]; ok && start > boundary {
+				b.First = start
+			}
+			if b.First == 0 {
+				b.First = rd.Row // no data rows at all
+			}
+			if b.Subcat == "" {
+				b.Subcat = ix.colB[rd.Row]
+			}
+			ix.blocks = append(ix.blocks, b)
+			ix.byTotal[rd.Row] = b
+			boundary, first, label = rd.Row, 0, ""
+		case "header-month", "header-col", "separator":
+			boundary, first, label = rd.Row, 0, ""
+		case "category-label":
+		default:
+			if first == 0 {
+				first = rd.Row
+			}
+			if label == "" {
+				label = ix.colB[rd.Row]
+			}
+		}
+	}
+	return ix
+}
+
+// blocksOf returns the sections labelled sub.
+func (ix *sheetIndex) blocksOf(sub string) []block {
+	var out []block
+	for _, b := range ix.blocks {
+		if sameName(b.Subcat, sub) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// filled returns the rows from..to holding a value or formula in col.
+func (ix *sheetIndex) filled(col string, from, to int) []int {
+	var rows []int
+	for r := from; r <= to; r++ {
+		if c, ok := ix.cells[col+strconv.Itoa(r)]; ok && (c.Value != "" || c.Formula != "") {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// ── Total ranges ─────────────────────────────────────────────────────────────
+
+var sumRangeRe = regexp.MustCompile(`(?i)^SUM\(\$?([A-Z]+)\$?(\d+):\$?([A-Z]+)\$?(\d+)\)$`)
+
+// checkTotals checks every single-range SUM on a total row against the block
+// above it, and flags totals typed over in a column other total rows sum.
+func (l *linter) checkTotals(ix *sheetIndex) {
+	sumCols := map[string]bool{}
+	for _, b := range ix.blocks {
+		for _, c := range ix.rows[b.Total] {
+			if sumRangeRe.MatchString(c.Formula) {
+				sumCols[c.Col] = true
+			}
+		}
+	}
+
+	for _, b := range ix.blocks {
+		for _, c := range ix.rows[b.Total] {
+			ref := c.Col + strconv.Itoa(b.Total)
+			if c.Formula == "" {
+				if sumCols[c.Col] && c.Value != "" {
+					l.add(SeverityWarning, CheckTotalRange, ix.name, ref,
+						"%s total is typed in (%s) instead of summed", orUnnamed(b.Subcat), c.Value)
+				}
+				continue
+			}
+			m := sumRangeRe.FindStringSubmatch(c.Formula)
+			if m == nil {
+				continue
+			}
+			l.checkSum(ix, b, c.Col, strings.ToUpper(m[1]), atoi(m[2]), strings.ToUpper(m[3]), atoi(m[4]))
+		}
+	}
+}
+
+func (l *linter) checkSum(ix *sheetIndex, b block, col, fromCol string, from int, toCol string, to int) {
+	ref := col + strconv.Itoa(b.Total)
+	formula := "=" + ix.cells[ref].Formula
+	name := orUnnamed(b.Subcat)
+	if fromCol != col || toCol != col {
+		l.add(SeverityError, CheckTotalRange, ix.name, ref, "%s total %s sums column %s, not %s", name, formula, fromCol, col)
+		return
+	}
+	if to >= b.Total {
+		l.add(SeverityError, CheckTotalRange, ix.name, ref, "%s total %s reaches its own total row %d", name, formula, b.Total)
+	} else if to < b.Total-1 {
+		l.reportUncovered(ix, b, ref, formula, "stops short", to+1, b.Total-1)
+	}
+	if from < b.First {
+		l.add(SeverityError, CheckTotalRange, ix.name, ref, "%s total %s starts above its block (row %d)", name, formula, b.First)
+	} else if from > b.First {
+		l.reportUncovered(ix, b, ref, formula, "starts late", b.First, from-1)
+	}
+}
+
+// reportUncovered reports block rows from..to left out of a total: an error
+// when they already hold amounts, a warning while they are empty.
+func (l *linter) reportUncovered(ix *sheetIndex, b block, ref, formula, what string, from, to int) {
+	col, _, _ := splitRef(ref)
+	rows := rowSpan(from, to)
+	if filled := ix.filled(col, from, to); len(filled) > 0 {
+		l.add(SeverityError, CheckTotalRange, ix.name, ref, "%s total %s %s: %s left out, %d of them filled",
+			orUnnamed(b.Subcat), formula, what, rows, len(filled))
+		return
+	}
+	l.add(SeverityWarning, CheckTotalRange, ix.name, ref, "%s total %s %s: %s left out (empty for now)",
+		orUnnamed(b.Subcat), formula, what, rows)
+}
+
+// ── Summary pulls ────────────────────────────────────────────────────────────
+
+var pullRe = regexp.MustCompile(`^(?:'([^']+)'|([^!'()+\-*/,:]+))!\$?([A-Z]+)\$?(\d+)$`)
+
+// pull is a summary cell that is nothing but a reference to one cell of
+// another sheet.
+type pull struct {
+	Ref    string // the summary cell
+	Row    int
+	Col    string
+	Label  string // the summary row's column-C label
+	Sheet  string // the pulled sheet
+	Target string // the pulled cell
+	TCol   string
+	TRow   int
+}
+
+func collectPulls(s *inspect.SheetDump) []pull {
+	var pulls []pull
+	for _, rd := range s.Rows {
+		label := ""
+		for _, c := range rd.Cells {
+			if c.Col == "C" {
+				label = c.Value
+			}
+		}
+		for _, c := range rd.Cells {
+			m := pullRe.FindStringSubmatch(c.Formula)
+			if m == nil {
+				continue
+			}
+			sheet := m[1]
+			if sheet == "" {
+				sheet = m[2]
+			}
+			pulls = append(pulls, pull{
+				Ref: c.Col + strconv.Itoa(rd.Row), Row: rd.Row, Col: c.Col, Label: label,
+				Sheet: sheet, Target: m[3] + m[4], TCol: m[3], TRow: atoi(m[4]),
+			})
+		}
+	}
+	return pulls
+}
+
+// checkPulls checks that each pull lands on a total row, in the month of the
+// summary column it sits in, of the subcategory its row is labelled with; and
+// that every block's total is pulled somewhere.
+func (l *linter) checkPulls(summary *inspect.SheetDump, pulls []pull) {
+	pulled := map[string]map[int]bool{}
+	labelled := map[string]bool{} // sheet\x00row already checked for its label
+	for _, p := range pulls {
+		ix := l.sheets[p.Sheet]
+		if ix == nil {
+			if l.dump(p.Sheet) == nil {
+				l.add(SeverityError, CheckPull, summary.Sheet, p.Ref, "pulls %s!%s, but there is no sheet %s", p.Sheet, p.Target, p.Sheet)
+			}
+			continue
+		}
+		if pulled[p.Sheet] == nil {
+			pulled[p.Sheet] = map[int]bool{}
+		}
+		pulled[p.Sheet][p.TRow] = true
+
+		b, ok := ix.byTotal[p.TRow]
+		if !ok {
+			l.add(SeverityError, CheckPull, summary.Sheet, p.Ref, "pulls %s!%s, which is a %s, not a total row%s",
+				p.Sheet, p.Target, rowKind(ix.types[p.TRow]), nearestTotal(ix, p.TRow))
+			continue
+		}
+		if k, inMonths := summaryMonth(p.Col); inMonths {
+			if want := valorCol(k); p.TCol != want && isValorCol(p.TCol) {
+				l.add(SeverityError, CheckPull, summary.Sheet, p.Ref, "pulls %s!%s, another month's total (expected column %s)",
+					p.Sheet, p.Target, want)
+			}
+		}
+		key := fmt.Sprintf("%s\x00%d", p.Sheet, p.Row)
+		if p.Label != "" && b.Subcat != "" && !sameName(p.Label, b.Subcat) && !labelled[key] {
+			labelled[key] = true
+			l.add(SeverityError, CheckPull, summary.Sheet, p.Ref, "row labelled %s pulls the total of %s (%s!%s)",
+				p.Label, b.Subcat, p.Sheet, p.Target)
+		}
+	}
+
+	for _, name := range l.order {
+		if pulled[name] == nil {
+			continue
+		}
+		ix := l.sheets[name]
+		for _, b := range ix.blocks {
+			if !pulled[name][b.Total] {
+				l.add(SeverityWarning, CheckPull, name, "B"+strconv.Itoa(b.First), "%s (total row %d) is not pulled by %s",
+					orUnnamed(b.Subcat), b.Total, summary.Sheet)
+			}
+		}
+	}
+}
+
+func nearestTotal(ix *sheetIndex, row int) string {
+	for _, b := range ix.blocks {
+		if row >= b.First && row < b.Total {
+			return fmt.Sprintf(" (%s totals on row %d)", orUnnamed(b.Subcat), b.Total)
+		}
+	}
+	return ""
+}
+
+func rowKind(t string) string {
+	if t == "" {
+		return "blank row"
+	}
+	return t
+}
+
+// summaryMonth returns the month index of a summary column (D = January …
+// O = December).
+func summaryMonth(col string) (int, bool) {
+	n, err := excelize.ColumnNameToNumber(col)
+	if err != nil || n < 4 || n > 15 {
+		return 0, false
+	}
+	return n - 4, true
+}
+
+// valorCol returns the Valor column of month k on a block sheet (E, H, … AL).
+func valorCol(k int) string {
+	name, _ := excelize.ColumnNumberToName(5 + 3*k)
+	return name
+}
+
+func isValorCol(col string) bool {
+	n, err := excelize.ColumnNameToNumber(col)
+	return err == nil && n >= 5 && n <= 38 && (n-5)%3 == 0
+}
+
+// ── Reference sheet ──────────────────────────────────────────────────────────
+
+// checkReference checks each reference-sheet row against its sheet — the sheet
+// exists, holds the subcategory, and has it at the header and total rows the
+// reference records — then lists the sheets' subcategories it lacks.
+func (l *linter) checkReference() {
+	listed := map[string]bool{} // sheet\x00subcategory key
+	for _, m := range sortedMappings(l.in.Reference) {
+		listed[m.SheetName+"\x00"+nameKey(m.Subcategory)] = true
+		ix := l.sheets[m.SheetName]
+		if ix == nil {
+			l.add(SeverityError, CheckReference, excel.ReferenceSheet, "", "%s is filed under sheet %s, which the workbook does not have",
+				m.Subcategory, m.SheetName)
+			continue
+		}
+		blocks := ix.blocksOf(m.Subcategory)
+		if len(blocks) == 0 && !ix.hasLabel(m.Subcategory) {
+			l.add(SeverityError, CheckReference, m.SheetName, "", "%s is in the reference sheet but not in column B of %s",
+				m.Subcategory, m.SheetName)
+			continue
+		}
+		if m.RowNumber > 0 && !sameName(ix.colB[m.RowNumber], m.Subcategory) {
+			l.add(SeverityError, CheckReference, m.SheetName, "B"+strconv.Itoa(m.RowNumber),
+				"the reference sheet puts %s on row %d, which holds %s%s",
+				m.Subcategory, m.RowNumber, quoteOrEmpty(ix.colB[m.RowNumber]), actualRows(blocks, "starts", func(b block) int { return b.First }))
+		}
+		if m.TotalRow > 0 {
+			b, ok := ix.byTotal[m.TotalRow]
+			switch {
+			case !ok:
+				l.add(SeverityError, CheckReference, m.SheetName, "A"+strconv.Itoa(m.TotalRow),
+					"the reference sheet's total row for %s is %d, which is not a total row%s",
+					m.Subcategory, m.TotalRow, actualRows(blocks, "totals", func(b block) int { return b.Total }))
+			case b.Subcat != "" && !sameName(b.Subcat, m.Subcategory):
+				l.add(SeverityError, CheckReference, m.SheetName, "A"+strconv.Itoa(m.TotalRow),
+					"the reference sheet's total row for %s is %d, which totals %s%s",
+					m.Subcategory, m.TotalRow, b.Subcat, actualRows(blocks, "totals", func(b block) int { return b.Total }))
+			}
+		}
+	}
+
+	for _, name := range l.order {
+		if !l.referenced(name) {
+			continue
+		}
+		for _, b := range l.sheets[name].blocks {
+			if b.Subcat != "" && !listed[name+"\x00"+nameKey(b.Subcat)] {
+				l.add(SeverityWarning, CheckReference, name, "B"+strconv.Itoa(b.First), "%s is missing from the reference sheet", b.Subcat)
+			}
+		}
+	}
+}
+
+// referenced reports whether the reference sheet files anything under sheet.
+func (l *linter) referenced(sheet string) bool {
+	for _, list := range l.in.Reference {
+		for _, m := range list {
+			if m.SheetName == sheet {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasLabel reports whether sub appears anywhere in column B.
+func (ix *sheetIndex) hasLabel(sub string) bool {
+	for _, v := range ix.colB {
+		if sameName(v, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func actualRows(blocks []block, verb string, row func(block) int) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	rows := make([]string, len(blocks))
+	for i, b := range blocks {
+		rows[i] = strconv.Itoa(row(b))
+	}
+	return fmt.Sprintf(" (it %s on row %s)", verb, strings.Join(rows, ", "))
+}
+
+func sortedMappings(ref map[string][]resolver.SubcategoryMapping) []resolver.SubcategoryMapping {
+	var all []resolver.SubcategoryMapping
+	for _, list := range ref {
+		all = append(all, list...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].SheetName != all[j].SheetName {
+			return all[i].SheetName < all[j].SheetName
+		}
+		return all[i].Subcategory < all[j].Subcategory
+	})
+	return all
+}
+
+// ── taxonomy.json ────────────────────────────────────────────────────────────
+
+// checkTaxonomy checks that every taxonomy type has a sheet holding each of its
+// subcategories, that the reference sheet files them under the same category,
+// and lists the sheets' subcategories the taxonomy lacks.
+func (l *linter) checkTaxonomy() {
+	for _, t := range l.in.Taxonomy {
+		ix := l.sheets[t.Name]
+		if ix == nil {
+			l.add(SeverityError, CheckTaxonomy, t.Name, "", "taxonomy.json type %s has no sheet in the workbook", t.Name)
+			continue
+		}
+		known := map[string]bool{}
+		for _, c := range t.Cats {
+			for _, s := range c.Subs {
+				known[nameKey(s.Name)] = true
+				if len(ix.blocksOf(s.Name)) == 0 {
+					l.add(SeverityWarning, CheckTaxonomy, t.Name, "", "taxonomy.json subcategory %s/%s has no block in the sheet", c.Name, s.Name)
+				}
+				if cat, ok := l.referenceCategory(t.Name, s.Name); ok && cat != "" && !sameName(cat, c.Name) {
+					l.add(SeverityWarning, CheckTaxonomy, excel.ReferenceSheet, "", "%s/%s is under category %s in the reference sheet but %s in taxonomy.json",
+						t.Name, s.Name, cat, c.Name)
+				}
+			}
+		}
+		for _, b := range ix.blocks {
+			if b.Subcat != "" && !known[nameKey(b.Subcat)] {
+				l.add(SeverityWarning, CheckTaxonomy, t.Name, "B"+strconv.Itoa(b.First), "%s is not in taxonomy.json under %s", b.Subcat, t.Name)
+			}
+		}
+	}
+}
+
+func (l *linter) referenceCategory(sheet, sub string) (string, bool) {
+	for _, m := range l.in.Reference[sub] {
+		if m.SheetName == sheet {
+			return m.Category, true
+		}
+	}
+	return "", false
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────────────
+
+func nameKey(s string) string {
+	return strings.ToLower(norm.NFC.String(strings.TrimSpace(s)))
+}
+
+func sameName(a, b string) bool {
+	return nameKey(a) == nameKey(b)
+}
+
+func orUnnamed(sub string) string {
+	if sub == "" {
+		return "unlabelled block"
+	}
+	return sub
+}
+
+func quoteOrEmpty(s string) string {
+	if s == "" {
+		return "nothing"
+	}
+	return strconv.Quote(s)
+}
+
+func rowSpan(from, to int) string {
+	if from == to {
+		return fmt.Sprintf("row %d", from)
+	}
+	return fmt.Sprintf("rows %d-%d", from, to)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func splitRef(ref string) (col string, row int, ok bool) {
+	c, r, err := excelize.SplitCellName(ref)
+	return c, r, err == nil
+}
+
+func parseRange(rng string) (col1 string, row1 int, col2 string, row2 int, ok bool) {
+	a, b, found := strings.Cut(rng, ":")
+	if !found {
+		return "", 0, "", 0, false
+	}
+	c1, r1, ok1 := splitRef(a)
+	c2, r2, ok2 := splitRef(b)
+	return c1, r1, c2, r2, ok1 && ok2
+}
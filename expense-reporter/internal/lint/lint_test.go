@@ -0,0 +1,192 @@
+package lint
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/generate"
+	"expense-reporter/internal/inspect"
+	"expense-reporter/internal/taxonomy"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+const fixtureTaxonomy = "../../test/fixtures/generate-basic/taxonomy.json"
+
+// generated builds the generate-basic workbook, letting edit change it after.
+// With one headroom row, Fixas has Diarista on rows 3-4 (total 5) and
+// Aluguel on rows 6-7 (total 8).
+func generated(t *testing.T, edit func(f *excelize.File)) Input {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "book.xlsx")
+	require.NoError(t, generate.Generate(generate.Options{TaxonomyPath: fixtureTaxonomy, OutPath: path, Year: 2026, Headroom: 1}))
+	if edit != nil {
+		f, err := excelize.OpenFile(path)
+		require.NoError(t, err)
+		edit(f)
+		require.NoError(t, f.Save())
+		require.NoError(t, f.Close())
+	}
+	book, err := inspect.Load(path)
+	require.NoError(t, err)
+	types, _, err := taxonomy.LoadTaxonomy(fixtureTaxonomy, "", "", 0)
+	require.NoError(t, err)
+	return Input{Book: book, Summary: "Listas de itens", Taxonomy: types}
+}
+
+func find(r Report, check, location string) (Finding, bool) {
+	for _, f := range r.Findings {
+		if f.Check == check && f.Location() == location {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+func TestLint_GeneratedWorkbookIsClean(t *testing.T) {
+	r := Lint(generated(t, nil))
+	assert.Empty(t, r.Findings)
+}
+
+func TestLint_TotalRanges(t *testing.T) {
+	r := Lint(generated(t, func(f *excelize.File) {
+		require.NoError(t, f.SetCellValue("Fixas", "E3", 150))
+		require.NoError(t, f.SetCellFormula("Fixas", "E5", "SUM(E4:E4)")) // Diarista total skips E3
+		require.NoError(t, f.SetCellFormula("Fixas", "H5", "SUM(H3:H3)")) // stops short of the empty H4
+		require.NoError(t, f.SetCellFormula("Fixas", "K5", "SUM(H3:H4)")) // another month
+		require.NoError(t, f.SetCellValue("Fixas", "N5", 300))            // typed over
+	}))
+
+	f, ok := find(r, CheckTotalRange, "Fixas!E5")
+	require.True(t, ok, "%v", r.Findings)
+	assert.Equal(t, SeverityError, f.Severity)
+	assert.Contains(t, f.Message, "row 3 left out, 1 of them filled")
+	f, ok = find(r, CheckTotalRange, "Fixas!H5")
+	require.True(t, ok)
+	assert.Equal(t, SeverityWarning, f.Severity)
+	f, ok = find(r, CheckTotalRange, "Fixas!K5")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "sums column H, not K")
+	f, ok = find(r, CheckTotalRange, "Fixas!N5")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "typed in")
+	assert.Equal(t, 2, r.Count(SeverityError))
+}
+
+func TestLint_Pulls(t *testing.T) {
+	in := generated(t, nil)
+	var pullRef string
+	for _, rd := range in.Book.Sheets[0].Rows {
+		for _, c := range rd.Cells {
+			if c.Col == "D" && c.Formula == "Fixas!E5" {
+				pullRef = "D" + strconv.Itoa(rd.Row)
+			}
+		}
+	}
+	require.NotEmpty(t, pullRef, "Listas pulls Diarista's January total")
+
+	r := Lint(generated(t, func(f *excelize.File) {
+		require.NoError(t, f.SetCellFormula("Listas de itens", pullRef, "Fixas!E4")) // a data row
+		require.NoError(t, f.SetCellFormula("Listas de itens", "E"+pullRef[1:], "Fixas!E8"))
+	}))
+	f, ok := find(r, CheckPull, "Listas de itens!"+pullRef)
+	require.True(t, ok, "%v", r.Findings)
+	assert.Contains(t, f.Message, "not a total row (Diarista totals on row 5)")
+	f, ok = find(r, CheckPull, "Listas de itens!E"+pullRef[1:])
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "pulls Fixas!E8, another month's total (expected column H)")
+	_, ok = find(r, CheckPull, "Listas de itens!F"+pullRef[1:])
+	assert.False(t, ok)
+}
+
+func TestLint_Taxonomy(t *testing.T) {
+	in := generated(t, func(f *excelize.File) {
+		require.NoError(t, f.SetCellValue("Fixas", "B3", "Faxina"))
+	})
+	r := Lint(in)
+	f, ok := find(r, CheckTaxonomy, "Fixas!B3")
+	require.True(t, ok, "%v", r.Findings)
+	assert.Contains(t, f.Message, "Faxina is not in taxonomy.json under Fixas")
+	f, ok = find(r, CheckTaxonomy, "Fixas")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "Habitação/Diarista has no block")
+	var relabelled []string
+	for _, f := range r.Findings {
+		if f.Check == CheckPull {
+			relabelled = append(relabelled, f.Message)
+		}
+	}
+	require.NotEmpty(t, relabelled)
+	assert.Contains(t, relabelled[0], "row labelled Diarista pulls the total of Faxina")
+}
+
+// handWorkbook has a reference sheet and a Variáveis sheet typed by hand.
+func handWorkbook(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Despesas.xlsx")
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetName("Sheet1", excel.ReferenceSheet))
+	for ref, v := range map[string]any{
+		"A5": "Variáveis", "B5": "Transporte", "C5": "Uber/Taxi", "D5": 3, "F5": 6,
+		"A6": "Variáveis", "B6": "Transporte", "C6": "Ônibus", "D6": 8, "F6": 10,
+		"A7": "Variáveis", "B7": "Transporte", "C7": "Metrô",
+		"A8": "Extras", "B8": "Saúde", "C8": "Dentista",
+	} {
+		require.NoError(t, f.SetCellValue(excel.ReferenceSheet, ref, v))
+	}
+	const sheet = "Variáveis"
+	_, err := f.NewSheet(sheet)
+	require.NoError(t, err)
+	for ref, v := range map[string]any{
+		"A3": "Transporte", "B3": "Uber/Taxi", "D3": "Uber", "F3": 35.5,
+		"D4": "Uber", "F4": 20,
+		"D5": "Total", "E5": "-",
+		"B7": "Ônibus", "D7": "Passe", "F7": 150,
+		"D8": "Total", "E8": "-",
+		"B9": "Bicicleta", "D9": "Aluguel", "F9": 10,
+		"D10": "Total", "E10": "-",
+	} {
+		require.NoError(t, f.SetCellValue(sheet, ref, v))
+	}
+	require.NoError(t, f.SetCellFormula(sheet, "F5", "SUM(F3:F4)"))
+	require.NoError(t, f.SetCellFormula(sheet, "F8", "SUM(F7:F7)"))
+	require.NoError(t, f.SetCellFormula(sheet, "F10", "SUM(F9:F9)"))
+	require.NoError(t, f.SaveAs(path))
+	return path
+}
+
+func TestLint_Reference(t *testing.T) {
+	path := handWorkbook(t)
+	ref, err := excel.LoadReferenceSheet(path)
+	require.NoError(t, err)
+	book, err := inspect.Load(path)
+	require.NoError(t, err)
+	r := Lint(Input{Book: book, Summary: "Listas de itens", Reference: ref})
+
+	f, ok := find(r, CheckReference, "Variáveis!A6")
+	require.True(t, ok, "%v", r.Findings)
+	assert.Contains(t, f.Message, "total row for Uber/Taxi is 6, which is not a total row (it totals on row 5)")
+	f, ok = find(r, CheckReference, "Variáveis!B8")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "puts Ônibus on row 8, which holds nothing (it starts on row 7)")
+	f, ok = find(r, CheckReference, "Variáveis!A10")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "which totals Bicicleta")
+	f, ok = find(r, CheckReference, "Variáveis")
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "Metrô is in the reference sheet but not in column B")
+	f, ok = find(r, CheckReference, excel.ReferenceSheet)
+	require.True(t, ok)
+	assert.Contains(t, f.Message, "sheet Extras, which the workbook does not have")
+	f, ok = find(r, CheckReference, "Variáveis!B9")
+	require.True(t, ok)
+	assert.Equal(t, SeverityWarning, f.Severity)
+	assert.Contains(t, f.Message, "Bicicleta is missing from the reference sheet")
+	_, ok = find(r, CheckTotalRange, "Variáveis!F5")
+	assert.False(t, ok, "hand-typed totals cover their blocks")
+}
@@ -0,0 +1,51 @@
+package logschema
+
+import "encoding/json"
+
+// Expenses is the schema of expenses_log.jsonl lines (feedback.ExpenseEntry).
+//
+//	1  unversioned; the expense type may sit under the pre-rename "sheet" key
+//	2  "schema" field; "sheet" folded into "type"
+//	3  "op" field: edit and void records supersede earlier lines (see Resolve)
+var Expenses = &Schema{
+	Name:    "expenses_log",
+	Current: 3,
+	Steps: map[int]Step{
+		1: sheetToType,
+		2: noChange, // op is new and optional; the bump keeps older builds from ignoring it
+	},
+}
+
+// Classifications is the schema of classifications.jsonl lines (feedback.Entry).
+//
+//	1  unversioned; the expense type may sit under the pre-rename "sheet" key
+//	2  "schema" field; "sheet" folded into "type"
+//	3  "op" field: a void record leaves its ID without a classification, and an
+//	   edit record restores an earlier one (see Resolve)
+var Classifications = &Schema{
+	Name:    "classifications",
+	Current: 3,
+	Steps: map[int]Step{
+		1: sheetToType,
+		2: noChange,
+	},
+}
+
+// Investments is the schema of investments_log.jsonl lines
+// (feedback.InvestmentEntry).
+//
+//	1  contributions and withdrawals by asset class
+var Investments = &Schema{
+	Name:    "investments_log",
+	Current: 1,
+}
+
+func noChange(map[string]json.RawMessage) error { return nil }
+
+// sheetToType folds the legacy "sheet" key into "type" (the workbook sheet
+// became the expense type; see apply.ReviewedLocation for the same rename in
+// reviewed.json).
+func sheetToType(fields map[string]json.RawMessage) error {
+	renameField(fields, "sheet", "type")
+	return nil
+}
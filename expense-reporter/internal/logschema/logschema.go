@@ -0,0 +1,142 @@
+// Package logschema versions the lines of the JSONL logs. Every line written
+// carries a "schema" field; readers pass each line through Upgrade, which
+// applies the registered step functions to bring an older line up to the
+// current version before it is decoded, so compatibility lives in one place
+// instead of in per-type special-case unmarshalling.
+//
+// Lines written before versioning have no "schema" field and count as version 1.
+package logschema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"expense-reporter/internal/jsonlog"
+)
+
+// Field is the JSON key holding a line's schema version.
+const Field = "schema"
+
+// Legacy is the version of lines written before versioning (no Field key).
+const Legacy = 1
+
+// Step upgrades one line, given as its top-level fields, from version v to v+1
+// in place.
+type Step func(fields map[string]json.RawMessage) error
+
+// Schema describes one log kind: its current version and the steps that lead
+// up to it. Steps[v] upgrades a line from version v to v+1.
+type Schema struct {
+	Name    string
+	Current int
+	Steps   map[int]Step
+}
+
+// Version returns the schema version of line.
+func Version(line []byte) (int, error) {
+	var peek struct {
+		Schema *int `json:"schema"`
+	}
+	if err := json.Unmarshal(line, &peek); err != nil {
+		return 0, err
+	}
+	if peek.Schema == nil {
+		return Legacy, nil
+	}
+	return *peek.Schema, nil
+}
+
+// Upgrade returns line at the current version, along with the version it was
+// read at. A current line is returned unchanged; a line from a newer version
+// is an error, since fields this build does not know would be silently lost.
+func (s *Schema) Upgrade(line []byte) ([]byte, int, error) {
+	v, err := Version(line)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch {
+	case v == s.Current:
+		return line, v, nil
+	case v > s.Current:
+		return nil, v, fmt.Errorf("%s line has schema %d, newer than this build supports (%d); upgrade expense-reporter", s.Name, v, s.Current)
+	case v < Legacy:
+		return nil, v, fmt.Errorf("%s line has invalid schema %d", s.Name, v)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, v, err
+	}
+	for from := v; from < s.Current; from++ {
+		step, ok := s.Steps[from]
+		if !ok {
+			return nil, v, fmt.Errorf("%s: no upgrade from schema %d", s.Name, from)
+		}
+		if err := step(fields); err != nil {
+			return nil, v, fmt.Errorf("%s: upgrading schema %d: %w", s.Name, from, err)
+		}
+	}
+	fields[Field] = json.RawMessage(strconv.Itoa(s.Current))
+	out, err := json.Marshal(fields)
+	return out, v, err
+}
+
+// Stamp prefixes the JSON object obj with the current schema version. obj must
+// not already carry the Field key.
+func (s *Schema) Stamp(obj []byte) []byte {
+	head := `{"` + Field + `":` + strconv.Itoa(s.Current)
+	body := bytes.TrimPrefix(obj, []byte("{"))
+	if len(bytes.TrimSpace(body)) > 1 { // more than the closing brace
+		head += ","
+	}
+	return append([]byte(head), body...)
+}
+
+// Versions counts the lines of the log at path by schema version. A missing
+// file counts as empty.
+func (s *Schema) Versions(path string) (map[int]int, error) {
+	counts := map[int]int{}
+	f, err := jsonlog.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return counts, nil
+		}
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		v, err := Version(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s line %d: %w", path, n, err)
+		}
+		counts[v]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return counts, nil
+}
+
+// renameField moves the value of key from to key to, unless to is already set.
+func renameField(fields map[string]json.RawMessage, from, to string) {
+	v, ok := fields[from]
+	if !ok {
+		return
+	}
+	delete(fields, from)
+	if cur, set := fields[to]; set && string(cur) != `""` && string(cur) != "null" {
+		return
+	}
+	fields[to] = v
+}
@@ -0,0 +1,107 @@
+package logschema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersion(t *testing.T) {
+	v, err := Version([]byte(`{"id":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, Legacy, v, "unversioned lines are legacy")
+
+	v, err = Version([]byte(`{"schema":2,"id":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	_, err = Version([]byte(`{"id":`))
+	assert.Error(t, err)
+}
+
+func TestUpgrade_LegacySheetBecomesType(t *testing.T) {
+	out, from, err := Expenses.Upgrade([]byte(`{"id":"a","sheet":"Fixas","subcategory":"Aluguel"}`))
+	require.NoError(t, err)
+	assert.Equal(t, Legacy, from)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(out, &fields))
+	assert.Equal(t, "Fixas", fields["type"])
+	assert.NotContains(t, fields, "sheet")
+	assert.Equal(t, float64(Expenses.Current), fields["schema"])
+}
+
+func TestUpgrade_ExistingTypeWins(t *testing.T) {
+	out, _, err := Classifications.Upgrade([]byte(`{"sheet":"Fixas","type":"Variáveis"}`))
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(out, &fields))
+	assert.Equal(t, "Variáveis", fields["type"])
+}
+
+func TestUpgrade_CurrentLineUnchanged(t *testing.T) {
+	line := []byte(`{"schema":3,"id":"a","type":"Fixas"}`)
+	out, from, err := Expenses.Upgrade(line)
+	require.NoError(t, err)
+	assert.Equal(t, 3, from)
+	assert.Equal(t, line, out)
+}
+
+func TestUpgrade_NewerSchemaRejected(t *testing.T) {
+	_, _, err := Expenses.Upgrade([]byte(`{"schema":99,"id":"a"}`))
+	assert.ErrorContains(t, err, "newer than this build supports")
+}
+
+func TestStamp(t *testing.T) {
+	s := &Schema{Name: "test", Current: 3}
+	assert.Equal(t, `{"schema":3,"id":"a"}`, string(s.Stamp([]byte(`{"id":"a"}`))))
+	assert.Equal(t, `{"schema":3}`, string(s.Stamp([]byte(`{}`))))
+}
+
+func TestVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"a\"}\n\n{\"schema\":2,\"id\":\"b\"}\n{\"id\":\"c\"}\n"), 0o644))
+
+	counts, err := Expenses.Versions(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{1: 2, 2: 1}, counts)
+
+	counts, err = Expenses.Versions(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+type line struct{ id, op, v string }
+
+func TestResolve(t *testing.T) {
+	lines := []line{
+		{"a", "", "a1"},
+		{"b", "", "b1"},
+		{"c", "", "c1"},
+		{"c", "", "c1-twin"},
+		{"a", OpEdit, "a2"},
+		{"b", OpVoid, "b-void"},
+		{"", "", "anon"},
+		{"a", OpEdit, "a3"},
+		{"d", OpVoid, "d-void"},
+		{"d", OpEdit, "d-restored"},
+	}
+	got := Resolve(lines, func(l line) string { return l.id }, func(l line) string { return l.op })
+
+	var vs []string
+	for _, l := range got {
+		vs = append(vs, l.v)
+	}
+	assert.Equal(t, []string{"a3", "c1", "c1-twin", "anon", "d-restored"}, vs)
+}
+
+func TestUpgrade_ExpensesV2ToV3(t *testing.T) {
+	out, from, err := Expenses.Upgrade([]byte(`{"schema":2,"id":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, from)
+	assert.JSONEq(t, `{"schema":3,"id":"a"}`, string(out))
+}
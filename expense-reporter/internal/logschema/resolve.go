@@ -0,0 +1,47 @@
+package logschema
+
+// Op values for the "op" field of a log line. An original record has no op.
+const (
+	// OpEdit supersedes every earlier line with the same ID; the edit line
+	// carries the record's full new state.
+	OpEdit = "edit"
+	// OpVoid is a tombstone: the record with that ID no longer counts.
+	OpVoid = "void"
+)
+
+// Resolve applies edits and tombstones to the lines of a log, given in file
+// order. For an ID with any edit or void line, the last such line wins: an edit
+// replaces the record (keeping the position of its first line) and a void drops
+// it. IDs without one pass through unchanged — including identical entries that
+// share an ID, which stay separate until one of them is edited. Lines with an
+// empty ID are never superseded.
+func Resolve[T any](lines []T, id func(T) string, op func(T) string) []T {
+	last := map[string]int{} // ID → index of its last edit/void line
+	for i, line := range lines {
+		if k := id(line); k != "" && op(line) != "" {
+			last[k] = i
+		}
+	}
+	if len(last) == 0 {
+		return lines
+	}
+
+	out := make([]T, 0, len(lines))
+	placed := map[string]bool{}
+	for _, line := range lines {
+		k := id(line)
+		j, superseded := last[k]
+		if k == "" || !superseded {
+			out = append(out, line)
+			continue
+		}
+		if placed[k] {
+			continue
+		}
+		placed[k] = true
+		if op(lines[j]) == OpEdit {
+			out = append(out, lines[j])
+		}
+	}
+	return out
+}
@@ -12,6 +12,10 @@ type Installment struct {
 	Total   float64 // Original total amount (e.g., 300.00)
 	Count   int     // Number of installments (e.g., 3)
 	Current int     // Current installment number (1-based, 0 = unexpanded)
+
+	// Values lists each payment of an uneven plan (down payment, explicit
+	// values — see utils.ParseInstallments); nil when all payments are Total/Count.
+	Values []float64
 }
 
 // Expense represents a single expense entry
@@ -21,6 +25,8 @@ type Expense struct {
 	Value       float64
 	Subcategory string
 	Installment *Installment // nil = regular expense, non-nil = installment
+	Currency    string       // ISO 4217 code of Value; "" = BRL (see utils.SplitCurrencyCode)
+	Account     string       // card or account it was paid with; "" = not recorded
 }
 
 func NewExpense(item string, subcategory string, dateStr string, valueStr string) (*Expense, error) {
@@ -40,17 +46,20 @@ func NewExpense(item string, subcategory string, dateStr string, valueStr string
 		return nil, fmt.Errorf("invalid date: %w", err)
 	}
 
-	// Parse value with installments
-	value, installmentCount, err := utils.ParseCurrencyWithInstallments(valueStr)
+	// Parse value with optional currency code and installments
+	currency, valueStr := utils.SplitCurrencyCode(valueStr)
+	sched, err := utils.ParseInstallments(valueStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid value: %w", err)
 	}
+	value, installmentCount := sched.Values[0], sched.Count()
 
 	expense := &Expense{
 		Item:        item,
 		Date:        date,
 		Value:       value, // Per-installment value if installments > 1
 		Subcategory: subcategory,
+		Currency:    currency,
 	}
 
 	// Add installment info if applicable
@@ -61,11 +70,25 @@ func NewExpense(item string, subcategory string, dateStr string, valueStr string
 			Count:   installmentCount,
 			Current: 0, // Unexpanded yet
 		}
+		if !sched.Equal() {
+			expense.Installment.Total = sched.Total()
+			expense.Installment.Values = sched.Values
+		}
 	}
 
 	return expense, nil
 }
 
+// IsForeign returns true if Value is in a currency other than BRL
+func (e *Expense) IsForeign() bool {
+	return e.Currency != ""
+}
+
+// IsUneven returns true if this expense's installments are not all the same amount
+func (e *Expense) IsUneven() bool {
+	return e.Installment != nil && e.Installment.Values != nil
+}
+
 // IsInstallment returns true if this expense is an installment
 func (e *Expense) IsInstallment() bool {
 	return e.Installment != nil
@@ -286,3 +286,42 @@ func TestExpense_Validate_Installment(t *testing.T) {
 		t.Error("installment with negative total should fail validation")
 	}
 }
+
+func TestNewExpense_CurrencyCode(t *testing.T) {
+	foreign, err := NewExpense("Steam", "Jogos", "17/04", "USD 20,00/2")
+	if err != nil {
+		t.Fatalf("NewExpense with currency code: %v", err)
+	}
+	if foreign.Currency != "USD" || !foreign.IsForeign() {
+		t.Errorf("Currency = %q, IsForeign = %v, want USD/true", foreign.Currency, foreign.IsForeign())
+	}
+	if foreign.Value != 10.00 || foreign.Installment == nil || foreign.Installment.Count != 2 {
+		t.Errorf("Value = %v, Installment = %+v, want 10.00 per installment over 2", foreign.Value, foreign.Installment)
+	}
+
+	local, err := NewExpense("Padaria", "Padaria", "17/04", "BRL 12,00")
+	if err != nil {
+		t.Fatalf("NewExpense with BRL code: %v", err)
+	}
+	if local.IsForeign() {
+		t.Errorf("BRL value must not be foreign, got Currency %q", local.Currency)
+	}
+}
+
+func TestNewExpense_UnevenInstallments(t *testing.T) {
+	down, err := NewExpense("Sofá", "Móveis", "10/05", "500,00+4x250,00")
+	if err != nil {
+		t.Fatalf("NewExpense with down payment: %v", err)
+	}
+	if !down.IsUneven() || down.Installment.Count != 5 || down.Installment.Total != 1500 {
+		t.Errorf("Installment = %+v, want uneven 5 payments totalling 1500", down.Installment)
+	}
+
+	equal, err := NewExpense("Curso", "Cursos", "10/05", "300,00/3")
+	if err != nil {
+		t.Fatalf("NewExpense with equal parts: %v", err)
+	}
+	if equal.IsUneven() {
+		t.Error("total/N installments must not be uneven")
+	}
+}
@@ -0,0 +1,188 @@
+// Package reconcile compares the expense log with a workbook filled in by the
+// legacy insertion path (batch, add), entry by entry, so the two records of the
+// same year can be brought back in step.
+package reconcile
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+	"expense-reporter/pkg/utils"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Mismatch fields: which side of a paired entry disagrees.
+const (
+	FieldValue = "value"
+	FieldDate  = "date"
+)
+
+// Mismatch pairs a log entry with the workbook entry it most likely records,
+// which differs from it in Field.
+type Mismatch struct {
+	Log      feedback.ExpenseEntry
+	Workbook excel.WorkbookEntry
+	Field    string
+}
+
+// Report is the outcome of Compare.
+type Report struct {
+	Matched        int
+	Mismatched     []Mismatch
+	OnlyInLog      []feedback.ExpenseEntry
+	OnlyInWorkbook []excel.WorkbookEntry
+}
+
+// InSync reports whether the log and the workbook agree entry for entry.
+func (r Report) InSync() bool {
+	return len(r.Mismatched)+len(r.OnlyInLog)+len(r.OnlyInWorkbook) == 0
+}
+
+// Compare pairs log entries with workbook entries by item (case- and
+// accent-form-insensitive). A pair with the same date and value (to the cent)
+// matches; failing that, the parts of a split transaction (sharing a
+// ParentID), none of them matched on its own, match together one row of the
+// split's item and date holding their sum; then the same date pairs as a
+// value mismatch, and the same value as a date mismatch. Each entry pairs at
+// most once, in log order; the rest are reported on their own side.
+func Compare(logged []feedback.ExpenseEntry, book []excel.WorkbookEntry) Report {
+	var r Report
+	used := make([]bool, len(book))
+	paired := make([]bool, len(logged))
+	dates := make([]time.Time, len(logged))
+	for i, e := range logged {
+		dates[i], _ = utils.ParseDateFlexible(e.Date)
+	}
+	byItem := map[string][]int{}
+	for j, w := range book {
+		byItem[itemKey(w.Item)] = append(byItem[itemKey(w.Item)], j)
+	}
+
+	pass := func(same func(i, j int) bool, pair func(i, j int)) {
+		for i, e := range logged {
+			if paired[i] {
+				continue
+			}
+			for _, j := range byItem[itemKey(e.Item)] {
+				if used[j] || !same(i, j) {
+					continue
+				}
+				paired[i], used[j] = true, true
+				pair(i, j)
+				break
+			}
+		}
+	}
+	sameDate := func(i, j int) bool { return !book[j].Date.IsZero() && dates[i].Equal(book[j].Date) }
+	sameValue := func(i, j int) bool { return cents(logged[i].Value) == cents(book[j].Value) }
+
+	pass(func(i, j int) bool { return sameDate(i, j) && sameValue(i, j) }, func(i, j int) { r.Matched++ })
+	r.Matched += pairSplits(logged, book, byItem, paired, used, sameDate)
+	pass(sameDate, func(i, j int) {
+		r.Mismatched = append(r.Mismatched, Mismatch{Log: logged[i], Workbook: book[j], Field: FieldValue})
+	})
+	pass(sameValue, func(i, j int) {
+		r.Mismatched = append(r.Mismatched, Mismatch{Log: logged[i], Workbook: book[j], Field: FieldDate})
+	})
+
+	for i, e := range logged {
+		if !paired[i] {
+			r.OnlyInLog = append(r.OnlyInLog, e)
+		}
+	}
+	for j, w := range book {
+		if !used[j] {
+			r.OnlyInWorkbook = append(r.OnlyInWorkbook, w)
+		}
+	}
+	return r
+}
+
+// pairSplits pairs each split transaction none of whose parts is paired yet
+// with an unused workbook row of the same item and date holding the parts'
+// sum, marking the parts and the row; it returns how many splits it paired.
+func pairSplits(logged []feedback.ExpenseEntry, book []excel.WorkbookEntry, byItem map[string][]int,
+	paired, used []bool, sameDate func(i, j int) bool) int {
+
+	var parents []string
+	parts := map[string][]int{}
+	for i, e := range logged {
+		if e.ParentID == "" {
+			continue
+		}
+		if _, seen := parts[e.ParentID]; !seen {
+			parents = append(parents, e.ParentID)
+		}
+		parts[e.ParentID] = append(parts[e.ParentID], i)
+	}
+
+	n := 0
+	for _, parent := range parents {
+		idx := parts[parent]
+		var sum int64
+		open := true
+		for _, i := range idx {
+			sum += cents(logged[i].Value)
+			open = open && !paired[i]
+		}
+		if !open {
+			continue
+		}
+		first := idx[0]
+		for _, j := range byItem[itemKey(logged[first].Item)] {
+			if used[j] || !sameDate(first, j) || cents(book[j].Value) != sum {
+				continue
+			}
+			used[j] = true
+			for _, i := range idx {
+				paired[i] = true
+			}
+			n++
+			break
+		}
+	}
+	return n
+}
+
+// ToLogEntry converts a workbook-only entry to an expense log line filed under
+// its sheet, category and subcategory. An entry without a date is dated the
+// first of its month in year.
+func ToLogEntry(w excel.WorkbookEntry, year int) feedback.ExpenseEntry {
+	date := w.Date
+	if date.IsZero() {
+		date = time.Date(year, w.Month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	e := feedback.NewExpenseEntry(w.Item, utils.FormatDate(date), w.Value, w.Subcategory, w.Category)
+	e.Type = w.Sheet
+	return e
+}
+
+// ToInsertLine converts a log-only entry to a batch CSV line
+// ("item;DD/MM;value;path"), with the fullest taxonomy path the entry carries
+// so the subcategory resolves unambiguously.
+func ToInsertLine(e feedback.ExpenseEntry) string {
+	date := e.Date
+	if t, err := utils.ParseDateFlexible(e.Date); err == nil {
+		date = t.Format("02/01")
+	}
+	path := []string{e.Subcategory}
+	if e.Category != "" {
+		path = []string{e.Category, e.Subcategory}
+		if e.Type != "" {
+			path = []string{e.Type, e.Category, e.Subcategory}
+		}
+	}
+	return utils.BuildInsertString(e.Item, date, e.Value, strings.Join(path, ","))
+}
+
+func itemKey(item string) string {
+	return strings.ToLower(strings.Join(strings.Fields(norm.NFC.String(item)), " "))
+}
+
+func cents(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
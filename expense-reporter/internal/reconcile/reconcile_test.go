@@ -0,0 +1,81 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"expense-reporter/internal/excel"
+	"expense-reporter/internal/feedback"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(m time.Month, d int) time.Time {
+	return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCompare(t *testing.T) {
+	logged := []feedback.ExpenseEntry{
+		feedback.NewExpenseEntry("Uber Centro", "15/04/2025", 35.5, "Uber/Taxi", "Transporte"),
+		feedback.NewExpenseEntry("Mercado", "03/03/2025", 210, "Supermercado", "Alimentação"),
+		feedback.NewExpenseEntry("Padaria", "10/02/2025", 12, "Padaria", "Alimentação"),
+		feedback.NewExpenseEntry("Spotify", "05/03/2025", 21.9, "Spotify", "Assinaturas"),
+		feedback.NewExpenseEntry("Uber Centro", "16/04/2025", 35.5, "Uber/Taxi", "Transporte"),
+	}
+	book := []excel.WorkbookEntry{
+		{Item: "uber  centro", Date: day(4, 16), Month: time.April, Value: 35.5},
+		{Item: "Uber Centro", Date: day(4, 15), Month: time.April, Value: 35.5},
+		{Item: "Mercado", Date: day(3, 3), Month: time.March, Value: 201},
+		{Item: "Padaria", Date: day(2, 11), Month: time.February, Value: 12},
+		{Item: "Cinema", Date: day(1, 20), Month: time.January, Value: 40},
+	}
+
+	r := Compare(logged, book)
+	assert.Equal(t, 2, r.Matched, "both rides pair exactly, whatever the order and spacing")
+	require.Len(t, r.Mismatched, 2)
+	assert.Equal(t, "Mercado", r.Mismatched[0].Log.Item)
+	assert.Equal(t, FieldValue, r.Mismatched[0].Field)
+	assert.Equal(t, "Padaria", r.Mismatched[1].Log.Item)
+	assert.Equal(t, FieldDate, r.Mismatched[1].Field)
+	require.Len(t, r.OnlyInLog, 1)
+	assert.Equal(t, "Spotify", r.OnlyInLog[0].Item)
+	require.Len(t, r.OnlyInWorkbook, 1)
+	assert.Equal(t, "Cinema", r.OnlyInWorkbook[0].Item)
+	assert.False(t, r.InSync())
+}
+
+func TestCompare_SplitPairsAsOneTransaction(t *testing.T) {
+	parent := feedback.GenerateID("Mercado", "03/03/2025", 150)
+	logged := []feedback.ExpenseEntry{
+		feedback.NewSplitExpenseEntry(parent, "Mercado", "03/03/2025", 100, "Supermercado", "Alimentação"),
+		feedback.NewSplitExpenseEntry(parent, "Mercado", "03/03/2025", 50, "Limpeza", "Casa"),
+	}
+
+	t.Run("one row for the whole line", func(t *testing.T) {
+		r := Compare(logged, []excel.WorkbookEntry{{Item: "Mercado", Date: day(3, 3), Month: time.March, Value: 150}})
+		assert.Equal(t, 1, r.Matched)
+		assert.True(t, r.InSync(), "the parts are not value mismatches of the row")
+	})
+
+	t.Run("one row per part", func(t *testing.T) {
+		r := Compare(logged, []excel.WorkbookEntry{
+			{Item: "Mercado", Date: day(3, 3), Month: time.March, Value: 50},
+			{Item: "Mercado", Date: day(3, 3), Month: time.March, Value: 100},
+		})
+		assert.Equal(t, 2, r.Matched)
+		assert.True(t, r.InSync())
+	})
+}
+
+func TestToLogEntryAndInsertLine(t *testing.T) {
+	w := excel.WorkbookEntry{Sheet: "Variáveis", Category: "Lazer", Subcategory: "Cinema", Item: "Cinema", Month: time.January, Value: 40}
+	e := ToLogEntry(w, 2025)
+	assert.Equal(t, "01/01/2025", e.Date, "a missing date falls on the first of the month")
+	assert.Equal(t, "Variáveis", e.Type)
+	assert.Equal(t, feedback.GenerateID("Cinema", "01/01/2025", 40), e.ID)
+
+	assert.Equal(t, "Cinema;01/01;40,00;Variáveis,Lazer,Cinema", ToInsertLine(e))
+	e.Type = ""
+	assert.Equal(t, "Cinema;01/01;40,00;Lazer,Cinema", ToInsertLine(e))
+}
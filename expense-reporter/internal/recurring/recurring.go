@@ -0,0 +1,155 @@
+// Package recurring expands fixed monthly-ish expenses (rent, condo fees,
+// subscriptions) from a schedule file into dated occurrences, so they can be
+// materialized into expenses_log.jsonl instead of being typed in every month.
+package recurring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/feedback"
+)
+
+// monthLayout is the format of a schedule's start/end and of --through.
+const monthLayout = "01/2006"
+
+// frequencyMonths maps each supported frequency to its step in months.
+var frequencyMonths = map[string]int{
+	"monthly":    1,
+	"quarterly":  3,
+	"semiannual": 6,
+	"yearly":     12,
+}
+
+// Schedule is one entry of the schedule file. Start and End are MM/YYYY and
+// inclusive; an empty End means the schedule runs indefinitely. Day is the
+// day-of-month and is clamped to the month's length (31 → 28/02).
+type Schedule struct {
+	Item        string  `json:"item"`
+	Value       float64 `json:"value"`
+	Day         int     `json:"day"`
+	Frequency   string  `json:"frequency"`
+	Start       string  `json:"start"`
+	End         string  `json:"end,omitempty"`
+	Type        string  `json:"type"`
+	Category    string  `json:"category"`
+	Subcategory string  `json:"subcategory"`
+}
+
+// Occurrence is one due instance of a schedule. ID is the expenses_log.jsonl ID
+// the occurrence gets when appended. Key, the schedule's item and the due date
+// (see feedback.ItemDateKey), is the idempotency key: it leaves the value out,
+// so raising a schedule's value does not make its past months due again.
+type Occurrence struct {
+	Schedule Schedule
+	Date     time.Time
+	ID       string
+	Key      string
+}
+
+// Load reads and validates the schedule file at path (a JSON array).
+func Load(path string) ([]Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading recurring file: %w", err)
+	}
+	var schedules []Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		return nil, fmt.Errorf("parsing recurring file: %w", err)
+	}
+	for i, s := range schedules {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("schedule %d (%q): %w", i, s.Item, err)
+		}
+	}
+	return schedules, nil
+}
+
+func (s Schedule) validate() error {
+	if strings.TrimSpace(s.Item) == "" {
+		return fmt.Errorf("item is required")
+	}
+	if s.Value <= 0 {
+		return fmt.Errorf("value must be positive")
+	}
+	if s.Day < 1 || s.Day > 31 {
+		return fmt.Errorf("day must be between 1 and 31, got %d", s.Day)
+	}
+	if _, ok := frequencyMonths[s.Frequency]; !ok {
+		return fmt.Errorf("unknown frequency %q (want monthly, quarterly, semiannual or yearly)", s.Frequency)
+	}
+	if s.Type == "" || s.Category == "" || s.Subcategory == "" {
+		return fmt.Errorf("type, category and subcategory are required")
+	}
+	start, err := ParseMonth(s.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if s.End != "" {
+		end, err := ParseMonth(s.End)
+		if err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("end %s is before start %s", s.End, s.Start)
+		}
+	}
+	return nil
+}
+
+// ParseMonth parses an MM/YYYY string to the first day of that month (UTC).
+func ParseMonth(s string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid month %q (expected MM/YYYY)", s)
+	}
+	return t, nil
+}
+
+// Occurrences returns the schedule's occurrences from its start through the
+// month containing through (inclusive), bounded by End. Schedules must have
+// passed validation (Load).
+func (s Schedule) Occurrences(through time.Time) []Occurrence {
+	start, _ := ParseMonth(s.Start)
+	last := time.Date(through.Year(), through.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if s.End != "" {
+		if end, _ := ParseMonth(s.End); end.Before(last) {
+			last = end
+		}
+	}
+	step := frequencyMonths[s.Frequency]
+
+	var out []Occurrence
+	for month := start; !month.After(last); month = month.AddDate(0, step, 0) {
+		date := dueDate(month, s.Day)
+		out = append(out, Occurrence{
+			Schedule: s,
+			Date:     date,
+			ID:       feedback.GenerateID(s.Item, date.Format("02/01/2006"), s.Value),
+			Key:      feedback.ItemDateKey(s.Item, date),
+		})
+	}
+	return out
+}
+
+// dueDate returns day of the month starting at month, clamped to its last day.
+func dueDate(month time.Time, day int) time.Time {
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Due returns every occurrence of schedules through the given month, in
+// schedule order then date order.
+func Due(schedules []Schedule, through time.Time) []Occurrence {
+	var out []Occurrence
+	for _, s := range schedules {
+		out = append(out, s.Occurrences(through)...)
+	}
+	return out
+}
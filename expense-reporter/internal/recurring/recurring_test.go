@@ -0,0 +1,94 @@
+package recurring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/feedback"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rent() Schedule {
+	return Schedule{
+		Item: "Aluguel", Value: 2500, Day: 5, Frequency: "monthly", Start: "01/2026",
+		Type: "Fixas", Category: "Moradia", Subcategory: "Aluguel",
+	}
+}
+
+func month(s string) time.Time {
+	t, err := ParseMonth(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestOccurrences(t *testing.T) {
+	t.Run("monthly through inclusive month", func(t *testing.T) {
+		occ := rent().Occurrences(month("03/2026"))
+		require.Len(t, occ, 3)
+		assert.Equal(t, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), occ[0].Date)
+		assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), occ[2].Date)
+		assert.Equal(t, feedback.GenerateID("Aluguel", "05/03/2026", 2500), occ[2].ID)
+	})
+
+	t.Run("day clamped to month length", func(t *testing.T) {
+		s := rent()
+		s.Day = 31
+		occ := s.Occurrences(month("02/2026"))
+		require.Len(t, occ, 2)
+		assert.Equal(t, 28, occ[1].Date.Day())
+	})
+
+	t.Run("end bounds the schedule", func(t *testing.T) {
+		s := rent()
+		s.End = "02/2026"
+		assert.Len(t, s.Occurrences(month("12/2026")), 2)
+	})
+
+	t.Run("quarterly steps three months", func(t *testing.T) {
+		s := rent()
+		s.Frequency = "quarterly"
+		occ := s.Occurrences(month("12/2026"))
+		require.Len(t, occ, 4)
+		assert.Equal(t, time.October, occ[3].Date.Month())
+	})
+
+	t.Run("through before start yields nothing", func(t *testing.T) {
+		assert.Empty(t, rent().Occurrences(month("12/2025")))
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("valid file", func(t *testing.T) {
+		path := filepath.Join(dir, "ok.json")
+		require.NoError(t, os.WriteFile(path, []byte(`[{"item":"Diarista","value":180,"day":10,
+			"frequency":"monthly","start":"02/2026","type":"Variáveis","category":"Casa","subcategory":"Diarista"}]`), 0o644))
+		schedules, err := Load(path)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		assert.Equal(t, "Diarista", schedules[0].Item)
+	})
+
+	cases := map[string]string{
+		"unknown frequency": `[{"item":"X","value":1,"day":1,"frequency":"weekly","start":"01/2026","type":"T","category":"C","subcategory":"S"}]`,
+		"bad day":           `[{"item":"X","value":1,"day":32,"frequency":"monthly","start":"01/2026","type":"T","category":"C","subcategory":"S"}]`,
+		"missing path":      `[{"item":"X","value":1,"day":1,"frequency":"monthly","start":"01/2026"}]`,
+		"end before start":  `[{"item":"X","value":1,"day":1,"frequency":"monthly","start":"05/2026","end":"01/2026","type":"T","category":"C","subcategory":"S"}]`,
+		"bad start":         `[{"item":"X","value":1,"day":1,"frequency":"monthly","start":"2026-01","type":"T","category":"C","subcategory":"S"}]`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "bad.json")
+			require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package report totals the expense log by month along one dimension — type,
+// category, subcategory, the account that paid or tag — answering questions the
+// workbook's taxonomy layout does not, such as what each card billed or what a
+// trip cost across categories.
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
+	"expense-reporter/pkg/utils"
+)
+
+// Dimensions a report can group by.
+const (
+	ByType        = "type"
+	ByCategory    = "category"
+	BySubcategory = "subcategory"
+	ByAccount     = "account"
+	ByTag         = "tag"
+)
+
+// Dimensions lists the accepted Options.By values; the first is the default.
+var Dimensions = []string{ByType, ByCategory, BySubcategory, ByAccount, ByTag}
+
+// Options selects what a report totals.
+type Options struct {
+	Year int    // the year reported; entries without a year never match
+	By   string // one of Dimensions; "" is ByType
+
+	// Account, when set, keeps only the entries paid with that account.
+	Account string
+
+	// Tag, when set, keeps only the entries carrying that tag (compared
+	// case-insensitively).
+	Tag string
+
+	// ClosingDays buckets each card's entries by the month of the invoice that
+	// bills them (see utils.InvoiceMonth) instead of the purchase month, so a
+	// card's row matches its invoices. Accounts without a closing day keep the
+	// purchase month.
+	ClosingDays map[string]int
+}
+
+// Row is one group's monthly totals. Key is the group's value; an empty key
+// groups the entries that do not record the dimension (e.g. no account). By
+// tag, an entry counts in the row of each of its tags, so rows can add up to
+// more than the total.
+type Row struct {
+	Key    string      `json:"key"`
+	Months [12]float64 `json:"months"`
+	Total  float64     `json:"total"`
+}
+
+// Table is a report: one row per group, largest total first, and the total of
+// every row.
+type Table struct {
+	Year    int    `json:"year"`
+	By      string `json:"by"`
+	Account string `json:"account,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+	Invoice bool   `json:"invoice,omitempty"` // months are invoice months
+	Rows    []Row  `json:"rows"`
+	Total   Row    `json:"total"`
+}
+
+// Build totals entries (the current state of the log: edits and voids already
+// resolved) per opts.
+func Build(entries []feedback.ExpenseEntry, opts Options) (Table, error) {
+	by := opts.By
+	if by == "" {
+		by = Dimensions[0]
+	}
+	key, err := keyFunc(by)
+	if err != nil {
+		return Table{}, err
+	}
+
+	t := Table{Year: opts.Year, By: by, Account: opts.Account, Tag: opts.Tag, Invoice: len(opts.ClosingDays) > 0}
+	groups := map[string]*Row{}
+	for _, e := range entries {
+		if opts.Account != "" && e.Account != opts.Account {
+			continue
+		}
+		if opts.Tag != "" && !tags.Has(e.Tags, opts.Tag) {
+			continue
+		}
+		k, ok := month(e, opts)
+		if !ok {
+			continue
+		}
+		for _, g := range key(e) {
+			row := groups[g]
+			if row == nil {
+				row = &Row{Key: g}
+				groups[g] = row
+			}
+			row.Months[k] += e.Value
+		}
+		t.Total.Months[k] += e.Value
+	}
+
+	for _, row := range groups {
+		row.finish()
+		t.Rows = append(t.Rows, *row)
+	}
+	sort.Slice(t.Rows, func(i, j int) bool {
+		if t.Rows[i].Total != t.Rows[j].Total {
+			return t.Rows[i].Total > t.Rows[j].Total
+		}
+		return t.Rows[i].Key < t.Rows[j].Key
+	})
+	t.Total.Key = "Total"
+	t.Total.finish()
+	return t, nil
+}
+
+// keyFunc returns the function naming the groups an entry counts under: one,
+// except by tag, where a tagged entry counts under each of its tags.
+func keyFunc(by string) (func(feedback.ExpenseEntry) []string, error) {
+	switch by {
+	case ByType:
+		return func(e feedback.ExpenseEntry) []string { return []string{e.Type} }, nil
+	case ByCategory:
+		return func(e feedback.ExpenseEntry) []string { return []string{e.Category} }, nil
+	case BySubcategory:
+		return func(e feedback.ExpenseEntry) []string { return []string{e.Subcategory} }, nil
+	case ByAccount:
+		return func(e feedback.ExpenseEntry) []string { return []string{e.Account} }, nil
+	case ByTag:
+		return func(e feedback.ExpenseEntry) []string {
+			if len(e.Tags) == 0 {
+				return []string{""}
+			}
+			return e.Tags
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown report dimension %q (want %s)", by, strings.Join(Dimensions, ", "))
+}
+
+// month returns the month index (0 = January) e is reported in; ok is false
+// when it falls outside opts.Year. A DD/MM date takes its year from when the
+// line was logged, as the workbook's accounts and tags sheets read it (see
+// utils.LoggedDate).
+func month(e feedback.ExpenseEntry, opts Options) (int, bool) {
+	t, err := utils.LoggedDate(e.Date, e.Timestamp, opts.Year)
+	if err != nil {
+		return 0, false
+	}
+	if day, ok := opts.ClosingDays[e.Account]; ok {
+		t = utils.InvoiceMonth(t, day)
+	}
+	if t.Year() != opts.Year {
+		return 0, false
+	}
+	return int(t.Month()) - 1, true
+}
+
+// finish rounds the months to cents and sums the total.
+func (r *Row) finish() {
+	r.Total = 0
+	for k, v := range r.Months {
+		r.Months[k] = math.Round(v*100) / 100
+		r.Total += r.Months[k]
+	}
+	r.Total = math.Round(r.Total*100) / 100
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"expense-reporter/internal/feedback"
+)
+
+func entry(item, date string, value float64, typ, account string) feedback.ExpenseEntry {
+	e := feedback.NewExpenseEntry(item, date, value, item, item)
+	e.Date = date // keep a yearless date as older builds logged it
+	e.Type = typ
+	e.Account = account
+	return e
+}
+
+func testEntries() []feedback.ExpenseEntry {
+	return []feedback.ExpenseEntry{
+		entry("Aluguel", "05/01/2026", 2500, "Fixas", "pix"),
+		entry("Uber", "04/01/2026", 30, "Variáveis", "nubank"),
+		entry("Padaria", "20/01/2026", 12.5, "Variáveis", "nubank"),
+		entry("Cinema", "28/12/2025", 60, "Extras", "nubank"),
+		entry("Feira", "10/02/2026", 80, "Variáveis", ""),
+		logged(entry("Antigo", "10/02", 99, "Variáveis", "nubank"), "2025-03-01T10:00:00Z"), // 10/02/2025
+	}
+}
+
+// logged sets when e was logged, which dates a DD/MM entry.
+func logged(e feedback.ExpenseEntry, timestamp string) feedback.ExpenseEntry {
+	e.Timestamp = timestamp
+	return e
+}
+
+func TestBuild_ByType(t *testing.T) {
+	table, err := Build(testEntries(), Options{Year: 2026})
+	require.NoError(t, err)
+
+	assert.Equal(t, ByType, table.By)
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, "Fixas", table.Rows[0].Key, "largest total first")
+	assert.Equal(t, "Variáveis", table.Rows[1].Key)
+	assert.Equal(t, 42.5, table.Rows[1].Months[0])
+	assert.Equal(t, 80.0, table.Rows[1].Months[1])
+	assert.Equal(t, 122.5, table.Rows[1].Total)
+	assert.Equal(t, 2622.5, table.Total.Total)
+}
+
+func TestBuild_ByAccountWithInvoices(t *testing.T) {
+	table, err := Build(testEntries(), Options{Year: 2026, By: ByAccount, ClosingDays: map[string]int{"nubank": 10}})
+	require.NoError(t, err)
+
+	assert.True(t, table.Invoice)
+	byKey := map[string]Row{}
+	for _, r := range table.Rows {
+		byKey[r.Key] = r
+	}
+	// Uber (04/01) is on January's invoice, Padaria (20/01) on February's, and
+	// the December purchase after the closing day on January's.
+	assert.Equal(t, 90.0, byKey["nubank"].Months[0])
+	assert.Equal(t, 12.5, byKey["nubank"].Months[1])
+	assert.Equal(t, 2500.0, byKey["pix"].Months[0], "accounts without a closing day keep the purchase month")
+	assert.Equal(t, 80.0, byKey[""].Total, "entries without an account are grouped under an empty key")
+}
+
+func TestBuild_AccountFilter(t *testing.T) {
+	table, err := Build(testEntries(), Options{Year: 2026, By: BySubcategory, Account: "nubank"})
+	require.NoError(t, err)
+
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, 42.5, table.Total.Total)
+}
+
+func TestBuild_ByTag(t *testing.T) {
+	entries := testEntries()
+	entries[1].Tags = []string{"trabalho"}
+	entries[2].Tags = []string{"viagem", "trabalho"}
+	entries[4].Tags = []string{"viagem"}
+
+	table, err := Build(entries, Options{Year: 2026, By: ByTag})
+	require.NoError(t, err)
+	byKey := map[string]Row{}
+	for _, r := range table.Rows {
+		byKey[r.Key] = r
+	}
+	assert.Equal(t, 42.5, byKey["trabalho"].Total)
+	assert.Equal(t, 92.5, byKey["viagem"].Total, "a tag totals across categories and months")
+	assert.Equal(t, 2500.0, byKey[""].Total, "untagged entries are grouped under an empty key")
+	assert.Equal(t, 2622.5, table.Total.Total, "an entry with several tags counts once in the total")
+
+	table, err = Build(entries, Options{Year: 2026, By: ByCategory, Tag: "Viagem"})
+	require.NoError(t, err)
+	assert.Equal(t, "Viagem", table.Tag)
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, 92.5, table.Total.Total)
+}
+
+func TestBuild_YearlessDates(t *testing.T) {
+	entries := []feedback.ExpenseEntry{
+		logged(entry("Ceia", "28/12", 300, "Extras", "nubank"), "2027-01-03T09:00:00Z"),
+		logged(entry("Feira", "10/02", 80, "Variáveis", ""), "2026-02-12T09:00:00Z"),
+	}
+	table, err := Build(entries, Options{Year: 2026})
+	require.NoError(t, err)
+	assert.Equal(t, 300.0, table.Total.Months[11], "logged on 03/01/2027, so last December")
+	assert.Equal(t, 80.0, table.Total.Months[1])
+}
+
+func TestBuild_UnknownDimension(t *testing.T) {
+	_, err := Build(nil, Options{Year: 2026, By: "merchant"})
+	assert.ErrorContains(t, err, `unknown report dimension "merchant"`)
+}
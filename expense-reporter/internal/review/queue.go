@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/tags"
 	"expense-reporter/pkg/utils"
 )
 
@@ -47,8 +48,10 @@ func ReadQueue(csvPath string) ([]QueueEntry, error) {
 			continue
 		}
 
-		if len(record) != 8 {
-			return nil, fmt.Errorf("line %d: expected 8 fields, got %d", lineNumber, len(record))
+		// The account (9th) and tags (10th) columns are optional: CSVs written
+		// before they existed have 8 or 9 fields.
+		if len(record) < 8 || len(record) > 10 {
+			return nil, fmt.Errorf("line %d: expected 8 to 10 fields, got %d", lineNumber, len(record))
 		}
 
 		item := strings.TrimSpace(record[0])
@@ -59,8 +62,19 @@ func ReadQueue(csvPath string) ([]QueueEntry, error) {
 		confidenceStr := strings.TrimSpace(record[5])
 		autoInsertedStr := strings.TrimSpace(record[6])
 		expenseType := strings.TrimSpace(record[7])
+		var account string
+		if len(record) >= 9 {
+			account = strings.TrimSpace(record[8])
+		}
+		var entryTags []string
+		if len(record) == 10 {
+			if entryTags, err = tags.Parse(record[9]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid tags: %w", lineNumber, err)
+			}
+		}
 
-		perInstallment, _, err := utils.ParseCurrencyWithInstallments(valueStr)
+		currency, amountStr := utils.SplitCurrencyCode(valueStr)
+		perInstallment, _, err := utils.ParseCurrencyWithInstallments(amountStr)
 		if err != nil {
 			return nil, fmt.Errorf("line %d: invalid value: %w", lineNumber, err)
 		}
@@ -80,15 +94,19 @@ func ReadQueue(csvPath string) ([]QueueEntry, error) {
 			return nil, fmt.Errorf("line %d: invalid auto_inserted value %q", lineNumber, autoInsertedStr)
 		}
 
-		// ID uses DD/MM date (no year) — stable within a review-to-apply cycle only
+		// batch-auto writes qualified dates; a DD/MM one from an older queue gets
+		// its year as feedback.GenerateID infers it.
 		entries = append(entries, QueueEntry{
 			ID:           feedback.GenerateID(item, date, perInstallment),
 			Item:         item,
 			Date:         date,
 			RawValue:     valueStr,
 			Value:        perInstallment,
+			Currency:     currency,
 			Confidence:   confidence,
 			AutoInserted: autoInserted,
+			Account:      account,
+			Tags:         entryTags,
 			Predicted: Predicted{
 				Category:    category,
 				Subcategory: subcategory,
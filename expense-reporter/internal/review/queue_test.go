@@ -50,6 +50,24 @@ func TestReadQueue(t *testing.T) {
 				assert.Equal(t, "Aluguel", e.Predicted.Subcategory)
 			},
 		},
+		{
+			name:       "account column populates Account",
+			csvContent: "item;date;value;subcategory;category;confidence;auto_inserted;type;account\nAluguel;05/01;2500,00;Aluguel;Moradia;0.95;0;Fixas;nubank",
+			wantCount:  1,
+			assertions: func(t *testing.T, entries []QueueEntry) {
+				assert.Equal(t, "nubank", entries[0].Account)
+				assert.Equal(t, "Fixas", entries[0].Predicted.Type)
+			},
+		},
+		{
+			name:       "tags column populates Tags",
+			csvContent: "item;date;value;subcategory;category;confidence;auto_inserted;type;account;tags\nPousada;12/01;800,00;Hospedagem;Viagens;0.70;0;Extras;nubank;viagem,floripa",
+			wantCount:  1,
+			assertions: func(t *testing.T, entries []QueueEntry) {
+				assert.Equal(t, []string{"viagem", "floripa"}, entries[0].Tags)
+				assert.Equal(t, "nubank", entries[0].Account)
+			},
+		},
 		{
 			name:      "blank lines skipped",
 			csvContent: "item;date;value;subcategory;category;confidence;auto_inserted;type\n\nUber Centro;15/05;35,50;Taxi;Transporte;0.95;1;\n\nUber Centro 2;16/05;40,00;Taxi;Transporte;0.90;0;\n\nUber Centro 3;17/05;45,00;Taxi;Transporte;0.85;1;",
@@ -64,6 +82,16 @@ func TestReadQueue(t *testing.T) {
 				assert.Equal(t, 125.0, entries[0].Value)
 			},
 		},
+		{
+			name:       "foreign currency value keeps original amount",
+			csvContent: "item;date;value;subcategory;category;confidence;auto_inserted;type\nSteam;17/04;USD 20,00/2;Jogos;Lazer;0.70;0;",
+			wantCount:  1,
+			assertions: func(t *testing.T, entries []QueueEntry) {
+				assert.Equal(t, "USD 20,00/2", entries[0].RawValue)
+				assert.Equal(t, "USD", entries[0].Currency)
+				assert.Equal(t, 10.0, entries[0].Value)
+			},
+		},
 		{
 			name:          "malformed confidence value",
 			csvContent:    "item;date;value;subcategory;category;confidence;auto_inserted;type\nTest Item;15/05;35,50;Taxi;Transporte;abc;1;",
@@ -80,7 +108,7 @@ func TestReadQueue(t *testing.T) {
 			name:          "wrong field count",
 			csvContent:    "item;date;value;subcategory;category;confidence;auto_inserted;type\nTest Item;15/05;35,50;Taxi;Transporte",
 			wantError:     true,
-			errorContains: "expected 8 fields",
+			errorContains: "expected 8 to 10 fields",
 		},
 		{
 			name:       "header only returns empty slice",
@@ -13,8 +13,11 @@ type QueueEntry struct {
 	Date         string    `json:"date"`
 	RawValue     string    `json:"rawValue"`
 	Value        float64   `json:"value"`
+	Currency     string    `json:"currency,omitempty"` // ISO code of Value; "" = BRL
 	Confidence   float64   `json:"confidence"`
 	AutoInserted bool      `json:"autoInserted"`
+	Account      string    `json:"account,omitempty"` // card or account that paid; "" = not recorded
+	Tags         []string  `json:"tags,omitempty"`
 	Predicted    Predicted `json:"predicted"`
 }
 
@@ -0,0 +1,153 @@
+// Package runs keeps the run journal (runs.jsonl): one record per batch-auto or
+// apply run, so everything a run appended to the logs can be found by its ID
+// and undone as a unit. The journal is append-only; a run's state changes
+// (completed, failed, reverted) are new lines, and the last line per ID wins.
+package runs
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/jsonlog"
+)
+
+// Run statuses. A run is recorded as running before it writes anything, so a
+// run that crashed midway still shows up and can be reverted.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusReverted  = "reverted"
+)
+
+// Run is one line in the run journal.
+type Run struct {
+	ID       string `json:"id"`
+	Command  string `json:"command"`            // "batch-auto" or "apply"
+	Source   string `json:"source,omitempty"`   // input CSV the run read
+	Workbook string `json:"workbook,omitempty"` // workbook the run wrote, if any
+	Backup   string `json:"backup,omitempty"`   // backup taken before writing the workbook
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"` // why a failed run stopped
+
+	Started    string `json:"started"`
+	Finished   string `json:"finished,omitempty"`
+	RevertedAt string `json:"reverted_at,omitempty"`
+	Timestamp  string `json:"timestamp"`
+}
+
+// NewID returns a fresh run ID: the UTC start time to the second plus four
+// random hex characters, so IDs sort by start time and stay unique.
+func NewID() string {
+	suffix := make([]byte, 2)
+	_, _ = rand.Read(suffix)
+	return feedback.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// Start returns the running record of a new run of command reading source.
+func Start(command, source string) Run {
+	now := stamp()
+	return Run{ID: NewID(), Command: command, Source: source, Status: StatusRunning, Started: now, Timestamp: now}
+}
+
+// Finish returns a copy of r completed, or failed with runErr.
+func (r Run) Finish(runErr error) Run {
+	r.Status = StatusCompleted
+	if runErr != nil {
+		r.Status = StatusFailed
+		r.Error = runErr.Error()
+	}
+	r.Finished = stamp()
+	r.Timestamp = r.Finished
+	return r
+}
+
+// Revert returns a copy of r marked reverted.
+func (r Run) Revert() Run {
+	r.Status = StatusReverted
+	r.RevertedAt = stamp()
+	r.Timestamp = r.RevertedAt
+	return r
+}
+
+// FinishedAt parses Finished; ok is false while the run has not finished.
+func (r Run) FinishedAt() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, r.Finished)
+	return t, err == nil
+}
+
+func stamp() string {
+	return feedback.Now().UTC().Format(time.RFC3339)
+}
+
+// Append writes run as a single JSON line to path (creates if absent).
+func Append(path string, run Run) error {
+	if _, err := jsonlog.AppendLine(path, run); err != nil {
+		return fmt.Errorf("appending run: %w", err)
+	}
+	return nil
+}
+
+// Load reads the journal at path and returns the latest record of each run,
+// most recent first. A missing file yields no runs.
+func Load(path string) ([]Run, error) {
+	f, err := jsonlog.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening run journal: %w", err)
+	}
+	defer f.Close()
+
+	latest := map[string]Run{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var r Run
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("parsing run line: %w", err)
+		}
+		latest[r.ID] = r
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading run journal: %w", err)
+	}
+
+	out := make([]Run, 0, len(latest))
+	for _, r := range latest {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID > out[j].ID })
+	return out, nil
+}
+
+// Find returns the run whose ID starts with prefix. It errors when no run or
+// more than one run matches.
+func Find(all []Run, prefix string) (Run, error) {
+	var matches []Run
+	for _, r := range all {
+		if strings.HasPrefix(r.ID, prefix) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Run{}, fmt.Errorf("no run with ID %q", prefix)
+	case 1:
+		return matches[0], nil
+	default:
+		return Run{}, fmt.Errorf("ID prefix %q matches %d runs — use more characters", prefix, len(matches))
+	}
+}
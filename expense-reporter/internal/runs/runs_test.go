@@ -0,0 +1,70 @@
+package runs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/feedback"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedNow(t *testing.T, at time.Time) {
+	orig := feedback.Now
+	feedback.Now = func() time.Time { return at }
+	t.Cleanup(func() { feedback.Now = orig })
+}
+
+func TestNewID_SortsByStartTime(t *testing.T) {
+	fixedNow(t, time.Date(2026, 10, 18, 14, 30, 5, 0, time.UTC))
+	id := NewID()
+	assert.Regexp(t, `^20261018T143005-[0-9a-f]{4}$`, id)
+
+	fixedNow(t, time.Date(2026, 10, 18, 14, 30, 6, 0, time.UTC))
+	assert.Greater(t, NewID(), id)
+}
+
+func TestLoad_LatestRecordPerRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	fixedNow(t, time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))
+	first := Start("batch-auto", "oct.csv")
+	require.NoError(t, Append(path, first))
+	require.NoError(t, Append(path, first.Finish(nil)))
+
+	fixedNow(t, time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC))
+	second := Start("apply", "review.csv")
+	require.NoError(t, Append(path, second))
+	require.NoError(t, Append(path, second.Finish(errors.New("workbook locked"))))
+	require.NoError(t, Append(path, first.Finish(nil).Revert()))
+
+	all, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, second.ID, all[0].ID, "most recent first")
+	assert.Equal(t, StatusFailed, all[0].Status)
+	assert.Equal(t, "workbook locked", all[0].Error)
+	assert.Equal(t, StatusReverted, all[1].Status)
+	assert.NotEmpty(t, all[1].Finished, "revert keeps the finish time")
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	all, err := Load(filepath.Join(t.TempDir(), "none.jsonl"))
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestFind(t *testing.T) {
+	all := []Run{{ID: "20261001T090000-ab12"}, {ID: "20261001T090000-cd34"}, {ID: "20261002T090000-ef56"}}
+
+	r, err := Find(all, "20261002")
+	require.NoError(t, err)
+	assert.Equal(t, "20261002T090000-ef56", r.ID)
+
+	_, err = Find(all, "20261001")
+	assert.ErrorContains(t, err, "matches 2 runs")
+	_, err = Find(all, "2025")
+	assert.ErrorContains(t, err, "no run")
+}
@@ -0,0 +1,361 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"expense-reporter/internal/jsonlog"
+)
+
+// indexSuffix names the sidecar next to the log: expenses_log.jsonl.idx.
+const indexSuffix = ".idx"
+
+// indexRow locates one log line: its byte range plus the fields lookups need,
+// so Get/Latest read a single line and Query reads only lines in its date range.
+type indexRow struct {
+	Offset int64  `json:"o"`
+	Length int64  `json:"n"` // bytes, excluding the newline
+	ID     string `json:"id"`
+	Date   string `json:"d"` // dateKey of the record's date
+}
+
+// Indexed is the backend for large logs: a sidecar index (path + ".idx") maps IDs
+// and dates to line offsets. The index is loaded once per process and kept in
+// step with the log: lines appended by other writers are indexed on the next
+// call, and a log that shrank or no longer matches is reindexed from scratch.
+// The log stays plain JSONL, so deleting the sidecar is always safe.
+type Indexed[T Record] struct {
+	path      string
+	indexPath string
+	rows      []indexRow
+	byID      map[string][]int // positions in rows, in log order
+	byDate    []int            // positions in rows sorted by date key; nil = not built yet
+	covered   int64            // log bytes the index accounts for
+}
+
+// OpenIndexed opens the log at path with its sidecar index, building or
+// repairing the index as needed.
+func OpenIndexed[T Record](path string) (*Indexed[T], error) {
+	s := &Indexed[T]{path: path, indexPath: path + indexSuffix}
+	if err := s.loadIndex(); err != nil {
+		return nil, err
+	}
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Append writes rec to the log and its location to the index.
+func (s *Indexed[T]) Append(rec T) error {
+	if err := s.sync(); err != nil {
+		return err
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling record: %w", err)
+	}
+	offset, err := jsonlog.AppendLine(s.path, rec)
+	if err != nil {
+		return err
+	}
+	if offset != s.covered {
+		// Another writer got in between sync and append; reindex the gap.
+		return s.sync()
+	}
+	row := indexRow{Offset: offset, Length: int64(len(line)), ID: rec.RecordID(), Date: dateKey(rec.RecordDate())}
+	if err := appendRows(s.indexPath, []indexRow{row}); err != nil {
+		return err
+	}
+	s.add(row)
+	return nil
+}
+
+// Get returns the first record with id.
+func (s *Indexed[T]) Get(id string) (T, bool, error) {
+	return s.byIDAt(id, func(positions []int) int { return positions[0] })
+}
+
+// Latest returns the last record with id.
+func (s *Indexed[T]) Latest(id string) (T, bool, error) {
+	return s.byIDAt(id, func(positions []int) int { return positions[len(positions)-1] })
+}
+
+func (s *Indexed[T]) byIDAt(id string, pick func([]int) int) (T, bool, error) {
+	var zero T
+	if err := s.sync(); err != nil {
+		return zero, false, err
+	}
+	positions := s.byID[id]
+	if len(positions) == 0 {
+		return zero, false, nil
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return zero, false, fmt.Errorf("opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+	rec, err := s.read(f, s.rows[pick(positions)])
+	if err != nil {
+		return zero, false, err
+	}
+	return rec, true, nil
+}
+
+// Query returns every record matching q, in log order. Only lines within the
+// date range are read from the log.
+func (s *Indexed[T]) Query(q Query) ([]T, error) {
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	candidates := s.candidates(q)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	var out []T
+	for _, pos := range candidates {
+		rec, err := s.read(f, s.rows[pos])
+		if err != nil {
+			return nil, err
+		}
+		if q.matchPath(rec) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+// candidates returns the positions of rows within q's date range, in log order.
+func (s *Indexed[T]) candidates(q Query) []int {
+	if q.From.IsZero() && q.To.IsZero() {
+		all := make([]int, len(s.rows))
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	if s.byDate == nil {
+		s.byDate = make([]int, len(s.rows))
+		for i := range s.byDate {
+			s.byDate[i] = i
+		}
+		sort.SliceStable(s.byDate, func(i, j int) bool { return s.rows[s.byDate[i]].Date < s.rows[s.byDate[j]].Date })
+	}
+	from := sort.Search(len(s.byDate), func(i int) bool {
+		return q.From.IsZero() || s.rows[s.byDate[i]].Date >= q.From.Format("2006-01-02")
+	})
+	var out []int
+	for _, pos := range s.byDate[from:] {
+		if !q.matchDate(s.rows[pos].Date) {
+			if !q.To.IsZero() && s.rows[pos].Date > q.To.Format("2006-01-02") {
+				break
+			}
+			continue
+		}
+		out = append(out, pos)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// read decodes the log line at row.
+func (s *Indexed[T]) read(f *os.File, row indexRow) (T, error) {
+	var rec T
+	buf := make([]byte, row.Length)
+	if _, err := f.ReadAt(buf, row.Offset); err != nil {
+		return rec, fmt.Errorf("reading %s at %d: %w", s.path, row.Offset, err)
+	}
+	if err := json.Unmarshal(buf, &rec); err != nil {
+		return rec, fmt.Errorf("parsing %s at %d: %w (index stale? delete %s)", s.path, row.Offset, err, s.indexPath)
+	}
+	return rec, nil
+}
+
+// loadIndex reads the sidecar. A missing or unreadable sidecar leaves the index
+// empty, so sync rebuilds it.
+func (s *Indexed[T]) loadIndex() error {
+	s.reset()
+	data, err := os.ReadFile(s.indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading index %s: %w", s.indexPath, err)
+	}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var row indexRow
+		if err := json.Unmarshal(line, &row); err != nil || row.Offset < s.covered {
+			s.reset()
+			return nil
+		}
+		s.add(row)
+	}
+	return nil
+}
+
+// sync brings the index in step with the log: indexes lines appended since the
+// last call, or rebuilds when the log shrank or its last indexed line moved.
+// A torn final line is quarantined first (see jsonlog.Repair).
+func (s *Indexed[T]) sync() error {
+	if _, err := jsonlog.Repair(s.path); err != nil {
+		return err
+	}
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("opening %s: %w", s.path, err)
+		}
+		if s.covered > 0 {
+			return s.rebuild()
+		}
+		return nil
+	}
+	switch {
+	case info.Size() < s.covered || !s.lastRowValid():
+		return s.rebuild()
+	case info.Size() > s.covered:
+		return s.indexFrom(s.covered)
+	}
+	return nil
+}
+
+// lastRowValid spot-checks the index against the log: its last row must still
+// decode to a record with the indexed ID.
+func (s *Indexed[T]) lastRowValid() bool {
+	if len(s.rows) == 0 {
+		return true
+	}
+	f, err := os.Open(s.path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	last := s.rows[len(s.rows)-1]
+	rec, err := s.read(f, last)
+	return err == nil && rec.RecordID() == last.ID
+}
+
+// rebuild discards the sidecar and indexes the whole log.
+func (s *Indexed[T]) rebuild() error {
+	s.reset()
+	if err := os.Remove(s.indexPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing index %s: %w", s.indexPath, err)
+	}
+	return s.indexFrom(0)
+}
+
+// indexFrom indexes every complete line from offset on and appends the rows to
+// the sidecar. A trailing line without a newline is left for a later call — a
+// writer that bypasses the lock may still be writing it.
+func (s *Indexed[T]) indexFrom(offset int64) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("reading %s: %w", s.path, err)
+	}
+
+	var rows []indexRow
+	r := bufio.NewReader(f)
+	pos := offset
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", s.path, err)
+		}
+		length := int64(len(line)) - 1
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var rec T
+			if err := json.Unmarshal(trimmed, &rec); err != nil {
+				return fmt.Errorf("parsing %s at %d: %w", s.path, pos, err)
+			}
+			rows = append(rows, indexRow{Offset: pos, Length: length, ID: rec.RecordID(), Date: dateKey(rec.RecordDate())})
+		}
+		pos += length + 1
+	}
+
+	if len(rows) > 0 {
+		if err := appendRows(s.indexPath, rows); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		s.add(row)
+	}
+	s.covered = pos
+	return nil
+}
+
+func appendRows(path string, rows []indexRow) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return fmt.Errorf("marshaling index row: %w", err)
+		}
+	}
+	unlock, err := jsonlog.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening index %s: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing index %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *Indexed[T]) reset() {
+	s.rows = nil
+	s.byID = map[string][]int{}
+	s.byDate = nil
+	s.covered = 0
+}
+
+// add records row in the in-memory index. Rows arrive in log order.
+func (s *Indexed[T]) add(row indexRow) {
+	pos := len(s.rows)
+	s.rows = append(s.rows, row)
+	s.byID[row.ID] = append(s.byID[row.ID], pos)
+	s.byDate = nil
+	if end := row.Offset + row.Length + 1; end > s.covered {
+		s.covered = end
+	}
+}
+
+// DropIndex deletes the sidecar index of the log at path, for callers that
+// rewrite the log in place; the next OpenIndexed rebuilds it.
+func DropIndex(path string) error {
+	if err := os.Remove(path + indexSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing index for %s: %w", path, err)
+	}
+	return nil
+}
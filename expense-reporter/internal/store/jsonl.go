@@ -0,0 +1,104 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"expense-reporter/internal/jsonlog"
+)
+
+// JSONL is the plain backend: every lookup scans the file from the start. It
+// needs no state besides the log itself, so it is always consistent with
+// writers that append to the file directly.
+type JSONL[T Record] struct {
+	path string
+}
+
+// NewJSONL returns the JSONL backend for the log at path. The file is created
+// on first Append; until then the log reads as empty.
+func NewJSONL[T Record](path string) *JSONL[T] {
+	return &JSONL[T]{path: path}
+}
+
+// Append writes rec as a single JSON line (creates the file if absent).
+func (s *JSONL[T]) Append(rec T) error {
+	_, err := jsonlog.AppendLine(s.path, rec)
+	return err
+}
+
+// Get returns the first record with id.
+func (s *JSONL[T]) Get(id string) (T, bool, error) {
+	var first T
+	found := false
+	err := s.scan(func(rec T) bool {
+		if rec.RecordID() == id {
+			first, found = rec, true
+			return false
+		}
+		return true
+	})
+	return first, found, err
+}
+
+// Latest returns the last record with id.
+func (s *JSONL[T]) Latest(id string) (T, bool, error) {
+	var latest T
+	found := false
+	err := s.scan(func(rec T) bool {
+		if rec.RecordID() == id {
+			latest, found = rec, true
+		}
+		return true
+	})
+	return latest, found, err
+}
+
+// Query returns every record matching q, in file order.
+func (s *JSONL[T]) Query(q Query) ([]T, error) {
+	var out []T
+	err := s.scan(func(rec T) bool {
+		if q.match(rec) {
+			out = append(out, rec)
+		}
+		return true
+	})
+	return out, err
+}
+
+// scan decodes each non-blank line in order until fn returns false. A missing
+// file scans as empty.
+func (s *JSONL[T]) scan(fn func(rec T) bool) error {
+	f, err := jsonlog.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("opening %s: %w", s.path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var rec T
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("parsing %s: %w", s.path, err)
+		}
+		if !fn(rec) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// maxLineBytes bounds one log line; records are small, so anything larger is corruption.
+const maxLineBytes = 1 << 20
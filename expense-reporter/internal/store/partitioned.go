@@ -0,0 +1,121 @@
+package store
+
+import (
+	"time"
+
+	"expense-reporter/internal/jsonlog"
+)
+
+// Partitioned spans a live log and the per-year archives close-year moved its
+// closed years to (see jsonlog.PartitionPath). Appends go to the live log;
+// lookups also search the archives, which are read-only, and a date-bounded
+// Query reads only the archives of the years it spans. Archives use the live
+// log's backend, so with Indexed a lookup that misses the live log reads each
+// archive's sidecar index rather than the whole archive. Each archive is
+// opened once per Partitioned.
+type Partitioned[T Record] struct {
+	path    string
+	backend string
+	live    Log[T]
+	opened  map[string]Log[T] // archive path → its log
+}
+
+// NewPartitioned wraps live, the log at path, with its archives, opened with
+// the named backend (see Open).
+func NewPartitioned[T Record](path, backend string, live Log[T]) *Partitioned[T] {
+	return &Partitioned[T]{path: path, backend: backend, live: live, opened: map[string]Log[T]{}}
+}
+
+// Append writes rec to the live log.
+func (s *Partitioned[T]) Append(rec T) error {
+	return s.live.Append(rec)
+}
+
+// Get returns the first record with id, searching the oldest archive first.
+func (s *Partitioned[T]) Get(id string) (T, bool, error) {
+	archives, err := s.archives(Query{})
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	for _, a := range archives {
+		if rec, found, err := a.Get(id); err != nil || found {
+			return rec, found, err
+		}
+	}
+	return s.live.Get(id)
+}
+
+// Latest returns the last record with id: from the live log when it has one,
+// else from the newest archive that does.
+func (s *Partitioned[T]) Latest(id string) (T, bool, error) {
+	if rec, found, err := s.live.Latest(id); err != nil || found {
+		return rec, found, err
+	}
+	archives, err := s.archives(Query{})
+	if err != nil {
+		var zero T
+		return zero, false, err
+	}
+	for i := len(archives) - 1; i >= 0; i-- {
+		if rec, found, err := archives[i].Latest(id); err != nil || found {
+			return rec, found, err
+		}
+	}
+	var zero T
+	return zero, false, nil
+}
+
+// Query returns every record matching q: the matching archives' records in
+// year order, then the live log's.
+func (s *Partitioned[T]) Query(q Query) ([]T, error) {
+	archives, err := s.archives(q)
+	if err != nil {
+		return nil, err
+	}
+	var out []T
+	for _, a := range archives {
+		recs, err := a.Query(q)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, recs...)
+	}
+	recs, err := s.live.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, recs...), nil
+}
+
+// archives returns the archives holding years within q's date range, oldest first.
+func (s *Partitioned[T]) archives(q Query) ([]Log[T], error) {
+	files, err := jsonlog.Files(s.path, yearOf(q.From), yearOf(q.To))
+	if err != nil {
+		return nil, err
+	}
+	archives := make([]Log[T], 0, len(files)-1)
+	for _, f := range files[:len(files)-1] {
+		a, ok := s.opened[f]
+		if !ok {
+			if s.backend == BackendIndexed {
+				if a, err = OpenIndexed[T](f); err != nil {
+					return nil, err
+				}
+			} else {
+				a = NewJSONL[T](f)
+			}
+			s.opened[f] = a
+		}
+		archives = append(archives, a)
+	}
+	return archives, nil
+}
+
+// yearOf returns t's year, or 0 (unbounded) for the zero time.
+func yearOf(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Year()
+}
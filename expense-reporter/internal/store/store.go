@@ -0,0 +1,132 @@
+// Package store reads and writes the append-only JSONL logs (expenses_log.jsonl,
+// classifications.jsonl, income_log.jsonl) behind one interface, so callers can
+// look records up by ID or query them by date and taxonomy path without caring
+// whether the backend scans the file (JSONL) or consults a sidecar index (Indexed).
+package store
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Backend names accepted by Open (config key "store").
+const (
+	BackendJSONL   = "jsonl"
+	BackendIndexed = "indexed"
+)
+
+// Record is implemented by every log line type the store handles.
+type Record interface {
+	// RecordID is the line's ID. IDs repeat when a record is superseded
+	// (e.g. a correction in classifications.jsonl); the last line wins.
+	RecordID() string
+	// RecordDate is the line's date, DD/MM/YYYY (or legacy DD/MM without a year).
+	RecordDate() string
+	// RecordPath is the taxonomy path the line is filed under.
+	RecordPath() (typ, category, subcategory string)
+}
+
+// Log is an append-only log of records of type T.
+type Log[T Record] interface {
+	// Append writes rec as the last line of the log.
+	Append(rec T) error
+	// Get returns the first record with id; found is false when there is none.
+	Get(id string) (rec T, found bool, err error)
+	// Latest returns the last record with id; found is false when there is none.
+	Latest(id string) (rec T, found bool, err error)
+	// Query returns every record matching q, in log order.
+	Query(q Query) ([]T, error)
+}
+
+// Query selects records by date range and taxonomy path. Zero fields match
+// everything; path fields match exactly (after Unicode normalization).
+type Query struct {
+	From, To    time.Time // inclusive date bounds; zero = unbounded
+	Type        string
+	Category    string
+	Subcategory string
+}
+
+// Open returns the log at path using the named backend ("" means JSONL),
+// spanning the archives of any closed years (see Partitioned).
+func Open[T Record](path, backend string) (Log[T], error) {
+	var live Log[T]
+	switch backend {
+	case "", BackendJSONL:
+		live = NewJSONL[T](path)
+	case BackendIndexed:
+		indexed, err := OpenIndexed[T](path)
+		if err != nil {
+			return nil, err
+		}
+		live = indexed
+	default:
+		return nil, fmt.Errorf("unknown store backend %q (want %s or %s)", backend, BackendJSONL, BackendIndexed)
+	}
+	return NewPartitioned(path, backend, live), nil
+}
+
+// dateKey converts a record date to a sortable "YYYY-MM-DD" key. Dates without
+// a year get year 0000, and unparsable dates an empty key; neither matches a
+// bounded date range.
+func dateKey(date string) string {
+	parts := strings.Split(strings.TrimSpace(date), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ""
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return ""
+		}
+		nums[i] = n
+	}
+	day, month, year := nums[0], nums[1], nums[2]
+	// Validate against a leap year so 29/02 without a year is accepted.
+	check := year
+	if len(parts) == 2 {
+		check = 2000
+	}
+	t := time.Date(check, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day || int(t.Month()) != month {
+		return ""
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+}
+
+// matchDate reports whether a record with date key k falls within q's range.
+func (q Query) matchDate(k string) bool {
+	if q.From.IsZero() && q.To.IsZero() {
+		return true
+	}
+	if k == "" || strings.HasPrefix(k, "0000") {
+		return false
+	}
+	if !q.From.IsZero() && k < q.From.Format("2006-01-02") {
+		return false
+	}
+	if !q.To.IsZero() && k > q.To.Format("2006-01-02") {
+		return false
+	}
+	return true
+}
+
+// matchPath reports whether rec is filed under q's path fields.
+func (q Query) matchPath(rec Record) bool {
+	typ, cat, sub := rec.RecordPath()
+	return matchField(q.Type, typ) && matchField(q.Category, cat) && matchField(q.Subcategory, sub)
+}
+
+func matchField(want, got string) bool {
+	return want == "" || norm.NFC.String(want) == norm.NFC.String(got)
+}
+
+// match reports whether rec satisfies q.
+func (q Query) match(rec Record) bool {
+	return q.matchDate(dateKey(rec.RecordDate())) && q.matchPath(rec)
+}
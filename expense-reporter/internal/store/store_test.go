@@ -0,0 +1,195 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"expense-reporter/internal/feedback"
+	"expense-reporter/internal/jsonlog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expense(item, date string, value float64, typ, cat, sub string) feedback.ExpenseEntry {
+	e := feedback.NewExpenseEntry(item, date, value, sub, cat)
+	e.Type = typ
+	return e
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func backends(t *testing.T) map[string]func(path string) Log[feedback.ExpenseEntry] {
+	return map[string]func(path string) Log[feedback.ExpenseEntry]{
+		BackendJSONL: func(path string) Log[feedback.ExpenseEntry] { return NewJSONL[feedback.ExpenseEntry](path) },
+		BackendIndexed: func(path string) Log[feedback.ExpenseEntry] {
+			s, err := OpenIndexed[feedback.ExpenseEntry](path)
+			require.NoError(t, err)
+			return s
+		},
+	}
+}
+
+// TestLogContract runs the same behavior checks against every backend.
+func TestLogContract(t *testing.T) {
+	for name, open := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+			s := open(path)
+
+			_, found, err := s.Get("missing")
+			require.NoError(t, err, "empty log reads as empty")
+			assert.False(t, found)
+
+			aluguel := expense("Aluguel", "05/01/2026", 2500, "Fixas", "Moradia", "Aluguel")
+			uber := expense("Uber", "17/02/2026", 35.5, "Variáveis", "Transporte", "Uber/Taxi")
+			mercado := expense("Mercado", "03/03/2026", 210, "Variáveis", "Alimentação", "Supermercado")
+			legacy := expense("Padaria", "10/02", 12, "", "Alimentação", "Padaria")
+			legacy.Date = "10/02" // as logged before dates were qualified
+			revised := aluguel
+			revised.Value = 2600
+			for _, e := range []feedback.ExpenseEntry{aluguel, uber, mercado, legacy, revised} {
+				require.NoError(t, s.Append(e))
+			}
+
+			got, found, err := s.Get(aluguel.ID)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, 2500.0, got.Value, "Get returns the first record")
+
+			got, found, err = s.Latest(aluguel.ID)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, 2600.0, got.Value, "Latest returns the last record")
+
+			all, err := s.Query(Query{})
+			require.NoError(t, err)
+			assert.Len(t, all, 5)
+
+			feb, err := s.Query(Query{From: day(2026, 2, 1), To: day(2026, 2, 28)})
+			require.NoError(t, err)
+			require.Len(t, feb, 1, "yearless dates never match a bounded range")
+			assert.Equal(t, "Uber", feb[0].Item)
+
+			fromFeb, err := s.Query(Query{From: day(2026, 2, 1)})
+			require.NoError(t, err)
+			assert.Len(t, fromFeb, 2)
+
+			food, err := s.Query(Query{Category: "Alimentação"})
+			require.NoError(t, err)
+			assert.Len(t, food, 2)
+
+			sub, err := s.Query(Query{Type: "Variáveis", Subcategory: "Uber/Taxi", To: day(2026, 12, 31)})
+			require.NoError(t, err)
+			require.Len(t, sub, 1)
+			assert.Equal(t, uber.ID, sub[0].ID)
+		})
+	}
+}
+
+func TestIndexed_CatchesUpExternalAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	s, err := OpenIndexed[feedback.ExpenseEntry](path)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(expense("Aluguel", "05/01/2026", 2500, "Fixas", "Moradia", "Aluguel")))
+
+	// Another writer appends straight to the file.
+	other := expense("Uber", "17/02/2026", 35.5, "Variáveis", "Transporte", "Uber/Taxi")
+	require.NoError(t, feedback.AppendExpense(path, other))
+
+	_, found, err := s.Get(other.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	// A fresh process reuses the sidecar.
+	_, err = os.Stat(path + indexSuffix)
+	require.NoError(t, err)
+	reopened, err := OpenIndexed[feedback.ExpenseEntry](path)
+	require.NoError(t, err)
+	assert.Len(t, reopened.rows, 2)
+}
+
+func TestIndexed_RebuildsWhenLogRewritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+	s, err := OpenIndexed[feedback.ExpenseEntry](path)
+	require.NoError(t, err)
+	first := expense("Aluguel", "05/01/2026", 2500, "Fixas", "Moradia", "Aluguel")
+	require.NoError(t, s.Append(first))
+	require.NoError(t, s.Append(expense("Uber", "17/02/2026", 35.5, "Variáveis", "Transporte", "Uber/Taxi")))
+
+	// Rewrite the log with different content of a different length.
+	replacement := expense("Mercado", "03/03/2026", 210, "Variáveis", "Alimentação", "Supermercado")
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, feedback.AppendExpense(path, replacement))
+
+	_, found, err := s.Get(first.ID)
+	require.NoError(t, err)
+	assert.False(t, found, "stale index rows are dropped")
+	got, found, err := s.Get(replacement.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "Mercado", got.Item)
+}
+
+func TestPartitioned_SpansArchives(t *testing.T) {
+	for _, backend := range []string{BackendJSONL, BackendIndexed} {
+		t.Run(backend, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "expenses_log.jsonl")
+			old := expense("Aluguel", "05/12/2025", 2500, "Fixas", "Moradia", "Aluguel")
+			require.NoError(t, feedback.AppendExpense(jsonlog.PartitionPath(path, 2025), old))
+
+			s, err := Open[feedback.ExpenseEntry](path, backend)
+			require.NoError(t, err)
+			uber := expense("Uber", "17/02/2026", 35.5, "Variáveis", "Transporte", "Uber/Taxi")
+			revised := old
+			revised.Value = 2600
+			require.NoError(t, s.Append(uber))
+			require.NoError(t, s.Append(revised))
+
+			got, found, err := s.Get(old.ID)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, 2500.0, got.Value, "Get finds the archived line first")
+			got, _, err = s.Latest(old.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 2600.0, got.Value, "a later line in the live log wins")
+			_, found, err = s.Latest("missing")
+			require.NoError(t, err)
+			assert.False(t, found)
+			_, err = os.Stat(jsonlog.PartitionPath(path, 2025) + indexSuffix)
+			assert.Equal(t, backend == BackendIndexed, err == nil, "an indexed log indexes its archives too")
+
+			all, err := s.Query(Query{})
+			require.NoError(t, err)
+			assert.Len(t, all, 3)
+			y2026, err := s.Query(Query{From: day(2026, 1, 1), To: day(2026, 12, 31)})
+			require.NoError(t, err)
+			require.Len(t, y2026, 1, "the 2025 archive is skipped")
+			assert.Equal(t, "Uber", y2026[0].Item)
+			y2025, err := s.Query(Query{From: day(2025, 1, 1), To: day(2025, 12, 31)})
+			require.NoError(t, err)
+			assert.Len(t, y2025, 2, "lines for a closed year may still land in the live log")
+		})
+	}
+}
+
+func TestOpen_UnknownBackend(t *testing.T) {
+	_, err := Open[feedback.ExpenseEntry]("x.jsonl", "sqlite")
+	assert.ErrorContains(t, err, "unknown store backend")
+}
+
+func TestIncomeEntry_DerivedID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "income_log.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(
+		`{"date":"05/01/2026","value":1000.0,"income_category":"Salário","income_label":"Base","item_note":"Janeiro"}`+"\n"), 0o644))
+
+	s, err := OpenIndexed[feedback.IncomeEntry](path)
+	require.NoError(t, err)
+	_, found, err := s.Get(feedback.GenerateID("Janeiro", "05/01/2026", 1000))
+	require.NoError(t, err)
+	assert.True(t, found)
+}
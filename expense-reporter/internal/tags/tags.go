@@ -0,0 +1,179 @@
+// Package tags handles the free-form tags an expense may carry ("viagem-floripa",
+// "casamento", "reembolsavel"): questions that cut across the taxonomy. Tags are
+// set by hand (add --tag, a batch column, the review page) or by the tag rules
+// in config, which tag every expense they match as it is logged.
+package tags
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"expense-reporter/pkg/utils"
+)
+
+// Normalize trims tags and drops empty ones and repeats (case-insensitively,
+// keeping the first spelling). A tag may not hold a comma or a semicolon, the
+// separators of the batch column. The result is nil when no tag remains.
+func Normalize(tags []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if strings.ContainsAny(t, ",;") {
+			return nil, fmt.Errorf("tag %q may not contain a comma or semicolon", t)
+		}
+		if key := strings.ToLower(t); !seen[key] {
+			seen[key] = true
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// Parse reads a comma-separated tag list ("viagem, floripa") as Normalize does.
+func Parse(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return Normalize(strings.Split(s, ","))
+}
+
+// Join writes tags as the comma-separated list Parse reads.
+func Join(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// Merge returns the tags of a followed by those of b not already in a,
+// compared case-insensitively. Both must already be normalized.
+func Merge(a, b []string) []string {
+	out := append([]string(nil), a...)
+	for _, t := range b {
+		if !Has(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Without returns tags minus those in drop, compared case-insensitively; nil
+// when none is left.
+func Without(tags, drop []string) []string {
+	var out []string
+	for _, t := range tags {
+		if !Has(drop, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// Has reports whether tags holds tag, compared case-insensitively.
+func Has(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expense is what a rule looks at: an expense as it is logged, before its
+// installments are expanded, so every installment of a purchase gets the tags
+// the purchase matched.
+type Expense struct {
+	Item        string
+	Date        time.Time
+	Type        string
+	Category    string
+	Subcategory string
+	Account     string
+}
+
+// Rule tags the expenses matching every condition it sets. Item matches a
+// part of the item description; Type, Category, Subcategory and Account match
+// whole names; From and To (DD/MM/YYYY) bound the date, both inclusive. All
+// comparisons ignore case. A rule must set at least one condition.
+type Rule struct {
+	Tags        []string `json:"tags"`
+	Item        string   `json:"item,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Subcategory string   `json:"subcategory,omitempty"`
+	Account     string   `json:"account,omitempty"`
+	From        string   `json:"from,omitempty"`
+	To          string   `json:"to,omitempty"`
+}
+
+// Rules is the "tag_rules" list of config.
+type Rules []Rule
+
+// Validate checks every rule, so a malformed one fails the command instead of
+// silently tagging nothing (or everything).
+func (rs Rules) Validate() error {
+	for i, r := range rs {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("tag rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	tags, err := Normalize(r.Tags)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags")
+	}
+	if r.Item == "" && r.Type == "" && r.Category == "" && r.Subcategory == "" && r.Account == "" && r.From == "" && r.To == "" {
+		return fmt.Errorf("no condition: set item, type, category, subcategory, account, from or to")
+	}
+	for _, d := range []string{r.From, r.To} {
+		if d == "" {
+			continue
+		}
+		if strings.Count(d, "/") != 2 {
+			return fmt.Errorf("date %q must be DD/MM/YYYY", d)
+		}
+		if _, err := utils.ParseDateFlexible(d); err != nil {
+			return fmt.Errorf("date %q: %w", d, err)
+		}
+	}
+	return nil
+}
+
+// Match returns the tags of every rule e matches, in rule order. The rules
+// must be valid (see Validate).
+func (rs Rules) Match(e Expense) []string {
+	var out []string
+	for _, r := range rs {
+		if r.matches(e) {
+			tags, _ := Normalize(r.Tags)
+			out = Merge(out, tags)
+		}
+	}
+	return out
+}
+
+func (r Rule) matches(e Expense) bool {
+	if r.Item != "" && !strings.Contains(strings.ToLower(e.Item), strings.ToLower(r.Item)) {
+		return false
+	}
+	for _, f := range [][2]string{{r.Type, e.Type}, {r.Category, e.Category}, {r.Subcategory, e.Subcategory}, {r.Account, e.Account}} {
+		if f[0] != "" && !strings.EqualFold(f[0], f[1]) {
+			return false
+		}
+	}
+	if from, err := utils.ParseDateFlexible(r.From); r.From != "" && (err != nil || e.Date.Before(from)) {
+		return false
+	}
+	if to, err := utils.ParseDateFlexible(r.To); r.To != "" && (err != nil || e.Date.After(to)) {
+		return false
+	}
+	return true
+}
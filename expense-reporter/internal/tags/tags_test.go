@@ -0,0 +1,74 @@
+package tags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize([]string{" viagem ", "", "Floripa", "VIAGEM"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viagem", "Floripa"}, got)
+
+	got, err = Normalize([]string{" "})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = Normalize([]string{"a;b"})
+	assert.ErrorContains(t, err, `tag "a;b" may not contain a comma or semicolon`)
+}
+
+func TestParseAndJoin(t *testing.T) {
+	got, err := Parse("viagem, floripa,,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"viagem", "floripa"}, got)
+	assert.Equal(t, "viagem,floripa", Join(got))
+
+	got, err = Parse("")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestMerge(t *testing.T) {
+	assert.Equal(t, []string{"viagem", "casamento"}, Merge([]string{"viagem"}, []string{"Viagem", "casamento"}))
+	assert.Nil(t, Merge(nil, nil))
+}
+
+func TestWithout(t *testing.T) {
+	assert.Equal(t, []string{"casa"}, Without([]string{"viagem", "casa"}, []string{"VIAGEM"}))
+	assert.Nil(t, Without([]string{"viagem"}, []string{"viagem"}))
+}
+
+func TestRules_Match(t *testing.T) {
+	rules := Rules{
+		{Tags: []string{"viagem-floripa"}, From: "10/01/2026", To: "20/01/2026"},
+		{Tags: []string{"reembolsavel"}, Item: "uber", Account: "empresa"},
+		{Tags: []string{"casa", "viagem-floripa"}, Subcategory: "aluguel"},
+	}
+	require.NoError(t, rules.Validate())
+	date := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	assert.Equal(t, []string{"viagem-floripa", "reembolsavel"},
+		rules.Match(Expense{Item: "Uber Aeroporto", Date: date(20), Account: "Empresa"}))
+	assert.Nil(t, rules.Match(Expense{Item: "Uber Centro", Date: date(21), Account: "nubank"}))
+	assert.Equal(t, []string{"casa", "viagem-floripa"},
+		rules.Match(Expense{Item: "Aluguel", Date: date(5), Subcategory: "Aluguel"}))
+}
+
+func TestRules_Validate(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Item: "uber"}, "tag rule 1: no tags"},
+		{Rule{Tags: []string{"x"}}, "tag rule 1: no condition"},
+		{Rule{Tags: []string{"x"}, From: "10/01"}, `tag rule 1: date "10/01" must be DD/MM/YYYY`},
+		{Rule{Tags: []string{"a,b"}, Item: "uber"}, "may not contain a comma"},
+	}
+	for _, tt := range tests {
+		assert.ErrorContains(t, Rules{tt.rule}.Validate(), tt.want)
+	}
+}
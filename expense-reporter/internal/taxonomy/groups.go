@@ -0,0 +1,107 @@
+package taxonomy
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"time"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/pkg/utils"
+)
+
+// Group is one paying account or tag with its expenses per month (index 0 =
+// Janeiro). Name is "" for the entries that record no account.
+type Group struct {
+	Name   string
+	Months [12]float64
+}
+
+// LoadAccounts totals the expenses_log.jsonl entries of targetYear per paying
+// account. Entries are totalled whether or not the taxonomy routes them, since
+// a card invoice bills every purchase; those in exclude are left out, and a
+// date without a year is dated as report dates it (see loadGroups).
+//
+// closingDays files each card's entries under the month of the invoice that
+// bills them (see utils.InvoiceMonth), so a card's months match its invoices;
+// the previous year's archive is then read too, for the December purchases
+// billed in January. Accounts are returned by name, the unnamed one last.
+func LoadAccounts(entriesPath string, targetYear int, closingDays map[string]int, exclude map[string]bool) ([]Group, error) {
+	from := targetYear
+	if len(closingDays) > 0 {
+		from--
+	}
+	groups, err := loadGroups(entriesPath, from, targetYear, exclude, func(e logEntry, date time.Time) (time.Time, []string) {
+		return utils.InvoiceMonth(date, closingDays[e.Account]), []string{e.Account}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading accounts: %w", err)
+	}
+	return groups, nil
+}
+
+// LoadTags totals the expenses_log.jsonl entries of targetYear per tag, by
+// purchase month, like LoadAccounts without closing days. An entry counts under
+// each of its tags, so tags can add up to more than was spent; untagged entries
+// are left out. Tags are returned by name.
+func LoadTags(entriesPath string, targetYear int, exclude map[string]bool) ([]Group, error) {
+	groups, err := loadGroups(entriesPath, targetYear, targetYear, exclude, func(e logEntry, date time.Time) (time.Time, []string) {
+		return date, e.Tags
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading tags: %w", err)
+	}
+	return groups, nil
+}
+
+// loadGroups totals the entries of the from..targetYear archives outside
+// exclude under the groups key names, in the month key files them in given the
+// entry's date; only the months of targetYear are kept. A DD/MM date takes its
+// year from when the line was logged, or targetYear for a line without a
+// timestamp (see utils.LoggedDate). Groups are sorted by name, "" last.
+func loadGroups(entriesPath string, from, targetYear int, exclude map[string]bool, key func(logEntry, time.Time) (time.Time, []string)) ([]Group, error) {
+	file, err := jsonlog.OpenYears(entriesPath, from, targetYear)
+	if err != nil {
+		return nil, fmt.Errorf("opening entries file: %w", err)
+	}
+	defer file.Close()
+	entries, err := readLogEntries(bufio.NewScanner(file))
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]*Group{}
+	for _, entry := range entries {
+		if exclude[entry.ID] {
+			continue
+		}
+		date, err := utils.LoggedDate(entry.Date, entry.Timestamp, targetYear)
+		if err != nil {
+			return nil, fmt.Errorf("parsing date for item %q: %w", entry.Item, err)
+		}
+		billed, names := key(entry, date)
+		if billed.Year() != targetYear {
+			continue
+		}
+		for _, name := range names {
+			g := byName[name]
+			if g == nil {
+				g = &Group{Name: name}
+				byName[name] = g
+			}
+			g.Months[billed.Month()-1] += entry.Value
+		}
+	}
+
+	groups := make([]Group, 0, len(byName))
+	for _, g := range byName {
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Name == "") != (groups[j].Name == "") {
+			return groups[j].Name == ""
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups, nil
+}
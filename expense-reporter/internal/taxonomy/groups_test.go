@@ -0,0 +1,63 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAccounts(t *testing.T) {
+	logPath := writeTempFile(t, "expenses_log.jsonl", `{"schema":3,"id":"a","item":"Uber","date":"04/01/2026","value":30,"subcategory":"Uber","account":"nubank"}
+{"schema":3,"id":"b","item":"Padaria","date":"20/01/2026","value":12.5,"subcategory":"Padaria","account":"nubank"}
+{"schema":3,"id":"c","item":"Cinema","date":"28/12/2025","value":60,"subcategory":"Cinema","account":"nubank"}
+{"schema":3,"id":"d","item":"Aluguel","date":"05/01","value":2500,"subcategory":"Aluguel","account":"pix"}
+{"schema":3,"id":"e","item":"Feira","date":"10/02/2026","value":80,"subcategory":"Feira"}
+{"schema":3,"id":"f","item":"Parcela","date":"15/03/2026","value":100,"subcategory":"Loja","account":"nubank"}
+{"schema":3,"id":"b","op":"edit","item":"Padaria","date":"20/01/2026","value":15,"subcategory":"Padaria","account":"nubank"}
+`)
+
+	accounts, err := LoadAccounts(logPath, 2026, map[string]int{"nubank": 10}, map[string]bool{"f": true})
+	require.NoError(t, err)
+	require.Len(t, accounts, 3)
+
+	assert.Equal(t, "nubank", accounts[0].Name)
+	assert.Equal(t, 90.0, accounts[0].Months[0], "04/01 and the December purchase after closing are on January's invoice")
+	assert.Equal(t, 15.0, accounts[0].Months[1], "the edited value, billed on February's invoice")
+	assert.Zero(t, accounts[0].Months[2], "excluded entries are left out")
+	assert.Equal(t, "pix", accounts[1].Name)
+	assert.Equal(t, 2500.0, accounts[1].Months[0], "a date without a year counts as the target year")
+	assert.Equal(t, "", accounts[2].Name, "entries without an account come last")
+	assert.Equal(t, 80.0, accounts[2].Months[1])
+}
+
+func TestLoadAccounts_PurchaseMonths(t *testing.T) {
+	logPath := writeTempFile(t, "expenses_log.jsonl", `{"schema":3,"id":"a","item":"Padaria","date":"20/01/2026","value":12.5,"subcategory":"Padaria","account":"nubank"}
+{"schema":3,"id":"b","item":"Cinema","date":"28/12/2025","value":60,"subcategory":"Cinema","account":"nubank"}
+{"schema":3,"id":"c","item":"Ceia","date":"28/12","value":300,"subcategory":"Restaurante","account":"nubank","timestamp":"2026-01-03T09:00:00Z"}
+{"schema":3,"id":"d","item":"Uber","date":"15/01","value":20,"subcategory":"Uber","account":"nubank","timestamp":"2026-01-16T09:00:00Z"}
+`)
+
+	accounts, err := LoadAccounts(logPath, 2026, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, 32.5, accounts[0].Months[0], "a DD/MM date takes the year it was logged in")
+	assert.Zero(t, accounts[0].Months[11], "28/12 logged on 03/01/2026 is last December")
+}
+
+func TestLoadTags(t *testing.T) {
+	logPath := writeTempFile(t, "expenses_log.jsonl", `{"schema":3,"id":"a","item":"Pousada","date":"12/01/2026","value":800,"subcategory":"Hospedagem","tags":["viagem"]}
+{"schema":3,"id":"b","item":"Uber","date":"13/01/2026","value":40,"subcategory":"Uber","tags":["viagem","trabalho"]}
+{"schema":3,"id":"c","item":"Feira","date":"10/02/2026","value":80,"subcategory":"Feira"}
+{"schema":3,"id":"d","item":"Cinema","date":"28/12/2025","value":60,"subcategory":"Cinema","tags":["viagem"]}
+{"schema":3,"id":"e","item":"Jantar","date":"14/01","value":120,"subcategory":"Restaurante","tags":["viagem"]}
+`)
+
+	groups, err := LoadTags(logPath, 2026, map[string]bool{"e": true})
+	require.NoError(t, err)
+	require.Len(t, groups, 2, "untagged entries are left out")
+	assert.Equal(t, "trabalho", groups[0].Name)
+	assert.Equal(t, 40.0, groups[0].Months[0])
+	assert.Equal(t, "viagem", groups[1].Name)
+	assert.Equal(t, 840.0, groups[1].Months[0], "an entry counts under each of its tags")
+}
@@ -0,0 +1,32 @@
+package taxonomy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveIncomeLeaf finds the revenue leaf named by spec: "Block/Label", or a
+// bare label when only one block has it (a flat block's label is its own
+// name). Matching is case-insensitive.
+func ResolveIncomeLeaf(blocks []RevenueBlock, spec string) (RevenueBlock, error) {
+	want := strings.ToLower(normalizeKey(strings.TrimSpace(spec)))
+	var matches []RevenueBlock
+	for _, b := range blocks {
+		label := strings.ToLower(normalizeKey(b.Label))
+		full := strings.ToLower(normalizeKey(b.Block)) + "/" + label
+		if want == full || want == label {
+			matches = append(matches, b)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return RevenueBlock{}, fmt.Errorf("income line %q is not in the taxonomy's incomeCategories", spec)
+	}
+	paths := make([]string, len(matches))
+	for i, m := range matches {
+		paths[i] = m.Block + "/" + m.Label
+	}
+	return RevenueBlock{}, fmt.Errorf("income line %q is ambiguous (%s)\n  Hint: name it as Block/Label", spec, strings.Join(paths, ", "))
+}
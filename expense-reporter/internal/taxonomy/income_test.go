@@ -216,3 +216,29 @@ func buildByPath(t *testing.T, blocks []RevenueBlock) map[string]subcatTarget {
 func writeFile(path, content string) error {
 	return os.WriteFile(path, []byte(content), 0600)
 }
+
+// TestResolveIncomeLeaf verifies add-income's line lookup: "Block/Label" or a
+// bare label, case-insensitive, with an ambiguous bare label refused.
+func TestResolveIncomeLeaf(t *testing.T) {
+	blocks := []RevenueBlock{
+		{Block: "Salário", Label: "Salário"},
+		{Block: "Salário", Label: "INSS"},
+		{Block: "13°", Label: "INSS"},
+		{Block: "Dividendos", Label: "Dividendos"},
+	}
+
+	leaf, err := ResolveIncomeLeaf(blocks, "salário/inss")
+	require.NoError(t, err)
+	assert.Equal(t, "Salário", leaf.Block)
+	assert.Equal(t, "INSS", leaf.Label)
+
+	leaf, err = ResolveIncomeLeaf(blocks, " Dividendos ")
+	require.NoError(t, err)
+	assert.Equal(t, "Dividendos", leaf.Block)
+
+	_, err = ResolveIncomeLeaf(blocks, "INSS")
+	assert.ErrorContains(t, err, "ambiguous (Salário/INSS, 13°/INSS)")
+
+	_, err = ResolveIncomeLeaf(blocks, "Aluguel")
+	assert.ErrorContains(t, err, "not in the taxonomy's incomeCategories")
+}
@@ -0,0 +1,121 @@
+package taxonomy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+)
+
+// AssetClass is one investment asset class declared in the taxonomy
+// ("investmentClasses"), with the ledger's entries per month. Entry values are
+// signed as money leaving the month's budget: contributions are negative,
+// withdrawals positive.
+type AssetClass struct {
+	Name   string
+	Months [12][]Entry
+}
+
+// Net returns the class's signed total for month k (0 = Janeiro).
+func (a AssetClass) Net(k int) float64 {
+	var sum float64
+	for _, e := range a.Months[k] {
+		sum += e.Value
+	}
+	return sum
+}
+
+// LoadInvestments reads the asset classes declared in the taxonomy file and,
+// unless investmentsPath is "", routes the investments_log.jsonl entries into
+// them. targetYear filters entries as in LoadTaxonomy. A taxonomy without
+// "investmentClasses" yields no classes.
+func LoadInvestments(taxonomyPath, investmentsPath string, targetYear int) ([]AssetClass, error) {
+	data, err := os.ReadFile(taxonomyPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading taxonomy file: %w", err)
+	}
+	var raw struct {
+		InvestmentClasses []string `json:"investmentClasses"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing taxonomy JSON: %w", err)
+	}
+
+	classes := make([]AssetClass, len(raw.InvestmentClasses))
+	idx := make(map[string]*AssetClass, len(classes))
+	for i, name := range raw.InvestmentClasses {
+		key := normalizeKey(name)
+		if _, dup := idx[key]; dup {
+			return nil, fmt.Errorf("loading taxonomy: investment class %q appears more than once", name)
+		}
+		classes[i] = AssetClass{Name: name}
+		idx[key] = &classes[i]
+	}
+
+	if investmentsPath == "" {
+		return classes, nil
+	}
+	file, err := jsonlog.OpenYears(investmentsPath, targetYear, targetYear)
+	if err != nil {
+		return nil, fmt.Errorf("opening investments file: %w", err)
+	}
+	defer file.Close()
+	if err := scanInvestmentEntries(bufio.NewScanner(file), idx, targetYear); err != nil {
+		return nil, fmt.Errorf("loading investments: %w", err)
+	}
+	return classes, nil
+}
+
+// scanInvestmentEntries routes each investments_log.jsonl line into its asset
+// class and month. Lines naming an undeclared class are skipped with a warning.
+func scanInvestmentEntries(scanner *bufio.Scanner, idx map[string]*AssetClass, targetYear int) error {
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		upgraded, _, err := logschema.Investments.Upgrade([]byte(line))
+		if err != nil {
+			return err
+		}
+
+		var row struct {
+			Item       string  `json:"item"`
+			Date       string  `json:"date"`
+			Value      float64 `json:"value"`
+			Kind       string  `json:"kind"`
+			AssetClass string  `json:"asset_class"`
+		}
+		if err := json.Unmarshal(upgraded, &row); err != nil {
+			return fmt.Errorf("parsing investment entry line: %w", err)
+		}
+
+		class, ok := idx[normalizeKey(row.AssetClass)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "skipping investment %q: asset class %q not in taxonomy\n", row.Item, row.AssetClass)
+			continue
+		}
+		day, month, entryYear, err := parseDate(row.Date)
+		if err != nil {
+			return fmt.Errorf("investment %q: %w", row.Item, err)
+		}
+		if entryYear != 0 && targetYear != 0 && entryYear != targetYear {
+			continue
+		}
+
+		value := row.Value
+		switch row.Kind {
+		case "contribution":
+			value = -value
+		case "withdrawal":
+		default:
+			return fmt.Errorf("investment %q: unknown kind %q", row.Item, row.Kind)
+		}
+		class.Months[month-1] = append(class.Months[month-1], Entry{Item: row.Item, Day: day, Value: value, Year: entryYear})
+	}
+	return scanner.Err()
+}
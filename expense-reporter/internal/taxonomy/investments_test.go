@@ -0,0 +1,44 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadInvestments(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{
+    "types": [],
+    "investmentClasses": ["Renda fixa", "Ações"]
+}`)
+	logPath := writeTempFile(t, "investments_log.jsonl", `{"schema":1,"id":"a","item":"Tesouro Selic","date":"05/01/2026","value":1000,"kind":"contribution","asset_class":"Renda fixa"}
+{"schema":1,"id":"b","item":"CDB","date":"12/01/2026","value":500,"kind":"contribution","asset_class":"Renda fixa"}
+{"schema":1,"id":"c","item":"Venda BOVA11","date":"20/03/2026","value":300,"kind":"withdrawal","asset_class":"Ações"}
+{"schema":1,"id":"d","item":"Tesouro IPCA","date":"05/01/2025","value":800,"kind":"contribution","asset_class":"Renda fixa"}
+{"schema":1,"id":"e","item":"Bitcoin","date":"07/02/2026","value":200,"kind":"contribution","asset_class":"Cripto"}
+`)
+
+	classes, err := LoadInvestments(taxonomyPath, logPath, 2026)
+	require.NoError(t, err)
+	require.Len(t, classes, 2)
+	assert.Equal(t, "Renda fixa", classes[0].Name)
+	assert.Equal(t, -1500.0, classes[0].Net(0), "contributions are negative; the 2025 line is filtered out")
+	assert.Equal(t, "Ações", classes[1].Name)
+	assert.Equal(t, 300.0, classes[1].Net(2))
+	assert.Zero(t, classes[1].Net(1), "the undeclared Cripto line is skipped")
+}
+
+func TestLoadInvestments_NoClasses(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{"types": []}`)
+	classes, err := LoadInvestments(taxonomyPath, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, classes)
+}
+
+func TestLoadInvestments_DuplicateClassRejected(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{"investmentClasses": ["Ações", "Ações"]}`)
+	_, err := LoadInvestments(taxonomyPath, "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `investment class "Ações" appears more than once`)
+}
@@ -8,6 +8,10 @@ import (
 	"strconv"
 	"strings"
 
+	"expense-reporter/internal/jsonlog"
+	"expense-reporter/internal/logschema"
+	"expense-reporter/pkg/utils"
+
 	"golang.org/x/text/unicode/norm"
 )
 
@@ -25,6 +29,13 @@ func normalizeKey(s string) string {
 // Pass 0 to keep all entries regardless of year (legacy single-year log behavior).
 // Pass "" for entriesPath or incomeEntriesPath to skip loading that file.
 func LoadTaxonomy(taxonomyPath, entriesPath, incomeEntriesPath string, targetYear int) ([]ExpenseType, []RevenueBlock, error) {
+	return LoadTaxonomyExcluding(taxonomyPath, entriesPath, incomeEntriesPath, targetYear, nil)
+}
+
+// LoadTaxonomyExcluding is LoadTaxonomy, skipping expense entries whose ID is in
+// exclude — log lines that stay in the append-only log but no longer count
+// (e.g. installments voided by a cancelled plan).
+func LoadTaxonomyExcluding(taxonomyPath, entriesPath, incomeEntriesPath string, targetYear int, exclude map[string]bool) ([]ExpenseType, []RevenueBlock, error) {
 	types, incomeBlocks, err := loadTaxonomyFile(taxonomyPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("loading taxonomy: %w", err)
@@ -39,7 +50,7 @@ func LoadTaxonomy(taxonomyPath, entriesPath, incomeEntriesPath string, targetYea
 	}
 
 	if entriesPath != "" {
-		if err := loadEntries(entriesPath, byPath, byName, ambiguous, targetYear); err != nil {
+		if err := loadEntries(entriesPath, byPath, byName, ambiguous, targetYear, exclude); err != nil {
 			return nil, nil, fmt.Errorf("loading entries: %w", err)
 		}
 	}
@@ -57,11 +68,33 @@ func LoadTaxonomy(taxonomyPath, entriesPath, incomeEntriesPath string, targetYea
 type rawType struct {
 	Name       string `json:"name"`
 	Categories []struct {
-		Name          string   `json:"name"`
-		Subcategories []string `json:"subcategories"`
+		Name          string      `json:"name"`
+		Budget        Budget      `json:"budget"`
+		Subcategories []rawSubcat `json:"subcategories"`
 	} `json:"categories"`
 }
 
+// rawSubcat represents one element of a taxonomy category's subcategories array.
+// It accepts two JSON shapes:
+//
+//   - flat string: "Aluguel" (no budget)
+//   - object: {"name":"Aluguel","budget":{"monthly":2500}} (or {"annual":...})
+type rawSubcat struct {
+	Name   string `json:"name"`
+	Budget Budget `json:"budget"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler for rawSubcat, handling both the
+// flat string format and the object format with a budget.
+func (r *rawSubcat) UnmarshalJSON(data []byte) error {
+	trimmed := strings.TrimSpace(string(data))
+	if len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(data, &r.Name)
+	}
+	type rawSubcatAlias rawSubcat
+	return json.Unmarshal(data, (*rawSubcatAlias)(r))
+}
+
 // rawIncomeBlock represents one element of a taxonomy incomeCategories[].blocks array.
 // It accepts two JSON shapes:
 //
@@ -110,6 +143,9 @@ func loadTaxonomyFile(path string) ([]ExpenseType, []RevenueBlock, error) {
 	}
 
 	types := rawTypesToExpenseTypes(raw.Types)
+	if err := validateBudgets(types); err != nil {
+		return nil, nil, err
+	}
 	incomeBlocks := incomeCatsToRevenueBlocks(raw.IncomeCategories)
 
 	return types, incomeBlocks, nil
@@ -122,10 +158,10 @@ func rawTypesToExpenseTypes(raw []rawType) []ExpenseType {
 		types[i] = ExpenseType{Name: rs.Name}
 		cats := make([]Category, len(rs.Categories))
 		for j, rc := range rs.Categories {
-			cats[j] = Category{Name: rc.Name}
+			cats[j] = Category{Name: rc.Name, Budget: rc.Budget}
 			subs := make([]Subcat, len(rc.Subcategories))
-			for k, name := range rc.Subcategories {
-				subs[k] = Subcat{Name: name}
+			for k, sub := range rc.Subcategories {
+				subs[k] = Subcat{Name: sub.Name, Budget: sub.Budget}
 			}
 			cats[j].Subs = subs
 		}
@@ -134,6 +170,33 @@ func rawTypesToExpenseTypes(raw []rawType) []ExpenseType {
 	return types
 }
 
+// validateBudgets rejects negative budget amounts and budgets that set both
+// monthly and annual, since one is derived from the other.
+func validateBudgets(types []ExpenseType) error {
+	check := func(path string, b Budget) error {
+		if b.Monthly < 0 || b.Annual < 0 {
+			return fmt.Errorf("budget of %s is negative", path)
+		}
+		if b.Monthly != 0 && b.Annual != 0 {
+			return fmt.Errorf("budget of %s sets both monthly and annual; give one", path)
+		}
+		return nil
+	}
+	for _, t := range types {
+		for _, c := range t.Cats {
+			if err := check(t.Name+"/"+c.Name, c.Budget); err != nil {
+				return err
+			}
+			for _, s := range c.Subs {
+				if err := check(t.Name+"/"+c.Name+"/"+s.Name, s.Budget); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // incomeCatsToRevenueBlocks flattens income categories into a []RevenueBlock slice.
 // Each (category, block, subline) triple becomes one RevenueBlock leaf.
 func incomeCatsToRevenueBlocks(raw []struct {
@@ -156,22 +219,24 @@ func incomeCatsToRevenueBlocks(raw []struct {
 }
 
 // loadEntries reads entries from a JSONL file and routes them using the two
-// pre-built routing maps and the ambiguity set.
-func loadEntries(path string, byPath, byName map[string]subcatTarget, ambiguous map[string]bool, targetYear int) error {
-	file, err := os.Open(path)
+// pre-built routing maps and the ambiguity set. Of the archives close-year
+// wrote next to the file, only targetYear's is read (all of them for 0).
+func loadEntries(path string, byPath, byName map[string]subcatTarget, ambiguous map[string]bool, targetYear int, exclude map[string]bool) error {
+	file, err := jsonlog.OpenYears(path, targetYear, targetYear)
 	if err != nil {
 		return fmt.Errorf("opening entries file: %w", err)
 	}
 	defer file.Close()
 
-	return scanEntries(bufio.NewScanner(file), byPath, byName, ambiguous, targetYear)
+	return scanEntries(bufio.NewScanner(file), byPath, byName, ambiguous, targetYear, exclude)
 }
 
 // loadIncomeEntries reads income entries from a JSONL file (income_log.jsonl schema)
 // and routes each line into the matching RevenueBlock leaf via a secondary index
-// built from byPath (avoids rebuilding the full taxonomy map).
+// built from byPath (avoids rebuilding the full taxonomy map). Archives are
+// read as in loadEntries.
 func loadIncomeEntries(path string, byPath map[string]subcatTarget, targetYear int) error {
-	file, err := os.Open(path)
+	file, err := jsonlog.OpenYears(path, targetYear, targetYear)
 	if err != nil {
 		return fmt.Errorf("opening income entries file: %w", err)
 	}
@@ -197,27 +262,43 @@ func buildIncomeIndex(byPath map[string]subcatTarget) map[string]subcatTarget {
 	return idx
 }
 
+// incomeRow is the part of an income_log.jsonl line the loader reads.
+type incomeRow struct {
+	ID             string  `json:"id"`
+	Op             string  `json:"op"`
+	Date           string  `json:"date"`
+	Value          float64 `json:"value"`
+	IncomeCategory string  `json:"income_category"` // = block
+	IncomeLabel    string  `json:"income_label"`    // = leaf
+	ItemNote       string  `json:"item_note"`
+}
+
 // scanIncomeEntries routes each income JSONL line into the correct RevenueBlock month.
 // income_category = block name (mid-level); income_label = leaf subline.
-// Values are kept signed — deductions are negative in the source data.
+// Values are kept signed — deductions are negative in the source data. Void
+// lines (a reverted run) are applied first, so voided entries are left out.
 func scanIncomeEntries(scanner *bufio.Scanner, idx map[string]subcatTarget, targetYear int) error {
-	noDateSkipped := 0
+	var rows []incomeRow
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.TrimSpace(line) == "" {
 			continue
 		}
-
-		var row struct {
-			Date           string  `json:"date"`
-			Value          float64 `json:"value"`
-			IncomeCategory string  `json:"income_category"` // = block
-			IncomeLabel    string  `json:"income_label"`    // = leaf
-			ItemNote       string  `json:"item_note"`
-		}
+		var row incomeRow
 		if err := json.Unmarshal([]byte(line), &row); err != nil {
 			return fmt.Errorf("parsing income entry line: %w", err)
 		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	rows = logschema.Resolve(rows,
+		func(r incomeRow) string { return r.ID },
+		func(r incomeRow) string { return r.Op })
+
+	noDateSkipped := 0
+	for _, row := range rows {
 
 		block := normalizeKey(row.IncomeCategory)
 		label := normalizeKey(row.IncomeLabel)
@@ -242,7 +323,7 @@ func scanIncomeEntries(scanner *bufio.Scanner, idx map[string]subcatTarget, targ
 			continue
 		}
 
-		target.attachEntry(Entry{Item: row.ItemNote, Day: day, Value: row.Value}, month-1)
+		target.attachEntry(Entry{Item: row.ItemNote, Day: day, Value: row.Value, Year: entryYear}, month-1)
 	}
 
 	if noDateSkipped > 0 {
@@ -250,8 +331,7 @@ func scanIncomeEntries(scanner *bufio.Scanner, idx map[string]subcatTarget, targ
 			"warning: %d income entr%s skipped — missing or malformed date (not placed in any month)\n",
 			noDateSkipped, plural(noDateSkipped, "y", "ies"))
 	}
-
-	return scanner.Err()
+	return nil
 }
 
 // scanEntries reads each non-blank JSONL line, parses it, routes it to a target via
@@ -261,26 +341,22 @@ func scanIncomeEntries(scanner *bufio.Scanner, idx map[string]subcatTarget, targ
 // this resolves ambiguous leaf names to exactly one block.
 // Tier 2 (type-less entry): fall back to the bare-name map (today's behavior), which
 // still skips genuinely-ambiguous names. Legacy/auto/batch-auto lines take this path.
-func scanEntries(scanner *bufio.Scanner, byPath, byName map[string]subcatTarget, ambiguous map[string]bool, targetYear int) error {
+//
+// Edit and void lines (schema 3) are applied first, so each edited entry is
+// routed once with its latest state and voided entries not at all. Entries
+// whose ID is in exclude are skipped before routing.
+func scanEntries(scanner *bufio.Scanner, byPath, byName map[string]subcatTarget, ambiguous map[string]bool, targetYear int, exclude map[string]bool) error {
+	entries, err := readLogEntries(scanner)
+	if err != nil {
+		return err
+	}
+
 	fallbackCount := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.TrimSpace(line) == "" {
+	for _, entry := range entries {
+		if exclude[entry.ID] {
 			continue
 		}
 
-		var entry struct {
-			Item        string  `json:"item"`
-			Date        string  `json:"date"`
-			Value       float64 `json:"value"`
-			Type        string  `json:"type"` // expense type (Plan A); "" for legacy/auto entries
-			Category    string  `json:"category"`
-			Subcategory string  `json:"subcategory"`
-		}
-		if err := json.Unmarshal([]byte(line), &entry); err != nil {
-			return fmt.Errorf("parsing entry line: %w", err)
-		}
-
 		// NFC-normalize the routing fields so they match the (also-NFC) map keys
 		// regardless of how the source encoded accents. Item is display-only — left as-is.
 		entry.Type = normalizeKey(entry.Type)
@@ -296,15 +372,17 @@ func scanEntries(scanner *bufio.Scanner, byPath, byName map[string]subcatTarget,
 			fallbackCount++
 		}
 
-		day, month, entryYear, err := parseDate(entry.Date)
+		// A DD/MM date takes the year the line was logged in; a line without a
+		// timestamp falls back to targetYear, or stays undated (year 0) for all.
+		date, err := utils.LoggedDate(entry.Date, entry.Timestamp, targetYear)
 		if err != nil {
 			return fmt.Errorf("parsing date for item %q: %w", entry.Item, err)
 		}
-		if entryYear != 0 && targetYear != 0 && entryYear != targetYear {
+		if targetYear != 0 && date.Year() != targetYear {
 			continue
 		}
 
-		subcat.attachEntry(Entry{Item: entry.Item, Day: day, Value: entry.Value}, month-1)
+		subcat.attachEntry(Entry{Item: entry.Item, Day: date.Day(), Value: entry.Value, Year: date.Year()}, int(date.Month())-1)
 	}
 
 	if fallbackCount > 0 {
@@ -314,7 +392,50 @@ func scanEntries(scanner *bufio.Scanner, byPath, byName map[string]subcatTarget,
 			fallbackCount, plural(fallbackCount, "y", "ies"))
 	}
 
-	return scanner.Err()
+	return nil
+}
+
+// readLogEntries parses every expenses_log.jsonl line and applies the edit and
+// void lines, returning each entry's latest state.
+func readLogEntries(scanner *bufio.Scanner) ([]logEntry, error) {
+	var entries []logEntry
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		upgraded, _, err := logschema.Expenses.Upgrade([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("parsing entry line: %w", err)
+		}
+		var entry logEntry
+		if err := json.Unmarshal(upgraded, &entry); err != nil {
+			return nil, fmt.Errorf("parsing entry line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return logschema.Resolve(entries,
+		func(e logEntry) string { return e.ID },
+		func(e logEntry) string { return e.Op }), nil
+}
+
+// logEntry is the part of an expenses_log.jsonl line the loader reads.
+type logEntry struct {
+	ID          string   `json:"id"`
+	Item        string   `json:"item"`
+	Date        string   `json:"date"`
+	Value       float64  `json:"value"`
+	Type        string   `json:"type"` // expense type (Plan A); "" for legacy/auto entries
+	Category    string   `json:"category"`
+	Subcategory string   `json:"subcategory"`
+	Account     string   `json:"account"` // paying card or account; "" when not recorded
+	Tags        []string `json:"tags"`
+	Timestamp   string   `json:"timestamp"` // when the line was logged; dates a DD/MM entry
+	Op          string   `json:"op"`        // logschema.OpEdit / OpVoid; "" for an original line
 }
 
 // routeEntry resolves an entry to a target using two-tier lookup: full-path key when a
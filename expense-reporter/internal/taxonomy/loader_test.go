@@ -205,6 +205,60 @@ func TestLoadTaxonomy_CrossPathDuplicateAllowed(t *testing.T) {
 	assert.Equal(t, "Diarista", sheets[1].Cats[0].Subs[0].Name)
 }
 
+func TestLoadTaxonomy_Budgets(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{
+    "types": [
+        { "name": "Fixas", "categories": [
+            { "name": "Habitação", "subcategories": [
+                "Diarista",
+                { "name": "Aluguel", "budget": { "monthly": 2500 } },
+                { "name": "IPTU", "budget": { "annual": 1200 } } ] },
+            { "name": "Lazer", "budget": { "monthly": 300 }, "subcategories": ["Netflix"] } ] }
+    ],
+    "incomeCategories": []
+}`)
+
+	sheets, _, err := LoadTaxonomy(taxonomyPath, "", "", 0)
+	require.NoError(t, err)
+	habitacao := sheets[0].Cats[0]
+	assert.True(t, habitacao.Subs[0].Budget.IsZero())
+	assert.Equal(t, Budget{Monthly: 2500}, habitacao.Subs[1].Budget)
+	assert.Equal(t, 30000.0, habitacao.Subs[1].Budget.Year())
+	assert.Equal(t, 100.0, habitacao.Subs[2].Budget.Month())
+	assert.Equal(t, Budget{Monthly: 2600, Annual: 31200}, habitacao.Planned(), "summed from the subcategories")
+	assert.Equal(t, Budget{Monthly: 300}, sheets[0].Cats[1].Planned(), "the category's own budget")
+}
+
+func TestLoadTaxonomy_NegativeBudgetRejected(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{
+    "types": [
+        { "name": "Fixas", "categories": [
+            { "name": "Habitação", "subcategories": [
+                { "name": "Aluguel", "budget": { "monthly": -1 } } ] } ] }
+    ],
+    "incomeCategories": []
+}`)
+
+	_, _, err := LoadTaxonomy(taxonomyPath, "", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Fixas/Habitação/Aluguel")
+}
+
+func TestLoadTaxonomy_MonthlyAndAnnualBudgetRejected(t *testing.T) {
+	taxonomyPath := writeTempFile(t, "taxonomy.json", `{
+    "types": [
+        { "name": "Fixas", "categories": [
+            { "name": "Lazer", "budget": { "monthly": 300, "annual": 3000 }, "subcategories": ["Netflix"] } ] }
+    ],
+    "incomeCategories": []
+}`)
+
+	_, _, err := LoadTaxonomy(taxonomyPath, "", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Fixas/Lazer")
+	assert.Contains(t, err.Error(), "both monthly and annual")
+}
+
 // TestLoadTaxonomy_AmbiguousEntrySkipped is the real coverage for the
 // ambiguous-routing safety: a bare name that maps to 3+ full paths (Orion in
 // Pet/Pets across three sheets) must NOT route an entry to any of them while the
@@ -348,6 +402,99 @@ func TestLoadTaxonomy_TypelessUnambiguousEntryRoutes(t *testing.T) {
 	assert.Equal(t, "Aluguel Jan", aluguel.Months[0][0].Item)
 }
 
+func TestLoadTaxonomyExcluding_SkipsExcludedIDs(t *testing.T) {
+	dir := t.TempDir()
+	taxonomyPath := filepath.Join(dir, "taxonomy.json")
+	require.NoError(t, os.WriteFile(taxonomyPath, []byte(`{
+    "types": [
+        { "name": "Extras", "categories": [
+            { "name": "Casa", "subcategories": ["Eletrodomésticos"] } ] }
+    ],
+    "incomeCategories": []
+}`), 0644))
+
+	entriesPath := filepath.Join(dir, "entries.jsonl")
+	require.NoError(t, os.WriteFile(entriesPath, []byte(
+		`{"id":"aaa","item":"Geladeira (1/2)","date":"05/01","value":250.0,"type":"Extras","category":"Casa","subcategory":"Eletrodomésticos"}`+"\n"+
+			`{"id":"bbb","item":"Geladeira (2/2)","date":"05/02","value":250.0,"type":"Extras","category":"Casa","subcategory":"Eletrodomésticos"}`+"\n"), 0644))
+
+	sheets, _, err := LoadTaxonomyExcluding(taxonomyPath, entriesPath, "", 0, map[string]bool{"bbb": true})
+	require.NoError(t, err)
+
+	sub := sheets[0].Cats[0].Subs[0]
+	assert.Len(t, sub.Months[0], 1)
+	assert.Empty(t, sub.Months[1])
+}
+
+func TestLoadTaxonomy_YearlessDateTakesLoggedYear(t *testing.T) {
+	dir := t.TempDir()
+	taxonomyPath := filepath.Join(dir, "taxonomy.json")
+	require.NoError(t, os.WriteFile(taxonomyPath, []byte(`{
+    "types": [
+        { "name": "Variáveis", "categories": [
+            { "name": "Alimentação", "subcategories": ["Feira"] } ] }
+    ],
+    "incomeCategories": []
+}`), 0644))
+
+	entriesPath := filepath.Join(dir, "entries.jsonl")
+	require.NoError(t, os.WriteFile(entriesPath, []byte(
+		`{"id":"aaa","item":"Feira","date":"28/12","value":80.0,"timestamp":"2026-01-03T10:00:00Z","type":"Variáveis","category":"Alimentação","subcategory":"Feira"}`+"\n"+
+			`{"id":"bbb","item":"Feira","date":"02/01","value":90.0,"timestamp":"2026-01-03T10:00:00Z","type":"Variáveis","category":"Alimentação","subcategory":"Feira"}`+"\n"+
+			`{"id":"ccc","item":"Feira","date":"10/03","value":70.0,"type":"Variáveis","category":"Alimentação","subcategory":"Feira"}`+"\n"), 0644))
+
+	t.Run("all years", func(t *testing.T) {
+		sheets, _, err := LoadTaxonomy(taxonomyPath, entriesPath, "", 0)
+		require.NoError(t, err)
+		feira := sheets[0].Cats[0].Subs[0]
+		require.Len(t, feira.Months[11], 1)
+		assert.Equal(t, 2025, feira.Months[11][0].Year, "logged on 03/01 and dated 28/12 is the year before")
+		require.Len(t, feira.Months[0], 1)
+		assert.Equal(t, 2026, feira.Months[0][0].Year)
+		require.Len(t, feira.Months[2], 1)
+		assert.Zero(t, feira.Months[2][0].Year, "no timestamp to go by")
+	})
+
+	t.Run("target year", func(t *testing.T) {
+		sheets, _, err := LoadTaxonomy(taxonomyPath, entriesPath, "", 2026)
+		require.NoError(t, err)
+		feira := sheets[0].Cats[0].Subs[0]
+		assert.Empty(t, feira.Months[11], "December purchase belongs to 2025")
+		assert.Len(t, feira.Months[0], 1)
+		assert.Len(t, feira.Months[2], 1, "untimestamped DD/MM takes the target year")
+	})
+}
+
+func TestLoadTaxonomy_HonoursEditsAndVoids(t *testing.T) {
+	dir := t.TempDir()
+	taxonomyPath := filepath.Join(dir, "taxonomy.json")
+	require.NoError(t, os.WriteFile(taxonomyPath, []byte(`{
+    "types": [
+        { "name": "Variáveis", "categories": [
+            { "name": "Alimentação", "subcategories": ["Feira", "Padaria"] } ] }
+    ],
+    "incomeCategories": []
+}`), 0644))
+
+	entriesPath := filepath.Join(dir, "entries.jsonl")
+	require.NoError(t, os.WriteFile(entriesPath, []byte(
+		`{"id":"aaa","item":"Feira","date":"05/01","value":80.0,"type":"Variáveis","category":"Alimentação","subcategory":"Feira"}`+"\n"+
+			`{"id":"bbb","item":"Pão","date":"06/01","value":12.0,"type":"Variáveis","category":"Alimentação","subcategory":"Padaria"}`+"\n"+
+			`{"schema":3,"id":"aaa","item":"Feira","date":"05/02","value":85.0,"type":"Variáveis","category":"Alimentação","subcategory":"Padaria","op":"edit"}`+"\n"+
+			`{"schema":3,"id":"bbb","item":"Pão","date":"06/01","value":12.0,"type":"Variáveis","category":"Alimentação","subcategory":"Padaria","op":"void"}`+"\n"), 0644))
+
+	sheets, _, err := LoadTaxonomy(taxonomyPath, entriesPath, "", 0)
+	require.NoError(t, err)
+
+	feira, padaria := sheets[0].Cats[0].Subs[0], sheets[0].Cats[0].Subs[1]
+	for m := range feira.Months {
+		assert.Empty(t, feira.Months[m], "edited entry moved out of Feira")
+	}
+	assert.Empty(t, padaria.Months[0], "voided entry is gone")
+	require.Len(t, padaria.Months[1], 1)
+	assert.Equal(t, 85.0, padaria.Months[1][0].Value)
+}
+
 // TestLoadTaxonomy_NFDEntryRoutesToNFCTaxonomy guards the Unicode-normalization
 // safeguard: the apply path (workbook-derived) and config/taxonomy.json are authored
 // independently and may differ in accent encoding. Here the taxonomy uses composed
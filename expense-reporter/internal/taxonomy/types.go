@@ -5,17 +5,34 @@ package taxonomy
 
 // Entry is one expense/income line within a subcategory's month.
 // Day is the day-of-month; the builder pairs it with the column's month + the
-// config year to form the cell date. Value is the BRL amount.
+// config year to form the cell date. Value is the BRL amount. Year is the
+// year of the log date, 0 when neither it (DD/MM) nor the line's timestamp
+// gives one.
 type Entry struct {
 	Item  string
 	Day   int
 	Value float64
+	Year  int
+}
+
+// forYear returns the entries of months dated in year.
+func forYear(months [12][]Entry, year int) [12][]Entry {
+	var out [12][]Entry
+	for m, entries := range months {
+		for _, e := range entries {
+			if e.Year == year {
+				out[m] = append(out[m], e)
+			}
+		}
+	}
+	return out
 }
 
 // Subcat is one subcategory block. v2: composed sub-items are a single col-B string.
 // Months holds entries per month (index 0 = Janeiro). A month with no entries is nil.
 type Subcat struct {
 	Name   string
+	Budget Budget // zero when the taxonomy plans no spending for it
 	Months [12][]Entry
 }
 
@@ -33,8 +50,55 @@ func (s Subcat) MaxEntries() int {
 
 // Category groups subcategories under one bold category label.
 type Category struct {
-	Name string
-	Subs []Subcat
+	Name   string
+	Budget Budget // the category's own budget; see Planned
+	Subs   []Subcat
+}
+
+// Planned returns the category's budget: its own when the taxonomy sets one,
+// otherwise the sum of its subcategories' budgets.
+func (c Category) Planned() Budget {
+	if !c.Budget.IsZero() {
+		return c.Budget
+	}
+	var sum Budget
+	for _, s := range c.Subs {
+		if s.Budget.IsZero() {
+			continue
+		}
+		sum.Monthly += s.Budget.Month()
+		sum.Annual += s.Budget.Year()
+	}
+	return sum
+}
+
+// Budget is the spending planned for a subcategory or category, as a monthly
+// or an annual amount (BRL). Whichever is unset is derived from the other.
+type Budget struct {
+	Monthly float64 `json:"monthly,omitempty"`
+	Annual  float64 `json:"annual,omitempty"`
+}
+
+// IsZero reports whether nothing is planned.
+func (b Budget) IsZero() bool {
+	return b.Monthly == 0 && b.Annual == 0
+}
+
+// Month returns the amount planned for each month: Monthly, or an even
+// twelfth of Annual.
+func (b Budget) Month() float64 {
+	if b.Monthly != 0 {
+		return b.Monthly
+	}
+	return b.Annual / 12
+}
+
+// Year returns the amount planned for the year: Annual, or twelve times Monthly.
+func (b Budget) Year() float64 {
+	if b.Annual != 0 {
+		return b.Annual
+	}
+	return b.Monthly * 12
 }
 
 // ExpenseType is one of Fixas/Variáveis/Extras/Adicionais.
@@ -43,6 +107,19 @@ type ExpenseType struct {
 	Cats []Category
 }
 
+// ForYear returns a copy of the sheet holding only the entries dated in year,
+// for splitting a tree loaded across years (target year 0).
+func (t ExpenseType) ForYear(year int) ExpenseType {
+	out := ExpenseType{Name: t.Name, Cats: make([]Category, len(t.Cats))}
+	for i, c := range t.Cats {
+		out.Cats[i] = Category{Name: c.Name, Budget: c.Budget, Subs: make([]Subcat, len(c.Subs))}
+		for j, s := range c.Subs {
+			out.Cats[i].Subs[j] = Subcat{Name: s.Name, Budget: s.Budget, Months: forYear(s.Months, year)}
+		}
+	}
+	return out
+}
+
 // RevenueBlock is one income leaf — the intersection of a taxonomy category, a
 // mid-level block grouping, and a subline label. One RevenueBlock == one data row
 // on the Receitas sheet (mirrors how one Subcat == one expense data row).
@@ -62,6 +139,12 @@ type RevenueBlock struct {
 	Months   [12][]Entry
 }
 
+// ForYear mirrors ExpenseType.ForYear for income blocks.
+func (b RevenueBlock) ForYear(year int) RevenueBlock {
+	b.Months = forYear(b.Months, year)
+	return b
+}
+
 // MaxEntries mirrors Subcat.MaxEntries for income blocks.
 func (b RevenueBlock) MaxEntries() int {
 	max := 0
@@ -6,6 +6,7 @@ import (
 	"expense-reporter/internal/models"
 	"expense-reporter/internal/parser"
 	"expense-reporter/internal/resolver"
+	"expense-reporter/pkg/utils"
 	"fmt"
 	"time"
 )
@@ -137,6 +138,23 @@ func parseExpenseStrings(expenseStrings []string) ([]*models.Expense, []*models.
 			errors[i] = models.NewParseError(err.Error(), err)
 			continue
 		}
+		if expense.IsForeign() {
+			// The workbook path has no rate table; converting here would duplicate
+			// the log path's conversion, so foreign rows are refused up front.
+			err := fmt.Errorf("foreign-currency value (%s) is only supported by the log-append commands (add, batch-auto)", expense.Currency)
+			errors[i] = models.NewParseError(err.Error(), err)
+			continue
+		}
+		if utils.IsSplitSpec(expense.Subcategory) {
+			err := fmt.Errorf("split notation is only supported by the log-append commands (add, batch-auto)")
+			errors[i] = models.NewParseError(err.Error(), err)
+			continue
+		}
+		if expense.IsUneven() {
+			err := fmt.Errorf("uneven installments are only supported by the log-append commands (add, batch-auto)")
+			errors[i] = models.NewParseError(err.Error(), err)
+			continue
+		}
 		parsedExpenses[i] = expense
 	}
 	return parsedExpenses, errors
@@ -39,47 +39,75 @@ func ParseCurrency(valueStr string) (float64, error) {
 //   "300,00/3"  → (100.00, 3, nil)       // 3 installments of 100 each
 //   "300,00/0"  → (0, 0, error)          // Invalid: zero divisor
 //   "300,00/abc"→ (0, 0, error)          // Invalid: non-numeric divisor
+//
+// For uneven schedules (see ParseInstallments) perInstallment is the first
+// payment; callers that write the individual payments use ParseInstallments.
 func ParseCurrencyWithInstallments(s string) (perInstallment float64, count int, err error) {
-	// Trim whitespace
-	s = strings.TrimSpace(s)
+	sched, err := ParseInstallments(s)
+	if err != nil {
+		return 0, 0, err
+	}
+	return sched.Values[0], sched.Count(), nil
+}
 
-	// Check for "/" (installment syntax)
-	if strings.Contains(s, "/") {
-		parts := strings.Split(s, "/")
-		if len(parts) != 2 {
-			return 0, 0, fmt.Errorf("invalid installment format: %s", s)
-		}
+// parseEqualInstallments parses one "total/N" term into N equal payments.
+func parseEqualInstallments(s string) (perInstallment float64, count int, err error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid installment format: %s", s)
+	}
 
-		// Parse total value (PT-BR: "300,00" → 300.00)
-		total, err := ParseCurrency(parts[0])
-		if err != nil {
-			return 0, 0, fmt.Errorf("invalid total value in installment: %w", err)
-		}
+	// Parse total value (PT-BR: "300,00" → 300.00)
+	total, err := ParseCurrency(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid total value in installment: %w", err)
+	}
 
-		// Parse installment count
-		countStr := strings.TrimSpace(parts[1])
-		count, err := strconv.Atoi(countStr)
-		if err != nil {
-			return 0, 0, fmt.Errorf("invalid installment count '%s': must be a number", countStr)
-		}
+	// Parse installment count
+	countStr := strings.TrimSpace(parts[1])
+	count, err = strconv.Atoi(countStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid installment count '%s': must be a number", countStr)
+	}
 
-		// Validate count
-		if count <= 0 {
-			return 0, 0, fmt.Errorf("installment count must be positive, got %d", count)
-		}
-		if count > 60 {
-			return 0, 0, fmt.Errorf("installment count too large: %d (max 60)", count)
-		}
+	// Validate count
+	if count <= 0 {
+		return 0, 0, fmt.Errorf("installment count must be positive, got %d", count)
+	}
+	if count > maxInstallments {
+		return 0, 0, fmt.Errorf("installment count too large: %d (max %d)", count, maxInstallments)
+	}
+
+	// Calculate per-installment value
+	return total / float64(count), count, nil
+}
 
-		// Calculate per-installment value
-		perInstallment := total / float64(count)
-		return perInstallment, count, nil
+// SplitCurrencyCode peels an optional leading ISO 4217 currency code off a value
+// string. The code must be three uppercase letters followed by whitespace:
+//
+//	"USD 20,00"     → ("USD", "20,00")
+//	"EUR 300,00/3"  → ("EUR", "300,00/3")
+//	"20,00"         → ("", "20,00")
+//
+// BRL is the local currency and is returned as "" so callers only ever branch on
+// "needs conversion" vs "does not".
+func SplitCurrencyCode(s string) (code, rest string) {
+	s = strings.TrimSpace(s)
+	if len(s) < 4 || !isCurrencyCode(s[:3]) || (s[3] != ' ' && s[3] != '\t') {
+		return "", s
+	}
+	code, rest = s[:3], strings.TrimSpace(s[3:])
+	if code == "BRL" {
+		return "", rest
 	}
+	return code, rest
+}
 
-	// Regular value (no installments)
-	value, err := ParseCurrency(s)
-	if err != nil {
-		return 0, 0, err
+func isCurrencyCode(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
 	}
-	return value, 1, nil
+	return true
 }
@@ -237,3 +237,28 @@ func TestParseCurrencyWithInstallments(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitCurrencyCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantCode string
+		wantRest string
+	}{
+		{"no code", "20,00", "", "20,00"},
+		{"USD prefix", "USD 20,00", "USD", "20,00"},
+		{"EUR with installments", "EUR 300,00/3", "EUR", "300,00/3"},
+		{"BRL is local", "BRL 15,00", "", "15,00"},
+		{"surrounding whitespace", "  USD   9,99 ", "USD", "9,99"},
+		{"lowercase is not a code", "usd 20,00", "", "usd 20,00"},
+		{"code without space is not split", "USD20,00", "", "USD20,00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, rest := SplitCurrencyCode(tt.input)
+			if code != tt.wantCode || rest != tt.wantRest {
+				t.Errorf("SplitCurrencyCode(%q) = (%q, %q), want (%q, %q)", tt.input, code, rest, tt.wantCode, tt.wantRest)
+			}
+		})
+	}
+}
@@ -50,12 +50,17 @@ func ParseDate(dateStr string) (time.Time, error) {
 }
 
 // ParseDateWithYear parses a date string in DD/MM format and returns a time.Time for the given year.
+// A DD/MM/YYYY date keeps its own year: year is ignored for it, not checked
+// against it, so a caller that needs the two to agree compares them itself.
 func ParseDateWithYear(dateStr string, year int) (time.Time, error) {
 	if dateStr == "" {
 		return time.Time{}, errors.New("date string cannot be empty")
 	}
 
 	parts := strings.Split(dateStr, "/")
+	if len(parts) == 3 {
+		return ParseDateFlexible(dateStr)
+	}
 	if len(parts) != 2 {
 		return time.Time{}, fmt.Errorf("invalid date format, expected DD/MM, got: %s", dateStr)
 	}
@@ -126,11 +131,56 @@ func ParseDateFlexible(dateStr string) (time.Time, error) {
 	return date, nil
 }
 
+// InferDate parses a DD/MM or DD/MM/YYYY date recorded at logged. A DD/MM date
+// takes logged's year, or the year before when that would put it after the day
+// it was recorded: "28/12" recorded on 03/01/2027 is 28/12/2026.
+func InferDate(dateStr string, logged time.Time) (time.Time, error) {
+	if strings.Count(dateStr, "/") != 1 {
+		return ParseDateFlexible(dateStr)
+	}
+	day := time.Date(logged.Year(), logged.Month(), logged.Day(), 0, 0, 0, 0, time.UTC)
+	t, err := ParseDateWithYear(dateStr, day.Year())
+	if err == nil && !t.After(day) {
+		return t, nil
+	}
+	// A leap day fails in a common year; the year before may still hold it.
+	if prev, perr := ParseDateWithYear(dateStr, day.Year()-1); perr == nil {
+		return prev, nil
+	}
+	return t, err
+}
+
+// LoggedDate returns the date of a log line. A DD/MM date takes its year from
+// timestamp, the RFC 3339 time the line was logged (see InferDate); a line
+// without one falls back to year.
+func LoggedDate(dateStr, timestamp string, year int) (time.Time, error) {
+	dateStr = strings.TrimSpace(dateStr)
+	if strings.Count(dateStr, "/") != 1 {
+		return ParseDateFlexible(dateStr)
+	}
+	if logged, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return InferDate(dateStr, logged.UTC())
+	}
+	return ParseDateWithYear(dateStr, year)
+}
+
 // FormatDate formats a time.Time as DD/MM/YYYY (Brazilian format).
 func FormatDate(t time.Time) string {
 	return fmt.Sprintf("%02d/%02d/%04d", t.Day(), int(t.Month()), t.Year())
 }
 
+// InvoiceMonth returns the first day of the month whose card invoice bills a
+// purchase made on date, for an invoice closing on closingDay: purchases after
+// the closing day go to the next month's invoice. A closingDay of 0 (or one
+// past the month's end) bills every purchase in its own month.
+func InvoiceMonth(date time.Time, closingDay int) time.Time {
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if closingDay > 0 && date.Day() > closingDay {
+		return month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
 // TimeToExcelDate converts a time.Time to Excel serial date number
 // Excel epoch is December 30, 1899
 func TimeToExcelDate(t time.Time) float64 {
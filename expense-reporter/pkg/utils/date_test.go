@@ -184,6 +184,7 @@ func TestParseDateWithYear(t *testing.T) {
 		{name: "impossible date feb 30", input: "30/02", year: 2026, wantErr: true},
 		{name: "empty string", input: "", year: 2026, wantErr: true},
 		{name: "bad format", input: "15-03", year: 2026, wantErr: true},
+		{name: "own year kept", input: "15/03/2024", year: 2026, wantDay: 15, wantMonth: time.March, wantYear: 2024},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -210,3 +211,73 @@ func TestParseDateWithYear(t *testing.T) {
 		})
 	}
 }
+
+func TestInferDate(t *testing.T) {
+	logged := time.Date(2027, 1, 3, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"28/12", "28/12/2026"}, // New Year crossing: not after the day it was logged
+		{"03/01", "03/01/2027"},
+		{"02/01", "02/01/2027"},
+		{"15/03/2025", "15/03/2025"},
+	}
+	for _, tt := range tests {
+		got, err := InferDate(tt.input, logged)
+		if err != nil {
+			t.Fatalf("InferDate(%q) unexpected error: %v", tt.input, err)
+		}
+		if FormatDate(got) != tt.want {
+			t.Errorf("InferDate(%q) = %s, want %s", tt.input, FormatDate(got), tt.want)
+		}
+	}
+	if got, err := InferDate("29/02", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)); err != nil || FormatDate(got) != "29/02/2024" {
+		t.Errorf("InferDate(29/02) = %v, %v; want the last leap year", got, err)
+	}
+	if _, err := InferDate("30/02", logged); err == nil {
+		t.Error("InferDate(30/02) error = nil, want error")
+	}
+}
+
+func TestLoggedDate(t *testing.T) {
+	tests := []struct {
+		date, timestamp string
+		want            string
+	}{
+		{"28/12", "2027-01-03T09:00:00Z", "28/12/2026"}, // year from when it was logged
+		{"15/04", "2025-04-20T10:00:00Z", "15/04/2025"},
+		{"15/04", "", "15/04/2024"},                      // no timestamp: the fallback year
+		{"15/04/2023", "2025-04-20T10:00:00Z", "15/04/2023"},
+	}
+	for _, tt := range tests {
+		got, err := LoggedDate(tt.date, tt.timestamp, 2024)
+		if err != nil {
+			t.Fatalf("LoggedDate(%q, %q) unexpected error: %v", tt.date, tt.timestamp, err)
+		}
+		if FormatDate(got) != tt.want {
+			t.Errorf("LoggedDate(%q, %q) = %s, want %s", tt.date, tt.timestamp, FormatDate(got), tt.want)
+		}
+	}
+}
+
+func TestInvoiceMonth(t *testing.T) {
+	tests := []struct {
+		name       string
+		date       time.Time
+		closingDay int
+		want       time.Time
+	}{
+		{"no closing day", time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC), 0, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"on the closing day", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), 5, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"after the closing day", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC), 5, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"december rolls into january", time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), 10, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InvoiceMonth(tt.date, tt.closingDay); !got.Equal(tt.want) {
+				t.Errorf("InvoiceMonth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxInstallments bounds the number of monthly payments in one value.
+const maxInstallments = 60
+
+// InstallmentSchedule is the parsed form of an installment value: the amount
+// of each monthly payment, in order, plus the interest applied, if any.
+type InstallmentSchedule struct {
+	Values       []float64 // one amount per monthly payment; a single element for a regular value
+	InterestRate float64   // monthly rate of the financed term (0.0199 = 1,99% a.m.); 0 = none
+	Principal    float64   // cash price before interest; equals Total when InterestRate is 0
+}
+
+// Count returns the number of monthly payments.
+func (s InstallmentSchedule) Count() int {
+	return len(s.Values)
+}
+
+// Total returns the sum of all payments — what is actually paid, interest included.
+func (s InstallmentSchedule) Total() float64 {
+	var sum float64
+	for _, v := range s.Values {
+		sum += v
+	}
+	return math.Round(sum*100) / 100
+}
+
+// Equal reports whether every payment has the same amount, i.e. the schedule
+// is fully described by one per-installment value and a count.
+func (s InstallmentSchedule) Equal() bool {
+	for _, v := range s.Values[1:] {
+		if v != s.Values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseInstallments parses a PT-BR value with optional installment notation
+// into its payment schedule. The value is one or more terms joined by "+",
+// each paid in consecutive months:
+//
+//	"100,00"              → one payment of 100
+//	"300,00/3"            → 3 equal payments of 100 (total/N)
+//	"200,00+10x90,00"     → a 200 down payment, then 10 payments of 90 (N×value)
+//	"120,00+90,00+90,00"  → explicit per-installment values
+//	"1000,00/10@1,99%"    → 1000 financed over 10 months at 1,99% a.m. (Price table)
+//
+// Interest may appear on a single total/N term; payments with interest are
+// rounded to centavos.
+func ParseInstallments(s string) (InstallmentSchedule, error) {
+	s = strings.TrimSpace(s)
+	var sched InstallmentSchedule
+	for _, term := range strings.Split(s, "+") {
+		term = strings.TrimSpace(term)
+		values, rate, principal, err := parseInstallmentTerm(term)
+		if err != nil {
+			return InstallmentSchedule{}, err
+		}
+		if rate > 0 {
+			if sched.InterestRate > 0 {
+				return InstallmentSchedule{}, fmt.Errorf("invalid installment format: %s (interest on more than one term)", s)
+			}
+			sched.InterestRate = rate
+		}
+		sched.Values = append(sched.Values, values...)
+		sched.Principal += principal
+	}
+	if len(sched.Values) > maxInstallments {
+		return InstallmentSchedule{}, fmt.Errorf("installment count too large: %d (max %d)", len(sched.Values), maxInstallments)
+	}
+	sched.Principal = math.Round(sched.Principal*100) / 100
+	return sched, nil
+}
+
+// parseInstallmentTerm parses one "+"-separated term of an installment value.
+// principal is the term's amount before interest.
+func parseInstallmentTerm(term string) (values []float64, rate, principal float64, err error) {
+	switch {
+	case strings.Contains(term, "/"):
+		financed := term
+		if at := strings.Index(term, "@"); at >= 0 {
+			financed = term[:at]
+			if rate, err = parseInterestRate(term[at+1:]); err != nil {
+				return nil, 0, 0, err
+			}
+		}
+		per, count, err := parseEqualInstallments(financed)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		principal = per * float64(count)
+		if rate > 0 {
+			per = priceInstallment(principal, rate, count)
+		}
+		return repeat(per, count), rate, principal, nil
+
+	case strings.ContainsAny(term, "xX"):
+		i := strings.IndexAny(term, "xX")
+		countStr := strings.TrimSpace(term[:i])
+		count, err := strconv.Atoi(countStr)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid installment count '%s': must be a number", countStr)
+		}
+		if count <= 0 {
+			return nil, 0, 0, fmt.Errorf("installment count must be positive, got %d", count)
+		}
+		per, err := ParseCurrency(term[i+1:])
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid installment value: %w", err)
+		}
+		return repeat(per, count), 0, per * float64(count), nil
+
+	default:
+		v, err := ParseCurrency(term)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return []float64{v}, 0, v, nil
+	}
+}
+
+// parseInterestRate parses a monthly percentage such as "1,99%" into a fraction.
+func parseInterestRate(s string) (float64, error) {
+	pct := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	rate, err := ParseCurrency(pct)
+	if err != nil || rate <= 0 {
+		return 0, fmt.Errorf("invalid interest rate '%s': expected a positive monthly percentage like 1,99%%", s)
+	}
+	return rate / 100, nil
+}
+
+// priceInstallment returns the fixed payment that amortizes principal over count
+// months at the monthly rate (Tabela Price), rounded to centavos.
+func priceInstallment(principal, rate float64, count int) float64 {
+	pmt := principal * rate / (1 - math.Pow(1+rate, -float64(count)))
+	return math.Round(pmt*100) / 100
+}
+
+func repeat(v float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInstallments(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		want          []float64
+		wantRate      float64
+		wantPrincipal float64
+		wantErr       string
+	}{
+		{name: "regular value", input: "100,00", want: []float64{100}, wantPrincipal: 100},
+		{name: "equal parts", input: "300,00/3", want: []float64{100, 100, 100}, wantPrincipal: 300},
+		{name: "down payment plus N×value", input: "200,00+3x90,00", want: []float64{200, 90, 90, 90}, wantPrincipal: 470},
+		{name: "explicit values", input: "120,00 + 90,00 + 95,50", want: []float64{120, 90, 95.5}, wantPrincipal: 305.5},
+		{name: "uppercase X", input: "2X50,00", want: []float64{50, 50}, wantPrincipal: 100},
+		{
+			name:          "interest (Price table)",
+			input:         "1000,00/10@1,99%",
+			want:          repeat(111.27, 10),
+			wantRate:      0.0199,
+			wantPrincipal: 1000,
+		},
+		{
+			name:          "down payment plus financed balance",
+			input:         "200,00+600,00/3@2%",
+			want:          []float64{200, 208.05, 208.05, 208.05},
+			wantRate:      0.02,
+			wantPrincipal: 800,
+		},
+		{name: "bad N×value count", input: "ax90,00", wantErr: "must be a number"},
+		{name: "bad N×value value", input: "3x9a", wantErr: "invalid installment value"},
+		{name: "bad interest", input: "1000,00/10@abc", wantErr: "invalid interest rate"},
+		{name: "two interest terms", input: "100,00/2@1%+100,00/2@1%", wantErr: "more than one term"},
+		{name: "too many payments", input: "100,00+60x10,00", wantErr: "too large"},
+		{name: "empty term", input: "100,00+", wantErr: "cannot be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInstallments(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseInstallments(%q) error = %v, want containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseInstallments(%q) unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got.Values, tt.want) {
+				t.Errorf("Values = %v, want %v", got.Values, tt.want)
+			}
+			if got.InterestRate != tt.wantRate {
+				t.Errorf("InterestRate = %v, want %v", got.InterestRate, tt.wantRate)
+			}
+			if got.Principal != tt.wantPrincipal {
+				t.Errorf("Principal = %v, want %v", got.Principal, tt.wantPrincipal)
+			}
+		})
+	}
+}
+
+func TestInstallmentSchedule_TotalAndEqual(t *testing.T) {
+	sched, err := ParseInstallments("200,00+3x90,00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sched.Total() != 470 || sched.Count() != 4 {
+		t.Errorf("Total/Count = %v/%d, want 470/4", sched.Total(), sched.Count())
+	}
+	if sched.Equal() {
+		t.Error("down-payment schedule reported as equal")
+	}
+	if eq, _ := ParseInstallments("300,00/3"); !eq.Equal() {
+		t.Error("total/N schedule reported as uneven")
+	}
+
+	// ParseCurrencyWithInstallments reports the first payment and the count.
+	v, n, err := ParseCurrencyWithInstallments("200,00+3x90,00")
+	if err != nil || v != 200 || n != 4 {
+		t.Errorf("ParseCurrencyWithInstallments = (%v, %d, %v), want (200, 4, nil)", v, n, err)
+	}
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SplitPart is one share of a split transaction: the subcategory (or hierarchical
+// path) it belongs to and its amount.
+type SplitPart struct {
+	Subcategory string
+	Value       float64
+}
+
+// IsSplitSpec reports whether a subcategory field holds split notation rather
+// than a single subcategory. Subcategory names never contain '='.
+func IsSplitSpec(s string) bool {
+	return strings.Contains(s, "=")
+}
+
+// ParseSplit parses split notation — subcategory=value pairs joined by '|':
+//
+//	Supermercado=120,00|Limpeza=30,00|Farmácia=15,50
+//
+// Values use the same PT-BR format as ParseCurrency. At least two parts are
+// required, each with a positive value, and no subcategory may appear twice.
+func ParseSplit(s string) ([]SplitPart, error) {
+	fields := strings.Split(s, "|")
+	if len(fields) < 2 {
+		return nil, errors.New("split needs at least two parts (sub=value|sub=value)")
+	}
+	parts := make([]SplitPart, 0, len(fields))
+	seen := map[string]bool{}
+	for _, f := range fields {
+		eq := strings.LastIndex(f, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("split part %q: expected sub=value", strings.TrimSpace(f))
+		}
+		sub := strings.TrimSpace(f[:eq])
+		if sub == "" {
+			return nil, fmt.Errorf("split part %q: empty subcategory", strings.TrimSpace(f))
+		}
+		value, err := ParseCurrency(f[eq+1:])
+		if err != nil {
+			return nil, fmt.Errorf("split part %q: %w", sub, err)
+		}
+		if value == 0 {
+			return nil, fmt.Errorf("split part %q: value must be positive", sub)
+		}
+		key := strings.ToLower(sub)
+		if seen[key] {
+			return nil, fmt.Errorf("split part %q appears more than once", sub)
+		}
+		seen[key] = true
+		parts = append(parts, SplitPart{Subcategory: sub, Value: value})
+	}
+	return parts, nil
+}
+
+// CheckSplitTotal returns an error unless parts sum to total, compared in centavos.
+func CheckSplitTotal(parts []SplitPart, total float64) error {
+	var sum float64
+	for _, p := range parts {
+		sum += p.Value
+	}
+	if math.Round(sum*100) != math.Round(total*100) {
+		return fmt.Errorf("split parts sum to %s, expected %s", FormatBRValue(sum), FormatBRValue(total))
+	}
+	return nil
+}
+
+// FormatSplit renders parts back into split notation (inverse of ParseSplit).
+func FormatSplit(parts []SplitPart) string {
+	fields := make([]string, len(parts))
+	for i, p := range parts {
+		fields[i] = p.Subcategory + "=" + FormatBRValue(p.Value)
+	}
+	return strings.Join(fields, "|")
+}
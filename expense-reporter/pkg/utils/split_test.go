@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsSplitSpec(t *testing.T) {
+	if !IsSplitSpec("Supermercado=120,00|Limpeza=30,00") {
+		t.Error("split notation not detected")
+	}
+	if IsSplitSpec("Supermercado") || IsSplitSpec("Habitação,Diarista") {
+		t.Error("plain subcategory detected as split")
+	}
+}
+
+func TestParseSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []SplitPart
+		wantErr string
+	}{
+		{
+			name:  "two parts",
+			input: "Supermercado=120,00|Limpeza=30,00",
+			want:  []SplitPart{{"Supermercado", 120}, {"Limpeza", 30}},
+		},
+		{
+			name:  "three parts, spaces and period decimal",
+			input: " Supermercado = 120,00 | Limpeza=30.50|Farmácia=15,50 ",
+			want:  []SplitPart{{"Supermercado", 120}, {"Limpeza", 30.5}, {"Farmácia", 15.5}},
+		},
+		{name: "single part", input: "Supermercado=150,00", wantErr: "at least two"},
+		{name: "missing value", input: "Supermercado=120,00|Limpeza", wantErr: "expected sub=value"},
+		{name: "empty subcategory", input: "=120,00|Limpeza=30,00", wantErr: "empty subcategory"},
+		{name: "bad value", input: "Supermercado=abc|Limpeza=30,00", wantErr: "invalid value"},
+		{name: "zero value", input: "Supermercado=0|Limpeza=30,00", wantErr: "positive"},
+		{name: "duplicate subcategory", input: "Limpeza=10,00|limpeza=20,00", wantErr: "more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSplit(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSplit(%q) error = %v, want containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSplit(%q) unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSplit(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSplitTotal(t *testing.T) {
+	parts := []SplitPart{{"Supermercado", 120.10}, {"Limpeza", 29.90}}
+	if err := CheckSplitTotal(parts, 150); err != nil {
+		t.Errorf("matching total rejected: %v", err)
+	}
+	err := CheckSplitTotal(parts, 150.01)
+	if err == nil || !strings.Contains(err.Error(), "150,00") {
+		t.Errorf("mismatched total: got %v, want error naming the parts' sum", err)
+	}
+}
+
+func TestFormatSplit_RoundTrip(t *testing.T) {
+	in := "Supermercado=120,00|Limpeza=30,50"
+	parts, err := ParseSplit(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FormatSplit(parts); got != in {
+		t.Errorf("FormatSplit = %q, want %q", got, in)
+	}
+}
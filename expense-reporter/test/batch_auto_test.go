@@ -31,7 +31,7 @@ func TestBatchAuto_MixedConfidence(t *testing.T) {
 	fixDir := filepath.Join(fixturesDir(), "batch-auto-basic")
 
 	harness.Run(t, harness.Scenario{
-		Name:  "batch-auto — classified.csv has 11 rows (1 header + 10 data), 8 columns",
+		Name:  "batch-auto — classified.csv has 11 rows (1 header + 10 data), 10 columns",
 		Given: tenMixedExpensesReadyForBatch(fixDir),
 		When:  actions.RunBatchAutoWithFixture(fixDir),
 		Then:  allInputExpensesClassified(11),
@@ -167,7 +167,7 @@ func classifiedAndReviewFilesProduced() []func(*harness.Context) {
 		verify.OutputFileExists("classified.csv"),
 		verify.OutputFileExists("review.csv"),
 		verify.OutputFileHasAtLeastRows("classified.csv", 1),
-		verify.OutputFileHasColumns("classified.csv", 8),
+		verify.OutputFileHasColumns("classified.csv", 10),
 		verify.AllClassificationScoresValid("classified.csv"),
 	}
 }
@@ -178,7 +178,7 @@ func allInputExpensesClassified(rows int) []func(*harness.Context) {
 		verify.OutputFileExists("classified.csv"),
 		verify.OutputFileExists("review.csv"),
 		verify.OutputFileHasRows("classified.csv", rows),
-		verify.OutputFileHasColumns("classified.csv", 8),
+		verify.OutputFileHasColumns("classified.csv", 10),
 		verify.AllClassificationScoresValid("classified.csv"),
 	}
 }
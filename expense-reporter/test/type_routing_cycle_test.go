@@ -236,7 +236,7 @@ func createAmbiguousReferenceWorkbook(t testing.TB, dir string) string {
 func classifiedCsvCarriesTypeColumn() []func(*harness.Context) {
 	return []func(*harness.Context){
 		verify.OutputFileExists("classified.csv"),
-		verify.OutputFileHasColumns("classified.csv", 8), // 7 original + type
+		verify.OutputFileHasColumns("classified.csv", 10), // 7 original + type + account + tags
 	}
 }
 